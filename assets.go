@@ -0,0 +1,191 @@
+package figmaextractor
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"image"
+	_ "image/jpeg" // decode the size of exported JPEGs
+	_ "image/png"  // decode the size of exported PNGs
+	"io"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/hellenic-development/figma-extractor/pkg/extractor"
+	"github.com/hellenic-development/figma-extractor/pkg/figma"
+	"github.com/hellenic-development/figma-extractor/pkg/imager"
+)
+
+// AssetsFile is the name of the asset manifest written to the image directory.
+const AssetsFile = "assets.json"
+
+// AssetsManifest describes the images of an export for build tools, written as assets.json.
+type AssetsManifest struct {
+	File    string      `json:"file"`    // Figma file name
+	FileKey string      `json:"fileKey"` // Figma file key
+	Version string      `json:"version"` // Figma version ID the images were exported from
+	Assets  []AssetInfo `json:"assets"`  // sorted by path
+}
+
+// AssetInfo describes an exported image.
+type AssetInfo struct {
+	NodeID     string  `json:"nodeId"`
+	Name       string  `json:"name"`
+	Path       string  `json:"path"` // relative to the image directory, slash-separated
+	Format     string  `json:"format"`
+	Scale      float64 `json:"scale"`
+	Width      int     `json:"width,omitempty"`  // pixels of raster images, points of vector ones
+	Height     int     `json:"height,omitempty"` // pixels of raster images, points of vector ones
+	Hash       string  `json:"hash"`             // SHA-256 of the file
+	FigmaURL   string  `json:"figmaUrl"`         // opens the node in Figma
+	Screenshot bool    `json:"screenshot,omitempty"`
+}
+
+// writeAssetsJSON writes assets.json, describing the exported images, to the image store.
+func writeAssetsJSON(ctx context.Context, store imager.AssetStore, fileKey string, specs *extractor.DesignSpecs, fileResp *figma.FileResponse) error {
+	manifest := AssetsManifest{
+		File:    fileResp.Name,
+		FileKey: fileKey,
+		Version: fileResp.Version,
+		Assets:  make([]AssetInfo, 0, len(specs.ExportedAssets)),
+	}
+
+	for _, a := range specs.ExportedAssets {
+		info := AssetInfo{
+			NodeID:     a.NodeID,
+			Name:       a.NodeName,
+			Path:       a.FileName,
+			Format:     a.Format,
+			Scale:      a.Scale,
+			FigmaURL:   figma.NodeURL(fileKey, a.NodeID),
+			Screenshot: a.IsScreenshot,
+		}
+		info.Hash, info.Width, info.Height = describeAsset(ctx, store, a.FileName)
+		if info.Width == 0 {
+			// Vector images have no pixel size: use the size of the node.
+			if node := findNode(&fileResp.Document, a.NodeID); node != nil && node.AbsoluteBoundingBox != nil {
+				info.Width = int(math.Round(node.AbsoluteBoundingBox.Width))
+				info.Height = int(math.Round(node.AbsoluteBoundingBox.Height))
+			}
+		}
+		manifest.Assets = append(manifest.Assets, info)
+	}
+	sort.Slice(manifest.Assets, func(i, j int) bool {
+		return manifest.Assets[i].Path < manifest.Assets[j].Path
+	})
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return store.Put(ctx, AssetsFile, bytes.NewReader(append(data, '\n')))
+}
+
+// describeAsset returns the SHA-256 of an asset and, for PNG and JPEG images, its size in pixels.
+func describeAsset(ctx context.Context, store imager.AssetStore, name string) (hash string, width, height int) {
+	data, err := readAsset(ctx, store, name)
+	if err != nil {
+		return "", 0, 0
+	}
+	sum := sha256.Sum256(data)
+	hash = hex.EncodeToString(sum[:])
+	if config, _, err := image.DecodeConfig(bytes.NewReader(data)); err == nil {
+		width, height = config.Width, config.Height
+	}
+	return hash, width, height
+}
+
+// writeAssetCatalog moves the exported images, except the screenshot, into an Xcode asset
+// catalog, updating their file names in specs.
+func writeAssetCatalog(ctx context.Context, opts *Options, store imager.AssetStore, specs *extractor.DesignSpecs, manifest *imager.Manifest) {
+	assets, indexes := layoutAssets(specs)
+	if len(assets) == 0 {
+		return
+	}
+	assets, err := imager.WriteAssetCatalog(ctx, store, assets, manifest)
+	if err != nil {
+		opts.warnf(WarningOutput, "Could not write %s: %v", imager.AssetCatalogDir, err)
+		return
+	}
+	for j, i := range indexes {
+		specs.ExportedAssets[i].FileName = assets[j].FileName
+	}
+	opts.logInfo("Wrote the asset catalog %s", imager.AssetCatalogDir)
+}
+
+// writeAndroidResources lays the exported images, except the screenshot, out as Android
+// drawable resources, updating their file names in specs.
+func writeAndroidResources(ctx context.Context, opts *Options, store imager.AssetStore, specs *extractor.DesignSpecs, manifest *imager.Manifest) {
+	assets, indexes := layoutAssets(specs)
+	if len(assets) == 0 {
+		return
+	}
+	assets, errs := imager.WriteAndroidResources(ctx, store, assets, manifest)
+	for _, err := range errs {
+		opts.warnf(WarningOutput, "Android resources: %v", err)
+	}
+	for j, i := range indexes {
+		specs.ExportedAssets[i].FileName = assets[j].FileName
+	}
+	opts.logInfo("Wrote the Android drawables to %s", imager.AndroidResDir)
+}
+
+// layoutAssets returns the exported images to lay out for a platform, all but the screenshot,
+// and their indexes in specs.
+func layoutAssets(specs *extractor.DesignSpecs) ([]imager.ExportedAsset, []int) {
+	var assets []imager.ExportedAsset
+	var indexes []int
+	for i, a := range specs.ExportedAssets {
+		if a.IsScreenshot {
+			continue
+		}
+		assets = append(assets, imager.ExportedAsset{
+			NodeID:   a.NodeID,
+			NodeName: a.NodeName,
+			FileName: a.FileName,
+			Format:   a.Format,
+			Scale:    a.Scale,
+		})
+		indexes = append(indexes, i)
+	}
+	return assets, indexes
+}
+
+// writeSprite combines the exported SVGs, except the screenshot, into an SVG sprite.
+func writeSprite(ctx context.Context, opts *Options, store imager.AssetStore, assets []extractor.ExportedAssetInfo) {
+	var icons []string
+	for _, a := range assets {
+		if a.Format == "svg" && !a.IsScreenshot {
+			icons = append(icons, a.FileName)
+		}
+	}
+	if len(icons) == 0 {
+		opts.warnf(WarningOption, "No SVG images to build a sprite of, export them with the svg image format")
+		return
+	}
+	symbols, err := imager.WriteSprite(ctx, store, icons)
+	if err != nil {
+		opts.warnf(WarningOutput, "Could not write %s: %v", imager.SpriteFile, err)
+		return
+	}
+	opts.logInfo("Combined %d icon(s) into %s, see %s for usage", len(symbols), imager.SpriteFile, imager.SpriteUsageFile)
+}
+
+// readAsset returns the content of an asset.
+func readAsset(ctx context.Context, store imager.AssetStore, name string) ([]byte, error) {
+	r, err := store.Open(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// frameScreenshotName returns the file name of the screenshot of a frame of a per-frame
+// report, e.g. "screenshot_12-34.png" for node 12:34.
+func frameScreenshotName(nodeID, format string) string {
+	return "screenshot_" + strings.NewReplacer(":", "-", ";", "_").Replace(nodeID) + "." + format
+}
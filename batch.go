@@ -0,0 +1,166 @@
+package figmaextractor
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hellenic-development/figma-extractor/internal/yaml"
+)
+
+// BatchEntry is a Figma file of a batch extracted by RunBatch.
+type BatchEntry struct {
+	URL     string   `json:"url"`
+	NodeIDs []string `json:"nodes,omitempty"`  // nodes to extract instead of Options.NodeIDs
+	Output  string   `json:"output,omitempty"` // where the caller writes the outputs of the file
+}
+
+// ParseBatch parses a batch file listing Figma files, in one of three forms. A JSON array of
+// {"url", "nodes", "output"} objects; a YAML list of the same mappings, or of plain URLs:
+//
+//	# batch.yaml
+//	- url: https://www.figma.com/design/ABC123/Foundations
+//	  nodes: [1:2, 1:3]
+//	  output: specs/foundations
+//	- https://www.figma.com/design/DEF456/Marketing
+//
+// or text with a URL per line, optionally followed by nodes= and output= fields:
+//
+//	https://www.figma.com/design/ABC123/Foundations nodes=1:2,1:3 output=specs/foundations
+//
+// Blank lines and lines starting with "#" are skipped in YAML and text.
+func ParseBatch(data []byte) ([]BatchEntry, error) {
+	var (
+		entries []BatchEntry
+		err     error
+	)
+	switch first := firstBatchLine(data); {
+	case strings.HasPrefix(first, "["):
+		if err = json.Unmarshal(data, &entries); err != nil {
+			return nil, fmt.Errorf("parse batch: %w", err)
+		}
+	case strings.HasPrefix(first, "-"):
+		entries, err = parseBatchYAML(data)
+	default:
+		entries, err = parseBatchText(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("parse batch: no Figma files listed")
+	}
+	for i, entry := range entries {
+		if entry.URL == "" {
+			return nil, fmt.Errorf("parse batch: entry %d has no url", i+1)
+		}
+	}
+	return entries, nil
+}
+
+// firstBatchLine returns the first line of data that is not blank or a comment.
+func firstBatchLine(data []byte) string {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		if text := strings.TrimSpace(scanner.Text()); text != "" && text != "---" && !strings.HasPrefix(text, "#") {
+			return text
+		}
+	}
+	return ""
+}
+
+// parseBatchText parses the text form of a batch file.
+func parseBatchText(data []byte) ([]BatchEntry, error) {
+	var entries []BatchEntry
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		entry := BatchEntry{URL: fields[0]}
+		for _, field := range fields[1:] {
+			if strings.HasPrefix(field, "#") {
+				break
+			}
+			key, value, _ := strings.Cut(field, "=")
+			if err := entry.set(key, value); err != nil {
+				return nil, fmt.Errorf("parse batch: line %d: %w", line, err)
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// parseBatchYAML parses the YAML form of a batch file. Besides flow lists, nodes may be given
+// as a block list of node IDs.
+func parseBatchYAML(data []byte) ([]BatchEntry, error) {
+	lines, err := yaml.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse batch: %w", err)
+	}
+	var (
+		entries   []BatchEntry
+		indent    int  // indentation of the list of files
+		nodesList bool // reading a block list of nodes
+	)
+	for _, line := range lines {
+		switch {
+		case !line.Item && len(entries) == 0:
+			return nil, fmt.Errorf("parse batch: line %d: expected a list of files", line.Num)
+		case line.Item && (len(entries) == 0 || line.Indent <= indent):
+			if len(entries) == 0 {
+				indent = line.Indent
+			}
+			entries = append(entries, BatchEntry{})
+			nodesList = false
+			if !line.Pair {
+				entries[len(entries)-1].URL = line.Value
+				continue
+			}
+		case line.Item:
+			if !nodesList || line.Pair {
+				return nil, fmt.Errorf("parse batch: line %d: unexpected nested list", line.Num)
+			}
+			entry := &entries[len(entries)-1]
+			entry.NodeIDs = append(entry.NodeIDs, line.Value)
+			continue
+		}
+
+		if !line.Pair {
+			return nil, fmt.Errorf("parse batch: line %d: expected \"key: value\", got %q", line.Num, line.Value)
+		}
+		entry := &entries[len(entries)-1]
+		nodesList = line.Key == "nodes" && line.Value == "" && line.Items == nil
+		switch {
+		case nodesList: // the node IDs follow as list items
+		case line.Items != nil && line.Key == "nodes":
+			entry.NodeIDs = append(entry.NodeIDs, line.Items...)
+		case line.Items != nil:
+			return nil, fmt.Errorf("parse batch: line %d: %q is not a list", line.Num, line.Key)
+		default:
+			if err := entry.set(line.Key, line.Value); err != nil {
+				return nil, fmt.Errorf("parse batch: line %d: %w", line.Num, err)
+			}
+		}
+	}
+	return entries, nil
+}
+
+// set sets the field key of e; nodes are comma-separated.
+func (e *BatchEntry) set(key, value string) error {
+	switch key {
+	case "url":
+		e.URL = value
+	case "nodes":
+		e.NodeIDs = append(e.NodeIDs, ParseNodeIDs(value)...)
+	case "output":
+		e.Output = value
+	default:
+		return fmt.Errorf("unknown field %q (want url, nodes or output)", key)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package figmaextractor
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseBatch(t *testing.T) {
+	foundations := BatchEntry{
+		URL:     "https://www.figma.com/design/ABC123/Foundations",
+		NodeIDs: []string{"1:2", "1:3"},
+		Output:  "specs/foundations",
+	}
+	marketing := BatchEntry{URL: "https://www.figma.com/design/DEF456/Marketing"}
+
+	tests := []struct {
+		name string
+		data string
+	}{
+		{
+			name: "json",
+			data: `[
+				{"url": "https://www.figma.com/design/ABC123/Foundations", "nodes": ["1:2", "1:3"], "output": "specs/foundations"},
+				{"url": "https://www.figma.com/design/DEF456/Marketing"}
+			]`,
+		},
+		{
+			name: "yaml flush",
+			data: `# batch.yaml
+- url: https://www.figma.com/design/ABC123/Foundations
+  nodes: [1:2, "1:3"]
+  output: specs/foundations # comment
+
+- https://www.figma.com/design/DEF456/Marketing
+`,
+		},
+		{
+			name: "yaml indented",
+			data: `---
+  - url: "https://www.figma.com/design/ABC123/Foundations"
+    nodes:
+      - 1:2
+      - '1:3'
+    output: 'specs/foundations'
+  - url: https://www.figma.com/design/DEF456/Marketing
+`,
+		},
+		{
+			name: "yaml entry on the next line",
+			data: `-
+  url: https://www.figma.com/design/ABC123/Foundations
+  nodes: 1:2, 1:3
+  output: specs/foundations
+- https://www.figma.com/design/DEF456/Marketing
+`,
+		},
+		{
+			name: "text",
+			data: `# files
+https://www.figma.com/design/ABC123/Foundations nodes=1:2,1:3 output=specs/foundations
+
+https://www.figma.com/design/DEF456/Marketing # no nodes
+`,
+		},
+	}
+
+	want := []BatchEntry{foundations, marketing}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseBatch([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("ParseBatch() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("ParseBatch() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestParseBatchErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"empty", "# nothing\n\n", "no Figma files listed"},
+		{"invalid json", `[{"url": 1}]`, "parse batch: json"},
+		{"entry without url", "- nodes: [1:2]\n", "entry 1 has no url"},
+		{"unknown yaml field", "- url: https://www.figma.com/design/ABC\n  name: x\n", `line 2: unknown field "name"`},
+		{"unknown text field", "https://www.figma.com/design/ABC name=x\n", `line 1: unknown field "name"`},
+		{"unterminated quote", "- url: 'https://www.figma.com/design/ABC\n", "line 1: unterminated string"},
+		{"nested list", "- url: https://www.figma.com/design/ABC\n  - 1:2\n", "line 2: unexpected nested list"},
+		{"list field", "- url: [a, b]\n", `line 1: "url" is not a list`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseBatch([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ParseBatch() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	figmaextractor "github.com/hellenic-development/figma-extractor"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes of the diff command.
+const (
+	diffExitChanged = 1 // the design changed since the baseline
+	diffExitError   = 2 // the diff could not run
+)
+
+var (
+	diffBase   string
+	diffFailOn string
+)
+
+// newDiffCommand returns the diff command, which compares the design with a saved baseline.
+func newDiffCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Report the token and component changes of the design since a saved baseline",
+		Long: "Extracts the design, then compares its tokens and components with a baseline saved with --format json " +
+			"and lists what was added, removed or modified. Exits with status 1 when the design changed " +
+			"(see --fail-on) and 2 when the diff could not run, for design-change gates in CI.",
+		Run: diff,
+	}
+	cmd.Flags().StringVarP(&figmaURL, "url", "u", "", "Figma file URL (required; default: $FIGMA_URL)")
+	addExtractFlags(cmd)
+	cmd.Flags().StringVar(&inputJSON, "input-json", "", "Extract offline from a saved Figma file JSON response instead of the API (--url and --token become optional)")
+	cmd.Flags().StringVar(&diffBase, "base", "", "Baseline design specifications, written by a previous run with --format json (required)")
+	cmd.Flags().StringVar(&diffFailOn, "fail-on", "any", "Changes that fail the diff: tokens, components, any or none")
+	cmd.MarkFlagRequired("base")
+	return cmd
+}
+
+// diff extracts the design and reports how it changed since the baseline.
+func diff(cmd *cobra.Command, args []string) {
+	red := color.New(color.FgRed)
+	green := color.New(color.FgGreen)
+	yellow := color.New(color.FgYellow)
+
+	fail := func(err error) {
+		printError(err)
+		os.Exit(errorExitCode(err, diffExitError))
+	}
+
+	switch diffFailOn {
+	case "tokens", "components", "any", "none":
+	default:
+		fail(usageErrorf("invalid --fail-on %q (must be tokens, components, any or none)", diffFailOn))
+	}
+
+	f, err := os.Open(diffBase)
+	if err != nil {
+		fail(err)
+	}
+	base, err := figmaextractor.ReadSpecs(f)
+	f.Close()
+	if err != nil {
+		fail(err)
+	}
+
+	opts, _, err := cliOptions(cmd)
+	if err != nil {
+		fail(err)
+	}
+
+	var result *figmaextractor.Result
+	if inputJSON != "" {
+		result, err = runOffline(opts)
+	} else if figmaURL == "" || accessToken == "" {
+		err = fmt.Errorf("%w (or use --input-json)", errNoFile)
+	} else {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		result, err = figmaextractor.Run(ctx, opts)
+	}
+	if err != nil {
+		fail(err)
+	}
+
+	report := figmaextractor.Diff(base, result.Specs)
+
+	if jsonLog == nil {
+		fmt.Println()
+	}
+	for _, group := range []struct {
+		label   string
+		changes []figmaextractor.Change
+	}{
+		{"token", report.Tokens},
+		{"component", report.Components},
+	} {
+		for _, c := range group.changes {
+			if jsonLog != nil {
+				jsonLog.Info("change", "kind", string(c.Kind), "type", group.label, "name", c.Name, "before", c.Before, "after", c.After)
+				continue
+			}
+			switch c.Kind {
+			case figmaextractor.ChangeAdded:
+				green.Printf("  + %-9s ", group.label)
+				fmt.Printf("%s: %s\n", c.Name, c.After)
+			case figmaextractor.ChangeRemoved:
+				red.Printf("  - %-9s ", group.label)
+				fmt.Printf("%s: %s\n", c.Name, c.Before)
+			default:
+				yellow.Printf("  ~ %-9s ", group.label)
+				fmt.Printf("%s: %s → %s\n", c.Name, c.Before, c.After)
+			}
+		}
+	}
+
+	switch {
+	case jsonLog != nil:
+		logResult("base", diffBase, "tokens", len(report.Tokens), "components", len(report.Components))
+	case !report.Changed():
+		green.Printf("✨ No token or component changes since %s\n", diffBase)
+	default:
+		fmt.Printf("\n%d token and %d component change(s) since %s\n", len(report.Tokens), len(report.Components), diffBase)
+	}
+	switch {
+	case diffFailOn == "any" && report.Changed(),
+		diffFailOn == "tokens" && len(report.Tokens) > 0,
+		diffFailOn == "components" && len(report.Components) > 0:
+		os.Exit(diffExitChanged)
+	}
+}
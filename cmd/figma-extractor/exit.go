@@ -0,0 +1,73 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+
+	figmaextractor "github.com/hellenic-development/figma-extractor"
+	"github.com/hellenic-development/figma-extractor/pkg/figma"
+)
+
+// Exit statuses of the commands, so scripts can branch on the outcome of a run. lint and diff
+// exit with 1 when the code drifted or the design changed instead.
+const (
+	exitOK            = 0
+	exitError         = 1 // a failure not listed below
+	exitUsage         = 2 // invalid flags or options
+	exitAuth          = 3 // Figma rejected the access token
+	exitNotFound      = 4 // the Figma file, or the --input-json file, does not exist
+	exitRateLimited   = 5 // Figma kept rate limiting the requests
+	exitPartialAssets = 6 // the run completed, but some images could not be exported
+	exitWarnings      = 7 // the run completed with other warnings
+)
+
+// errorExitCode returns the exit status of a run that failed with err, fallback for failures
+// without a status of their own.
+func errorExitCode(err error, fallback int) int {
+	status := 0
+	if apiErr := (*figma.APIError)(nil); errors.As(err, &apiErr) {
+		status = apiErr.StatusCode
+	}
+	var configErr *figmaextractor.ConfigError
+	switch {
+	case errors.Is(err, figma.ErrRateLimited):
+		return exitRateLimited
+	case status == http.StatusUnauthorized, status == http.StatusForbidden:
+		return exitAuth
+	case status == http.StatusNotFound, errors.Is(err, fs.ErrNotExist):
+		return exitNotFound
+	case errors.As(err, &configErr), errors.As(err, new(usageError)), errors.Is(err, errNoFile):
+		return exitUsage
+	}
+	return fallback
+}
+
+// usageError is an invalid flag value.
+type usageError struct {
+	error
+}
+
+func usageErrorf(format string, a ...any) error {
+	return usageError{fmt.Errorf(format, a...)}
+}
+
+// warningsExitCode returns the exit status of a run that completed with warnings.
+func warningsExitCode(warnings []figmaextractor.Warning) int {
+	code := exitOK
+	for _, w := range warnings {
+		if w.Category == figmaextractor.WarningImage {
+			return exitPartialAssets
+		}
+		code = exitWarnings
+	}
+	return code
+}
+
+// fatal prints err and exits with its status.
+func fatal(err error) {
+	printError(err)
+	os.Exit(errorExitCode(err, exitError))
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	figmaextractor "github.com/hellenic-development/figma-extractor"
+	"github.com/hellenic-development/figma-extractor/pkg/extractor"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// nonSlug matches the runs of characters replaced by a hyphen in the directory names of files.
+var nonSlug = regexp.MustCompile(`[^a-z0-9]+`)
+
+// runFiles extracts the files of the repeated --url flags, or of the --batch file, concurrently
+// and writes the outputs of each into its own output directory from the batch file, or a
+// directory named after it under --output or the current directory. A line per file reports
+// its success or failure once all are done.
+func runFiles(cmd *cobra.Command, opts figmaextractor.Options, templateOutput string) {
+	if inputJSON != "" {
+		fatal(usageErrorf("--input-json extracts a single file; give --url once"))
+	}
+	entries, concurrency := []figmaextractor.BatchEntry(nil), 0
+	if batchFile != "" {
+		if cmd.Flags().Changed("url") {
+			fatal(usageErrorf("--batch lists the files to extract; leave out --url"))
+		}
+		if batchConcurrency < 1 {
+			fatal(usageErrorf("--batch-concurrency must be at least 1, got %d", batchConcurrency))
+		}
+		data, err := os.ReadFile(batchFile)
+		if err != nil {
+			fatal(err)
+		}
+		if entries, err = figmaextractor.ParseBatch(data); err != nil {
+			fatal(usageError{err})
+		}
+		concurrency = batchConcurrency
+	} else {
+		for _, fileURL := range figmaURLs {
+			entries = append(entries, figmaextractor.BatchEntry{URL: fileURL})
+		}
+	}
+	if accessToken == "" {
+		fatal(errNoFile)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	results, err := figmaextractor.RunBatch(ctx, opts, entries, concurrency)
+	if err != nil {
+		fatal(err)
+	}
+
+	base := "."
+	if cmd.Flags().Changed("output") {
+		base = outputFile
+	}
+	used := make(map[string]bool)
+	var (
+		warnings []figmaextractor.Warning
+		errs     []error
+	)
+	for _, b := range results {
+		dir := b.Entry.Output
+		if b.Err == nil {
+			if dir == "" {
+				dir = filepath.Join(base, fileDirName(b.Result, used))
+			}
+			b.Err = writeOutputsTo(dir, b.Result.Outputs, templateOutput)
+		}
+		if b.Err != nil {
+			errs = append(errs, b.Err)
+			if jsonLog != nil {
+				jsonLog.Error("file failed", "url", b.Entry.URL, "error", b.Err.Error())
+			} else {
+				color.New(color.FgRed).Printf("\n✗ %s: %v\n", b.Entry.URL, b.Err)
+			}
+			continue
+		}
+		result := b.Result
+		warnings = append(warnings, result.Warnings...)
+
+		if jsonLog != nil {
+			logSummary(result.Specs, "url", b.Entry.URL, "file_key", result.FileKey, "file_name", result.FileName,
+				"version", result.Version, "output", dir, "warnings", len(result.Warnings))
+		} else if chatty() {
+			printFileSummary(result, dir)
+		}
+	}
+
+	code := warningsExitCode(warnings)
+	if len(errs) > 0 {
+		code = errorExitCode(errors.Join(errs...), exitError)
+	}
+	written := len(results) - len(errs)
+	switch {
+	case jsonLog != nil:
+		logResult("output", base, "files", written, "failed", len(errs), "warnings", len(warnings), "exit_code", code)
+	case chatty():
+		color.New(color.FgGreen).Printf("\n✨ Extracted %d of %d file(s)\n\n", written, len(results))
+		if n := len(warnings); n > 0 {
+			color.New(color.FgYellow).Printf("⚠ %d warning(s), see above\n\n", n)
+		}
+	}
+	os.Exit(code)
+}
+
+// fileDirName returns the directory name of the outputs of result: its file name in kebab
+// case, or its file key when the name is empty or already used, numbered when the same file was
+// given several times.
+func fileDirName(result *figmaextractor.Result, used map[string]bool) string {
+	name := strings.Trim(nonSlug.ReplaceAllString(strings.ToLower(result.FileName), "-"), "-")
+	if name == "" || used[name] {
+		name = result.FileKey
+	}
+	for i := 2; used[name]; i++ {
+		name = fmt.Sprintf("%s-%d", result.FileKey, i)
+	}
+	used[name] = true
+	return name
+}
+
+// printFileSummary prints a line summing up the extraction of one of several files.
+func printFileSummary(result *figmaextractor.Result, dir string) {
+	specs := result.Specs
+	cyan := color.New(color.FgCyan)
+	cyan.Printf("\n✓ %s", result.FileName)
+	fmt.Printf(" (%s): %d colors, %d font sizes, %d spacing values, %d components → %s",
+		result.FileKey, colorCount(specs), len(specs.Typography.FontSizes), len(specs.Spacing.Values), len(specs.Components), dir)
+	if n := len(result.Warnings); n > 0 {
+		color.New(color.FgYellow).Printf(", %d warning(s)", n)
+	}
+	fmt.Println()
+}
+
+// colorCount returns the number of extracted colors of every category.
+func colorCount(specs *extractor.DesignSpecs) int {
+	c := specs.Colors
+	return len(c.Primary) + len(c.Secondary) + len(c.Background) + len(c.Text) + len(c.Status) + len(c.Border) +
+		len(c.Brand) + len(c.Accent) + len(c.Neutral)
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+
+	figmaextractor "github.com/hellenic-development/figma-extractor"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var imagesScreenshot bool
+
+// newImagesCommand returns the images command, which exports the images of a file without
+// writing a report.
+func newImagesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "images",
+		Short: "Export the images and icons of a Figma file, without a report",
+		Long: "Exports the images of a Figma file: the nodes with export settings and the embedded images, " +
+			"or the nodes matching --nodes, in the chosen formats, scales and directory layout. " +
+			"No report or token file is written.",
+		Run: extractImages,
+	}
+	cmd.Flags().StringVarP(&figmaURL, "url", "u", "", "Figma file URL (required; default: $FIGMA_URL)")
+	cmd.Flags().StringVarP(&accessToken, "token", "t", "", "Figma Personal Access Token (required; default: $FIGMA_TOKEN)")
+	cmd.Flags().BoolVar(&oauthToken, "oauth", false, "Treat --token as an OAuth access token (sent as a bearer token)")
+	cmd.Flags().StringVarP(&nodeIDs, "node-ids", "n", "", "Comma-separated node IDs to export the images of (default: the entire file)")
+	cmd.Flags().StringVar(&fileVersion, "file-version", "", "File version to export: a version ID or the label of a saved version (default: current)")
+	cmd.Flags().StringVar(&imageNodes, "nodes", "", "Comma-separated name patterns of the nodes to export, e.g. \"Icons/*,Illustrations/*\", with or without export settings (default: nodes with export settings and embedded images)")
+	cmd.Flags().StringVarP(&imageFormat, "format", "f", "png", "Image format: png, svg, jpg, pdf")
+	cmd.Flags().StringVar(&imageScales, "scales", "1", "Comma-separated scale factors (e.g. \"1,2,3\")")
+	cmd.Flags().BoolVar(&exportSettings, "use-export-settings", false, "Export nodes in the formats, scales and suffixes set in their Figma export settings instead of --format and --scales")
+	cmd.Flags().StringVarP(&imageDir, "output", "o", "figma-assets", "Output directory")
+	cmd.Flags().StringVar(&imageStore, "store", "", "Store the images in an S3-compatible bucket instead of --output, e.g. s3://bucket/prefix (credentials from the AWS_* environment variables)")
+	cmd.Flags().BoolVar(&imageHierarchy, "hierarchy", false, "Place the images in subdirectories mirroring their Figma page and top-level frame")
+	cmd.Flags().BoolVar(&assetCatalog, "xcassets", false, "Place the images in an Xcode asset catalog, Images.xcassets, with an image set per node")
+	cmd.Flags().BoolVar(&androidResources, "android-res", false, "Place the images in Android res/drawable-<density> directories and convert SVGs to vector drawables")
+	cmd.Flags().BoolVar(&svgSprite, "svg-sprite", false, "Combine exported SVG icons into sprite.svg, a symbol per icon, with a sprite.html usage snippet")
+	cmd.Flags().BoolVar(&imagesScreenshot, "screenshot", false, "Also capture a screenshot of the design")
+	cmd.Flags().BoolVar(&incremental, "incremental", false, "Skip exporting images whose nodes did not change since the last export")
+	cmd.Flags().BoolVar(&optimizeImages, "optimize-images", false, "Losslessly recompress exported PNGs and strip PNG/JPEG metadata")
+	cmd.Flags().BoolVar(&optimizeSVG, "svg-optimize", false, "Minify exported SVGs: strip comments and editor metadata, collapse groups, round coordinates")
+	cmd.Flags().BoolVar(&svgIncludeID, "svg-include-id", false, "Add layer names as id attributes to exported SVG elements")
+	cmd.Flags().BoolVar(&svgSimplifyStroke, "svg-simplify-stroke", true, "Simplify inside and outside strokes in exported SVGs where possible")
+	cmd.Flags().BoolVar(&absoluteBounds, "use-absolute-bounds", false, "Render the full dimensions of nodes, including cropped content")
+	cmd.Flags().BoolVar(&contentsOnly, "contents-only", true, "Render nodes alone, without content overlapping them")
+	cmd.Flags().IntVar(&downloadWorkers, "download-concurrency", 5, "Number of images downloaded at the same time")
+	cmd.Flags().IntVar(&downloadRate, "download-rate", 0, "Maximum combined image download speed in KB per second (default: unlimited)")
+	cmd.Flags().IntVar(&downloadRetries, "download-retries", 3, "Retries of an image download that failed with a transient error (-1 = none)")
+	cmd.Flags().StringVar(&cacheDir, "cache-dir", "", "Directory caching file data, image URLs and downloaded assets per file version between runs (default: no cache)")
+	cmd.Flags().StringVar(&proxy, "proxy", "", "HTTP(S) or SOCKS5 proxy URL for Figma requests and image downloads (default: HTTPS_PROXY/HTTP_PROXY)")
+	cmd.Flags().IntVar(&rateLimit, "rate-limit", 0, "Maximum Figma API requests per minute; further requests wait (default: unlimited)")
+	return cmd
+}
+
+// extractImages exports the images of the file.
+func extractImages(cmd *cobra.Command, args []string) {
+	green := color.New(color.FgGreen)
+
+	if figmaURL == "" || accessToken == "" {
+		fatal(errNoFile)
+	}
+	opts, _, err := cliOptions(cmd)
+	if err != nil {
+		fatal(err)
+	}
+	opts.ExportImages = true
+	opts.NoScreenshot = !imagesScreenshot
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	result, err := figmaextractor.Run(ctx, opts)
+	if err != nil {
+		fatal(err)
+	}
+
+	location := imageDir
+	if imageStore != "" {
+		location = imageStore
+	}
+	code := warningsExitCode(result.Warnings)
+	switch {
+	case jsonLog != nil:
+		logResult("output", location, "images", len(result.Specs.ExportedAssets), "version", result.Version, "warnings", len(result.Warnings), "exit_code", code)
+	case chatty():
+		green.Printf("\n✨ Exported %d image(s) to %s\n\n", len(result.Specs.ExportedAssets), location)
+		if n := len(result.Warnings); n > 0 {
+			color.New(color.FgYellow).Printf("⚠ %d warning(s), see above\n\n", n)
+		}
+	}
+	os.Exit(code)
+}
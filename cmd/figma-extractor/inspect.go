@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+
+	figmaextractor "github.com/hellenic-development/figma-extractor"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	inspectFormat string
+	inspectDepth  int
+)
+
+// newInspectCommand returns the inspect command, which lists the pages, frames and components
+// of a file with their node IDs.
+func newInspectCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "inspect",
+		Short: "List the pages, frames and components of a Figma file with their node IDs",
+		Long: "Prints the pages of a Figma file, the sections and top-level frames of every page and its component sets " +
+			"and components, with the node IDs to pass to --node-ids, as a tree or as JSON.",
+		Run: inspect,
+	}
+	cmd.Flags().StringVarP(&figmaURL, "url", "u", "", "Figma file URL (required; default: $FIGMA_URL)")
+	cmd.Flags().StringVarP(&accessToken, "token", "t", "", "Figma Personal Access Token (required; default: $FIGMA_TOKEN)")
+	cmd.Flags().BoolVar(&oauthToken, "oauth", false, "Treat --token as an OAuth access token (sent as a bearer token)")
+	cmd.Flags().StringVar(&fileVersion, "file-version", "", "File version to inspect: a version ID or the label of a saved version (default: current)")
+	cmd.Flags().StringVarP(&inspectFormat, "format", "f", "tree", "Output format: tree or json")
+	cmd.Flags().IntVar(&inspectDepth, "depth", 0, "Levels of the document to fetch, for large files: 1 for the pages, 2 for their top-level frames (default: all, with every component)")
+	cmd.Flags().StringVar(&proxy, "proxy", "", "HTTP(S) or SOCKS5 proxy URL for Figma requests (default: HTTPS_PROXY/HTTP_PROXY)")
+	cmd.Flags().StringVar(&cacheDir, "cache-dir", "", "Directory caching file data per file version between runs (default: no cache)")
+	return cmd
+}
+
+// inspect prints the outline of the file.
+func inspect(cmd *cobra.Command, args []string) {
+	cyan := color.New(color.FgCyan)
+	dim := color.New(color.Faint)
+
+	if inspectFormat != "tree" && inspectFormat != "json" {
+		fatal(usageErrorf("unknown format %q (must be tree or json)", inspectFormat))
+	}
+	if figmaURL == "" || accessToken == "" {
+		fatal(errNoFile)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	outline, err := figmaextractor.Inspect(ctx, figmaextractor.Options{
+		AccessToken: accessToken,
+		OAuth:       oauthToken,
+		Proxy:       proxy,
+		CacheDir:    cacheDir,
+		FileURL:     figmaURL,
+		Version:     fileVersion,
+		TraceHTTP:   traceRequest,
+		Logger:      newLogger(),
+	}, inspectDepth)
+	if err != nil {
+		fatal(err)
+	}
+
+	if jsonLog != nil {
+		logResult("outline", outline)
+		return
+	}
+	if inspectFormat == "json" {
+		out, err := json.MarshalIndent(outline, "", "  ")
+		if err != nil {
+			fatal(err)
+		}
+		fmt.Println(string(out))
+		return
+	}
+
+	cyan.Printf("%s", outline.Name)
+	dim.Printf(" (version %s)\n", outline.Version)
+	var printNodes func(nodes []figmaextractor.OutlineNode, indent string)
+	printNodes = func(nodes []figmaextractor.OutlineNode, indent string) {
+		for i, n := range nodes {
+			branch, next := "├── ", "│   "
+			if i == len(nodes)-1 {
+				branch, next = "└── ", "    "
+			}
+			fmt.Printf("%s%s%s ", indent, branch, n.Name)
+			dim.Printf("%s %s\n", n.Type, n.ID)
+			printNodes(n.Children, indent+next)
+		}
+	}
+	printNodes(outline.Pages, "")
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	figmaextractor "github.com/hellenic-development/figma-extractor"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes of the lint command.
+const (
+	lintExitDrift = 1 // the code drifted from the design
+	lintExitError = 2 // the lint could not run
+)
+
+var (
+	lintTokensFile string
+	lintKind       string
+)
+
+// newLintCommand returns the lint command, which checks an existing token file against the
+// design.
+func newLintCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lint",
+		Short: "Report design tokens missing from or diverged in an existing token file",
+		Long: "Extracts the design, then compares its tokens with an existing token file of the codebase " +
+			"(CSS custom properties, a Tailwind config or DTCG JSON) and lists the tokens the code lacks or defines " +
+			"with another value. Exits with status 1 when the code drifted from the design and 2 when the lint could not run, for CI.",
+		Run: lint,
+	}
+	cmd.Flags().StringVarP(&figmaURL, "url", "u", "", "Figma file URL (required; default: $FIGMA_URL)")
+	addExtractFlags(cmd)
+	cmd.Flags().StringVar(&inputJSON, "input-json", "", "Extract offline from a saved Figma file JSON response instead of the API (--url and --token become optional)")
+	cmd.Flags().StringVar(&lintTokensFile, "tokens", "", "Token file of the codebase to check, e.g. src/tokens.css, tailwind.config.js or tokens.json (required)")
+	cmd.Flags().StringVar(&lintKind, "kind", "", "Kind of the token file: css, tailwind or dtcg (default: guessed from its name)")
+	cmd.MarkFlagRequired("tokens")
+	return cmd
+}
+
+// lint extracts the design and reports how the token file drifted from it.
+func lint(cmd *cobra.Command, args []string) {
+	red := color.New(color.FgRed)
+	green := color.New(color.FgGreen)
+	yellow := color.New(color.FgYellow)
+
+	fail := func(err error) {
+		printError(err)
+		os.Exit(errorExitCode(err, lintExitError))
+	}
+
+	opts, _, err := cliOptions(cmd)
+	if err != nil {
+		fail(err)
+	}
+
+	var result *figmaextractor.Result
+	if inputJSON != "" {
+		result, err = runOffline(opts)
+	} else if figmaURL == "" || accessToken == "" {
+		err = fmt.Errorf("%w (or use --input-json)", errNoFile)
+	} else {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		result, err = figmaextractor.Run(ctx, opts)
+	}
+	if err != nil {
+		fail(err)
+	}
+
+	kind := lintKind
+	if kind == "" {
+		kind = figmaextractor.TokenFileKind(lintTokensFile)
+	}
+	f, err := os.Open(lintTokensFile)
+	if err != nil {
+		fail(err)
+	}
+	defer f.Close()
+	report, err := figmaextractor.Lint(result.Specs, f, kind)
+	if err != nil {
+		fail(err)
+	}
+
+	if jsonLog != nil {
+		for _, d := range report.Missing {
+			jsonLog.Warn("missing token", "token", d.Token, "design", d.Design)
+		}
+		for _, d := range report.Diverged {
+			jsonLog.Warn("diverged token", "token", d.Token, "design", d.Design, "code", d.Code)
+		}
+		logResult("tokens_file", lintTokensFile, "checked", report.Checked, "missing", len(report.Missing), "diverged", len(report.Diverged))
+		if report.Drifted() {
+			os.Exit(lintExitDrift)
+		}
+		return
+	}
+
+	fmt.Println()
+	for _, d := range report.Missing {
+		red.Printf("  ✗ missing   ")
+		fmt.Printf("%s: %s\n", d.Token, d.Design)
+	}
+	for _, d := range report.Diverged {
+		yellow.Printf("  ≠ diverged  ")
+		fmt.Printf("%s: %s in the design, %s in the code\n", d.Token, d.Design, d.Code)
+	}
+	if report.Drifted() {
+		red.Printf("\n%d of %d design token(s) missing, %d diverged in %s\n", len(report.Missing), report.Checked, len(report.Diverged), lintTokensFile)
+		os.Exit(lintExitDrift)
+	}
+	green.Printf("✨ All %d design token(s) match %s\n", report.Checked, lintTokensFile)
+}
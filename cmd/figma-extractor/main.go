@@ -1,11 +1,21 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
 
 	figmaextractor "github.com/hellenic-development/figma-extractor"
+	"github.com/hellenic-development/figma-extractor/pkg/extractor"
 	"github.com/hellenic-development/figma-extractor/pkg/figma"
+	"github.com/hellenic-development/figma-extractor/pkg/formatter"
+	"github.com/hellenic-development/figma-extractor/pkg/imager"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
@@ -15,15 +25,66 @@ const version = figma.Version
 
 var (
 	figmaURL           string
+	figmaURLs          []string
+	batchFile          string
+	batchConcurrency   int
 	accessToken        string
+	oauthToken         bool
+	proxy              string
+	rateLimit          int
+	cacheDir           string
+	inputJSON          string
+	captureDir         string
 	outputFile         string
 	nodeIDs            string
+	fileVersion        string
 	inheritFileContext bool
+	includeNodes       string
+	excludeNodes       string
+	nodeTypes          string
 	exportImages       bool
 	imageFormat        string
 	imageScales        string
 	imageDir           string
+	imageStore         string
+	exportSettings     bool
+	imageNodes         string
+	noScreenshot       bool
+	imageHierarchy     bool
+	assetCatalog       bool
+	androidResources   bool
+	incremental        bool
+	downloadWorkers    int
+	downloadRate       int
+	downloadRetries    int
+	optimizeImages     bool
+	optimizeSVG        bool
+	svgSprite          bool
+	svgIncludeID       bool
+	svgSimplifyStroke  bool
+	absoluteBounds     bool
+	contentsOnly       bool
 	componentTree      bool
+	vectorPaths        bool
+	pluginData         string
+	variables          bool
+	comments           bool
+	componentUsage     bool
+	devResources       bool
+	libraryStyles      bool
+	teamID             string
+	rulesFile          string
+	outputFormat       string
+	templateFile       string
+	sections           string
+	frontMatter        bool
+	tableOfContents    bool
+	perFrame           bool
+	splitNodes         bool
+	naming             string
+	unit               string
+	baseFontSize       float64
+	pollInterval       time.Duration
 )
 
 func main() {
@@ -32,52 +93,333 @@ func main() {
 		Short: "Extract design specifications from Figma files",
 		Long:  "A tool to extract design tokens, colors, typography, and other specifications from Figma files via the Figma API",
 		Run:   run,
+		// The file and token may come from the environment, to keep the token out of shell
+		// history and CI command lines.
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			flagsFromEnv()
+			if err := setupLogging(); err != nil {
+				fatal(err)
+			}
+		},
 	}
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", "text", "Format of the progress and results: text, or json for JSON lines (for CI and wrappers)")
+	rootCmd.PersistentFlags().BoolVarP(&quietOutput, "quiet", "q", false, "Print errors only")
+	rootCmd.PersistentFlags().BoolVarP(&verboseLogs, "verbose", "v", false, "Also print every fetched node and downloaded image")
+	rootCmd.PersistentFlags().BoolVar(&debugLogs, "debug", false, "Print everything --verbose does and trace every HTTP request with its status and duration")
 
-	rootCmd.Flags().StringVarP(&figmaURL, "url", "u", "", "Figma file URL (required)")
-	rootCmd.Flags().StringVarP(&accessToken, "token", "t", "", "Figma Personal Access Token (required)")
-	rootCmd.Flags().StringVarP(&outputFile, "output", "o", "FIGMA_DESIGN_SPECIFICATIONS.md", "Output markdown file")
-	rootCmd.Flags().StringVarP(&nodeIDs, "node-ids", "n", "", "Comma-separated node IDs to extract (optional, extracts specific nodes instead of entire file)")
-	rootCmd.Flags().BoolVarP(&inheritFileContext, "inherit-context", "i", false, "Inherit file-level context (colors, styles) when extracting specific nodes")
-	rootCmd.Flags().BoolVar(&exportImages, "export-images", false, "Export images/assets from Figma")
-	rootCmd.Flags().StringVar(&imageFormat, "image-format", "png", "Image format: png, svg, jpg, pdf")
-	rootCmd.Flags().StringVar(&imageScales, "image-scales", "1", "Comma-separated scale factors (e.g. \"1,2,3\")")
-	rootCmd.Flags().StringVar(&imageDir, "image-dir", "figma-assets", "Output directory for exported images")
-	rootCmd.Flags().BoolVar(&componentTree, "component-tree", false, "Include hierarchical component tree in output")
-
-	rootCmd.MarkFlagRequired("url")
-	rootCmd.MarkFlagRequired("token")
+	rootCmd.Flags().StringArrayVarP(&figmaURLs, "url", "u", nil, "Figma file URL, repeated to extract several files at once (required; default: $FIGMA_URL)")
+	addExtractFlags(rootCmd)
+	rootCmd.Flags().StringVar(&batchFile, "batch", "", "Text, YAML or JSON file listing the Figma files to extract, each optionally with its own node IDs and output directory")
+	rootCmd.Flags().IntVar(&batchConcurrency, "batch-concurrency", 4, "Number of --batch files extracted at the same time")
+	rootCmd.Flags().StringVar(&inputJSON, "input-json", "", "Extract offline from a saved Figma file JSON response instead of the API (--url and --token become optional)")
 
 	versionCmd := &cobra.Command{
 		Use:   "version",
 		Short: "Print the version number",
 		Run: func(cmd *cobra.Command, args []string) {
+			if jsonLog != nil {
+				logResult("version", version)
+				return
+			}
 			fmt.Printf("figma-extractor version %s\n", version)
 		},
 	}
 
-	rootCmd.AddCommand(versionCmd)
+	watchCmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Extract, then extract again whenever the Figma file changes",
+		Long:  "Runs the extraction, then polls the Figma file's version and rewrites the outputs every time the design changes, until interrupted",
+		Run:   watch,
+	}
+	watchCmd.Flags().StringVarP(&figmaURL, "url", "u", "", "Figma file URL (required; default: $FIGMA_URL)")
+	addExtractFlags(watchCmd)
+	watchCmd.Flags().DurationVar(&pollInterval, "interval", 30*time.Second, "How often to check the Figma file for changes")
+
+	versionsCmd := &cobra.Command{
+		Use:   "versions",
+		Short: "List the recent versions of a Figma file",
+		Long:  "Lists the most recent versions of a Figma file's version history, to pick one for --file-version",
+		Run:   listVersions,
+	}
+	versionsCmd.Flags().StringVarP(&figmaURL, "url", "u", "", "Figma file URL (required; default: $FIGMA_URL)")
+	versionsCmd.Flags().StringVarP(&accessToken, "token", "t", "", "Figma Personal Access Token (required; default: $FIGMA_TOKEN)")
+	versionsCmd.Flags().BoolVar(&oauthToken, "oauth", false, "Treat --token as an OAuth access token (sent as a bearer token)")
+
+	rootCmd.AddCommand(versionCmd, watchCmd, versionsCmd, newLintCommand(), newTokensCommand(), newImagesCommand(), newDiffCommand(), newServeCommand(), newInspectCommand())
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
-		os.Exit(1)
+		os.Exit(exitUsage)
+	}
+}
+
+// errNoFile reports that neither the flags nor the environment name the Figma file and token.
+var errNoFile = errors.New(`required flags "url" and "token" not set, nor FIGMA_URL and FIGMA_TOKEN`)
+
+// flagsFromEnv sets --url and --token, when not given, from the FIGMA_URL and FIGMA_TOKEN
+// environment variables. Flags take precedence.
+func flagsFromEnv() {
+	if len(figmaURLs) > 0 {
+		figmaURL = figmaURLs[0]
+	}
+	if figmaURL == "" {
+		figmaURL = os.Getenv("FIGMA_URL")
+	}
+	if accessToken == "" {
+		accessToken = os.Getenv("FIGMA_TOKEN")
 	}
 }
 
+// addExtractFlags registers the extraction flags shared by the root, watch, lint and diff
+// commands, but --url, which only the root command repeats.
+func addExtractFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&accessToken, "token", "t", "", "Figma Personal Access Token (required; default: $FIGMA_TOKEN)")
+	cmd.Flags().BoolVar(&oauthToken, "oauth", false, "Treat --token as an OAuth access token (sent as a bearer token)")
+	cmd.Flags().StringVar(&cacheDir, "cache-dir", "", "Directory caching file data, image URLs and downloaded assets per file version between runs (default: no cache)")
+	cmd.Flags().StringVar(&captureDir, "capture-dir", "", "Write every raw Figma API response to this directory, with tokens redacted, e.g. to attach to bug reports")
+	cmd.Flags().IntVar(&rateLimit, "rate-limit", 0, "Maximum Figma API requests per minute; further requests wait (default: unlimited)")
+	cmd.Flags().StringVar(&proxy, "proxy", "", "HTTP(S) or SOCKS5 proxy URL for Figma requests and image downloads (default: HTTPS_PROXY/HTTP_PROXY)")
+	cmd.Flags().StringVarP(&outputFile, "output", "o", "FIGMA_DESIGN_SPECIFICATIONS.md", "Output file (or directory for formats that produce several files)")
+	cmd.Flags().StringVarP(&outputFormat, "format", "f", "markdown", "Output format, or a comma-separated list of formats to render in one run: "+strings.Join(formatter.Formats(), ", "))
+	cmd.Flags().StringVar(&sections, "sections", "", "Comma-separated sections of the markdown report to include, or to leave out with a leading \"-\" (e.g. -tree,-assets): "+strings.Join(formatter.MarkdownSections(), ", ")+" (default: all)")
+	cmd.Flags().BoolVar(&frontMatter, "front-matter", false, "Start the markdown report with YAML front matter (file name, version, extraction date) for static-site generators")
+	cmd.Flags().BoolVar(&tableOfContents, "toc", false, "Add a table of contents with stable heading anchors to the markdown report")
+	cmd.Flags().BoolVar(&perFrame, "per-frame", false, "Add a section per --node-ids frame with its own screenshot, tokens and node tree to the markdown report")
+	cmd.Flags().BoolVar(&splitNodes, "split-nodes", false, "Write a markdown report and json file per --node-ids node, named after it, plus an index, instead of a single merged one")
+	cmd.Flags().StringVar(&naming, "naming", "kebab", "Naming of the css and scss tokens: "+strings.Join(formatter.NamingStrategies(), ", ")+" (e.g. camel for --colorPrimary500, tailwind for --space-4)")
+	addUnitFlags(cmd)
+	cmd.Flags().StringVar(&templateFile, "template", "", "Go text/template file to render the design specifications with (implies --format template)")
+	cmd.Flags().StringVarP(&nodeIDs, "node-ids", "n", "", "Comma-separated node IDs to extract (optional, extracts specific nodes instead of entire file)")
+	cmd.Flags().StringVar(&fileVersion, "file-version", "", "File version to extract: a version ID or the label of a saved version (default: current; see the versions command)")
+	cmd.Flags().BoolVarP(&inheritFileContext, "inherit-context", "i", false, "Inherit file-level context (colors, styles) when extracting specific nodes")
+	addNodeFilterFlags(cmd)
+	cmd.Flags().BoolVar(&exportImages, "export-images", false, "Export images/assets from Figma")
+	cmd.Flags().StringVar(&imageFormat, "image-format", "png", "Image format: png, svg, jpg, pdf")
+	cmd.Flags().StringVar(&imageScales, "image-scales", "1", "Comma-separated scale factors (e.g. \"1,2,3\")")
+	cmd.Flags().StringVar(&imageDir, "image-dir", "figma-assets", "Output directory for exported images")
+	cmd.Flags().StringVar(&imageStore, "image-store", "", "Store exported images in an S3-compatible bucket instead of --image-dir, e.g. s3://bucket/prefix (credentials from the AWS_* environment variables)")
+	cmd.Flags().StringVar(&imageNodes, "image-nodes", "", "Comma-separated name patterns of the nodes to export, e.g. \"Icons/*\", with or without export settings (default: nodes with export settings and embedded images)")
+	cmd.Flags().BoolVar(&noScreenshot, "no-screenshot", false, "Export no screenshot of the design, only the images of its nodes")
+	cmd.Flags().BoolVar(&exportSettings, "use-export-settings", false, "Export nodes in the formats, scales and suffixes set in their Figma export settings instead of --image-format and --image-scales")
+	cmd.Flags().BoolVar(&imageHierarchy, "image-hierarchy", false, "Place exported images in subdirectories mirroring their Figma page and top-level frame")
+	cmd.Flags().BoolVar(&assetCatalog, "xcassets", false, "Place exported images in an Xcode asset catalog, Images.xcassets, with an image set per node")
+	cmd.Flags().BoolVar(&androidResources, "android-res", false, "Place exported images in Android res/drawable-<density> directories and convert SVGs to vector drawables")
+	cmd.Flags().IntVar(&downloadWorkers, "download-concurrency", 5, "Number of images downloaded at the same time")
+	cmd.Flags().IntVar(&downloadRate, "download-rate", 0, "Maximum combined image download speed in KB per second (default: unlimited)")
+	cmd.Flags().IntVar(&downloadRetries, "download-retries", 3, "Retries of an image download that failed with a transient error (-1 = none)")
+	cmd.Flags().BoolVar(&incremental, "incremental", false, "Skip exporting images whose nodes did not change since the last export (tracked in a manifest in the image directory)")
+	cmd.Flags().BoolVar(&optimizeImages, "optimize-images", false, "Losslessly recompress exported PNGs and strip PNG/JPEG metadata")
+	cmd.Flags().BoolVar(&optimizeSVG, "svg-optimize", false, "Minify exported SVGs: strip comments and editor metadata, collapse groups, round coordinates")
+	cmd.Flags().BoolVar(&svgSprite, "svg-sprite", false, "Combine exported SVG icons into sprite.svg, a symbol per icon, with a sprite.html usage snippet")
+	cmd.Flags().BoolVar(&svgIncludeID, "svg-include-id", false, "Add layer names as id attributes to exported SVG elements")
+	cmd.Flags().BoolVar(&svgSimplifyStroke, "svg-simplify-stroke", true, "Simplify inside and outside strokes in exported SVGs where possible")
+	cmd.Flags().BoolVar(&absoluteBounds, "use-absolute-bounds", false, "Render the full dimensions of nodes, including cropped content")
+	cmd.Flags().BoolVar(&contentsOnly, "contents-only", true, "Render nodes alone, without content overlapping them")
+	cmd.Flags().BoolVar(&componentTree, "component-tree", false, "Include hierarchical component tree in output")
+	cmd.Flags().StringVar(&pluginData, "plugin-data", "", "Comma-separated plugin IDs whose node data to include in the component tree; \"shared\" for shared plugin data (e.g. Tokens Studio)")
+	cmd.Flags().BoolVar(&vectorPaths, "vector-paths", false, "Fetch vector paths and include icons as inline SVG, without rendering them")
+	cmd.Flags().BoolVar(&variables, "variables", false, "Extract Figma variables as per-mode token sets (Enterprise plan only)")
+	cmd.Flags().BoolVar(&comments, "comments", false, "Include unresolved design comments in the report, next to the nodes they refer to")
+	cmd.Flags().BoolVar(&componentUsage, "component-usage", false, "Add library analytics usage counts to the component catalog (Enterprise plan only)")
+	cmd.Flags().BoolVar(&devResources, "dev-resources", false, "Add the dev resources linked to components (Storybook, GitHub, docs) to the component catalog")
+	cmd.Flags().BoolVar(&libraryStyles, "library-styles", false, "Name tokens of styles from shared team libraries after their published library names")
+	cmd.Flags().StringVar(&rulesFile, "rules", "", "YAML or JSON file mapping color name patterns onto palette categories (e.g. \"Brand/*\": color.primary) instead of the keyword heuristics")
+	cmd.Flags().StringVar(&teamID, "team-id", "", "Team whose published library styles are fetched at once with --library-styles (default: look styles up one by one)")
+}
+
 func run(cmd *cobra.Command, args []string) {
 	green := color.New(color.FgGreen)
-	red := color.New(color.FgRed)
+
+	printBanner()
+
+	opts, templateOutput, err := cliOptions(cmd)
+	if err != nil {
+		fatal(err)
+	}
+	if batchFile != "" || len(figmaURLs) > 1 {
+		runFiles(cmd, opts, templateOutput)
+		return
+	}
+
+	var result *figmaextractor.Result
+	if inputJSON != "" {
+		result, err = runOffline(opts)
+	} else if figmaURL == "" || accessToken == "" {
+		err = fmt.Errorf("%w (or use --input-json)", errNoFile)
+	} else {
+		// Cancel in-flight requests on Ctrl+C instead of waiting for them to finish.
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		result, err = figmaextractor.Run(ctx, opts)
+	}
+	if err != nil {
+		fatal(err)
+	}
+
+	printSummary(result.Specs)
+
+	// Write the rendered output.
+	outputPath, err := writeOutput(result.Outputs, templateOutput, cmd.Flags().Changed("output"))
+	if err != nil {
+		fatal(err)
+	}
+
+	code := warningsExitCode(result.Warnings)
+	switch {
+	case jsonLog != nil:
+		logResult("output", outputPath, "version", result.Version, "warnings", len(result.Warnings), "exit_code", code)
+	case chatty():
+		green.Printf("\n✨ Successfully extracted design specifications to %s\n\n", outputPath)
+		if n := len(result.Warnings); n > 0 {
+			color.New(color.FgYellow).Printf("⚠ %d warning(s), see above\n\n", n)
+		}
+	}
+	os.Exit(code)
+}
+
+// runOffline runs the extraction on the saved file JSON of --input-json.
+func runOffline(opts figmaextractor.Options) (*figmaextractor.Result, error) {
+	f, err := os.Open(inputJSON)
+	if err != nil {
+		return nil, fmt.Errorf("open input JSON: %w", err)
+	}
+	defer f.Close()
+
+	return figmaextractor.RunFromFileJSON(f, opts)
+}
+
+// watch runs the extraction and rewrites the outputs every time the Figma file changes,
+// until interrupted.
+func watch(cmd *cobra.Command, args []string) {
+	green := color.New(color.FgGreen)
+
+	printBanner()
+
+	opts, templateOutput, err := cliOptions(cmd)
+	if err != nil {
+		fatal(err)
+	}
+	if figmaURL == "" || accessToken == "" {
+		fatal(errNoFile)
+	}
+	opts.PollInterval = pollInterval
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	err = figmaextractor.Watch(ctx, opts, func(result *figmaextractor.Result, err error) error {
+		// Keep watching after a failed run: the next change may fix it.
+		if err != nil {
+			printError(err)
+			return nil
+		}
+
+		printSummary(result.Specs)
+		outputPath, err := writeOutput(result.Outputs, templateOutput, cmd.Flags().Changed("output"))
+		if err != nil {
+			return err
+		}
+		if jsonLog != nil {
+			logResult("output", outputPath, "version", result.Version, "warnings", len(result.Warnings))
+			return nil
+		}
+		if !chatty() {
+			return nil
+		}
+		green.Printf("\n✨ Extracted version %s to %s at %s\n\n", result.Version, outputPath, time.Now().Format(time.TimeOnly))
+		return nil
+	})
+	if err != nil && !errors.Is(err, context.Canceled) {
+		fatal(err)
+	}
+}
+
+// listVersions prints the recent versions of the file, newest first.
+func listVersions(cmd *cobra.Command, args []string) {
 	cyan := color.New(color.FgCyan)
 
+	if figmaURL == "" || accessToken == "" {
+		fatal(errNoFile)
+	}
+	fileKey, err := figma.ExtractFileKey(figmaURL)
+	if err != nil {
+		fatal(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	client := figma.NewClient(accessToken)
+	if oauthToken {
+		client = figma.NewOAuthClient(accessToken, nil)
+	}
+	versionsResp, err := client.GetVersions(ctx, fileKey)
+	if err != nil {
+		fatal(err)
+	}
+
+	for _, v := range versionsResp.Versions {
+		if jsonLog != nil {
+			jsonLog.Info("version", "id", v.ID, "created_at", v.CreatedAt, "label", v.Label, "user", v.User.Handle)
+			continue
+		}
+		label := v.Label
+		if label == "" {
+			label = "(autosave)"
+		}
+		cyan.Printf("%-20s", v.ID)
+		fmt.Printf(" %s  %s", v.CreatedAt, label)
+		if v.User.Handle != "" {
+			fmt.Printf("  by %s", v.User.Handle)
+		}
+		fmt.Println()
+	}
+}
+
+// addNodeFilterFlags adds the flags leaving nodes out of the extraction to cmd.
+func addNodeFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&includeNodes, "include-nodes", "", "Comma-separated name patterns of the only nodes to extract and export, with their descendants, e.g. \"Tokens/*\" (default: all)")
+	cmd.Flags().StringVar(&excludeNodes, "exclude-nodes", "", "Comma-separated name patterns of noise layers to skip with their descendants, e.g. \"scratch,Archive/*\"")
+	cmd.Flags().StringVar(&nodeTypes, "node-types", "", "Comma-separated node types to extract and export, e.g. \"FRAME,COMPONENT,TEXT\"; nodes of other types give way to their children (default: all)")
+}
+
+// addUnitFlags adds the flags choosing the unit of the typography, spacing and radius tokens to
+// cmd.
+func addUnitFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&unit, "unit", "px", "Unit of the typography, spacing and radius tokens of the css, scss, typescript, tailwind and markdown formats: px or rem")
+	cmd.Flags().Float64Var(&baseFontSize, "base-font-size", 16, "Root font size in px that --unit rem values are relative to")
+}
+
+// splitList returns the non-empty, trimmed items of a comma-separated flag value.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// printBanner prints the tool's title.
+func printBanner() {
+	if !chatty() {
+		return
+	}
+	cyan := color.New(color.FgCyan)
 	cyan.Println("\n🎨 Figma Design Extractor")
 	cyan.Println("==========================")
 	cyan.Println()
+}
 
+// cliOptions builds the extraction options from the command-line flags. It also returns the
+// name to write template output to, empty when no template file was given.
+func cliOptions(cmd *cobra.Command) (figmaextractor.Options, string, error) {
 	// Parse scales from CLI string.
 	scales, err := figmaextractor.ParseScales(imageScales)
 	if err != nil {
-		red.Printf("Error: %v\n", err)
-		os.Exit(1)
+		return figmaextractor.Options{}, "", err
 	}
 
 	// Parse node IDs from CLI string.
@@ -86,43 +428,170 @@ func run(cmd *cobra.Command, args []string) {
 		parsedNodeIDs = figmaextractor.ParseNodeIDs(nodeIDs)
 	}
 
+	parsedPluginData := splitList(pluginData)
+	parsedImageNodes := splitList(imageNodes)
+	parsedSections := splitList(sections)
+
+	tokenNaming, err := formatter.Naming(naming)
+	if err != nil {
+		return figmaextractor.Options{}, "", err
+	}
+
+	// A template file selects the template format unless other formats were asked for.
+	// Its output is named after the template without its extension (tokens.css.tmpl -> tokens.css).
+	var outputTemplate, templateOutput string
+	if templateFile != "" {
+		data, err := os.ReadFile(templateFile)
+		if err != nil {
+			return figmaextractor.Options{}, "", fmt.Errorf("read template: %w", err)
+		}
+		outputTemplate = string(data)
+		templateOutput = strings.TrimSuffix(filepath.Base(templateFile), filepath.Ext(templateFile))
+
+		if !cmd.Flags().Changed("format") {
+			outputFormat = "template"
+		}
+	}
+	formats := strings.Split(outputFormat, ",")
+
+	var store imager.AssetStore
+	if imageStore != "" {
+		if store, err = imager.OpenStore(imageStore); err != nil {
+			return figmaextractor.Options{}, "", err
+		}
+	}
+
+	// Render options default to Figma's own defaults; only flags set explicitly are sent.
+	render := figma.RenderOptions{
+		SVGIncludeID:      svgIncludeID,
+		UseAbsoluteBounds: absoluteBounds,
+	}
+	if cmd.Flags().Changed("svg-simplify-stroke") {
+		render.SVGSimplifyStroke = &svgSimplifyStroke
+	}
+	if cmd.Flags().Changed("contents-only") {
+		render.ContentsOnly = &contentsOnly
+	}
+
 	opts := figmaextractor.Options{
 		AccessToken:        accessToken,
+		OAuth:              oauthToken,
+		Proxy:              proxy,
+		RateLimit:          rateLimit,
+		CacheDir:           cacheDir,
+		CaptureDir:         captureDir,
 		FileURL:            figmaURL,
 		NodeIDs:            parsedNodeIDs,
+		Version:            fileVersion,
 		InheritFileContext: inheritFileContext,
+		IncludeNodes:       splitList(includeNodes),
+		ExcludeNodes:       splitList(excludeNodes),
+		NodeTypes:          splitList(nodeTypes),
 		ExportImages:       exportImages,
 		ImageFormat:        imageFormat,
 		ImageScales:        scales,
 		ImageDir:           imageDir,
+		ImageStore:         store,
+		UseExportSettings:  exportSettings,
+		ImageNodes:         parsedImageNodes,
+		NoScreenshot:       noScreenshot,
+		ImageHierarchy:     imageHierarchy,
+		AssetCatalog:       assetCatalog,
+		AndroidResources:   androidResources,
+		Incremental:        incremental,
+		DownloadWorkers:    downloadWorkers,
+		DownloadRate:       downloadRate,
+		DownloadRetries:    downloadRetries,
+		OptimizeImages:     optimizeImages,
+		OptimizeSVG:        optimizeSVG,
+		SVGSprite:          svgSprite,
+		ImageRender:        render,
 		ComponentTree:      componentTree,
-		Logger:             &cliLogger{},
+		VectorPaths:        vectorPaths,
+		PluginData:         parsedPluginData,
+		Variables:          variables,
+		Comments:           comments,
+		ComponentUsage:     componentUsage,
+		DevResources:       devResources,
+		LibraryStyles:      libraryStyles,
+		TeamID:             teamID,
+		RulesFile:          rulesFile,
+		Formats:            formats,
+		OutputTemplate:     outputTemplate,
+		Sections:           parsedSections,
+		FrontMatter:        frontMatter,
+		TableOfContents:    tableOfContents,
+		PerFrame:           perFrame,
+		SplitNodes:         splitNodes,
+		Logger:             newLogger(),
+		Naming:             tokenNaming,
+		Unit:               unit,
+		BaseFontSize:       baseFontSize,
 	}
+	opts.OnProgress = logEvent
+	opts.TraceHTTP = traceRequest
+	return opts, templateOutput, nil
+}
 
-	result, err := figmaextractor.Run(opts)
-	if err != nil {
-		red.Printf("Error: %v\n", err)
-		os.Exit(1)
+// printSummary prints the number of extracted values per category.
+func printSummary(specs *extractor.DesignSpecs) {
+	if jsonLog != nil {
+		logSummary(specs)
+		return
 	}
-
-	// Display extracted stats.
-	specs := result.Specs
+	if !chatty() {
+		return
+	}
+	cyan := color.New(color.FgCyan)
 	cyan.Println("\n📊 Extraction Summary:")
 	fmt.Printf("  • Colors: %d primary, %d background, %d text, %d status\n",
 		len(specs.Colors.Primary),
 		len(specs.Colors.Background),
 		len(specs.Colors.Text),
 		len(specs.Colors.Status))
+	if n := len(specs.Colors.Brand) + len(specs.Colors.Accent) + len(specs.Colors.Neutral); n > 0 {
+		fmt.Printf("  • Inferred Colors: %d brand, %d accent, %d neutral\n",
+			len(specs.Colors.Brand),
+			len(specs.Colors.Accent),
+			len(specs.Colors.Neutral))
+	}
 
 	if specs.Typography.FontFamily != "" {
 		fmt.Printf("  • Font Family: %s\n", specs.Typography.FontFamily)
 	}
 
 	fmt.Printf("  • Font Sizes: %d\n", len(specs.Typography.FontSizes))
+	if len(specs.TextStyles) > 0 {
+		fmt.Printf("  • Text Styles: %d\n", len(specs.TextStyles))
+	}
 	fmt.Printf("  • Spacing Values: %d\n", len(specs.Spacing.Values))
 	fmt.Printf("  • Border Radii: %d\n", len(specs.Radii.Values))
 	fmt.Printf("  • Shadows: %d\n", len(specs.Shadows))
 
+	if len(specs.Components) > 0 {
+		fmt.Printf("  • Components: %d\n", len(specs.Components))
+	}
+
+	if len(specs.Interactions) > 0 {
+		fmt.Printf("  • Prototype Interactions: %d\n", len(specs.Interactions))
+	}
+
+	if len(specs.Comments) > 0 {
+		fmt.Printf("  • Open Comments: %d\n", len(specs.Comments))
+	}
+
+	if len(specs.Duplicates) > 0 {
+		copies := 0
+		for _, g := range specs.Duplicates {
+			copies += len(g.Copies)
+		}
+		fmt.Printf("  • Duplicate Layers: %d groups, %d copies\n", len(specs.Duplicates), copies)
+	}
+
+	if len(specs.Variables) > 0 {
+		fmt.Printf("  • Variable Collections: %d\n", len(specs.Variables))
+	}
+
 	if specs.Layout.HeaderHeight > 0 {
 		fmt.Printf("  • Header Height: %.0fpx\n", specs.Layout.HeaderHeight)
 	}
@@ -132,29 +601,104 @@ func run(cmd *cobra.Command, args []string) {
 	if len(specs.ExportedAssets) > 0 {
 		fmt.Printf("  • Exported Assets: %d\n", len(specs.ExportedAssets))
 	}
+}
 
-	// Write markdown to file.
-	green.Printf("\n💾 Writing to %s... ", outputFile)
-	err = os.WriteFile(outputFile, []byte(result.Markdown), 0644)
-	if err != nil {
+// writeOutput writes the rendered files to disk and returns where they were written.
+// When a single format producing a single file was requested, the file is written to --output
+// when it was given explicitly (or for markdown, whose default --output is the traditional file
+// name) and to its format's default name otherwise. Otherwise every file is written into the
+// --output directory, or the current directory when --output was not given. Template output
+// is named templateOutput instead of the generic default name.
+func writeOutput(outputs []figmaextractor.Output, templateOutput string, outputChanged bool) (string, error) {
+	if len(outputs) == 1 && len(outputs[0].Files) == 1 {
+		out := outputs[0]
+		path := out.Files[0].Name
+		switch {
+		case outputChanged || out.Format == "markdown":
+			path = outputFile
+		case out.Format == "template" && templateOutput != "":
+			path = templateOutput
+		}
+		return path, writeFile(path, out.Files[0].Content)
+	}
+
+	dir := "."
+	if outputChanged {
+		dir = outputFile
+	}
+	return dir, writeOutputsTo(dir, outputs, templateOutput)
+}
+
+// writeOutputsTo writes every rendered file into dir under its default name, or templateOutput
+// for template output.
+func writeOutputsTo(dir string, outputs []figmaextractor.Output, templateOutput string) error {
+	for _, out := range outputs {
+		for _, f := range out.Files {
+			name := f.Name
+			if out.Format == "template" && templateOutput != "" && len(out.Files) == 1 {
+				name = templateOutput
+			}
+			if err := writeFile(filepath.Join(dir, name), f.Content); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeFile writes a single output file, creating its parent directories.
+func writeFile(path string, content []byte) error {
+	write := func() error {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		return os.WriteFile(path, content, 0644)
+	}
+	if jsonLog != nil {
+		if err := write(); err != nil {
+			return err
+		}
+		jsonLog.Info("wrote file", "path", path, "bytes", len(content))
+		return nil
+	}
+	if !chatty() {
+		return write()
+	}
+
+	green := color.New(color.FgGreen)
+	red := color.New(color.FgRed)
+
+	green.Printf("\n💾 Writing to %s... ", path)
+	if err := write(); err != nil {
 		red.Printf("✗\n")
-		red.Printf("Error: %v\n", err)
-		os.Exit(1)
+		return err
 	}
 	green.Println("✓")
+	return nil
+}
 
-	green.Printf("\n✨ Successfully extracted design specifications to %s\n\n", outputFile)
+// cliLogger implements figmaextractor.DebugLogger with colored terminal output, printing the
+// messages of level and above.
+type cliLogger struct {
+	level slog.Level
 }
 
-// cliLogger implements figmaextractor.Logger with colored terminal output.
-type cliLogger struct{}
+func (l *cliLogger) Debugf(format string, args ...any) {
+	if l.level <= slog.LevelDebug {
+		color.New(color.Faint).Printf(format+"\n", args...)
+	}
+}
 
 func (l *cliLogger) Infof(format string, args ...any) {
-	color.New(color.FgYellow).Printf(format+"\n", args...)
+	if l.level <= slog.LevelInfo {
+		color.New(color.FgYellow).Printf(format+"\n", args...)
+	}
 }
 
 func (l *cliLogger) Warnf(format string, args ...any) {
-	color.New(color.FgYellow).Printf("⚠ "+format+"\n", args...)
+	if l.level <= slog.LevelWarn {
+		color.New(color.FgYellow).Printf("⚠ "+format+"\n", args...)
+	}
 }
 
 func (l *cliLogger) Errorf(format string, args ...any) {
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+
+	figmaextractor "github.com/hellenic-development/figma-extractor"
+	"github.com/hellenic-development/figma-extractor/pkg/extractor"
+
+	"github.com/fatih/color"
+)
+
+var (
+	logFormat    string
+	quietOutput  bool
+	verboseLogs  bool
+	debugLogs    bool
+	logLevel     = slog.LevelInfo
+	traceRequest bool
+)
+
+// jsonLog writes the progress and results of the commands as JSON lines to the standard
+// output with --log-format json, for CI systems and wrappers; nil for colored text.
+var jsonLog *slog.Logger
+
+// setupLogging applies --log-format and the verbosity flags: --quiet prints errors only,
+// --verbose adds the fetched nodes and downloaded images, --debug also traces the HTTP requests.
+func setupLogging() error {
+	switch {
+	case quietOutput && (verboseLogs || debugLogs):
+		return usageErrorf("--quiet cannot be combined with --verbose or --debug")
+	case quietOutput:
+		logLevel = slog.LevelError
+	case verboseLogs || debugLogs:
+		logLevel = slog.LevelDebug
+	}
+	traceRequest = debugLogs
+
+	switch logFormat {
+	case "text":
+	case "json":
+		jsonLog = slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: logLevel}))
+		color.NoColor = true
+	default:
+		return usageErrorf("unknown log format %q: want text or json", logFormat)
+	}
+	return nil
+}
+
+// chatty reports whether the colored text output includes progress and results, that is
+// without --quiet and --log-format json.
+func chatty() bool {
+	return jsonLog == nil && logLevel <= slog.LevelInfo
+}
+
+// newLogger returns the Logger of the runs: colored text, or JSON lines with --log-format json.
+func newLogger() figmaextractor.Logger {
+	if jsonLog != nil {
+		return figmaextractor.SlogLogger(jsonLog)
+	}
+	return &cliLogger{level: logLevel}
+}
+
+// logEvent logs the progress of a run: its phases with --log-format json, the fetched nodes
+// and downloaded images with --verbose.
+func logEvent(e figmaextractor.Event) {
+	if jsonLog != nil {
+		switch e.Kind {
+		case figmaextractor.PhaseStarted:
+			jsonLog.Info("phase started", "phase", e.Phase)
+		case figmaextractor.PhaseCompleted:
+			jsonLog.Info("phase completed", "phase", e.Phase, "count", e.Count)
+		case figmaextractor.NodeFetched:
+			jsonLog.Debug("node fetched", "node_id", e.NodeID, "node_name", e.NodeName)
+		case figmaextractor.AssetDownloaded:
+			jsonLog.Debug("asset downloaded", "node_id", e.NodeID, "node_name", e.NodeName, "file", e.FileName, "completed", e.Completed, "total", e.Total)
+		}
+		return
+	}
+	if logLevel > slog.LevelDebug {
+		return
+	}
+	dim := color.New(color.Faint)
+	switch e.Kind {
+	case figmaextractor.NodeFetched:
+		dim.Printf("  · fetched %s (%s)\n", e.NodeName, e.NodeID)
+	case figmaextractor.AssetDownloaded:
+		dim.Printf("  · downloaded %s [%d/%d]\n", e.FileName, e.Completed, e.Total)
+	}
+}
+
+// printError prints the error a command failed with.
+func printError(err error) {
+	if jsonLog != nil {
+		jsonLog.Error("failed", "error", err.Error())
+		return
+	}
+	color.New(color.FgRed).Printf("Error: %v\n", err)
+}
+
+// logResult logs the outcome of a command, the last record of a successful run, with
+// --log-format json.
+func logResult(args ...any) {
+	jsonLog.Info("result", args...)
+}
+
+// logSummary logs the number of extracted values per category, see printSummary, with the
+// fields of args.
+func logSummary(specs *extractor.DesignSpecs, args ...any) {
+	copies := 0
+	for _, g := range specs.Duplicates {
+		copies += len(g.Copies)
+	}
+	jsonLog.Info("summary", append(args,
+		slog.Group("colors",
+			"primary", len(specs.Colors.Primary),
+			"background", len(specs.Colors.Background),
+			"text", len(specs.Colors.Text),
+			"status", len(specs.Colors.Status),
+			"brand", len(specs.Colors.Brand),
+			"accent", len(specs.Colors.Accent),
+			"neutral", len(specs.Colors.Neutral)),
+		"font_family", specs.Typography.FontFamily,
+		"font_sizes", len(specs.Typography.FontSizes),
+		"text_styles", len(specs.TextStyles),
+		"spacing_values", len(specs.Spacing.Values),
+		"border_radii", len(specs.Radii.Values),
+		"shadows", len(specs.Shadows),
+		"components", len(specs.Components),
+		"interactions", len(specs.Interactions),
+		"comments", len(specs.Comments),
+		"duplicate_groups", len(specs.Duplicates),
+		"duplicate_copies", copies,
+		"variable_collections", len(specs.Variables),
+		"header_height", specs.Layout.HeaderHeight,
+		"sidebar_width", specs.Layout.SidebarWidth,
+		"exported_assets", len(specs.ExportedAssets))...)
+}
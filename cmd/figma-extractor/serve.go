@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	figmaextractor "github.com/hellenic-development/figma-extractor"
+	"github.com/hellenic-development/figma-extractor/pkg/formatter"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	serveAddr             string
+	serveAllowServerToken bool
+)
+
+// newServeCommand returns the serve command, which runs the extraction as an HTTP service.
+func newServeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Run an HTTP and gRPC service extracting Figma files",
+		Long: "Serves POST /extract, whose JSON body names the Figma file (url), optionally nodes (nodeIds), " +
+			"the output format (format) and the file version (version), and responds with the rendered output. " +
+			"Requests send their own access token in the X-Figma-Token header; --allow-server-token lets requests without one use --token. " +
+			"The same address serves the figmaextractor.v1.Extractor gRPC service of proto/figmaextractor/v1/extractor.proto " +
+			"over unencrypted HTTP/2 (h2c): ExtractSpecs, ExportImages and Diff, streaming the progress of the runs.",
+		Run: serve,
+	}
+	cmd.Flags().StringVar(&serveAddr, "addr", "127.0.0.1:8080", "Address to listen on, e.g. :8080 to accept connections on every interface")
+	cmd.Flags().StringVarP(&accessToken, "token", "t", "", "Figma Personal Access Token for requests without an X-Figma-Token header or x-figma-token metadata, with --allow-server-token (default: $FIGMA_TOKEN)")
+	cmd.Flags().BoolVar(&serveAllowServerToken, "allow-server-token", false, "Extract requests without a token of their own with --token, letting every client read the files of that token (default: such requests get 401 or UNAUTHENTICATED)")
+	cmd.Flags().BoolVar(&oauthToken, "oauth", false, "Treat the access tokens as OAuth access tokens (sent as bearer tokens)")
+	cmd.Flags().StringVar(&proxy, "proxy", "", "HTTP(S) or SOCKS5 proxy URL for Figma requests (default: HTTPS_PROXY/HTTP_PROXY)")
+	cmd.Flags().IntVar(&rateLimit, "rate-limit", 0, "Maximum Figma API requests per minute, shared by all requests (default: unlimited)")
+	cmd.Flags().StringVar(&cacheDir, "cache-dir", "", "Directory caching file data per file version between requests (default: no cache)")
+	cmd.Flags().BoolVarP(&inheritFileContext, "inherit-context", "i", false, "Inherit file-level context (colors, styles) when extracting specific nodes")
+	cmd.Flags().BoolVar(&variables, "variables", false, "Extract Figma variables as per-mode token sets (Enterprise plan only)")
+	cmd.Flags().BoolVar(&libraryStyles, "library-styles", false, "Name tokens of styles from shared team libraries after their published library names")
+	cmd.Flags().StringVar(&teamID, "team-id", "", "Team whose published library styles are fetched at once with --library-styles")
+	cmd.Flags().StringVar(&rulesFile, "rules", "", "YAML or JSON file mapping color name patterns onto palette categories")
+	cmd.Flags().BoolVar(&componentTree, "component-tree", false, "Include hierarchical component tree in output")
+	cmd.Flags().StringVar(&sections, "sections", "", "Comma-separated sections of the markdown report to include, or to leave out with a leading \"-\" (default: all)")
+	cmd.Flags().BoolVar(&frontMatter, "front-matter", false, "Start the markdown report with YAML front matter")
+	cmd.Flags().BoolVar(&tableOfContents, "toc", false, "Add a table of contents to the markdown report")
+	cmd.Flags().StringVar(&naming, "naming", "kebab", "Naming of the css and scss tokens: "+strings.Join(formatter.NamingStrategies(), ", "))
+	return cmd
+}
+
+// serve runs the extraction service until interrupted.
+func serve(cmd *cobra.Command, args []string) {
+	green := color.New(color.FgGreen)
+
+	opts, _, err := cliOptions(cmd)
+	if err != nil {
+		fatal(err)
+	}
+	if serveAllowServerToken && opts.AccessToken == "" {
+		fatal(errors.New("--allow-server-token needs --token or $FIGMA_TOKEN"))
+	}
+	// Concurrent requests log with the file and phase of their run.
+	logger := slog.Default()
+	if jsonLog != nil {
+		logger = jsonLog
+	}
+	opts.Logger = figmaextractor.SlogLogger(logger)
+	opts.OnProgress = nil
+
+	mux := http.NewServeMux()
+	extract := figmaextractor.NewExtractHandler(opts)
+	extract.AllowServerToken = serveAllowServerToken
+	mux.Handle("/extract", extract)
+	grpcHandler := figmaextractor.NewGRPCHandler(opts)
+	grpcHandler.AllowServerToken = serveAllowServerToken
+	mux.Handle(figmaextractor.GRPCPrefix, grpcHandler)
+	// gRPC clients speak HTTP/2, without TLS unless a proxy terminates it.
+	var protocols http.Protocols
+	protocols.SetHTTP1(true)
+	protocols.SetUnencryptedHTTP2(true)
+	server := &http.Server{Addr: serveAddr, Handler: mux, Protocols: &protocols, ReadHeaderTimeout: 10 * time.Second}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	if jsonLog != nil {
+		jsonLog.Info("serving", "addr", serveAddr, "path", "/extract", "grpc_service", strings.Trim(figmaextractor.GRPCPrefix, "/"))
+	} else {
+		green.Printf("Serving POST /extract and the %s gRPC service on %s\n", strings.Trim(figmaextractor.GRPCPrefix, "/"), serveAddr)
+	}
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fatal(err)
+	}
+}
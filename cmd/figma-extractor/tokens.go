@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	figmaextractor "github.com/hellenic-development/figma-extractor"
+	"github.com/hellenic-development/figma-extractor/pkg/formatter"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// tokenFormats maps the formats of the tokens command onto output formats.
+var tokenFormats = map[string]string{
+	"css":      "css",
+	"json":     "dtcg",
+	"tailwind": "tailwind",
+}
+
+var (
+	tokensFormat string
+	tokensOutput string
+)
+
+// newTokensCommand returns the tokens command, which writes the design tokens alone, without
+// the report and assets.
+func newTokensCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tokens",
+		Short: "Extract only the design tokens, as CSS, JSON or a Tailwind config",
+		Long: "Extracts the design tokens of a Figma file and writes them in a single format, " +
+			"without screenshots, images or the markdown report, for fast token syncs in CI.",
+		Run: extractTokens,
+	}
+	cmd.Flags().StringVarP(&figmaURL, "url", "u", "", "Figma file URL (required; default: $FIGMA_URL)")
+	cmd.Flags().StringVarP(&accessToken, "token", "t", "", "Figma Personal Access Token (required; default: $FIGMA_TOKEN)")
+	cmd.Flags().BoolVar(&oauthToken, "oauth", false, "Treat --token as an OAuth access token (sent as a bearer token)")
+	cmd.Flags().StringVar(&inputJSON, "input-json", "", "Extract offline from a saved Figma file JSON response instead of the API (--url and --token become optional)")
+	cmd.Flags().StringVarP(&tokensFormat, "format", "f", "css", "Token format: css (tokens.css), json (DTCG tokens.json) or tailwind (tailwind.config.js)")
+	cmd.Flags().StringVarP(&tokensOutput, "output", "o", "", "Output file (default: the format's file name)")
+	cmd.Flags().StringVar(&naming, "naming", "kebab", "Naming of the css tokens: "+strings.Join(formatter.NamingStrategies(), ", "))
+	addUnitFlags(cmd)
+	cmd.Flags().StringVarP(&nodeIDs, "node-ids", "n", "", "Comma-separated node IDs to extract the tokens of (default: the entire file)")
+	cmd.Flags().BoolVarP(&inheritFileContext, "inherit-context", "i", false, "Inherit file-level context (colors, styles) when extracting specific nodes")
+	addNodeFilterFlags(cmd)
+	cmd.Flags().StringVar(&fileVersion, "file-version", "", "File version to extract: a version ID or the label of a saved version (default: current)")
+	cmd.Flags().BoolVar(&variables, "variables", false, "Extract Figma variables as per-mode token sets (Enterprise plan only)")
+	cmd.Flags().BoolVar(&libraryStyles, "library-styles", false, "Name tokens of styles from shared team libraries after their published library names")
+	cmd.Flags().StringVar(&teamID, "team-id", "", "Team whose published library styles are fetched at once with --library-styles")
+	cmd.Flags().StringVar(&rulesFile, "rules", "", "YAML or JSON file mapping color name patterns onto palette categories")
+	cmd.Flags().StringVar(&cacheDir, "cache-dir", "", "Directory caching file data per file version between runs (default: no cache)")
+	cmd.Flags().StringVar(&proxy, "proxy", "", "HTTP(S) or SOCKS5 proxy URL for Figma requests (default: HTTPS_PROXY/HTTP_PROXY)")
+	cmd.Flags().IntVar(&rateLimit, "rate-limit", 0, "Maximum Figma API requests per minute; further requests wait (default: unlimited)")
+	return cmd
+}
+
+// extractTokens extracts the design and writes its tokens in the format of --format.
+func extractTokens(cmd *cobra.Command, args []string) {
+	green := color.New(color.FgGreen)
+
+	format, ok := tokenFormats[tokensFormat]
+	if !ok {
+		fatal(usageErrorf("unknown token format %q (must be css, json or tailwind)", tokensFormat))
+	}
+	tokenNaming, err := formatter.Naming(naming)
+	if err != nil {
+		fatal(err)
+	}
+	var parsedNodeIDs []string
+	if nodeIDs != "" {
+		parsedNodeIDs = figmaextractor.ParseNodeIDs(nodeIDs)
+	}
+
+	// No images, screenshots or optional API data beyond what tokens are made of.
+	opts := figmaextractor.Options{
+		AccessToken:        accessToken,
+		OAuth:              oauthToken,
+		Proxy:              proxy,
+		RateLimit:          rateLimit,
+		CacheDir:           cacheDir,
+		FileURL:            figmaURL,
+		NodeIDs:            parsedNodeIDs,
+		Version:            fileVersion,
+		InheritFileContext: inheritFileContext,
+		IncludeNodes:       splitList(includeNodes),
+		ExcludeNodes:       splitList(excludeNodes),
+		NodeTypes:          splitList(nodeTypes),
+		Variables:          variables,
+		LibraryStyles:      libraryStyles,
+		TeamID:             teamID,
+		RulesFile:          rulesFile,
+		Formats:            []string{format},
+		Logger:             newLogger(),
+		Naming:             tokenNaming,
+		Unit:               unit,
+		BaseFontSize:       baseFontSize,
+	}
+	opts.OnProgress = logEvent
+	opts.TraceHTTP = traceRequest
+
+	var result *figmaextractor.Result
+	if inputJSON != "" {
+		result, err = runOffline(opts)
+	} else if figmaURL == "" || accessToken == "" {
+		err = fmt.Errorf("%w (or use --input-json)", errNoFile)
+	} else {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		result, err = figmaextractor.Run(ctx, opts)
+	}
+	if err != nil {
+		fatal(err)
+	}
+
+	file := result.Outputs[0].Files[0]
+	path := tokensOutput
+	if path == "" {
+		path = file.Name
+	}
+	if err := writeFile(path, file.Content); err != nil {
+		fatal(err)
+	}
+
+	code := warningsExitCode(result.Warnings)
+	switch {
+	case jsonLog != nil:
+		logResult("output", path, "version", result.Version, "warnings", len(result.Warnings), "exit_code", code)
+	case chatty():
+		green.Printf("\n✨ Wrote the design tokens to %s\n", path)
+		if n := len(result.Warnings); n > 0 {
+			color.New(color.FgYellow).Printf("⚠ %d warning(s), see above\n", n)
+		}
+	}
+	os.Exit(code)
+}
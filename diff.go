@@ -0,0 +1,119 @@
+package figmaextractor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/hellenic-development/figma-extractor/pkg/extractor"
+	"github.com/hellenic-development/figma-extractor/pkg/formatter"
+)
+
+// ChangeKind is the kind of a Change.
+type ChangeKind string
+
+// Kinds of changes.
+const (
+	ChangeAdded    ChangeKind = "added"    // only the current design has it
+	ChangeRemoved  ChangeKind = "removed"  // only the baseline has it
+	ChangeModified ChangeKind = "modified" // both have it, with different values
+)
+
+// Change is a token or component that differs between a baseline and the current design.
+type Change struct {
+	Kind   ChangeKind
+	Name   string // token name as the css format names it, e.g. "color-primary-500", or component name
+	Before string // value in the baseline; empty for added tokens and components
+	After  string // value in the current design; empty for removed tokens and components
+}
+
+// DiffReport lists how a design changed since a baseline.
+type DiffReport struct {
+	Tokens     []Change // sorted by name
+	Components []Change // sorted by name
+}
+
+// Changed reports whether the design changed since the baseline.
+func (r *DiffReport) Changed() bool {
+	return len(r.Tokens) > 0 || len(r.Components) > 0
+}
+
+// ReadSpecs decodes design specifications saved by the json format, e.g. a design-specs.json
+// kept as the baseline of Diff.
+func ReadSpecs(r io.Reader) (*extractor.DesignSpecs, error) {
+	var specs extractor.DesignSpecs
+	if err := json.NewDecoder(r).Decode(&specs); err != nil {
+		return nil, fmt.Errorf("read design specs: %w", err)
+	}
+	return &specs, nil
+}
+
+// Diff compares the design with a baseline extraction and reports the tokens and components
+// that were added, removed or modified. Tokens are matched by the names the css format gives
+// them and compared by value; components are matched by name and compared by type, size,
+// variant count and properties.
+func Diff(base, current *extractor.DesignSpecs) *DiffReport {
+	return &DiffReport{
+		Tokens:     diffValues(cssTokens(formatter.ToCSS(base, "")), cssTokens(formatter.ToCSS(current, ""))),
+		Components: diffValues(componentSummaries(base.Components), componentSummaries(current.Components)),
+	}
+}
+
+// diffValues returns the changes from before to after, sorted by name.
+func diffValues(before, after map[string]string) []Change {
+	names := make(map[string]bool, len(before)+len(after))
+	for name := range before {
+		names[name] = true
+	}
+	for name := range after {
+		names[name] = true
+	}
+
+	var changes []Change
+	for _, name := range slices.Sorted(maps.Keys(names)) {
+		b, inBefore := before[name]
+		a, inAfter := after[name]
+		switch {
+		case !inBefore:
+			changes = append(changes, Change{Kind: ChangeAdded, Name: name, After: a})
+		case !inAfter:
+			changes = append(changes, Change{Kind: ChangeRemoved, Name: name, Before: b})
+		case normalizeTokenValue(a) != normalizeTokenValue(b):
+			changes = append(changes, Change{Kind: ChangeModified, Name: name, Before: b, After: a})
+		}
+	}
+	return changes
+}
+
+// componentSummaries describes components by name, e.g. "COMPONENT_SET 120x40, 4 variant(s),
+// properties: Size (VARIANT: Small|Large, default Small), Disabled (BOOLEAN, default false)".
+func componentSummaries(components []extractor.Component) map[string]string {
+	summaries := make(map[string]string, len(components))
+	for _, c := range components {
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "%s %gx%g", c.Type, c.Width, c.Height)
+		if c.VariantCount > 0 {
+			fmt.Fprintf(&sb, ", %d variant(s)", c.VariantCount)
+		}
+		for i, p := range c.Properties {
+			if i == 0 {
+				sb.WriteString(", properties: ")
+			} else {
+				sb.WriteString(", ")
+			}
+			fmt.Fprintf(&sb, "%s (%s", p.Name, p.Type)
+			if len(p.Options) > 0 {
+				fmt.Fprintf(&sb, ": %s", strings.Join(p.Options, "|"))
+			}
+			if p.Default != "" {
+				fmt.Fprintf(&sb, ", default %s", p.Default)
+			}
+			sb.WriteString(")")
+		}
+		summaries[c.Name] = sb.String()
+	}
+	return summaries
+}
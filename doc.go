@@ -15,7 +15,7 @@
 //
 // # Quick start
 //
-//	result, err := figmaextractor.Run(figmaextractor.Options{
+//	result, err := figmaextractor.Run(context.Background(), figmaextractor.Options{
 //	    AccessToken: os.Getenv("FIGMA_TOKEN"),
 //	    FileURL:     "https://www.figma.com/design/ABC123/My-Design",
 //	    ExportImages: true,
@@ -27,6 +27,34 @@
 //	}
 //	os.WriteFile("design.md", []byte(result.Markdown), 0644)
 //
+// Design systems split across files are extracted at once, concurrently, by
+// [RunFiles] with [Options.FileURLs], returning a [Result] per file. [RunBatch]
+// extracts the files of a batch file read by [ParseBatch], a few at a time,
+// each with its own nodes.
+//
+// # Cancellation
+//
+// [Run] and every [figma.Client] method accept a [context.Context]. Cancelling
+// it, or letting its deadline expire, aborts the in-flight Figma API request,
+// any pending retry wait and the image downloads of an export:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+//	defer cancel()
+//	result, err := figmaextractor.Run(ctx, opts)
+//
+// # Validation
+//
+// [Options.Validate] checks the options before any request is made and
+// reports every problem as a [ConfigError] naming the option, wrapping one of
+// the Err* values such as [ErrMissingToken] or [ErrNotWritable]:
+//
+//	if err := opts.Validate(); errors.Is(err, figmaextractor.ErrMissingToken) {
+//	    return errors.New("set FIGMA_TOKEN")
+//	}
+//
+// [Run] validates its options the same way and fails before the pipeline
+// starts.
+//
 // # Logging
 //
 // Pass a [Logger] implementation in [Options.Logger] to receive progress
@@ -37,6 +65,50 @@
 //	func (l *myLogger) Warnf(f string, a ...any)  { log.Printf("[WARN]  "+f, a...) }
 //	func (l *myLogger) Errorf(f string, a ...any) { log.Printf("[ERROR] "+f, a...) }
 //
+// Loggers that also implement [DebugLogger] receive debug messages, such as
+// the API requests and image downloads traced with [Options.TraceHTTP].
+//
+// # HTTP
+//
+// Requests go through a client tuned for large files that honors the
+// HTTPS_PROXY environment variable. [Options.Proxy], [Options.HTTPClient]
+// and [Options.RateLimit] adjust it; rate-limited responses are always
+// retried after the delay Figma asks for. Set [Options.OAuth] for OAuth
+// access tokens, with [Options.RefreshToken] renewing them when they expire.
+//
+// Long-lived processes that extract the same file repeatedly can keep an
+// [Options.ResponseCache] across runs, so unchanged files are revalidated by
+// their ETag instead of downloaded again:
+//
+//	cache := figma.NewMemoryCache()
+//	result, err := figmaextractor.Run(ctx, figmaextractor.Options{
+//	    AccessToken:   token,
+//	    FileURL:       url,
+//	    ResponseCache: cache,
+//	})
+//
+// [Options.CacheDir] persists the cache on disk instead, together with the
+// downloaded assets, keyed by file version.
+//
+// To offer extraction as a service, mount an [ExtractHandler]; it extracts
+// the file, nodes and format of each POSTed [ExtractRequest]:
+//
+//	http.Handle("/extract", figmaextractor.NewExtractHandler(opts))
+//
+// A [GRPCHandler] serves the same over gRPC, with streaming progress, for the
+// clients generated from proto/figmaextractor/v1/extractor.proto:
+//
+//	http.Handle(figmaextractor.GRPCPrefix, figmaextractor.NewGRPCHandler(opts))
+//
+// # Offline extraction
+//
+// [RunFromFileJSON] runs the same pipeline on a saved response of the Figma
+// file endpoint, without any API request, which keeps tests deterministic:
+//
+//	f, _ := os.Open("testdata/design.json")
+//	defer f.Close()
+//	result, err := figmaextractor.RunFromFileJSON(f, figmaextractor.Options{Format: "css"})
+//
 // # Node-scoped extraction
 //
 // To extract specific frames or components rather than the entire file,
@@ -44,10 +116,101 @@
 // Figma URL. Set [Options.InheritFileContext] to true to include
 // file-level colors and styles alongside the targeted nodes.
 //
+// [Options.ExcludeNodes] skips noise layers such as scratch areas and
+// archives, by name patterns, and [Options.IncludeNodes] and
+// [Options.NodeTypes] keep only the matching subtrees and node types; the
+// filtered nodes contribute no tokens, components or exported images.
+//
+// # Custom analyzers
+//
+// Extraction runs a pipeline of [extractor.Analyzer] plugins over every node:
+// the built-in ones collect colors, typography, effects, spacing and layout.
+// [Options.Analyzers] adds analyzers of your own, to pull domain-specific data
+// out of the nodes without forking; they store it in the Custom map of the
+// specs, which the json format renders and templates can read:
+//
+//	opts.Analyzers = []extractor.Analyzer{extractor.AnalyzerFunc(func(node *figma.Node, a *extractor.Analysis) {
+//	    if strings.HasPrefix(node.Name, "Ad/") {
+//	        slots, _ := a.Specs.Custom["adSlots"].([]string)
+//	        a.Set("adSlots", append(slots, node.Name))
+//	    }
+//	})}
+//
+// # Variables
+//
+// Set [Options.Variables] to read the file's Figma variables. Each variable
+// collection is resolved into one token set per mode (for example Light and
+// Dark) and rendered as a :root block plus a [data-theme] block per extra
+// mode. The Variables API is limited to Enterprise plans; when it is not
+// available a warning is logged and extraction continues without variables.
+//
+// # Output formats
+//
+// [Options.Format] selects the output format; see formatter.Formats for the
+// full list. The default "markdown" also fills [Result.Markdown]. Every
+// format is returned as one or more files in [Result.Files]:
+//
+//	result, err := figmaextractor.Run(ctx, figmaextractor.Options{
+//	    AccessToken: token,
+//	    FileURL:     url,
+//	    Format:      "dtcg", // W3C Design Tokens JSON
+//	})
+//	for _, f := range result.Files {
+//	    os.WriteFile(f.Name, f.Content, 0644)
+//	}
+//
+// Set [Options.Formats] instead to render several formats from a single
+// extraction, without fetching the file again for each one. [Result.Outputs]
+// holds the files of each format:
+//
+//	Formats: []string{"markdown", "css", "typescript"},
+//
+// Any other format can be produced with a Go text/template in
+// [Options.OutputTemplate], which selects the "template" format. The
+// template is executed with formatter.TemplateData and may use the helpers
+// listed by formatter.TemplateFuncs:
+//
+//	result, err := figmaextractor.Run(ctx, figmaextractor.Options{
+//	    AccessToken:    token,
+//	    FileURL:        url,
+//	    OutputTemplate: `{{range $name, $hex := .Colors.Primary}}{{kebab $name}}={{$hex}}{{"\n"}}{{end}}`,
+//	})
+//
+// Packages add formats of their own with formatter.Register, usually from an
+// init function; [Options.Formats] then accepts them by name.
+//
 // # Image export
 //
 // When [Options.ExportImages] is true the pipeline captures a full design
 // screenshot, exports nodes that have Figma ExportSettings, downloads
 // embedded IMAGE fills, and falls back to the render API for any
 // unresolved images. Duplicates are automatically removed.
+//
+// [Options.Progress] reports the images completed and the bytes downloaded
+// across all of these steps, e.g. to draw a progress bar:
+//
+//	opts.Progress = func(p imager.Progress) {
+//	    fmt.Printf("\r%d/%d images, %d KB", p.Completed, p.Total, p.Bytes/1024)
+//	}
+//
+// [Options.OnProgress] receives typed events of the whole run instead: the
+// start and completion of every phase (with the count of what it produced),
+// every fetched node and every downloaded image:
+//
+//	opts.OnProgress = func(e figmaextractor.Event) {
+//	    if e.Kind == figmaextractor.PhaseCompleted {
+//	        fmt.Printf("%s: %d\n", e.Phase, e.Count)
+//	    }
+//	}
+//
+// Images are written to [Options.ImageDir] unless [Options.ImageStore] stores
+// them elsewhere, e.g. straight to an S3-compatible bucket behind a CDN:
+//
+//	opts.ImageStore = &imager.S3Store{
+//	    Bucket:          "design-assets",
+//	    Prefix:          "figma",
+//	    Region:          "eu-central-1",
+//	    AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+//	    SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+//	}
 package figmaextractor
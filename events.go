@@ -0,0 +1,75 @@
+package figmaextractor
+
+import "github.com/hellenic-development/figma-extractor/pkg/imager"
+
+// EventKind is the kind of an Event.
+type EventKind string
+
+// Kinds of events.
+const (
+	PhaseStarted    EventKind = "phase-started"    // a phase of the run started
+	PhaseCompleted  EventKind = "phase-completed"  // a phase of the run completed, with the count of what it produced
+	NodeFetched     EventKind = "node-fetched"     // a requested node, or the document of the file, was fetched
+	AssetDownloaded EventKind = "asset-downloaded" // an exported image was downloaded
+)
+
+// Event reports the progress of a run to Options.OnProgress.
+type Event struct {
+	Kind  EventKind
+	Phase string // auth, fetch, library-styles, extract, variables, component-usage, dev-resources, comments, images or render
+
+	// Count is what a completed phase produced: nodes fetched, variable collections, components
+	// with usage data or dev resources, comment threads, exported assets or rendered files; 0
+	// for the other phases.
+	Count int
+
+	NodeID   string // node of NodeFetched and AssetDownloaded events
+	NodeName string
+	FileName string // path of the asset of AssetDownloaded events, relative to the image directory
+
+	// Completed and Total count the images of the export of AssetDownloaded events; Total grows
+	// as batches are rendered.
+	Completed, Total int
+}
+
+// emit reports e to OnProgress.
+func (o *Options) emit(e Event) {
+	if o.OnProgress != nil {
+		o.OnProgress(e)
+	}
+}
+
+// startPhase makes phase the phase of the run, logged by SlogLogger and reported to OnProgress.
+func (o *Options) startPhase(phase string) {
+	o.phase = phase
+	o.emit(Event{Kind: PhaseStarted, Phase: phase})
+}
+
+// completePhase reports the completion of the phase of the run, which produced count items.
+func (o *Options) completePhase(count int) {
+	o.emit(Event{Kind: PhaseCompleted, Phase: o.phase, Count: count})
+}
+
+// assetEvents returns the progress function reporting the downloaded assets of an image export
+// to OnProgress, then to fn; nil when neither is set.
+func (o *Options) assetEvents(fn imager.ProgressFunc) imager.ProgressFunc {
+	if o.OnProgress == nil {
+		return fn
+	}
+	return func(p imager.Progress) {
+		if p.Asset != nil {
+			o.emit(Event{
+				Kind:      AssetDownloaded,
+				Phase:     phaseImages,
+				NodeID:    p.Asset.NodeID,
+				NodeName:  p.Asset.NodeName,
+				FileName:  p.Asset.FileName,
+				Completed: p.Completed,
+				Total:     p.Total,
+			})
+		}
+		if fn != nil {
+			fn(p)
+		}
+	}
+}
@@ -1,11 +1,15 @@
 package figmaextractor
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/hellenic-development/figma-extractor/pkg/extractor"
 	"github.com/hellenic-development/figma-extractor/pkg/figma"
@@ -16,15 +20,79 @@ import (
 // Options configures the extraction.
 type Options struct {
 	AccessToken        string
-	FileURL            string    // Figma file URL
-	NodeIDs            []string  // empty = entire file
+	OAuth              bool                 // AccessToken is an OAuth access token rather than a personal access token
+	RefreshToken       figma.TokenRefresher // renews an expired OAuth access token; nil = no refresh
+	HTTPClient         *http.Client         // sends Figma API requests and image downloads; nil = a client tuned for large files
+	Proxy              string               // HTTP(S) or SOCKS5 proxy URL for API requests and image downloads; empty = HTTPS_PROXY/HTTP_PROXY
+	RateLimit          int                  // maximum Figma API requests per minute, the rest are queued; 0 = unlimited (Retry-After is honored either way)
+	ResponseCache      figma.ResponseCache  // caches file responses and revalidates them by ETag, e.g. figma.NewMemoryCache(); nil = no caching
+	CacheDir           string               // directory caching file JSON, image URLs and downloaded assets per file version between runs; empty = none
+	CaptureDir         string               // directory receiving every raw API response, tokens redacted, for bug reports; empty = none
+	TraceHTTP          bool                 // logs every API request and image download, with its status and duration, at the debug level (see DebugLogger)
+	FileURL            string               // Figma file URL
+	FileURLs           []string             // several Figma files extracted by RunFiles, besides FileURL
+	NodeIDs            []string             // empty = entire file
+	Version            string               // file version to extract: a version ID or the label of a saved version; empty = current
+	IncludeNodes       []string             // path.Match patterns of the names of the nodes to extract and export, with their descendants, e.g. "Tokens/*"; empty = all
+	ExcludeNodes       []string             // path.Match patterns of the names of noise layers to skip with their descendants, e.g. "scratch", "Archive/*"
+	NodeTypes          []string             // node types to extract and export, e.g. FRAME, COMPONENT, TEXT; nodes of other types give way to their children; empty = all
 	InheritFileContext bool
 	ExportImages       bool
-	ImageFormat        string    // "png", "svg", "jpg", "pdf"
+	ImageFormat        string // "png", "svg", "jpg", "pdf"
 	ImageScales        []float64
 	ImageDir           string
+	ImageStore         imager.AssetStore   // stores the exported images instead of ImageDir, e.g. an imager.S3Store for a CDN bucket; nil = ImageDir
+	DownloadWorkers    int                 // images downloaded at the same time; 0 = 5
+	DownloadRate       int                 // combined image download speed in KB per second; 0 = unlimited
+	DownloadRetries    int                 // retries of a failed image download, with backoff; 0 = 3, negative = none
+	Incremental        bool                // skip exporting images whose nodes did not change since the last export, tracked in .figma-manifest.json next to the images
+	ImageHierarchy     bool                // place exported images in subdirectories mirroring their page and top-level frame
+	AssetCatalog       bool                // place exported images in an Xcode asset catalog, Images.xcassets, an image set per node with its 1x/2x/3x images
+	AndroidResources   bool                // place exported images in Android drawable-<density> directories by scale, and convert SVGs to vector drawables, under res/
+	OptimizeImages     bool                // recompress exported PNGs and strip PNG/JPEG metadata without changing pixels
+	OptimizeSVG        bool                // minify exported SVGs: strip comments and editor metadata, collapse groups, round coordinates
+	SVGSprite          bool                // combine the exported SVGs into sprite.svg, a symbol per icon, with a sprite.html usage snippet
+	UseExportSettings  bool                // export nodes in the formats, scales and suffixes set by their designers instead of ImageFormat and ImageScales
+	ImageNodes         []string            // path.Match patterns of the names of the nodes to export, e.g. "Icons/*", with or without export settings; other nodes and embedded images are skipped; empty = nodes with export settings and embedded images
+	NoScreenshot       bool                // skip the screenshot of the design and export the images of the nodes alone
+	ImageRender        figma.RenderOptions // Images API render options (svg_include_id, svg_simplify_stroke, use_absolute_bounds, contents_only)
+	Progress           imager.ProgressFunc // reports the progress of the image export; nil = none
+	OnProgress         func(Event)         // reports the phases of the run, the fetched nodes and the downloaded images; nil = none
 	ComponentTree      bool
-	Logger             Logger // nil = no logging
+	VectorPaths        bool                 // fetch vector paths (geometry=paths) and inline small icons as SVG; makes responses larger
+	PluginData         []string             // plugin IDs whose node data to fetch, "shared" for shared plugin data; shown in the component tree
+	Variables          bool                 // fetch Figma variables (Enterprise plan, file_variables:read scope)
+	Comments           bool                 // fetch unresolved comments (file_comments:read scope) and list them next to their nodes
+	ComponentUsage     bool                 // add library analytics usage counts to the component catalog (Enterprise plan, library_analytics:read scope)
+	DevResources       bool                 // add the dev resources linked to components (Storybook, GitHub, docs) to the component catalog (file_dev_resources:read scope)
+	LibraryStyles      bool                 // name styles from shared team libraries after their published names (library_content:read scope)
+	TeamID             string               // team whose published styles are fetched in one go for LibraryStyles; empty = look styles up one by one
+	RulesFile          string               // YAML or JSON file mapping color name patterns onto palette categories instead of keywords, see extractor.ParseColorRules
+	Analyzers          []extractor.Analyzer // custom analyzers run over every extracted node after the built-in ones, e.g. to collect domain-specific data into Specs.Custom
+	Format             string               // output format, see formatter.Formats(); default "markdown", or "template" when OutputTemplate is set
+	Formats            []string             // several output formats rendered from a single extraction; overrides Format
+	OutputTemplate     string               // text/template source for the "template" format, executed with formatter.TemplateData
+	Sections           []string             // sections of the markdown report, see formatter.MarkdownSections(); "-name" leaves one out; empty = all
+	FrontMatter        bool                 // start the markdown report with YAML front matter (file name, version, extraction date)
+	TableOfContents    bool                 // add a table of contents with stable heading anchors to the markdown report
+	PerFrame           bool                 // add a section per node of NodeIDs with its screenshot, tokens and node tree to the markdown report
+	SplitNodes         bool                 // write a markdown report and json file per node of NodeIDs, named after the node, plus an index, instead of merging the nodes
+	PollInterval       time.Duration        // how often Watch checks the file for changes; default 30s
+	Logger             Logger               // nil = no logging
+	// Naming names the css and scss tokens: formatter.KebabNaming, CamelNaming, BEMNaming or
+	// TailwindNaming (see formatter.Naming), or a formatter.NamingFunc. nil = KebabNaming.
+	Naming       formatter.NamingStrategy
+	Unit         string  // unit of the typography, spacing and radius tokens of the css, scss, typescript, tailwind and markdown formats: "px" (default) or "rem"; native formats use the units of their platform
+	BaseFontSize float64 // root font size in px that rem values are relative to; default 16
+
+	// CategorizeColor picks the palette category of every fill color by its style or layer name
+	// and hex value, overriding the keyword heuristics and RulesFile per project, e.g. for layers
+	// named in another language. Returning "" leaves the color to them.
+	CategorizeColor func(nodeName, hex string) extractor.Category
+
+	warnings []Warning // collected during a run, see Result.Warnings
+	fileKey  string    // file of the run, logged by SlogLogger
+	phase    string    // phase of the run, logged by SlogLogger
 }
 
 // Logger receives progress messages. A nil Logger means silent operation.
@@ -34,42 +102,144 @@ type Logger interface {
 	Errorf(format string, args ...any)
 }
 
+// DebugLogger is a Logger that also receives debug messages, such as the requests traced with
+// Options.TraceHTTP. Other Loggers do not receive them.
+type DebugLogger interface {
+	Logger
+	Debugf(format string, args ...any)
+}
+
 // Result contains the extraction output.
 type Result struct {
-	Specs    *extractor.DesignSpecs
-	FileName string // Figma file name
-	Markdown string // formatted markdown output
+	Specs        *extractor.DesignSpecs
+	FileKey      string           // key of the Figma file; empty for offline runs
+	FileName     string           // Figma file name
+	Version      string           // Figma version ID of the extracted file
+	LastModified string           // when the file was last modified, RFC 3339
+	Markdown     string           // formatted markdown output, set when "markdown" is one of the requested formats
+	Files        []formatter.File // rendered output files of all requested formats
+	Outputs      []Output         // rendered output files per requested format, in request order
+	Warnings     []Warning        // non-fatal issues of the run, in order of occurrence
+}
+
+// Output holds the files rendered for a single output format.
+type Output struct {
+	Format string
+	Files  []formatter.File
 }
 
 func (o *Options) logInfo(f string, a ...any) {
-	if o.Logger != nil {
-		o.Logger.Infof(f, a...)
-	}
+	o.log(slog.LevelInfo, nil, f, a...)
 }
 
 func (o *Options) logWarn(f string, a ...any) {
-	if o.Logger != nil {
-		o.Logger.Warnf(f, a...)
-	}
+	o.log(slog.LevelWarn, nil, f, a...)
 }
 
 func (o *Options) logError(f string, a ...any) {
-	if o.Logger != nil {
-		o.Logger.Errorf(f, a...)
+	o.log(slog.LevelError, nil, f, a...)
+}
+
+// newClient returns a Figma API client authenticating with the access token of the options.
+func (o *Options) newClient() (*figma.Client, error) {
+	client := figma.NewClient(o.AccessToken)
+	if o.OAuth {
+		client = figma.NewOAuthClient(o.AccessToken, o.RefreshToken)
+	}
+	if o.HTTPClient != nil {
+		client = client.WithHTTPClient(o.HTTPClient)
+	}
+	if o.Proxy != "" {
+		proxyURL, err := figma.ParseProxy(o.Proxy)
+		if err != nil {
+			return nil, err
+		}
+		client = client.WithProxy(proxyURL)
+	}
+	if o.RateLimit > 0 {
+		client = client.WithRateLimit(o.RateLimit)
+	}
+	if o.TraceHTTP {
+		client = client.WithTransport(&tracingTransport{opts: o, next: client.HTTPClient().Transport})
+	}
+	if o.ResponseCache != nil {
+		client = client.WithCache(o.ResponseCache)
+	} else if o.CacheDir != "" {
+		client = client.WithCache(figma.NewDiskCache(filepath.Join(o.CacheDir, "api")))
 	}
+	if o.CaptureDir != "" {
+		client = client.WithCapture(o.CaptureDir)
+	}
+	if o.VectorPaths {
+		client = client.WithGeometry()
+	}
+	if len(o.PluginData) > 0 {
+		client = client.WithPluginData(o.PluginData...)
+	}
+	return client, nil
 }
 
-// Run executes the Figma extraction pipeline and returns the result.
-func Run(opts Options) (*Result, error) {
-	// Apply defaults.
-	if opts.ImageFormat == "" {
-		opts.ImageFormat = "png"
+// imageStore returns the store of the exported images.
+func (o *Options) imageStore() imager.AssetStore {
+	if o.ImageStore != nil {
+		return o.ImageStore
 	}
-	if opts.ImageDir == "" {
-		opts.ImageDir = "figma-assets"
+	return imager.LocalStore{Dir: o.ImageDir}
+}
+
+// imageLocation describes where the exported images are written, for logs.
+func (o *Options) imageLocation() string {
+	if s, ok := o.imageStore().(fmt.Stringer); ok {
+		return s.String()
 	}
-	if len(opts.ImageScales) == 0 {
-		opts.ImageScales = []float64{1}
+	return "the image store"
+}
+
+// extractorConfig returns the extraction customizations of the options: the Analyzers, the
+// CategorizeColor hook, and the color rules of RulesFile.
+func (o *Options) extractorConfig() (extractor.Config, error) {
+	cfg := extractor.Config{Analyzers: o.Analyzers, CategorizeColor: o.CategorizeColor}
+	if o.RulesFile != "" {
+		data, err := os.ReadFile(o.RulesFile)
+		if err != nil {
+			return cfg, fmt.Errorf("read rules: %w", err)
+		}
+		if cfg.ColorRules, err = extractor.ParseColorRules(data); err != nil {
+			return cfg, fmt.Errorf("%s: %w", o.RulesFile, err)
+		}
+		o.logInfo("Categorizing colors by %d rule(s) of %s", len(cfg.ColorRules.Rules()), o.RulesFile)
+	}
+	return cfg, nil
+}
+
+// filterNodes removes the nodes left out by IncludeNodes, ExcludeNodes and NodeTypes from the
+// fetched document and nodes, before anything is extracted or exported from them.
+func (o *Options) filterNodes(fileResp *figma.FileResponse, nodesResp *figma.NodesResponse) {
+	filter := extractor.NodeFilter{Include: o.IncludeNodes, Exclude: o.ExcludeNodes, Types: o.NodeTypes}
+	if filter.IsZero() {
+		return
+	}
+	removed := filter.Apply(&fileResp.Document)
+	if nodesResp != nil {
+		removed = 0
+		for id, nd := range nodesResp.Nodes {
+			removed += filter.Apply(&nd.Document)
+			nodesResp.Nodes[id] = nd
+		}
+	}
+	o.logInfo("Filtered out %d node(s)", removed)
+}
+
+// Run executes the Figma extraction pipeline and returns the result.
+// Cancelling ctx aborts any in-flight Figma API request and stops the pipeline.
+func Run(ctx context.Context, opts Options) (*Result, error) {
+	formats, err := opts.prepare(true)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := opts.extractorConfig()
+	if err != nil {
+		return nil, err
 	}
 
 	// Extract file key from URL.
@@ -78,6 +248,7 @@ func Run(opts Options) (*Result, error) {
 	if err != nil {
 		return nil, fmt.Errorf("extract file key: %w", err)
 	}
+	opts.fileKey = fileKey
 	opts.logInfo("File key: %s", fileKey)
 
 	// Extract node IDs from URL or merge with explicit ones.
@@ -100,165 +271,414 @@ func Run(opts Options) (*Result, error) {
 	}
 
 	// Create Figma client.
+	opts.startPhase(phaseAuth)
 	opts.logInfo("Authenticating with Figma API...")
-	client := figma.NewClient(opts.AccessToken)
+	client, err := opts.newClient()
+	if err != nil {
+		return nil, err
+	}
+
+	// Pin a historical version for reproducible extractions.
+	if opts.Version != "" {
+		version, err := resolveVersion(ctx, client, fileKey, opts.Version)
+		if err != nil {
+			return nil, err
+		}
+		opts.logInfo("Using file version %s", version)
+		client = client.WithVersion(version)
+	}
+
+	// Cached responses are keyed by file version: pin the current one, so that a file that did
+	// not change since the last run is read from the cache entirely.
+	if opts.CacheDir != "" && opts.Version == "" {
+		meta, err := client.GetFileMetadata(ctx, fileKey)
+		if err != nil {
+			opts.warnf(WarningAPI, "Could not look up the current file version, skipping the cache: %v", err)
+		} else {
+			opts.logInfo("Using cache %s for file version %s", opts.CacheDir, meta.Version)
+			client = client.WithVersion(meta.Version)
+		}
+	}
+
+	opts.completePhase(0)
 
 	var specs *extractor.DesignSpecs
-	var fileName string
 	var fileResp *figma.FileResponse
 	var nodesResp *figma.NodesResponse
 
+	opts.startPhase(phaseFetch)
 	// Choose extraction strategy based on whether node IDs are provided.
 	if len(targetNodeIDs) > 0 {
 		opts.logInfo("Extracting %d specific node(s)...", len(targetNodeIDs))
 
 		opts.logInfo("Fetching nodes from Figma...")
-		nodesResp, err = client.GetFileNodes(fileKey, targetNodeIDs)
+		nodesResp, err = client.GetFileNodes(ctx, fileKey, targetNodeIDs)
 		if err != nil {
 			return nil, fmt.Errorf("fetch nodes: %w", err)
 		}
 		opts.logInfo("Retrieved %d node(s)", len(nodesResp.Nodes))
+		for _, id := range targetNodeIDs {
+			if nd, ok := nodesResp.Nodes[id]; ok {
+				opts.emit(Event{Kind: NodeFetched, Phase: phaseFetch, NodeID: id, NodeName: nd.Document.Name})
+			}
+		}
 
 		opts.logInfo("Fetching file metadata...")
-		fileResp, err = client.GetFile(fileKey)
+		fileResp, err = client.GetFile(ctx, fileKey)
 		if err != nil {
 			return nil, fmt.Errorf("fetch file metadata: %w", err)
 		}
 		opts.logInfo("File: %s", fileResp.Name)
-		fileName = fileResp.Name
+		opts.filterNodes(fileResp, nodesResp)
+		opts.completePhase(len(nodesResp.Nodes))
+
+		if opts.LibraryStyles {
+			opts.startPhase(phaseLibrary)
+			resolveLibraryStyles(ctx, &opts, client, fileResp, nodesResp, targetNodeIDs)
+			opts.completePhase(0)
+		}
 
+		opts.startPhase(phaseExtract)
 		opts.logInfo("Extracting design specifications from nodes...")
-		specs = extractor.ExtractNodes(fileResp, nodesResp, targetNodeIDs, opts.InheritFileContext)
+		specs = cfg.ExtractNodes(fileResp, nodesResp, targetNodeIDs, opts.InheritFileContext)
+		if opts.PerFrame || opts.SplitNodes {
+			specs.Frames = cfg.ExtractFrames(fileResp, nodesResp, targetNodeIDs)
+		}
 	} else {
 		opts.logInfo("Extracting entire file...")
 
 		opts.logInfo("Fetching file data from Figma...")
-		fileResp, err = client.GetFile(fileKey)
+		fileResp, err = client.GetFile(ctx, fileKey)
 		if err != nil {
 			return nil, fmt.Errorf("fetch file: %w", err)
 		}
 		opts.logInfo("File: %s", fileResp.Name)
-		fileName = fileResp.Name
+		opts.emit(Event{Kind: NodeFetched, Phase: phaseFetch, NodeID: fileResp.Document.ID, NodeName: fileResp.Document.Name})
+		opts.filterNodes(fileResp, nil)
+		opts.completePhase(1)
 
+		if opts.LibraryStyles {
+			opts.startPhase(phaseLibrary)
+			resolveLibraryStyles(ctx, &opts, client, fileResp, nil, nil)
+			opts.completePhase(0)
+		}
+
+		opts.startPhase(phaseExtract)
 		opts.logInfo("Extracting design specifications...")
-		specs = extractor.Extract(fileResp)
+		specs = cfg.Extract(fileResp)
+		if opts.PerFrame {
+			opts.warnf(WarningOption, "Per-frame sections need node IDs; writing a single report")
+		}
+		if opts.SplitNodes {
+			opts.warnf(WarningOption, "Per-node files need node IDs; writing a single report")
+		}
+	}
+	opts.completePhase(0)
+
+	// Variables are opt-in: the API is restricted to Enterprise plans, so failures are non-fatal.
+	if opts.Variables {
+		opts.startPhase(phaseVariables)
+		opts.logInfo("Fetching variables...")
+		varsResp, err := client.GetLocalVariables(ctx, fileKey)
+		if err != nil {
+			opts.warnf(WarningAPI, "Variables API failed: %v", err)
+		} else {
+			specs.Variables = extractor.ExtractVariables(varsResp)
+			opts.logInfo("Found %d variable collection(s)", len(specs.Variables))
+
+			// Light and dark variable modes are a more reliable theme source than frame names.
+			if themes := extractor.ThemesFromVariables(specs.Variables); themes != nil {
+				specs.Themes = themes
+			}
+		}
+		opts.completePhase(len(specs.Variables))
+	}
+
+	// Usage counts are opt-in and non-fatal: the Library Analytics API is restricted to Enterprise
+	// plans and only knows about published components.
+	if opts.ComponentUsage && len(specs.Components) > 0 {
+		opts.startPhase(phaseUsage)
+		opts.logInfo("Fetching component usage analytics...")
+		var usages []figma.ComponentUsage
+		cursor := ""
+		for {
+			usagesResp, err := client.GetComponentUsages(ctx, fileKey, cursor)
+			if err != nil {
+				opts.warnf(WarningAPI, "Library Analytics API failed: %v", err)
+				break
+			}
+			usages = append(usages, usagesResp.Rows...)
+			if !usagesResp.NextPage || usagesResp.Cursor == "" || usagesResp.Cursor == cursor {
+				break
+			}
+			cursor = usagesResp.Cursor
+		}
+		extractor.ApplyComponentUsages(specs.Components, usages)
+		withUsage := 0
+		for _, c := range specs.Components {
+			if c.Usage != nil {
+				withUsage++
+			}
+		}
+		opts.logInfo("Found usage data for %d component(s)", withUsage)
+		opts.completePhase(withUsage)
+	}
+
+	// Dev resources are opt-in and non-fatal: tokens without the file_dev_resources:read scope
+	// cannot read them.
+	if opts.DevResources && len(specs.Components) > 0 {
+		opts.startPhase(phaseDevRes)
+		opts.logInfo("Fetching dev resources...")
+		resourcesResp, err := client.GetDevResources(ctx, fileKey, nil)
+		linked := 0
+		if err != nil {
+			opts.warnf(WarningAPI, "Dev Resources API failed: %v", err)
+		} else {
+			extractor.ApplyDevResources(specs.Components, resourcesResp.DevResources)
+			for _, c := range specs.Components {
+				if len(c.Links) > 0 {
+					linked++
+				}
+			}
+			opts.logInfo("Found dev resources for %d component(s)", linked)
+		}
+		opts.completePhase(linked)
+	}
+
+	// Comments are opt-in and non-fatal: tokens without the file_comments:read scope cannot read them.
+	if opts.Comments {
+		opts.startPhase(phaseComments)
+		opts.logInfo("Fetching comments...")
+		commentsResp, err := client.GetComments(ctx, fileKey)
+		if err != nil {
+			opts.warnf(WarningAPI, "Comments API failed: %v", err)
+		} else {
+			roots := []*figma.Node{&fileResp.Document}
+			if len(targetNodeIDs) > 0 {
+				roots = roots[:0]
+				for _, id := range targetNodeIDs {
+					if nd, ok := nodesResp.Nodes[id]; ok {
+						roots = append(roots, &nd.Document)
+					}
+				}
+			}
+			specs.Comments = extractor.ExtractComments(commentsResp, roots)
+			opts.logInfo("Found %d open comment thread(s)", len(specs.Comments))
+		}
+		opts.completePhase(len(specs.Comments))
 	}
 
 	// Image export (opt-in).
 	if opts.ExportImages {
-		if err := exportImages(&opts, client, fileKey, specs, fileResp, nodesResp, targetNodeIDs); err != nil {
+		opts.startPhase(phaseImages)
+		if err := exportImages(ctx, &opts, client, fileKey, specs, fileResp, nodesResp, targetNodeIDs); err != nil {
 			return nil, err
 		}
+		opts.completePhase(len(specs.ExportedAssets))
 	}
 
-	// Component tree is opt-in.
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return render(ctx, &opts, formats, specs, fileResp)
+}
+
+// prepare applies the defaults of the options and validates them, before any time is spent on
+// API requests; online tells whether the run uses the API. It returns the formats to render.
+func (o *Options) prepare(online bool) ([]string, error) {
+	o.applyDefaults()
+	if err := o.validate(online); err != nil {
+		return nil, err
+	}
+	return o.formats(), nil
+}
+
+// render completes the extracted specs and renders them in every requested output format.
+func render(ctx context.Context, opts *Options, formats []string, specs *extractor.DesignSpecs, fileResp *figma.FileResponse) (*Result, error) {
+	opts.startPhase(phaseRender)
+
+	// Component tree is opt-in; per-frame sections always show the tree of their frame.
 	if opts.ComponentTree {
 		extractor.AttachAssetsToNodeTree(specs.NodeTree, specs.ExportedAssets)
 	} else {
 		specs.NodeTree = nil
 	}
+	for _, frame := range specs.Frames {
+		extractor.AttachAssetsToNodeTree(frame.Specs.NodeTree, specs.ExportedAssets)
+		if opts.SplitNodes {
+			// Per-node files show the assets and screenshot of their node alone.
+			frame.Specs.ExportedAssets = frameAssets(frame, specs.ExportedAssets)
+			if !opts.ComponentTree {
+				frame.Specs.NodeTree = nil
+			}
+		}
+	}
 
-	// Format as markdown.
-	opts.logInfo("Generating markdown documentation...")
-	markdown := formatter.ToMarkdown(specs, fileName, opts.ImageDir)
+	result := &Result{
+		Specs:        specs,
+		FileKey:      opts.fileKey,
+		FileName:     fileResp.Name,
+		Version:      fileResp.Version,
+		LastModified: fileResp.LastModified,
+		Warnings:     opts.warnings,
+	}
 
-	return &Result{
-		Specs:    specs,
-		FileName: fileName,
-		Markdown: markdown,
-	}, nil
-}
+	// Render every requested output format from the same extraction.
+	in := formatter.Input{
+		Specs:       specs,
+		FileName:    fileResp.Name,
+		ImageDir:    opts.ImageDir,
+		Template:    opts.OutputTemplate,
+		Naming:      opts.Naming,
+		Units:       formatter.Units{Rem: opts.Unit == "rem", BaseFontSize: opts.BaseFontSize},
+		Sections:    opts.Sections,
+		FrontMatter: opts.FrontMatter,
+		TOC:         opts.TableOfContents,
+		Version:     fileResp.Version,
+		ExtractedAt: time.Now(),
+		ReadAsset: func(name string) ([]byte, error) {
+			return readAsset(ctx, opts.imageStore(), name)
+		},
+	}
+	for _, format := range formats {
+		opts.logInfo("Generating %s output...", format)
+		renderFormat := formatter.Render
+		if opts.SplitNodes && len(specs.Frames) > 0 && formatter.CanSplit(format) {
+			renderFormat = formatter.RenderSplit
+		}
+		files, err := renderFormat(format, in)
+		if err != nil {
+			return nil, fmt.Errorf("render %s: %w", format, err)
+		}
 
-// exportImages handles the full image export pipeline: screenshot, ExportSettings nodes,
-// IMAGE fills, render fallback, and deduplication.
-func exportImages(opts *Options, client *figma.Client, fileKey string, specs *extractor.DesignSpecs, fileResp *figma.FileResponse, nodesResp *figma.NodesResponse, targetNodeIDs []string) error {
-	// Validate format.
-	validFormats := map[string]bool{"png": true, "svg": true, "jpg": true, "pdf": true}
-	if !validFormats[opts.ImageFormat] {
-		return fmt.Errorf("invalid image format %q (must be png, svg, jpg, or pdf)", opts.ImageFormat)
+		result.Outputs = append(result.Outputs, Output{Format: format, Files: files})
+		result.Files = append(result.Files, files...)
+		if format == "markdown" && len(files) > 0 {
+			result.Markdown = string(files[0].Content)
+		}
 	}
+	opts.completePhase(len(result.Files))
 
-	// Validate scales.
-	for _, s := range opts.ImageScales {
-		if s <= 0 {
-			return fmt.Errorf("scale value must be positive, got %g", s)
+	return result, nil
+}
+
+// frameAssets returns the assets of the nodes of frame, and its screenshot, among assets.
+func frameAssets(frame extractor.Frame, assets []extractor.ExportedAssetInfo) []extractor.ExportedAssetInfo {
+	nodes := map[string]bool{frame.NodeID: true}
+	var walk func(nd *extractor.NodeDescription)
+	walk = func(nd *extractor.NodeDescription) {
+		nodes[nd.ID] = true
+		for _, child := range nd.Children {
+			walk(child)
 		}
 	}
+	for _, root := range frame.Specs.NodeTree {
+		walk(root)
+	}
 
-	config := imager.ExportConfig{
-		Format:    opts.ImageFormat,
-		Scales:    opts.ImageScales,
-		OutputDir: opts.ImageDir,
+	var own []extractor.ExportedAssetInfo
+	for _, a := range assets {
+		if nodes[a.NodeID] && (!a.IsScreenshot || a.NodeID == frame.NodeID) {
+			own = append(own, a)
+		}
 	}
+	return own
+}
 
-	// Screenshot: render the target node(s) (or full document) as a complete design screenshot.
-	screenshotName := "complete_design_screenshot." + config.Format
-	screenshotNodes := make(map[string]string) // nodeID -> nodeName
+// resolveVersion returns the ID of the file version named by version: either an ID itself, or
+// the label of one of the file's recent saved versions.
+func resolveVersion(ctx context.Context, client *figma.Client, fileKey, version string) (string, error) {
+	if _, err := strconv.ParseUint(version, 10, 64); err == nil {
+		return version, nil
+	}
 
-	if len(targetNodeIDs) > 0 {
-		for _, id := range targetNodeIDs {
-			if nd, ok := nodesResp.Nodes[id]; ok {
-				screenshotNodes[id] = nd.Document.Name
-				for _, child := range nd.Document.Children {
-					screenshotNodes[child.ID] = child.Name
-				}
-			}
+	versionsResp, err := client.GetVersions(ctx, fileKey)
+	if err != nil {
+		return "", fmt.Errorf("fetch versions: %w", err)
+	}
+	for _, v := range versionsResp.Versions {
+		if v.Label == version {
+			return v.ID, nil
 		}
-	} else {
-		screenshotNodes[fileResp.Document.ID] = fileResp.Document.Name
-		for _, child := range fileResp.Document.Children {
-			screenshotNodes[child.ID] = child.Name
+	}
+	return "", fmt.Errorf("version %q not found among the %d most recent versions", version, len(versionsResp.Versions))
+}
+
+// uniqueFormats returns the non-empty format names in order, without duplicates.
+func uniqueFormats(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	formats := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
 		}
+		seen[name] = true
+		formats = append(formats, name)
 	}
+	return formats
+}
 
-	opts.logInfo("Capturing design screenshot to %s...", screenshotName)
-	screenshotResult, err := imager.ExportImages(client, fileKey, screenshotNodes, imager.ExportConfig{
-		Format:    config.Format,
-		Scales:    []float64{1},
-		OutputDir: config.OutputDir,
-	})
-	if err != nil {
-		opts.logWarn("Screenshot failed: %v", err)
-	} else {
-		for _, asset := range screenshotResult.Assets {
-			oldPath := filepath.Join(config.OutputDir, asset.FileName)
-			newPath := filepath.Join(config.OutputDir, screenshotName)
-			if err := os.Rename(oldPath, newPath); err != nil {
-				opts.logWarn("Could not rename screenshot: %v", err)
-				specs.ExportedAssets = append(specs.ExportedAssets, extractor.ExportedAssetInfo{
-					NodeID:       asset.NodeID,
-					NodeName:     asset.NodeName,
-					FileName:     asset.FileName,
-					Format:       asset.Format,
-					Scale:        asset.Scale,
-					IsScreenshot: true,
-				})
-			} else {
-				specs.ExportedAssets = append(specs.ExportedAssets, extractor.ExportedAssetInfo{
-					NodeID:       asset.NodeID,
-					NodeName:     asset.NodeName,
-					FileName:     screenshotName,
-					Format:       asset.Format,
-					Scale:        asset.Scale,
-					IsScreenshot: true,
-				})
-			}
+// exportImages handles the full image export pipeline: screenshot, ExportSettings nodes,
+// IMAGE fills, render fallback, and deduplication.
+func exportImages(ctx context.Context, opts *Options, client *figma.Client, fileKey string, specs *extractor.DesignSpecs, fileResp *figma.FileResponse, nodesResp *figma.NodesResponse, targetNodeIDs []string) error {
+	config := imager.ExportConfig{
+		Format:        opts.ImageFormat,
+		Scales:        opts.ImageScales,
+		OutputDir:     opts.ImageDir,
+		Store:         opts.imageStore(),
+		RenderOptions: opts.ImageRender,
+		Optimize:      opts.OptimizeImages,
+		OptimizeSVG:   opts.OptimizeSVG,
+		Concurrency:   opts.DownloadWorkers,
+		Retries:       opts.DownloadRetries,
+		HTTPClient:    client.HTTPClient(),
+	}
+	if opts.DownloadRate > 0 {
+		config.MaxBytesPerSecond = int64(opts.DownloadRate) * 1024
+	}
+	if opts.ImageHierarchy {
+		config.Dirs = imager.AssetDirs(&fileResp.Document)
+	}
+	if opts.Incremental {
+		manifest, err := imager.LoadManifest(ctx, config.Store, &fileResp.Document, fileResp.Version)
+		if err != nil {
+			opts.warnf(WarningOutput, "Exporting all images: %v", err)
+		} else {
+			config.Manifest = manifest
 		}
 	}
+	if opts.CacheDir != "" {
+		config.CacheDir = filepath.Join(opts.CacheDir, "assets")
+	}
+	progress := newExportProgress(opts.assetEvents(opts.Progress))
 
-	// Phase 1: Collect and export nodes with ExportSettings via render API.
-	exportNodes := make(map[string]string)
+	// Screenshot: render the target node(s) (or full document) as a complete design screenshot.
+	screenshotNodes := make(map[string]string) // nodeID -> nodeName
+	var bytesSaved int64                       // by optimizing the images
+	var skipped int                            // unchanged images of the manifest
+	if !opts.NoScreenshot {
+		screenshotNodes, bytesSaved = captureScreenshots(ctx, opts, client, fileKey, specs, fileResp, nodesResp, targetNodeIDs, config, progress)
+	}
 
+	// Phase 1: Collect and export nodes with ExportSettings, or the nodes of ImageNodes, via
+	// render API. Nodes that are part of the screenshot are not exported again.
+	collect := imager.CollectExportSettings
+	if len(opts.ImageNodes) > 0 {
+		collect = func(root *figma.Node) []imager.ExportableNode {
+			return imager.CollectNamedNodes(root, opts.ImageNodes)
+		}
+	}
+	var exportNodes []imager.ExportableNode
 	if len(targetNodeIDs) > 0 {
 		opts.logInfo("Discovering exportable child nodes...")
 		for _, id := range targetNodeIDs {
 			if nd, ok := nodesResp.Nodes[id]; ok {
-				childExport := imager.CollectExportableNodes(&nd.Document)
-				for cID, cName := range childExport {
-					if _, isRoot := screenshotNodes[cID]; isRoot {
-						continue
+				for _, n := range collect(&nd.Document) {
+					if _, isRoot := screenshotNodes[n.NodeID]; !isRoot {
+						exportNodes = append(exportNodes, n)
 					}
-					exportNodes[cID] = cName
 				}
 			}
 		}
@@ -269,8 +689,11 @@ func exportImages(opts *Options, client *figma.Client, fileKey string, specs *ex
 		}
 	} else {
 		opts.logInfo("Discovering exportable nodes...")
-		exportNodes = imager.CollectExportableNodes(&fileResp.Document)
-		delete(exportNodes, fileResp.Document.ID)
+		for _, n := range collect(&fileResp.Document) {
+			if n.NodeID != fileResp.Document.ID {
+				exportNodes = append(exportNodes, n)
+			}
+		}
 		if len(exportNodes) == 0 {
 			opts.logInfo("No additional exportable nodes")
 		} else {
@@ -279,31 +702,61 @@ func exportImages(opts *Options, client *figma.Client, fileKey string, specs *ex
 	}
 
 	if len(exportNodes) > 0 {
-		opts.logInfo("Exporting rendered images to %s...", opts.ImageDir)
-		result, err := imager.ExportImages(client, fileKey, exportNodes, config)
-		if err != nil {
-			return fmt.Errorf("export images: %w", err)
+		opts.logInfo("Exporting rendered images to %s...", opts.imageLocation())
+		// Nodes of ImageNodes without export settings get ImageFormat and ImageScales.
+		var withSettings []imager.ExportableNode
+		names := make(map[string]string, len(exportNodes))
+		for _, n := range exportNodes {
+			if opts.UseExportSettings && len(n.Settings) > 0 {
+				withSettings = append(withSettings, n)
+			} else {
+				names[n.NodeID] = n.NodeName
+			}
 		}
-		opts.logInfo("Exported %d image(s)", len(result.Assets))
-
-		for _, dlErr := range result.Errors {
-			opts.logWarn("%v", dlErr)
+		var results []*imager.ExportResult
+		if len(withSettings) > 0 {
+			config.Progress = progress.next()
+			result, err := imager.ExportWithSettings(ctx, client, fileKey, withSettings, config)
+			if err != nil {
+				return fmt.Errorf("export images: %w", err)
+			}
+			results = append(results, result)
+		}
+		if len(names) > 0 {
+			config.Progress = progress.next()
+			result, err := imager.ExportImages(ctx, client, fileKey, names, config)
+			if err != nil {
+				return fmt.Errorf("export images: %w", err)
+			}
+			results = append(results, result)
 		}
 
-		for _, asset := range result.Assets {
-			specs.ExportedAssets = append(specs.ExportedAssets, extractor.ExportedAssetInfo{
-				NodeID:   asset.NodeID,
-				NodeName: asset.NodeName,
-				FileName: asset.FileName,
-				Format:   asset.Format,
-				Scale:    asset.Scale,
-			})
+		for _, result := range results {
+			opts.logInfo("Exported %d image(s)", len(result.Assets))
+			bytesSaved += result.BytesSaved
+			skipped += result.Skipped
+
+			for _, dlErr := range result.Errors {
+				opts.warnf(WarningImage, "%v", dlErr)
+			}
+
+			for _, asset := range result.Assets {
+				specs.ExportedAssets = append(specs.ExportedAssets, extractor.ExportedAssetInfo{
+					NodeID:   asset.NodeID,
+					NodeName: asset.NodeName,
+					FileName: asset.FileName,
+					Format:   asset.Format,
+					Scale:    asset.Scale,
+				})
+			}
 		}
 	}
 
 	// Phase 2: Collect and export embedded IMAGE fill nodes via file images API.
 	var roots []*figma.Node
-	if len(targetNodeIDs) > 0 {
+	if len(opts.ImageNodes) > 0 {
+		// The nodes of ImageNodes were rendered with their embedded images.
+	} else if len(targetNodeIDs) > 0 {
 		for _, id := range targetNodeIDs {
 			if nd, ok := nodesResp.Nodes[id]; ok {
 				doc := nd.Document // copy
@@ -320,6 +773,7 @@ func exportImages(opts *Options, client *figma.Client, fileKey string, specs *ex
 			if _, isScreenshot := screenshotNodes[fill.NodeID]; isScreenshot {
 				continue
 			}
+
 			allImageFills = append(allImageFills, fill)
 		}
 	}
@@ -328,13 +782,14 @@ func exportImages(opts *Options, client *figma.Client, fileKey string, specs *ex
 		opts.logInfo("Found %d embedded image(s), fetching download URLs...", len(allImageFills))
 		var unresolvedNodes []imager.ImageFillNode
 
-		fileImagesResp, err := client.GetFileImages(fileKey)
+		fileImagesResp, err := client.GetFileImages(ctx, fileKey)
 		if err != nil {
-			opts.logWarn("File images API failed: %v", err)
+			opts.warnf(WarningAPI, "File images API failed: %v", err)
 			unresolvedNodes = allImageFills
 		} else {
-			opts.logInfo("Downloading embedded images to %s...", opts.ImageDir)
-			fillResult, err := imager.ExportImageFills(fileImagesResp, allImageFills, config)
+			opts.logInfo("Downloading embedded images to %s...", opts.imageLocation())
+			config.Progress = progress.next()
+			fillResult, err := imager.ExportImageFills(ctx, fileImagesResp, allImageFills, config)
 			if err != nil {
 				return fmt.Errorf("export image fills: %w", err)
 			}
@@ -342,9 +797,11 @@ func exportImages(opts *Options, client *figma.Client, fileKey string, specs *ex
 			if len(fillResult.Assets) > 0 {
 				opts.logInfo("Exported %d embedded image(s)", len(fillResult.Assets))
 			}
+			bytesSaved += fillResult.BytesSaved
+			skipped += fillResult.Skipped
 
 			for _, dlErr := range fillResult.Errors {
-				opts.logWarn("%v", dlErr)
+				opts.warnf(WarningImage, "%v", dlErr)
 			}
 
 			for _, asset := range fillResult.Assets {
@@ -367,15 +824,18 @@ func exportImages(opts *Options, client *figma.Client, fileKey string, specs *ex
 			for id := range screenshotNodes {
 				delete(renderNodes, id)
 			}
-			renderResult, err := imager.ExportImages(client, fileKey, renderNodes, config)
+			config.Progress = progress.next()
+			renderResult, err := imager.ExportImages(ctx, client, fileKey, renderNodes, config)
 			if err != nil {
-				opts.logError("Rendering images failed: %v", err)
+				opts.warnf(WarningImage, "Rendering images failed: %v", err)
 				// Non-fatal: continue.
 			} else {
 				opts.logInfo("Rendered %d image(s)", len(renderResult.Assets))
+				bytesSaved += renderResult.BytesSaved
+				skipped += renderResult.Skipped
 
 				for _, dlErr := range renderResult.Errors {
-					opts.logWarn("%v", dlErr)
+					opts.warnf(WarningImage, "%v", dlErr)
 				}
 
 				for _, asset := range renderResult.Assets {
@@ -402,7 +862,7 @@ func exportImages(opts *Options, client *figma.Client, fileKey string, specs *ex
 		filtered := specs.ExportedAssets[:0]
 		for _, a := range specs.ExportedAssets {
 			if !a.IsScreenshot && (excludeIDs[a.NodeID] || excludeNames[a.NodeName]) {
-				os.Remove(filepath.Join(opts.ImageDir, a.FileName))
+				config.Store.Delete(ctx, a.FileName)
 				continue
 			}
 			filtered = append(filtered, a)
@@ -410,9 +870,118 @@ func exportImages(opts *Options, client *figma.Client, fileKey string, specs *ex
 		specs.ExportedAssets = filtered
 	}
 
+	if opts.AssetCatalog {
+		writeAssetCatalog(ctx, opts, config.Store, specs, config.Manifest)
+	}
+	if opts.AndroidResources {
+		writeAndroidResources(ctx, opts, config.Store, specs, config.Manifest)
+	}
+	if opts.SVGSprite {
+		writeSprite(ctx, opts, config.Store, specs.ExportedAssets)
+	}
+	if err := writeAssetsJSON(ctx, config.Store, fileKey, specs, fileResp); err != nil {
+		opts.warnf(WarningOutput, "Could not write %s: %v", AssetsFile, err)
+	}
+	if config.Manifest != nil {
+		opts.logInfo("Kept %d unchanged image(s) from the last export", skipped)
+		if err := config.Manifest.Save(ctx); err != nil {
+			opts.warnf(WarningOutput, "Could not save the image manifest: %v", err)
+		}
+	}
+	if opts.OptimizeImages || opts.OptimizeSVG {
+		opts.logInfo("Optimized images, saved %.1f KB", float64(bytesSaved)/1024)
+	}
+
 	return nil
 }
 
+// captureScreenshots renders the target nodes, or the full document, as a complete design
+// screenshot, or a screenshot per target node for per-frame reports. It returns the nodes
+// making up the screenshot, which are not exported again, and the bytes saved by optimizing it.
+func captureScreenshots(ctx context.Context, opts *Options, client *figma.Client, fileKey string, specs *extractor.DesignSpecs, fileResp *figma.FileResponse, nodesResp *figma.NodesResponse, targetNodeIDs []string, config imager.ExportConfig, progress *exportProgress) (map[string]string, int64) {
+	screenshotName := "complete_design_screenshot." + config.Format
+	screenshotNodes := make(map[string]string) // nodeID -> nodeName
+
+	if len(targetNodeIDs) > 0 {
+		for _, id := range targetNodeIDs {
+			if nd, ok := nodesResp.Nodes[id]; ok {
+				screenshotNodes[id] = nd.Document.Name
+				for _, child := range nd.Document.Children {
+					screenshotNodes[child.ID] = child.Name
+				}
+			}
+		}
+	} else {
+		screenshotNodes[fileResp.Document.ID] = fileResp.Document.Name
+		for _, child := range fileResp.Document.Children {
+			screenshotNodes[child.ID] = child.Name
+		}
+	}
+
+	// Per-frame reports and per-node files get a screenshot of every target node, named after it.
+	perFrame := (opts.PerFrame || opts.SplitNodes) && len(targetNodeIDs) > 0
+	captureNodes := screenshotNodes
+	if perFrame {
+		captureNodes = make(map[string]string, len(targetNodeIDs))
+		for _, id := range targetNodeIDs {
+			if nd, ok := nodesResp.Nodes[id]; ok {
+				captureNodes[id] = nd.Document.Name
+			}
+		}
+		opts.logInfo("Capturing a screenshot of %d frame(s)...", len(captureNodes))
+	} else {
+		opts.logInfo("Capturing design screenshot to %s...", screenshotName)
+	}
+	screenshotResult, err := imager.ExportImages(ctx, client, fileKey, captureNodes, imager.ExportConfig{
+		Format:            config.Format,
+		Scales:            []float64{1},
+		OutputDir:         config.OutputDir,
+		Store:             config.Store,
+		RenderOptions:     config.RenderOptions,
+		Optimize:          config.Optimize,
+		OptimizeSVG:       config.OptimizeSVG,
+		Concurrency:       config.Concurrency,
+		MaxBytesPerSecond: config.MaxBytesPerSecond,
+		Retries:           config.Retries,
+		Progress:          progress.next(),
+		HTTPClient:        config.HTTPClient,
+		CacheDir:          config.CacheDir,
+	})
+	var bytesSaved int64
+	if err != nil {
+		opts.warnf(WarningImage, "Screenshot failed: %v", err)
+	} else {
+		bytesSaved += screenshotResult.BytesSaved
+		for _, asset := range screenshotResult.Assets {
+			screenshotName := screenshotName
+			if perFrame {
+				screenshotName = frameScreenshotName(asset.NodeID, asset.Format)
+			}
+			if err := imager.MoveAsset(ctx, config.Store, asset.FileName, screenshotName); err != nil {
+				opts.warnf(WarningImage, "Could not rename screenshot: %v", err)
+				specs.ExportedAssets = append(specs.ExportedAssets, extractor.ExportedAssetInfo{
+					NodeID:       asset.NodeID,
+					NodeName:     asset.NodeName,
+					FileName:     asset.FileName,
+					Format:       asset.Format,
+					Scale:        asset.Scale,
+					IsScreenshot: true,
+				})
+			} else {
+				specs.ExportedAssets = append(specs.ExportedAssets, extractor.ExportedAssetInfo{
+					NodeID:       asset.NodeID,
+					NodeName:     asset.NodeName,
+					FileName:     screenshotName,
+					Format:       asset.Format,
+					Scale:        asset.Scale,
+					IsScreenshot: true,
+				})
+			}
+		}
+	}
+	return screenshotNodes, bytesSaved
+}
+
 // ParseScales parses a comma-separated string of scale factors into a float64 slice.
 func ParseScales(scalesStr string) ([]float64, error) {
 	parts := strings.Split(scalesStr, ",")
@@ -456,3 +1025,39 @@ func ParseNodeIDs(nodeIDsStr string) []string {
 
 	return result
 }
+
+// exportProgress sums the progress of the successive image exports of a run.
+type exportProgress struct {
+	fn      imager.ProgressFunc
+	done    imager.Progress // of the finished exports
+	current imager.Progress // of the running export
+}
+
+// newExportProgress returns the progress of a run reporting to fn, nil when fn is nil.
+func newExportProgress(fn imager.ProgressFunc) *exportProgress {
+	if fn == nil {
+		return nil
+	}
+	return &exportProgress{fn: fn}
+}
+
+// next returns the progress function of the next export. Exports run one after the other.
+func (p *exportProgress) next() imager.ProgressFunc {
+	if p == nil {
+		return nil
+	}
+	p.done.Completed += p.current.Completed
+	p.done.Total += p.current.Total
+	p.done.Bytes += p.current.Bytes
+	p.current = imager.Progress{}
+
+	return func(current imager.Progress) {
+		p.current = current
+		p.fn(imager.Progress{
+			Completed: p.done.Completed + current.Completed,
+			Total:     p.done.Total + current.Total,
+			Bytes:     p.done.Bytes + current.Bytes,
+			Asset:     current.Asset,
+		})
+	}
+}
@@ -0,0 +1,395 @@
+package figmaextractor
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hellenic-development/figma-extractor/pkg/figma"
+)
+
+// GRPCPrefix is the path prefix of the methods of the figmaextractor.v1.Extractor gRPC service,
+// see proto/figmaextractor/v1/extractor.proto.
+const GRPCPrefix = "/figmaextractor.v1.Extractor/"
+
+// maxGRPCRequest bounds the size of a gRPC request message; diff baselines can be large.
+const maxGRPCRequest = 32 << 20
+
+// maxGRPCImages bounds the total size of the images of an ExportImages request, which are kept
+// in memory until the run ends.
+const maxGRPCImages = 256 << 20
+
+// gRPC status codes.
+const (
+	grpcOK                = 0
+	grpcCanceled          = 1
+	grpcUnknown           = 2
+	grpcInvalidArgument   = 3
+	grpcDeadlineExceeded  = 4
+	grpcNotFound          = 5
+	grpcPermissionDenied  = 7
+	grpcResourceExhausted = 8
+	grpcUnimplemented     = 12
+	grpcUnauthenticated   = 16
+)
+
+// grpcError is a failure of a gRPC request with its status code.
+type grpcError struct {
+	code int
+	msg  string
+}
+
+func (e *grpcError) Error() string {
+	return e.msg
+}
+
+// GRPCHandler is an http.Handler serving the figmaextractor.v1.Extractor gRPC service, so
+// services in any language can run the extractor with the stubs generated from
+// proto/figmaextractor/v1/extractor.proto:
+//
+//	mux.Handle(figmaextractor.GRPCPrefix, figmaextractor.NewGRPCHandler(opts))
+//
+// gRPC runs over HTTP/2: serve it with TLS, or enable unencrypted HTTP/2 (h2c) in
+// http.Server.Protocols. ExtractSpecs and ExportImages stream the progress events of the run
+// (see Event) before their result; ExportImages exports into memory and streams the stored
+// files once the export completed. Requests send their own Figma access token in the
+// x-figma-token metadata, and may send a deadline in grpc-timeout. Compressed messages are not
+// supported.
+type GRPCHandler struct {
+	opts Options
+
+	// AllowServerToken extracts the requests without x-figma-token metadata with the
+	// AccessToken of the options, instead of failing them with UNAUTHENTICATED. Every client
+	// reaching the handler can then read the files that token can.
+	AllowServerToken bool
+}
+
+// NewGRPCHandler returns a handler extracting with opts, whose file, nodes, version and formats
+// or image options are taken from each request.
+func NewGRPCHandler(opts Options) *GRPCHandler {
+	return &GRPCHandler{opts: opts}
+}
+
+// ServeHTTP handles a gRPC request, answering with its status in the grpc-status and
+// grpc-message trailers.
+func (h *GRPCHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || !strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+		http.Error(w, "gRPC requests only", http.StatusUnsupportedMediaType)
+		return
+	}
+	ctx := r.Context()
+	if timeout, ok := grpcTimeout(r.Header.Get("Grpc-Timeout")); ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	w.Header().Set("Content-Type", "application/grpc+proto")
+	w.WriteHeader(http.StatusOK)
+	stream := &grpcStream{w: w, rc: http.NewResponseController(w)}
+	stream.rc.Flush()
+
+	code, msg := grpcOK, ""
+	if err := h.serve(ctx, r, stream); err != nil {
+		code, msg = grpcStatus(ctx, err)
+	}
+	w.Header().Set(http.TrailerPrefix+"Grpc-Status", strconv.Itoa(code))
+	if msg != "" {
+		w.Header().Set(http.TrailerPrefix+"Grpc-Message", grpcEscape(msg))
+	}
+}
+
+// serve runs the method of r.
+func (h *GRPCHandler) serve(ctx context.Context, r *http.Request, stream *grpcStream) error {
+	token, ok := requestToken(r.Header.Get("X-Figma-Token"), h.opts, h.AllowServerToken)
+	if !ok {
+		return &grpcError{code: grpcUnauthenticated, msg: "missing x-figma-token metadata"}
+	}
+	data, err := readGRPCMessage(r.Body)
+	if err != nil {
+		return err
+	}
+	opts := h.opts
+	opts.AccessToken = token
+
+	switch method := strings.TrimPrefix(r.URL.Path, GRPCPrefix); method {
+	case "ExtractSpecs":
+		var req grpcExtractSpecsRequest
+		if err := req.unmarshal(data); err != nil {
+			return &grpcError{code: grpcInvalidArgument, msg: err.Error()}
+		}
+		return h.extractSpecs(ctx, opts, req, stream)
+	case "ExportImages":
+		var req grpcExportImagesRequest
+		if err := req.unmarshal(data); err != nil {
+			return &grpcError{code: grpcInvalidArgument, msg: err.Error()}
+		}
+		return h.exportImages(ctx, opts, req, stream)
+	case "Diff":
+		var req grpcDiffRequest
+		if err := req.unmarshal(data); err != nil {
+			return &grpcError{code: grpcInvalidArgument, msg: err.Error()}
+		}
+		return h.diff(ctx, opts, req, stream)
+	default:
+		return &grpcError{code: grpcUnimplemented, msg: fmt.Sprintf("unknown method %q", method)}
+	}
+}
+
+// extractSpecs runs ExtractSpecs.
+func (h *GRPCHandler) extractSpecs(ctx context.Context, opts Options, req grpcExtractSpecsRequest, stream *grpcStream) error {
+	opts.FileURL = req.URL
+	opts.NodeIDs = req.NodeIDs
+	opts.Format, opts.Formats = "", req.Formats
+	opts.Version = req.Version
+	opts.OnProgress = stream.progress
+
+	result, err := Run(ctx, opts)
+	if err != nil {
+		return err
+	}
+	var resp protoEncoder
+	resp.message(2, marshalExtractSpecsResult(result))
+	return stream.send(resp)
+}
+
+// exportImages runs ExportImages.
+func (h *GRPCHandler) exportImages(ctx context.Context, opts Options, req grpcExportImagesRequest, stream *grpcStream) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	store := &memoryStore{files: make(map[string][]byte), limit: maxGRPCImages, cancel: cancel}
+	opts.FileURL = req.URL
+	opts.NodeIDs = req.NodeIDs
+	opts.Version = req.Version
+	opts.ExportImages = true
+	opts.ImageFormat = req.Format
+	opts.ImageScales = req.Scales
+	opts.ImageNodes = req.NodePatterns
+	opts.UseExportSettings = req.UseExportSettings
+	opts.NoScreenshot = !req.Screenshot
+	opts.ImageStore = store
+	opts.Incremental = false // nothing of a previous export to keep
+	opts.Format, opts.Formats = "json", nil
+	opts.OnProgress = stream.progress
+
+	result, err := Run(ctx, opts)
+	if exceeded := store.err(); exceeded != nil {
+		return exceeded // rather than the cancellation or the failed downloads it caused
+	}
+	if err != nil {
+		return err
+	}
+	for _, name := range slices.Sorted(maps.Keys(store.files)) {
+		var resp protoEncoder
+		resp.message(2, marshalImage(name, store.files[name]))
+		if err := stream.send(resp); err != nil {
+			return err
+		}
+	}
+	var resp protoEncoder
+	resp.message(3, marshalExportImagesResult(result))
+	return stream.send(resp)
+}
+
+// diff runs Diff.
+func (h *GRPCHandler) diff(ctx context.Context, opts Options, req grpcDiffRequest, stream *grpcStream) error {
+	base, err := ReadSpecs(bytes.NewReader(req.Baseline))
+	if err != nil {
+		return &grpcError{code: grpcInvalidArgument, msg: "baseline: " + err.Error()}
+	}
+	opts.FileURL = req.URL
+	opts.NodeIDs = req.NodeIDs
+	opts.Version = req.Version
+	opts.Format, opts.Formats = "json", nil
+
+	result, err := Run(ctx, opts)
+	if err != nil {
+		return err
+	}
+	return stream.send(marshalDiffResponse(Diff(base, result.Specs)))
+}
+
+// readGRPCMessage reads the single, uncompressed message of a gRPC request.
+func readGRPCMessage(body io.Reader) ([]byte, error) {
+	var prefix [5]byte
+	if _, err := io.ReadFull(body, prefix[:]); err != nil {
+		return nil, &grpcError{code: grpcInvalidArgument, msg: "read request message: " + err.Error()}
+	}
+	if prefix[0] != 0 {
+		return nil, &grpcError{code: grpcUnimplemented, msg: "compressed messages are not supported"}
+	}
+	size := binary.BigEndian.Uint32(prefix[1:])
+	if size > maxGRPCRequest {
+		return nil, &grpcError{code: grpcResourceExhausted, msg: fmt.Sprintf("request message of %d bytes exceeds %d", size, maxGRPCRequest)}
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(body, data); err != nil {
+		return nil, &grpcError{code: grpcInvalidArgument, msg: "read request message: " + err.Error()}
+	}
+	return data, nil
+}
+
+// grpcStream writes the response messages of a gRPC request. Progress events arrive
+// concurrently from the image downloads.
+type grpcStream struct {
+	mu sync.Mutex
+	w  http.ResponseWriter
+	rc *http.ResponseController
+}
+
+// send writes and flushes a response message.
+func (s *grpcStream) send(msg []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var prefix [5]byte
+	binary.BigEndian.PutUint32(prefix[1:], uint32(len(msg)))
+	if _, err := s.w.Write(append(prefix[:], msg...)); err != nil {
+		return err
+	}
+	return s.rc.Flush()
+}
+
+// progress sends e as the progress event, field 1, of a streaming response. A client gone away
+// cancels the run through the request context, so failed sends are ignored.
+func (s *grpcStream) progress(e Event) {
+	var resp protoEncoder
+	resp.message(1, marshalProgress(e))
+	s.send(resp)
+}
+
+// grpcStatus returns the status code and message of a request that failed with err.
+func grpcStatus(ctx context.Context, err error) (int, string) {
+	status := 0
+	if apiErr := (*figma.APIError)(nil); errors.As(err, &apiErr) {
+		status = apiErr.StatusCode
+	}
+	var (
+		grpcErr   *grpcError
+		configErr *ConfigError
+	)
+	switch {
+	case errors.As(err, &grpcErr):
+		return grpcErr.code, grpcErr.msg
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return grpcDeadlineExceeded, err.Error()
+	case ctx.Err() != nil:
+		return grpcCanceled, err.Error()
+	case errors.As(err, &configErr):
+		return grpcInvalidArgument, err.Error()
+	case errors.Is(err, figma.ErrRateLimited):
+		return grpcResourceExhausted, err.Error()
+	case status == http.StatusUnauthorized:
+		return grpcUnauthenticated, err.Error()
+	case status == http.StatusForbidden:
+		return grpcPermissionDenied, err.Error()
+	case status == http.StatusNotFound:
+		return grpcNotFound, err.Error()
+	}
+	return grpcUnknown, err.Error()
+}
+
+// grpcTimeout parses a grpc-timeout header, e.g. "30S" or "500m".
+func grpcTimeout(v string) (time.Duration, bool) {
+	if len(v) < 2 {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(v[:len(v)-1], 10, 64)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	units := map[byte]time.Duration{'H': time.Hour, 'M': time.Minute, 'S': time.Second, 'm': time.Millisecond, 'u': time.Microsecond, 'n': time.Nanosecond}
+	unit, ok := units[v[len(v)-1]]
+	return time.Duration(n) * unit, ok
+}
+
+// grpcEscape percent-encodes a grpc-message, as the gRPC protocol requires for bytes outside
+// printable ASCII.
+func grpcEscape(msg string) string {
+	var sb strings.Builder
+	for i := 0; i < len(msg); i++ {
+		if c := msg[i]; c < ' ' || c > '~' || c == '%' {
+			fmt.Fprintf(&sb, "%%%02X", c)
+		} else {
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
+
+// memoryStore keeps the images of an ExportImages request in memory until they are streamed.
+// Once they exceed limit bytes, it fails the Put and stops the run with cancel.
+type memoryStore struct {
+	mu       sync.Mutex
+	files    map[string][]byte
+	size     int64 // of the files
+	limit    int64
+	exceeded bool
+	cancel   func()
+}
+
+func (s *memoryStore) Put(ctx context.Context, name string, r io.Reader) error {
+	s.mu.Lock()
+	available := s.limit - s.size + int64(len(s.files[name]))
+	s.mu.Unlock()
+	data, err := io.ReadAll(io.LimitReader(r, available+1))
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	size := s.size - int64(len(s.files[name])) + int64(len(data))
+	if size > s.limit {
+		s.exceeded = true
+		s.cancel()
+		return s.exceededError()
+	}
+	s.files[name], s.size = data, size
+	return nil
+}
+
+// err returns the RESOURCE_EXHAUSTED error of a run whose images exceeded the limit, if any.
+func (s *memoryStore) err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.exceeded {
+		return nil
+	}
+	return s.exceededError()
+}
+
+func (s *memoryStore) exceededError() error {
+	return &grpcError{code: grpcResourceExhausted, msg: fmt.Sprintf("exported images exceed %d bytes", s.limit)}
+}
+
+func (s *memoryStore) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.files[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s *memoryStore) Delete(ctx context.Context, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.size -= int64(len(s.files[name]))
+	delete(s.files, name)
+	return nil
+}
+
+func (s *memoryStore) String() string {
+	return "memory"
+}
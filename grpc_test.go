@@ -0,0 +1,595 @@
+package figmaextractor
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hellenic-development/figma-extractor/pkg/figma"
+)
+
+func TestProtoEncoder(t *testing.T) {
+	tests := []struct {
+		name   string
+		encode func(e *protoEncoder)
+		want   []byte
+	}{
+		{"varint", func(e *protoEncoder) { e.int(1, 150) }, []byte{0x08, 0x96, 0x01}},
+		{"negative int32", func(e *protoEncoder) { e.int(3, -1) }, []byte{0x18, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01}},
+		{"string", func(e *protoEncoder) { e.string(2, "testing") }, []byte{0x12, 0x07, 't', 'e', 's', 't', 'i', 'n', 'g'}},
+		{"bytes", func(e *protoEncoder) { e.bytes(3, []byte{0, 1}) }, []byte{0x1a, 0x02, 0, 1}},
+		{"bool", func(e *protoEncoder) { e.bool(7, true) }, []byte{0x38, 0x01}},
+		{"double", func(e *protoEncoder) { e.double(5, 1) }, []byte{0x29, 0, 0, 0, 0, 0, 0, 0xf0, 0x3f}},
+		{"repeated strings keep empty values", func(e *protoEncoder) { e.strings(5, []string{"a", ""}) }, []byte{0x2a, 0x01, 'a', 0x2a, 0x00}},
+		{"empty message", func(e *protoEncoder) { e.message(2, nil) }, []byte{0x12, 0x00}},
+		{"large field number", func(e *protoEncoder) { e.int(16, 1) }, []byte{0x80, 0x01, 0x01}},
+		{
+			name: "zero values are left out",
+			encode: func(e *protoEncoder) {
+				e.int(1, 0)
+				e.string(2, "")
+				e.bytes(3, nil)
+				e.bool(4, false)
+				e.double(5, 0)
+			},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var e protoEncoder
+			tt.encode(&e)
+			if !bytes.Equal(e, tt.want) {
+				t.Errorf("encoded % x, want % x", []byte(e), tt.want)
+			}
+		})
+	}
+}
+
+func TestReadProto(t *testing.T) {
+	var m protoEncoder
+	m.int(1, 300)
+	m.string(2, "name")
+	m.double(3, 2.5)
+	m.tag(4, wireFixed32)
+	m = binary.LittleEndian.AppendUint32(m, 7)
+	m.message(5, nil)
+
+	type field struct {
+		num, wireType int
+		n             uint64
+		b             string
+	}
+	var got []field
+	err := readProto(m, func(f protoField) error {
+		got = append(got, field{f.num, f.wireType, f.n, string(f.b)})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("readProto() error = %v", err)
+	}
+	want := []field{
+		{1, wireVarint, 300, ""},
+		{2, wireBytes, 0, "name"},
+		{3, wireFixed64, math.Float64bits(2.5), ""},
+		{4, wireFixed32, 7, ""},
+		{5, wireBytes, 0, ""},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("readProto() fields = %+v, want %+v", got, want)
+	}
+
+	errs := []struct {
+		name string
+		data []byte
+	}{
+		{"truncated key", []byte{0x80}},
+		{"truncated varint", []byte{0x08, 0x96}},
+		{"truncated fixed64", []byte{0x09, 1, 2, 3}},
+		{"truncated fixed32", []byte{0x0d, 1, 2}},
+		{"truncated length", []byte{0x12, 0x05, 'a'}},
+		{"group wire type", []byte{0x0b}},
+	}
+	for _, tt := range errs {
+		if err := readProto(tt.data, func(protoField) error { return nil }); err == nil {
+			t.Errorf("readProto(%s) returned no error", tt.name)
+		}
+	}
+}
+
+func TestProtoFieldDoubles(t *testing.T) {
+	var packed []byte
+	for _, v := range []float64{1, 2, 0.5} {
+		packed = binary.LittleEndian.AppendUint64(packed, math.Float64bits(v))
+	}
+
+	tests := []struct {
+		name    string
+		field   protoField
+		want    []float64
+		wantErr bool
+	}{
+		{"unpacked", protoField{wireType: wireFixed64, n: math.Float64bits(3)}, []float64{3}, false},
+		{"packed", protoField{wireType: wireBytes, b: packed}, []float64{1, 2, 0.5}, false},
+		{"packed of a wrong length", protoField{wireType: wireBytes, b: packed[:7]}, nil, true},
+		{"varint", protoField{wireType: wireVarint, n: 1}, nil, true},
+	}
+	for _, tt := range tests {
+		got, err := tt.field.doubles()
+		if (err != nil) != tt.wantErr || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("doubles(%s) = %v, %v, want %v, error %v", tt.name, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestGRPCRequestUnmarshal(t *testing.T) {
+	// Field numbers of proto/figmaextractor/v1/extractor.proto, with an unknown field 99.
+	var extract protoEncoder
+	extract.string(1, testFileURL)
+	extract.strings(2, []string{"1:2", "1:3"})
+	extract.strings(3, []string{"css", "json"})
+	extract.string(4, "42")
+	extract.string(99, "from a newer client")
+
+	var gotExtract grpcExtractSpecsRequest
+	if err := gotExtract.unmarshal(extract); err != nil {
+		t.Fatalf("ExtractSpecsRequest unmarshal() error = %v", err)
+	}
+	wantExtract := grpcExtractSpecsRequest{URL: testFileURL, NodeIDs: []string{"1:2", "1:3"}, Formats: []string{"css", "json"}, Version: "42"}
+	if !reflect.DeepEqual(gotExtract, wantExtract) {
+		t.Errorf("ExtractSpecsRequest = %+v, want %+v", gotExtract, wantExtract)
+	}
+
+	var export protoEncoder
+	export.string(1, testFileURL)
+	export.strings(2, []string{"1:2"})
+	export.string(3, "42")
+	export.string(4, "svg")
+	export.bytes(5, binary.LittleEndian.AppendUint64(binary.LittleEndian.AppendUint64(nil, math.Float64bits(1)), math.Float64bits(2)))
+	export.double(5, 3) // unpacked, as older encoders write them
+	export.strings(6, []string{"Icons/*"})
+	export.bool(7, true)
+	export.bool(8, true)
+	export.int(99, 1)
+
+	var gotExport grpcExportImagesRequest
+	if err := gotExport.unmarshal(export); err != nil {
+		t.Fatalf("ExportImagesRequest unmarshal() error = %v", err)
+	}
+	wantExport := grpcExportImagesRequest{
+		URL: testFileURL, NodeIDs: []string{"1:2"}, Version: "42", Format: "svg", Scales: []float64{1, 2, 3},
+		NodePatterns: []string{"Icons/*"}, UseExportSettings: true, Screenshot: true,
+	}
+	if !reflect.DeepEqual(gotExport, wantExport) {
+		t.Errorf("ExportImagesRequest = %+v, want %+v", gotExport, wantExport)
+	}
+
+	var diff protoEncoder
+	diff.string(1, testFileURL)
+	diff.strings(2, []string{"1:2"})
+	diff.string(3, "42")
+	diff.bytes(4, []byte(`{"colors":{}}`))
+
+	var gotDiff grpcDiffRequest
+	if err := gotDiff.unmarshal(diff); err != nil {
+		t.Fatalf("DiffRequest unmarshal() error = %v", err)
+	}
+	wantDiff := grpcDiffRequest{URL: testFileURL, NodeIDs: []string{"1:2"}, Version: "42", Baseline: []byte(`{"colors":{}}`)}
+	if !reflect.DeepEqual(gotDiff, wantDiff) {
+		t.Errorf("DiffRequest = %+v, want %+v", gotDiff, wantDiff)
+	}
+}
+
+func TestGRPCTimeout(t *testing.T) {
+	tests := []struct {
+		header string
+		want   time.Duration
+		ok     bool
+	}{
+		{"1H", time.Hour, true},
+		{"2M", 2 * time.Minute, true},
+		{"30S", 30 * time.Second, true},
+		{"500m", 500 * time.Millisecond, true},
+		{"10u", 10 * time.Microsecond, true},
+		{"5n", 5, true},
+		{"", 0, false},
+		{"S", 0, false},
+		{"5x", 0, false},
+		{"-1S", 0, false},
+		{"1.5S", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := grpcTimeout(tt.header)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("grpcTimeout(%q) = %v, %v, want %v, %v", tt.header, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestGRPCEscape(t *testing.T) {
+	tests := map[string]string{
+		"file not found": "file not found",
+		"100%":           "100%25",
+		"line\nbreak":    "line%0Abreak",
+		"café":           "caf%C3%A9",
+	}
+	for msg, want := range tests {
+		if got := grpcEscape(msg); got != want {
+			t.Errorf("grpcEscape(%q) = %q, want %q", msg, got, want)
+		}
+	}
+}
+
+func TestGRPCStatus(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		err  error
+		want int
+	}{
+		{"grpc error", context.Background(), &grpcError{code: grpcUnimplemented, msg: "x"}, grpcUnimplemented},
+		{"deadline", expired, context.DeadlineExceeded, grpcDeadlineExceeded},
+		{"canceled", canceled, context.Canceled, grpcCanceled},
+		{"config error", context.Background(), &ConfigError{Option: "FileURL", Err: ErrInvalidURL}, grpcInvalidArgument},
+		{"rate limited", context.Background(), fmt.Errorf("fetch: %w", &figma.APIError{StatusCode: http.StatusTooManyRequests}), grpcResourceExhausted},
+		{"unauthorized", context.Background(), &figma.APIError{StatusCode: http.StatusUnauthorized}, grpcUnauthenticated},
+		{"forbidden", context.Background(), fmt.Errorf("fetch: %w", &figma.APIError{StatusCode: http.StatusForbidden}), grpcPermissionDenied},
+		{"not found", context.Background(), &figma.APIError{StatusCode: http.StatusNotFound}, grpcNotFound},
+		{"other", context.Background(), errors.New("boom"), grpcUnknown},
+	}
+	for _, tt := range tests {
+		if code, _ := grpcStatus(tt.ctx, tt.err); code != tt.want {
+			t.Errorf("grpcStatus(%s) = %d, want %d", tt.name, code, tt.want)
+		}
+	}
+}
+
+// grpcCall is the response of a gRPC request sent by callGRPC.
+type grpcCall struct {
+	httpStatus int
+	status     int      // grpc-status trailer
+	message    string   // grpc-message trailer
+	messages   [][]byte // response messages, in order
+}
+
+// callGRPC serves h over unencrypted HTTP/2, as gRPC clients reach the serve command, and
+// calls method with the message msg, framed with the given compressed flag.
+func callGRPC(t *testing.T, h http.Handler, method string, header http.Header, msg []byte, compressed byte) grpcCall {
+	t.Helper()
+
+	server := httptest.NewUnstartedServer(h)
+	server.Config.Protocols = new(http.Protocols)
+	server.Config.Protocols.SetUnencryptedHTTP2(true)
+	server.Start()
+	defer server.Close()
+
+	var protocols http.Protocols
+	protocols.SetUnencryptedHTTP2(true)
+	client := &http.Client{Transport: &http.Transport{Protocols: &protocols}}
+
+	body := append([]byte{compressed, 0, 0, 0, 0}, msg...)
+	binary.BigEndian.PutUint32(body[1:], uint32(len(msg)))
+	req, err := http.NewRequest(http.MethodPost, server.URL+GRPCPrefix+method, bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header = header.Clone()
+	if req.Header == nil {
+		req.Header = make(http.Header)
+	}
+	if req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/grpc")
+	}
+	req.Header.Set("Te", "trailers")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("gRPC request: %v", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read gRPC response: %v", err)
+	}
+
+	call := grpcCall{httpStatus: resp.StatusCode, message: resp.Trailer.Get("Grpc-Message")}
+	if resp.StatusCode != http.StatusOK {
+		return call
+	}
+	if call.status, err = strconv.Atoi(resp.Trailer.Get("Grpc-Status")); err != nil {
+		t.Fatalf("grpc-status trailer %q: %v", resp.Trailer.Get("Grpc-Status"), err)
+	}
+	if got := resp.Header.Get("Content-Type"); got != "application/grpc+proto" {
+		t.Errorf("Content-Type = %q, want application/grpc+proto", got)
+	}
+	for len(data) > 0 {
+		if len(data) < 5 || data[0] != 0 {
+			t.Fatalf("malformed response frame % x", data)
+		}
+		size := binary.BigEndian.Uint32(data[1:5])
+		if uint32(len(data)-5) < size {
+			t.Fatalf("truncated response frame of %d bytes", size)
+		}
+		call.messages = append(call.messages, data[5:5+size])
+		data = data[5+size:]
+	}
+	return call
+}
+
+// protoFields returns the fields of a protobuf message by number.
+func protoFields(t *testing.T, msg []byte) map[int][]protoField {
+	t.Helper()
+	fields := make(map[int][]protoField)
+	if err := readProto(msg, func(f protoField) error {
+		fields[f.num] = append(fields[f.num], f)
+		return nil
+	}); err != nil {
+		t.Fatalf("readProto() error = %v", err)
+	}
+	return fields
+}
+
+// oneof returns the number and content of the single field of a oneof response message.
+func oneof(t *testing.T, msg []byte) (int, []byte) {
+	t.Helper()
+	fields := protoFields(t, msg)
+	if len(fields) != 1 {
+		t.Fatalf("response message has fields %v, want one", fields)
+	}
+	for num, values := range fields {
+		return num, values[0].b
+	}
+	return 0, nil
+}
+
+func TestGRPCHandlerExtractSpecs(t *testing.T) {
+	h := NewGRPCHandler(Options{HTTPClient: stubFigmaAPI(t), NoScreenshot: true})
+
+	var req protoEncoder
+	req.string(1, testFileURL)
+	req.strings(3, []string{"css", "json"})
+	call := callGRPC(t, h, "ExtractSpecs", http.Header{"X-Figma-Token": {"good"}}, req, 0)
+	if call.status != grpcOK {
+		t.Fatalf("grpc-status = %d (%s), want OK", call.status, call.message)
+	}
+	if len(call.messages) < 2 {
+		t.Fatalf("got %d response messages, want progress events and a result", len(call.messages))
+	}
+
+	// Progress events first, then the result.
+	phases := make(map[string]bool)
+	for _, msg := range call.messages[:len(call.messages)-1] {
+		num, progress := oneof(t, msg)
+		if num != 1 {
+			t.Fatalf("response field %d before the result, want progress (1)", num)
+		}
+		fields := protoFields(t, progress)
+		if len(fields[1]) == 0 {
+			t.Errorf("progress event without a kind: %v", fields)
+			continue
+		}
+		if kind := string(fields[1][0].b); kind == string(PhaseCompleted) && len(fields[2]) > 0 {
+			phases[string(fields[2][0].b)] = true
+		}
+	}
+	for _, phase := range []string{"fetch", "extract", "render"} {
+		if !phases[phase] {
+			t.Errorf("no phase-completed event of phase %q, got %v", phase, phases)
+		}
+	}
+
+	num, result := oneof(t, call.messages[len(call.messages)-1])
+	if num != 2 {
+		t.Fatalf("last response field %d, want result (2)", num)
+	}
+	fields := protoFields(t, result)
+	if got := string(fields[1][0].b); got != "ABC123" {
+		t.Errorf("file_key = %q, want ABC123", got)
+	}
+	if got := string(fields[2][0].b); got != "Foundations" {
+		t.Errorf("file_name = %q, want Foundations", got)
+	}
+	if got := string(fields[3][0].b); got != "42" {
+		t.Errorf("version = %q, want 42", got)
+	}
+	var formats []string
+	for _, file := range fields[4] {
+		f := protoFields(t, file.b)
+		formats = append(formats, string(f[1][0].b))
+		if len(f[2]) == 0 || len(f[3]) == 0 {
+			t.Errorf("file %v without a name or content", f)
+		}
+	}
+	if !reflect.DeepEqual(formats, []string{"css", "json"}) {
+		t.Errorf("file formats = %v, want [css json]", formats)
+	}
+}
+
+func TestGRPCHandlerExportImages(t *testing.T) {
+	h := NewGRPCHandler(Options{HTTPClient: stubFigmaAPI(t)})
+
+	var req protoEncoder
+	req.string(1, testFileURL)
+	req.string(4, "png")
+	req.strings(6, []string{"Primary"})
+	call := callGRPC(t, h, "ExportImages", http.Header{"X-Figma-Token": {"good"}}, req, 0)
+	if call.status != grpcOK {
+		t.Fatalf("grpc-status = %d (%s), want OK", call.status, call.message)
+	}
+
+	images := make(map[string]string)
+	downloaded := false
+	for i, msg := range call.messages {
+		num, content := oneof(t, msg)
+		fields := protoFields(t, content)
+		switch {
+		case i == len(call.messages)-1:
+			if num != 3 {
+				t.Fatalf("last response field %d, want result (3)", num)
+			}
+			if len(fields[4]) != 1 {
+				t.Fatalf("result has %d assets, want 1", len(fields[4]))
+			}
+			asset := protoFields(t, fields[4][0].b)
+			if got := string(asset[1][0].b); got != "1:1" {
+				t.Errorf("asset node_id = %q, want 1:1", got)
+			}
+			if got := math.Float64frombits(asset[5][0].n); got != 1 {
+				t.Errorf("asset scale = %g, want 1", got)
+			}
+			if _, ok := images[string(asset[3][0].b)]; !ok {
+				t.Errorf("asset %q was not streamed, got %v", asset[3][0].b, images)
+			}
+		case num == 1:
+			if string(fields[1][0].b) == string(AssetDownloaded) {
+				downloaded = true
+			}
+		case num == 2:
+			images[string(fields[1][0].b)] = string(fields[2][0].b)
+		default:
+			t.Fatalf("response field %d before the result", num)
+		}
+	}
+	if !downloaded {
+		t.Error("no asset-downloaded progress event")
+	}
+	found := false
+	for _, content := range images {
+		found = found || content == "rendered image"
+	}
+	if !found {
+		t.Errorf("streamed images %v lack the rendered image", images)
+	}
+}
+
+func TestMemoryStoreLimit(t *testing.T) {
+	ctx := context.Background()
+	canceled := false
+	store := &memoryStore{files: make(map[string][]byte), limit: 10, cancel: func() { canceled = true }}
+
+	for _, put := range []struct{ name, data string }{{"a.png", "123456"}, {"a.png", "1234"}, {"b.png", "123456"}} {
+		if err := store.Put(ctx, put.name, strings.NewReader(put.data)); err != nil {
+			t.Fatalf("Put(%s) error = %v", put.name, err)
+		}
+	}
+	if err := store.Delete(ctx, "b.png"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := store.Put(ctx, "c.png", strings.NewReader("123456")); err != nil {
+		t.Fatalf("Put() after Delete() error = %v", err)
+	}
+	if canceled || store.err() != nil {
+		t.Fatalf("store of %d bytes exceeded its limit of 10", store.size)
+	}
+
+	err := store.Put(ctx, "d.png", strings.NewReader("1"))
+	if code, _ := grpcStatus(ctx, err); code != grpcResourceExhausted {
+		t.Errorf("Put() beyond the limit error = %v, want RESOURCE_EXHAUSTED", err)
+	}
+	if !canceled {
+		t.Error("Put() beyond the limit did not stop the run")
+	}
+	if code, _ := grpcStatus(ctx, store.err()); code != grpcResourceExhausted {
+		t.Errorf("err() = %v, want RESOURCE_EXHAUSTED", store.err())
+	}
+	if _, err := store.Open(ctx, "d.png"); err == nil {
+		t.Error("Open() found the image beyond the limit")
+	}
+}
+
+func TestGRPCHandlerDiff(t *testing.T) {
+	h := NewGRPCHandler(Options{HTTPClient: stubFigmaAPI(t), NoScreenshot: true})
+
+	var req protoEncoder
+	req.string(1, testFileURL)
+	req.bytes(4, []byte(`{"colors": {}}`))
+	call := callGRPC(t, h, "Diff", http.Header{"X-Figma-Token": {"good"}}, req, 0)
+	if call.status != grpcOK {
+		t.Fatalf("grpc-status = %d (%s), want OK", call.status, call.message)
+	}
+	if len(call.messages) != 1 {
+		t.Fatalf("got %d response messages, want 1", len(call.messages))
+	}
+	fields := protoFields(t, call.messages[0])
+	if len(fields[1]) != 1 || fields[1][0].n != 1 {
+		t.Errorf("changed = %v, want true", fields[1])
+	}
+	added := false
+	for _, change := range fields[2] {
+		c := protoFields(t, change.b)
+		added = added || (string(c[1][0].b) == string(ChangeAdded) && string(c[4][0].b) != "")
+	}
+	if !added {
+		t.Errorf("tokens %v lack an added token", fields[2])
+	}
+}
+
+func TestGRPCHandlerErrors(t *testing.T) {
+	var extract protoEncoder
+	extract.string(1, testFileURL)
+	var invalidURL protoEncoder
+	invalidURL.string(1, "https://example.com/not-figma")
+	var badBaseline protoEncoder
+	badBaseline.string(1, testFileURL)
+	badBaseline.bytes(4, []byte("{"))
+
+	good := http.Header{"X-Figma-Token": {"good"}}
+	tests := []struct {
+		name       string
+		method     string
+		header     http.Header
+		allow      bool
+		msg        []byte
+		compressed byte
+		want       int
+	}{
+		{name: "no token", method: "ExtractSpecs", msg: extract, want: grpcUnauthenticated},
+		{name: "server token", method: "ExtractSpecs", allow: true, msg: extract, want: grpcOK},
+		{name: "rejected token", method: "ExtractSpecs", header: http.Header{"X-Figma-Token": {"bad"}}, msg: extract, want: grpcPermissionDenied},
+		{name: "unknown method", method: "Render", header: good, msg: extract, want: grpcUnimplemented},
+		{name: "compressed", method: "ExtractSpecs", header: good, msg: extract, compressed: 1, want: grpcUnimplemented},
+		{name: "malformed message", method: "ExtractSpecs", header: good, msg: []byte{0x0b}, want: grpcInvalidArgument},
+		{name: "invalid url", method: "ExtractSpecs", header: good, msg: invalidURL, want: grpcInvalidArgument},
+		{name: "invalid baseline", method: "Diff", header: good, msg: badBaseline, want: grpcInvalidArgument},
+		{name: "deadline", method: "ExtractSpecs", header: http.Header{"X-Figma-Token": {"good"}, "Grpc-Timeout": {"1n"}}, msg: extract, want: grpcDeadlineExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewGRPCHandler(Options{AccessToken: "good", HTTPClient: stubFigmaAPI(t), NoScreenshot: true})
+			h.AllowServerToken = tt.allow
+			call := callGRPC(t, h, tt.method, tt.header, tt.msg, tt.compressed)
+			if call.status != tt.want {
+				t.Errorf("grpc-status = %d (%s), want %d", call.status, call.message, tt.want)
+			}
+			if tt.want != grpcOK && call.message == "" {
+				t.Error("grpc-message trailer not set")
+			}
+		})
+	}
+
+	t.Run("not grpc", func(t *testing.T) {
+		h := NewGRPCHandler(Options{})
+		call := callGRPC(t, h, "ExtractSpecs", http.Header{"Content-Type": {"application/json"}}, extract, 0)
+		if call.httpStatus != http.StatusUnsupportedMediaType {
+			t.Errorf("HTTP status = %d, want %d", call.httpStatus, http.StatusUnsupportedMediaType)
+		}
+	})
+}
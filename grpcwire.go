@@ -0,0 +1,324 @@
+package figmaextractor
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// The messages of proto/figmaextractor/v1/extractor.proto, encoded and decoded by hand in the
+// protobuf wire format so that serving gRPC needs no code generation nor dependencies.
+
+// Protobuf wire types.
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+var errProtoTruncated = errors.New("truncated protobuf message")
+
+// protoEncoder appends the fields of a protobuf message. Like proto3, it leaves out the fields
+// holding their zero value.
+type protoEncoder []byte
+
+func (e *protoEncoder) tag(field, wireType int) {
+	*e = binary.AppendUvarint(*e, uint64(field)<<3|uint64(wireType))
+}
+
+func (e *protoEncoder) bytes(field int, b []byte) {
+	if len(b) > 0 {
+		e.message(field, b)
+	}
+}
+
+func (e *protoEncoder) string(field int, s string) {
+	if s != "" {
+		e.tag(field, wireBytes)
+		*e = binary.AppendUvarint(*e, uint64(len(s)))
+		*e = append(*e, s...)
+	}
+}
+
+func (e *protoEncoder) strings(field int, values []string) {
+	for _, s := range values {
+		// Repeated strings keep their empty values.
+		e.tag(field, wireBytes)
+		*e = binary.AppendUvarint(*e, uint64(len(s)))
+		*e = append(*e, s...)
+	}
+}
+
+func (e *protoEncoder) int(field, v int) {
+	if v != 0 {
+		e.tag(field, wireVarint)
+		*e = binary.AppendUvarint(*e, uint64(int64(v)))
+	}
+}
+
+func (e *protoEncoder) bool(field int, v bool) {
+	if v {
+		e.int(field, 1)
+	}
+}
+
+func (e *protoEncoder) double(field int, v float64) {
+	if v != 0 {
+		e.tag(field, wireFixed64)
+		*e = binary.LittleEndian.AppendUint64(*e, math.Float64bits(v))
+	}
+}
+
+// message appends an embedded message, even an empty one, as the oneof fields require.
+func (e *protoEncoder) message(field int, m []byte) {
+	e.tag(field, wireBytes)
+	*e = binary.AppendUvarint(*e, uint64(len(m)))
+	*e = append(*e, m...)
+}
+
+// protoField is a field read from a protobuf message: its number, and its value, a number for
+// varint and fixed fields or the bytes of length-delimited ones.
+type protoField struct {
+	num      int
+	wireType int
+	n        uint64
+	b        []byte
+}
+
+// readProto calls fn with every field of the protobuf message data, in order.
+func readProto(data []byte, fn func(f protoField) error) error {
+	for len(data) > 0 {
+		key, size := binary.Uvarint(data)
+		if size <= 0 {
+			return errProtoTruncated
+		}
+		data = data[size:]
+		f := protoField{num: int(key >> 3), wireType: int(key & 7)}
+		switch f.wireType {
+		case wireVarint:
+			if f.n, size = binary.Uvarint(data); size <= 0 {
+				return errProtoTruncated
+			}
+			data = data[size:]
+		case wireFixed64:
+			if len(data) < 8 {
+				return errProtoTruncated
+			}
+			f.n, data = binary.LittleEndian.Uint64(data), data[8:]
+		case wireFixed32:
+			if len(data) < 4 {
+				return errProtoTruncated
+			}
+			f.n, data = uint64(binary.LittleEndian.Uint32(data)), data[4:]
+		case wireBytes:
+			length, size := binary.Uvarint(data)
+			if size <= 0 || uint64(len(data)-size) < length {
+				return errProtoTruncated
+			}
+			f.b, data = data[size:size+int(length)], data[size+int(length):]
+		default:
+			return fmt.Errorf("unsupported protobuf wire type %d", f.wireType)
+		}
+		if err := fn(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// doubles returns the doubles of a repeated double field, packed (the proto3 default) or not.
+func (f protoField) doubles() ([]float64, error) {
+	if f.wireType == wireFixed64 {
+		return []float64{math.Float64frombits(f.n)}, nil
+	}
+	if f.wireType != wireBytes || len(f.b)%8 != 0 {
+		return nil, fmt.Errorf("field %d: invalid repeated double", f.num)
+	}
+	values := make([]float64, 0, len(f.b)/8)
+	for b := f.b; len(b) > 0; b = b[8:] {
+		values = append(values, math.Float64frombits(binary.LittleEndian.Uint64(b)))
+	}
+	return values, nil
+}
+
+// ExtractSpecsRequest, ExportImagesRequest and DiffRequest. Unknown fields are skipped, as
+// protobuf requires for newer clients.
+
+type grpcExtractSpecsRequest struct {
+	URL     string
+	NodeIDs []string
+	Formats []string
+	Version string
+}
+
+func (r *grpcExtractSpecsRequest) unmarshal(data []byte) error {
+	return readProto(data, func(f protoField) error {
+		switch f.num {
+		case 1:
+			r.URL = string(f.b)
+		case 2:
+			r.NodeIDs = append(r.NodeIDs, string(f.b))
+		case 3:
+			r.Formats = append(r.Formats, string(f.b))
+		case 4:
+			r.Version = string(f.b)
+		}
+		return nil
+	})
+}
+
+type grpcExportImagesRequest struct {
+	URL               string
+	NodeIDs           []string
+	Version           string
+	Format            string
+	Scales            []float64
+	NodePatterns      []string
+	UseExportSettings bool
+	Screenshot        bool
+}
+
+func (r *grpcExportImagesRequest) unmarshal(data []byte) error {
+	return readProto(data, func(f protoField) error {
+		switch f.num {
+		case 1:
+			r.URL = string(f.b)
+		case 2:
+			r.NodeIDs = append(r.NodeIDs, string(f.b))
+		case 3:
+			r.Version = string(f.b)
+		case 4:
+			r.Format = string(f.b)
+		case 5:
+			scales, err := f.doubles()
+			if err != nil {
+				return err
+			}
+			r.Scales = append(r.Scales, scales...)
+		case 6:
+			r.NodePatterns = append(r.NodePatterns, string(f.b))
+		case 7:
+			r.UseExportSettings = f.n != 0
+		case 8:
+			r.Screenshot = f.n != 0
+		}
+		return nil
+	})
+}
+
+type grpcDiffRequest struct {
+	URL      string
+	NodeIDs  []string
+	Version  string
+	Baseline []byte
+}
+
+func (r *grpcDiffRequest) unmarshal(data []byte) error {
+	return readProto(data, func(f protoField) error {
+		switch f.num {
+		case 1:
+			r.URL = string(f.b)
+		case 2:
+			r.NodeIDs = append(r.NodeIDs, string(f.b))
+		case 3:
+			r.Version = string(f.b)
+		case 4:
+			r.Baseline = f.b
+		}
+		return nil
+	})
+}
+
+// marshalProgress returns the Progress message of e.
+func marshalProgress(e Event) []byte {
+	var m protoEncoder
+	m.string(1, string(e.Kind))
+	m.string(2, e.Phase)
+	m.int(3, e.Count)
+	m.string(4, e.NodeID)
+	m.string(5, e.NodeName)
+	m.string(6, e.FileName)
+	m.int(7, e.Completed)
+	m.int(8, e.Total)
+	return m
+}
+
+// marshalExtractSpecsResult returns the ExtractSpecsResult message of result.
+func marshalExtractSpecsResult(result *Result) []byte {
+	var m protoEncoder
+	m.string(1, result.FileKey)
+	m.string(2, result.FileName)
+	m.string(3, result.Version)
+	for _, output := range result.Outputs {
+		for _, file := range output.Files {
+			var f protoEncoder
+			f.string(1, output.Format)
+			f.string(2, file.Name)
+			f.bytes(3, file.Content)
+			m.message(4, f)
+		}
+	}
+	m.strings(5, warningStrings(result.Warnings))
+	return m
+}
+
+// marshalImage returns the Image message of the stored file name.
+func marshalImage(name string, content []byte) []byte {
+	var m protoEncoder
+	m.string(1, name)
+	m.bytes(2, content)
+	return m
+}
+
+// marshalExportImagesResult returns the ExportImagesResult message of result.
+func marshalExportImagesResult(result *Result) []byte {
+	var m protoEncoder
+	m.string(1, result.FileKey)
+	m.string(2, result.FileName)
+	m.string(3, result.Version)
+	for _, asset := range result.Specs.ExportedAssets {
+		var a protoEncoder
+		a.string(1, asset.NodeID)
+		a.string(2, asset.NodeName)
+		a.string(3, asset.FileName)
+		a.string(4, asset.Format)
+		a.double(5, asset.Scale)
+		m.message(4, a)
+	}
+	m.strings(5, warningStrings(result.Warnings))
+	return m
+}
+
+// marshalDiffResponse returns the DiffResponse message of report.
+func marshalDiffResponse(report *DiffReport) []byte {
+	var m protoEncoder
+	m.bool(1, report.Changed())
+	for _, change := range report.Tokens {
+		m.message(2, marshalChange(change))
+	}
+	for _, change := range report.Components {
+		m.message(3, marshalChange(change))
+	}
+	return m
+}
+
+// marshalChange returns the Change message of change.
+func marshalChange(change Change) []byte {
+	var m protoEncoder
+	m.string(1, string(change.Kind))
+	m.string(2, change.Name)
+	m.string(3, change.Before)
+	m.string(4, change.After)
+	return m
+}
+
+// warningStrings returns the messages of warnings.
+func warningStrings(warnings []Warning) []string {
+	messages := make([]string, 0, len(warnings))
+	for _, w := range warnings {
+		messages = append(messages, w.String())
+	}
+	return messages
+}
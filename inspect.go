@@ -0,0 +1,92 @@
+package figmaextractor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hellenic-development/figma-extractor/pkg/figma"
+)
+
+// OutlineNode is a page, section, frame, component set or component of a FileOutline.
+type OutlineNode struct {
+	ID       string        `json:"id"`
+	Name     string        `json:"name"`
+	Type     string        `json:"type"` // Figma node type, e.g. "CANVAS" for pages
+	Children []OutlineNode `json:"children,omitempty"`
+}
+
+// FileOutline is the hierarchy of the pages, frames and components of a Figma file.
+type FileOutline struct {
+	Name    string        `json:"name"`
+	Version string        `json:"version"`
+	Pages   []OutlineNode `json:"pages"`
+}
+
+// Inspect fetches the file of opts.FileURL, at opts.Version, and returns the outline of its
+// pages, the sections and top-level frames of every page and the component sets and components
+// at any depth, with their node IDs, to find the NodeIDs to extract. depth limits the levels of
+// the document fetched, for large files: 1 for the pages, 2 for their top-level frames; 0
+// fetches the whole document.
+func Inspect(ctx context.Context, opts Options, depth int) (*FileOutline, error) {
+	if _, err := opts.prepare(true); err != nil {
+		return nil, err
+	}
+	fileKey, err := figma.ExtractFileKey(opts.FileURL)
+	if err != nil {
+		return nil, fmt.Errorf("extract file key: %w", err)
+	}
+	client, err := opts.newClient()
+	if err != nil {
+		return nil, err
+	}
+	if opts.Version != "" {
+		version, err := resolveVersion(ctx, client, fileKey, opts.Version)
+		if err != nil {
+			return nil, err
+		}
+		client = client.WithVersion(version)
+	}
+
+	fileResp, err := client.WithDepth(depth).GetFile(ctx, fileKey)
+	if err != nil {
+		return nil, fmt.Errorf("fetch file: %w", err)
+	}
+	return &FileOutline{
+		Name:    fileResp.Name,
+		Version: fileResp.Version,
+		Pages:   Outline(&fileResp.Document),
+	}, nil
+}
+
+// Outline returns the outline of the pages of a document: the sections and top-level frames of
+// every page and the component sets and components at any depth. Other nodes, such as groups,
+// are left out with their outlined descendants taking their place; instances are not entered.
+func Outline(document *figma.Node) []OutlineNode {
+	var walk func(node *figma.Node, topLevel bool) []OutlineNode
+	walk = func(node *figma.Node, topLevel bool) []OutlineNode {
+		var outline []OutlineNode
+		for i := range node.Children {
+			child := &node.Children[i]
+			entry := OutlineNode{ID: child.ID, Name: child.Name, Type: child.Type}
+			switch {
+			case child.Type == "INSTANCE":
+				continue
+			case child.Type == "COMPONENT_SET", child.Type == "COMPONENT":
+				// Component sets list their variants; components hold no further outline.
+				if child.Type == "COMPONENT_SET" {
+					entry.Children = walk(child, false)
+				}
+			case child.Type == "CANVAS", child.Type == "SECTION":
+				entry.Children = walk(child, true)
+			case child.Type == "FRAME" && topLevel:
+				entry.Children = walk(child, false)
+			default:
+				outline = append(outline, walk(child, topLevel)...)
+				continue
+			}
+			outline = append(outline, entry)
+		}
+		return outline
+	}
+	return walk(document, true)
+}
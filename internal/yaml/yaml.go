@@ -0,0 +1,176 @@
+// Package yaml parses the subset of YAML read from the rules and batch files: block lists and
+// "key: value" pairs of plain, single-quoted and double-quoted scalars or flow lists of them,
+// with comments. Nesting is left to the caller, which reads the indentation of each line.
+package yaml
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Line is a significant line of a document: not blank, a comment or a "---" marker.
+type Line struct {
+	Num    int      // line number, from 1
+	Indent int      // columns before the content, or before the "-" of a list item
+	Item   bool     // the line starts a list item, "- ..."
+	Pair   bool     // the line holds a "key: value" pair
+	Key    string   // key of the pair
+	Value  string   // value of the pair, or the scalar of the list item; empty for a flow list
+	Items  []string // items of a flow list value, "[a, b]"; nil for scalar values
+}
+
+// Parse returns the significant lines of data. The errors of malformed lines carry their
+// line number.
+func Parse(data []byte) ([]Line, error) {
+	var lines []Line
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for num := 1; scanner.Scan(); num++ {
+		text := strings.TrimRight(scanner.Text(), " \t")
+		content := strings.TrimLeft(text, " \t")
+		if content == "" || content == "---" || content[0] == '#' {
+			continue
+		}
+
+		line := Line{Num: num, Indent: len(text) - len(content)}
+		if content == "-" || strings.HasPrefix(content, "- ") {
+			line.Item = true
+			content = strings.TrimLeft(content[1:], " \t")
+		}
+		if err := line.parse(content); err != nil {
+			return nil, fmt.Errorf("line %d: %w", num, err)
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// parse reads the content of l after its indentation and list marker.
+func (l *Line) parse(s string) error {
+	if s == "" || s[0] == '#' {
+		return nil
+	}
+	key, rest, isKey, err := scalar(s, true)
+	if err != nil {
+		return err
+	}
+	if !isKey {
+		l.Value = key
+		return trailing(rest)
+	}
+
+	l.Pair, l.Key = true, key
+	rest = strings.TrimLeft(rest, " \t")
+	switch {
+	case rest == "" || rest[0] == '#':
+		return nil
+	case rest[0] == '[':
+		if l.Items, rest, err = flow(rest); err != nil {
+			return err
+		}
+	default:
+		if l.Value, rest, _, err = scalar(rest, false); err != nil {
+			return err
+		}
+	}
+	return trailing(rest)
+}
+
+// scalar reads the scalar at the start of s and returns it with the text after it. With
+// key set, a scalar followed by ": " or a final ":" is a key and the colon is consumed.
+// Plain scalars end at a comment, " #".
+func scalar(s string, key bool) (value, rest string, isKey bool, err error) {
+	if s[0] == '"' || s[0] == '\'' {
+		if value, rest, err = quoted(s); err != nil {
+			return "", "", false, err
+		}
+		rest = strings.TrimLeft(rest, " \t")
+		if key && (rest == ":" || strings.HasPrefix(rest, ": ")) {
+			return value, rest[1:], true, nil
+		}
+		return value, rest, false, nil
+	}
+
+	for i := 0; i < len(s); i++ {
+		switch {
+		case key && s[i] == ':' && (i+1 == len(s) || s[i+1] == ' ' || s[i+1] == '\t'):
+			return strings.TrimSpace(s[:i]), s[i+1:], true, nil
+		case s[i] == '#' && i > 0 && (s[i-1] == ' ' || s[i-1] == '\t'):
+			return strings.TrimSpace(s[:i]), s[i:], false, nil
+		}
+	}
+	return strings.TrimSpace(s), "", false, nil
+}
+
+// quoted reads the single-quoted or double-quoted scalar at the start of s.
+func quoted(s string) (value, rest string, err error) {
+	if s[0] == '"' {
+		prefix, err := strconv.QuotedPrefix(s)
+		if err != nil {
+			return "", "", fmt.Errorf("unterminated string %s", s)
+		}
+		value, _ = strconv.Unquote(prefix)
+		return value, s[len(prefix):], nil
+	}
+
+	for i := 1; i < len(s); i++ {
+		if s[i] != '\'' {
+			continue
+		}
+		if i+1 < len(s) && s[i+1] == '\'' {
+			i++
+			continue
+		}
+		return strings.ReplaceAll(s[1:i], "''", "'"), s[i+1:], nil
+	}
+	return "", "", fmt.Errorf("unterminated string %s", s)
+}
+
+// flow reads the flow list at the start of s, "[a, 'b', "c"]".
+func flow(s string) (items []string, rest string, err error) {
+	items = []string{}
+	s = s[1:]
+	for {
+		s = strings.TrimLeft(s, " \t")
+		switch {
+		case s == "":
+			return nil, "", errors.New("unterminated list, missing \"]\"")
+		case s[0] == ']':
+			return items, s[1:], nil
+		case s[0] == '"' || s[0] == '\'':
+			var item string
+			if item, s, err = quoted(s); err != nil {
+				return nil, "", err
+			}
+			items = append(items, item)
+		default:
+			i := strings.IndexAny(s, ",]")
+			if i < 0 {
+				return nil, "", errors.New("unterminated list, missing \"]\"")
+			}
+			if item := strings.TrimSpace(s[:i]); item != "" {
+				items = append(items, item)
+			}
+			s = s[i:]
+		}
+
+		s = strings.TrimLeft(s, " \t")
+		switch {
+		case strings.HasPrefix(s, ","):
+			s = s[1:]
+		case !strings.HasPrefix(s, "]"):
+			return nil, "", fmt.Errorf("unexpected %q in list", s)
+		}
+	}
+}
+
+// trailing checks that only a comment follows a value.
+func trailing(rest string) error {
+	if rest = strings.TrimSpace(rest); rest != "" && rest[0] != '#' {
+		return fmt.Errorf("unexpected %q", rest)
+	}
+	return nil
+}
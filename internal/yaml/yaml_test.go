@@ -0,0 +1,69 @@
+package yaml
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	data := `# header comment
+---
+plain: value
+"quoted key": 'it''s quoted' # comment
+'single': "double \"escaped\""
+
+url: https://www.figma.com/design/ABC123/Foundations?node-id=1:2 # trailing
+  - https://www.figma.com/design/DEF456/Marketing
+- name: Brand/*:suffix
+  nodes: [1:2, "1:3" , '1:4',]
+  empty:
+-
+	tab: value#not-a-comment # comment
+`
+	want := []Line{
+		{Num: 3, Pair: true, Key: "plain", Value: "value"},
+		{Num: 4, Pair: true, Key: "quoted key", Value: "it's quoted"},
+		{Num: 5, Pair: true, Key: "single", Value: `double "escaped"`},
+		{Num: 7, Pair: true, Key: "url", Value: "https://www.figma.com/design/ABC123/Foundations?node-id=1:2"},
+		{Num: 8, Indent: 2, Item: true, Value: "https://www.figma.com/design/DEF456/Marketing"},
+		{Num: 9, Item: true, Pair: true, Key: "name", Value: "Brand/*:suffix"},
+		{Num: 10, Indent: 2, Pair: true, Key: "nodes", Items: []string{"1:2", "1:3", "1:4"}},
+		{Num: 11, Indent: 2, Pair: true, Key: "empty"},
+		{Num: 12, Item: true},
+		{Num: 13, Indent: 1, Pair: true, Key: "tab", Value: "value#not-a-comment"},
+	}
+
+	got, err := Parse([]byte(data))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"unterminated double quote", "a: b\n\n\"key: value\n", `line 3: unterminated string "key: value`},
+		{"unterminated single quote", "# c\nkey: 'value\n", `line 2: unterminated string 'value`},
+		{"text after quoted value", "key: \"value\" extra\n", `line 1: unexpected "extra"`},
+		{"text after quoted key", "\"key\" value\n", `line 1: unexpected "value"`},
+		{"unterminated flow list", "a: b\nnodes: [1:2, 1:3\n", `line 2: unterminated list, missing "]"`},
+		{"text after flow list", "nodes: [1:2] 1:3\n", `line 1: unexpected "1:3"`},
+		{"garbage in flow list", "nodes: ['1:2' 1:3]\n", `line 1: unexpected "1:3]" in list`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package figmaextractor
+
+import (
+	"context"
+
+	"github.com/hellenic-development/figma-extractor/pkg/extractor"
+	"github.com/hellenic-development/figma-extractor/pkg/figma"
+)
+
+// resolveLibraryStyles names the team library styles the extracted nodes use after their
+// published library names. The published styles of opts.TeamID are fetched first; styles not
+// found there, or all of them without a team, are looked up one by one. Failures are logged and
+// leave the affected styles as they are.
+func resolveLibraryStyles(ctx context.Context, opts *Options, client *figma.Client, fileResp *figma.FileResponse, nodesResp *figma.NodesResponse, targetNodeIDs []string) {
+	roots := []*figma.Node{&fileResp.Document}
+	styles := []map[string]figma.Style{fileResp.Styles}
+	if len(targetNodeIDs) > 0 {
+		roots = roots[:0]
+		for _, id := range targetNodeIDs {
+			if nd, ok := nodesResp.Nodes[id]; ok {
+				roots = append(roots, &nd.Document)
+				styles = append(styles, nd.Styles)
+			}
+		}
+	}
+
+	var keys []string
+	for _, s := range styles {
+		keys = append(keys, extractor.LibraryStyleKeys(s, roots)...)
+	}
+	if len(keys) == 0 {
+		return
+	}
+	opts.logInfo("Resolving %d team library style(s)...", len(keys))
+
+	library := make(map[string]figma.StyleMetadata)
+	if opts.TeamID != "" {
+		for after := 0; ; {
+			page, err := client.GetTeamStyles(ctx, opts.TeamID, after)
+			if err != nil {
+				opts.warnf(WarningAPI, "Team styles API failed: %v", err)
+				break
+			}
+			for _, meta := range page.Meta.Styles {
+				library[meta.Key] = meta
+			}
+			if page.Meta.Cursor == nil || page.Meta.Cursor.After == 0 || page.Meta.Cursor.After == after {
+				break
+			}
+			after = page.Meta.Cursor.After
+		}
+	}
+	for _, key := range keys {
+		if _, ok := library[key]; ok {
+			continue
+		}
+		styleResp, err := client.GetStyle(ctx, key)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			opts.warnf(WarningAPI, "Could not resolve library style %s: %v", key, err)
+			continue
+		}
+		library[key] = styleResp.Meta
+	}
+
+	// Node-level lookups take precedence over the file's, so both are updated.
+	if fileResp.Styles == nil {
+		fileResp.Styles = make(map[string]figma.Style)
+	}
+	resolved := extractor.MergeLibraryStyles(fileResp.Styles, roots, library)
+	for _, id := range targetNodeIDs {
+		if nd, ok := nodesResp.Nodes[id]; ok && nd.Styles != nil {
+			extractor.MergeLibraryStyles(nd.Styles, roots, library)
+		}
+	}
+	opts.logInfo("Resolved %d library style(s)", resolved)
+}
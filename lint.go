@@ -0,0 +1,307 @@
+package figmaextractor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hellenic-development/figma-extractor/pkg/extractor"
+	"github.com/hellenic-development/figma-extractor/pkg/formatter"
+)
+
+// Kinds of token files Lint compares the design with.
+const (
+	TokensCSS      = "css"      // CSS custom properties, e.g. a tokens.css written by the css format
+	TokensTailwind = "tailwind" // a Tailwind config: theme (and theme.extend) colors, spacing, ...
+	TokensDTCG     = "dtcg"     // W3C Design Tokens JSON, e.g. a tokens.json written by the dtcg format
+)
+
+// TokenFileKind guesses the kind of a token file from its name: "tailwind" for tailwind.config.*,
+// "dtcg" for JSON files and "css" otherwise.
+func TokenFileKind(path string) string {
+	base := strings.ToLower(filepath.Base(path))
+	switch {
+	case strings.HasPrefix(base, "tailwind.config."):
+		return TokensTailwind
+	case strings.HasSuffix(base, ".json"):
+		return TokensDTCG
+	}
+	return TokensCSS
+}
+
+// Drift is a token of the design that the code lacks or defines differently.
+type Drift struct {
+	Token  string // token name, e.g. "color-primary-500" ("color.primary.500" for DTCG)
+	Design string // value in the Figma file
+	Code   string // value in the code; empty when the code lacks the token
+}
+
+// LintReport lists how the tokens of the code drifted from the design.
+type LintReport struct {
+	Checked  int     // design tokens compared
+	Missing  []Drift // design tokens the code lacks, sorted by name
+	Diverged []Drift // design tokens the code defines with another value, sorted by name
+}
+
+// Drifted reports whether the code drifted from the design.
+func (r *LintReport) Drifted() bool {
+	return len(r.Missing) > 0 || len(r.Diverged) > 0
+}
+
+// Lint compares the tokens of the design with an existing token file of the given kind
+// (TokensCSS, TokensTailwind or TokensDTCG) and reports the design tokens the code lacks or
+// defines with another value. Tokens are matched by the names the css format gives them (for
+// CSS and Tailwind, whose theme keys map onto them, e.g. colors.primary.500 onto
+// --color-primary-500) or the dtcg format (for DTCG). A Tailwind config is compared with the
+// tokens the tailwind format writes, so categories Tailwind has no theme key for, such as
+// borders, are not checked, and font stacks are compared by their first family. Values are
+// compared ignoring case, whitespace and quotes, with short hex colors expanded. Tokens only
+// the code has are not reported.
+func Lint(specs *extractor.DesignSpecs, code io.Reader, kind string) (*LintReport, error) {
+	data, err := io.ReadAll(code)
+	if err != nil {
+		return nil, fmt.Errorf("read tokens: %w", err)
+	}
+
+	var design, actual map[string]string
+	switch kind {
+	case TokensCSS:
+		design = cssTokens(formatter.ToCSS(specs, ""))
+		actual = cssTokens(string(data))
+	case TokensTailwind:
+		design = tailwindTokens(formatter.ToTailwind(specs, ""))
+		actual = tailwindTokens(string(data))
+	case TokensDTCG:
+		out, err := formatter.ToDTCG(specs)
+		if err != nil {
+			return nil, err
+		}
+		if design, err = dtcgTokens(out); err != nil {
+			return nil, err
+		}
+		if actual, err = dtcgTokens(data); err != nil {
+			return nil, fmt.Errorf("parse tokens: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown token file kind %q (must be one of %s, %s, %s)", kind, TokensCSS, TokensTailwind, TokensDTCG)
+	}
+
+	report := &LintReport{Checked: len(design)}
+	names := make([]string, 0, len(design))
+	for name := range design {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value, ok := actual[name]
+		switch {
+		case !ok:
+			report.Missing = append(report.Missing, Drift{Token: name, Design: design[name]})
+		case normalizeTokenValue(value) != normalizeTokenValue(design[name]):
+			report.Diverged = append(report.Diverged, Drift{Token: name, Design: design[name], Code: value})
+		}
+	}
+	return report, nil
+}
+
+// cssCustomProperty matches a custom property declaration.
+var cssCustomProperty = regexp.MustCompile(`--([A-Za-z0-9_-]+)\s*:\s*([^;}]+)`)
+
+// cssComment matches a CSS or JavaScript block comment.
+var cssComment = regexp.MustCompile(`(?s)/\*.*?\*/`)
+
+// cssTokens returns the custom properties of a stylesheet by name, without the leading dashes.
+// The first declaration of a name wins, which is the :root one in generated stylesheets.
+func cssTokens(css string) map[string]string {
+	tokens := make(map[string]string)
+	for _, m := range cssCustomProperty.FindAllStringSubmatch(cssComment.ReplaceAllString(css, ""), -1) {
+		if _, ok := tokens[m[1]]; !ok {
+			tokens[m[1]] = strings.TrimSpace(m[2])
+		}
+	}
+	return tokens
+}
+
+// tailwindPrefixes maps Tailwind theme keys onto the prefixes of the css format's token names.
+var tailwindPrefixes = map[string]string{
+	"colors":        "color",
+	"spacing":       "space",
+	"borderRadius":  "radius",
+	"fontSize":      "text",
+	"fontWeight":    "font",
+	"fontFamily":    "font",
+	"lineHeight":    "leading",
+	"boxShadow":     "shadow",
+	"blur":          "blur",
+	"screens":       "breakpoint",
+	"letterSpacing": "tracking",
+}
+
+// tailwindTokens returns the string and number values of the theme sections of a Tailwind
+// config named like the css format's tokens, e.g. theme.extend.colors.primary.500 as
+// "color-primary-500". DEFAULT keys name their parent. The config is scanned as an object
+// literal, without evaluating it: computed values are skipped, and arrays (such as font size
+// and line height pairs) contribute their first element. Font families are reduced to the
+// first family of their stack.
+func tailwindTokens(config string) map[string]string {
+	tokens := make(map[string]string)
+	var path []string
+	var key string // last key, until its value is read
+
+	record := func(value string) {
+		if key == "" {
+			return
+		}
+		full := append(append([]string(nil), path...), key)
+		for i, segment := range full {
+			prefix, ok := tailwindPrefixes[segment]
+			if !ok || i+1 >= len(full) {
+				continue
+			}
+			name := []string{prefix}
+			for _, s := range full[i+1:] {
+				if s != "DEFAULT" {
+					name = append(name, s)
+				}
+			}
+			if segment == "fontFamily" {
+				value = firstFontFamily(value)
+			}
+			if _, exists := tokens[strings.Join(name, "-")]; !exists {
+				tokens[strings.Join(name, "-")] = value
+			}
+			break
+		}
+		key = ""
+	}
+
+	toks := jsTokens(config)
+	for i := 0; i < len(toks); i++ {
+		t := toks[i]
+		switch {
+		case t == "{":
+			if key != "" {
+				path = append(path, key)
+				key = ""
+			} else {
+				path = append(path, "")
+			}
+		case t == "}":
+			if len(path) > 0 {
+				path = path[:len(path)-1]
+			}
+			key = ""
+		case t == "[":
+			// The first element of an array value, then skip to its end. Brackets that do not
+			// follow a key index a computed value.
+			depth := 1
+			if i > 0 && toks[i-1] == ":" && i+1 < len(toks) && isJSLiteral(toks[i+1]) {
+				record(jsValue(toks[i+1]))
+			}
+			for i++; i < len(toks) && depth > 0; i++ {
+				switch toks[i] {
+				case "[", "{":
+					depth++
+				case "]", "}":
+					depth--
+				}
+			}
+			i--
+			key = ""
+		case i+1 < len(toks) && toks[i+1] == ":" && (isJSLiteral(t) || isJSIdent(t)):
+			key = jsValue(t)
+			i++
+		case key != "" && isJSLiteral(t) && (i+1 >= len(toks) || toks[i+1] == "," || toks[i+1] == "}"):
+			record(jsValue(t))
+		case t == ",":
+			key = ""
+		}
+	}
+	return tokens
+}
+
+// firstFontFamily returns the first family of a font stack, "Inter" for "'Inter', sans-serif".
+func firstFontFamily(stack string) string {
+	family, _, _ := strings.Cut(stack, ",")
+	return strings.Trim(strings.TrimSpace(family), `"'`)
+}
+
+// jsToken matches a token of a JavaScript object literal: a string, a number, an identifier or
+// punctuation. Comments are removed before.
+var jsToken = regexp.MustCompile(`"(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*'|` + "`[^`]*`" + `|-?[0-9][0-9.]*[A-Za-z%]*|[A-Za-z_$][A-Za-z0-9_$]*|[{}\[\]:,()]`)
+
+// jsLineComment matches a JavaScript line comment that does not start inside a URL.
+var jsLineComment = regexp.MustCompile(`(?m)(^|[^:])//.*$`)
+
+func jsTokens(src string) []string {
+	src = cssComment.ReplaceAllString(src, "")
+	src = jsLineComment.ReplaceAllString(src, "$1")
+	return jsToken.FindAllString(src, -1)
+}
+
+func isJSLiteral(t string) bool {
+	return t != "" && (t[0] == '"' || t[0] == '\'' || t[0] == '`' || t[0] == '-' || (t[0] >= '0' && t[0] <= '9'))
+}
+
+func isJSIdent(t string) bool {
+	return t != "" && (t[0] == '_' || t[0] == '$' || (t[0] >= 'A' && t[0] <= 'Z') || (t[0] >= 'a' && t[0] <= 'z'))
+}
+
+// jsValue returns the value of a literal or identifier token, unquoted.
+func jsValue(t string) string {
+	if len(t) >= 2 && (t[0] == '"' || t[0] == '\'' || t[0] == '`') {
+		return t[1 : len(t)-1]
+	}
+	return t
+}
+
+// dtcgTokens returns the tokens of a DTCG document by their dot-separated path. Composite
+// values are compared as JSON.
+func dtcgTokens(data []byte) (map[string]string, error) {
+	var root map[string]any
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+	tokens := make(map[string]string)
+	var walk func(group map[string]any, path []string)
+	walk = func(group map[string]any, path []string) {
+		for name, node := range group {
+			child, ok := node.(map[string]any)
+			if !ok || strings.HasPrefix(name, "$") {
+				continue
+			}
+			p := append(append([]string(nil), path...), name)
+			value, ok := child["$value"]
+			if !ok {
+				walk(child, p)
+				continue
+			}
+			switch v := value.(type) {
+			case string:
+				tokens[strings.Join(p, ".")] = v
+			case float64:
+				tokens[strings.Join(p, ".")] = fmt.Sprintf("%g", v)
+			default:
+				encoded, _ := json.Marshal(v)
+				tokens[strings.Join(p, ".")] = string(encoded)
+			}
+		}
+	}
+	walk(root, nil)
+	return tokens, nil
+}
+
+// shortHex matches a three-digit hex color.
+var shortHex = regexp.MustCompile(`#([0-9a-f])([0-9a-f])([0-9a-f])\b`)
+
+// normalizeTokenValue returns a token value in a form that compares equal for equivalent
+// spellings.
+func normalizeTokenValue(v string) string {
+	v = strings.ToLower(v)
+	v = strings.NewReplacer(" ", "", "\t", "", "\n", "", "\"", "", "'", "").Replace(v)
+	return shortHex.ReplaceAllString(v, "#$1$1$2$2$3$3")
+}
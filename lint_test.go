@@ -0,0 +1,208 @@
+package figmaextractor
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hellenic-development/figma-extractor/pkg/extractor"
+	"github.com/hellenic-development/figma-extractor/pkg/formatter"
+)
+
+func TestCSSTokens(t *testing.T) {
+	tests := []struct {
+		name string
+		css  string
+		want map[string]string
+	}{
+		{
+			name: "root and themes",
+			css: `/* tokens */
+:root {
+  --color-primary-500: #3B82F6;
+  --font-primary: 'Inter', system-ui, sans-serif;
+  /* --space-4: 99px; */
+  --space-4:16px
+}
+[data-theme="dark"] { --color-primary-500: #000; }`,
+			want: map[string]string{
+				"color-primary-500": "#3B82F6",
+				"font-primary":      "'Inter', system-ui, sans-serif",
+				"space-4":           "16px",
+			},
+		},
+		{name: "no custom properties", css: "body { color: red; }", want: map[string]string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cssTokens(tt.css); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("cssTokens() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTailwindTokens(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		want   map[string]string
+	}{
+		{
+			name: "theme and extend",
+			config: `// tailwind.config.js
+module.exports = {
+  content: ["./src/**/*.{js,ts}"],
+  theme: {
+    screens: { md: '768px' },
+    extend: {
+      colors: {
+        primary: { DEFAULT: "#3b82f6", 500: '#3B82F6' }, // brand
+        error: "#ef4444",
+        computed: colors.red[500],
+      },
+      fontFamily: { primary: ["Inter", "system-ui", "sans-serif"], mono: "'JetBrains Mono', monospace" },
+      fontSize: { base: ["16px", { lineHeight: "24px" }] },
+      spacing: { 4: "16px" },
+      /* borderRadius: { lg: "8px" }, */
+    },
+  },
+}`,
+			want: map[string]string{
+				"breakpoint-md":     "768px",
+				"color-primary":     "#3b82f6",
+				"color-primary-500": "#3B82F6",
+				"color-error":       "#ef4444",
+				"font-primary":      "Inter",
+				"font-mono":         "JetBrains Mono",
+				"text-base":         "16px",
+				"space-4":           "16px",
+			},
+		},
+		{name: "empty theme", config: `export default { theme: { extend: {} } }`, want: map[string]string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tailwindTokens(tt.config); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tailwindTokens() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDTCGTokens(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "nested groups",
+			data: `{
+				"$description": "tokens",
+				"color": {"$type": "color", "primary": {"500": {"$value": "#3b82f6"}}},
+				"space": {"4": {"$value": 16}},
+				"shadow": {"sm": {"$value": {"blur": "2px"}}}
+			}`,
+			want: map[string]string{
+				"color.primary.500": "#3b82f6",
+				"space.4":           "16",
+				"shadow.sm":         `{"blur":"2px"}`,
+			},
+		},
+		{name: "invalid json", data: `{"color":`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := dtcgTokens([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("dtcgTokens() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("dtcgTokens() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLint(t *testing.T) {
+	specs := &extractor.DesignSpecs{
+		Colors: extractor.ColorPalette{Primary: map[string]string{"500": "#3b82f6"}},
+		Typography: extractor.Typography{
+			FontFamily: "Inter",
+			FontSizes:  map[string]float64{"base": 16},
+		},
+		Spacing: extractor.Spacing{Values: map[string]float64{"4": 16}},
+		Borders: map[string]extractor.Border{"Default": {Width: 1, Color: "#e5e7eb", Style: "solid"}},
+		Blurs:   []extractor.Blur{{Name: "Glass", Type: "BACKGROUND_BLUR", Radius: 8}},
+	}
+	dtcg, err := formatter.ToDTCG(specs)
+	if err != nil {
+		t.Fatalf("ToDTCG() error = %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		kind         string
+		code         string
+		wantMissing  []string
+		wantDiverged []string
+	}{
+		{name: "css in sync", kind: TokensCSS, code: formatter.ToCSS(specs, "")},
+		{name: "tailwind in sync", kind: TokensTailwind, code: formatter.ToTailwind(specs, "")},
+		{name: "dtcg in sync", kind: TokensDTCG, code: string(dtcg)},
+		{
+			name:         "css drifted",
+			kind:         TokensCSS,
+			code:         `:root { --color-primary-500: #3B82F6; --text-base: 15px; --font-primary: "Inter", system-ui, -apple-system, sans-serif; }`,
+			wantMissing:  []string{"blur-glass-backdrop", "border-default", "space-4"},
+			wantDiverged: []string{"text-base"},
+		},
+		{
+			name: "tailwind drifted",
+			kind: TokensTailwind,
+			code: `module.exports = { theme: { extend: {
+				colors: { primary: { 500: "#2563eb" } },
+				fontFamily: { primary: "Inter, sans-serif" },
+				fontSize: { base: "16px" },
+			} } }`,
+			wantMissing:  []string{"blur-glass-backdrop", "space-4"},
+			wantDiverged: []string{"color-primary-500"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := Lint(specs, strings.NewReader(tt.code), tt.kind)
+			if err != nil {
+				t.Fatalf("Lint() error = %v", err)
+			}
+			if report.Checked == 0 {
+				t.Error("Lint() checked no tokens")
+			}
+			if got := driftTokens(report.Missing); !reflect.DeepEqual(got, tt.wantMissing) {
+				t.Errorf("Lint() missing = %v, want %v", got, tt.wantMissing)
+			}
+			if got := driftTokens(report.Diverged); !reflect.DeepEqual(got, tt.wantDiverged) {
+				t.Errorf("Lint() diverged = %v, want %v", got, tt.wantDiverged)
+			}
+		})
+	}
+
+	if _, err := Lint(specs, strings.NewReader(""), "scss"); err == nil {
+		t.Error("Lint() with an unknown kind returned no error")
+	}
+}
+
+// driftTokens returns the token names of drifts.
+func driftTokens(drifts []Drift) []string {
+	var names []string
+	for _, d := range drifts {
+		names = append(names, d.Token)
+	}
+	return names
+}
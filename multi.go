@@ -0,0 +1,143 @@
+package figmaextractor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"sync"
+
+	"github.com/hellenic-development/figma-extractor/pkg/figma"
+	"github.com/hellenic-development/figma-extractor/pkg/imager"
+)
+
+// RunFiles extracts several Figma files, opts.FileURL (when set) and every URL of
+// opts.FileURLs, concurrently, for design systems split across files. Each file is extracted
+// like Run with the same options; the images of each are exported into a subdirectory of the
+// image directory (or store) named after its file key, and captured API responses into a
+// subdirectory of CaptureDir. The results are returned in the order of the URLs; a file that
+// failed leaves a nil result and its error, naming the URL, is joined into the returned error.
+//
+// Runs share Logger and OnProgress, which are called concurrently: SlogLogger logs the
+// "file_key" of every message.
+func RunFiles(ctx context.Context, opts Options) ([]*Result, error) {
+	var entries []BatchEntry
+	if opts.FileURL != "" {
+		entries = append(entries, BatchEntry{URL: opts.FileURL})
+	}
+	for _, fileURL := range opts.FileURLs {
+		entries = append(entries, BatchEntry{URL: fileURL})
+	}
+	batch, err := RunBatch(ctx, opts, entries, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*Result, len(batch))
+	var errs []error
+	for i, b := range batch {
+		results[i] = b.Result
+		if b.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", b.Entry.URL, b.Err))
+		}
+	}
+	return results, errors.Join(errs...)
+}
+
+// BatchResult is the outcome of the extraction of a BatchEntry: its Result, or the error it
+// failed with.
+type BatchResult struct {
+	Entry  BatchEntry
+	Result *Result
+	Err    error
+}
+
+// RunBatch extracts the files of entries like RunFiles, at most concurrency of them at a time
+// (all at once when concurrency <= 0), each with its own nodes when it lists any. A file listed
+// several times exports its images into numbered subdirectories, e.g. "ABC123-2". Invalid
+// options or URLs fail the whole batch before any file is extracted; otherwise a result is
+// returned per entry, in order, successful or not.
+func RunBatch(ctx context.Context, opts Options, entries []BatchEntry, concurrency int) ([]BatchResult, error) {
+	if len(entries) == 0 {
+		return nil, &ConfigError{Option: "FileURLs", Err: fmt.Errorf("%w: no Figma file URL", ErrInvalidURL)}
+	}
+	opts.FileURL, opts.FileURLs = "", nil
+	for _, entry := range entries {
+		opts.FileURLs = append(opts.FileURLs, entry.URL)
+	}
+	opts.applyDefaults()
+	if err := opts.validate(true); err != nil {
+		return nil, err
+	}
+	if concurrency <= 0 || concurrency > len(entries) {
+		concurrency = len(entries)
+	}
+
+	results := make([]BatchResult, len(entries))
+	slots := make(chan struct{}, concurrency)
+	seen := make(map[string]int)
+	var wg sync.WaitGroup
+	for i, entry := range entries {
+		fileOpts := opts
+		fileOpts.FileURL = entry.URL
+		fileOpts.FileURLs = nil
+		if len(entry.NodeIDs) > 0 {
+			fileOpts.NodeIDs = entry.NodeIDs
+		}
+		if len(entries) > 1 {
+			// Keep the images, manifests and captures of the files apart.
+			fileKey, _ := figma.ExtractFileKey(entry.URL)
+			dir := fileKey
+			if seen[fileKey]++; seen[fileKey] > 1 {
+				dir = fmt.Sprintf("%s-%d", fileKey, seen[fileKey])
+			}
+			if fileOpts.ImageStore != nil {
+				fileOpts.ImageStore = subStore{store: fileOpts.ImageStore, dir: dir}
+			} else {
+				fileOpts.ImageDir = filepath.Join(opts.ImageDir, dir)
+			}
+			if fileOpts.CaptureDir != "" {
+				fileOpts.CaptureDir = filepath.Join(opts.CaptureDir, dir)
+			}
+		}
+		results[i].Entry = entry
+
+		slots <- struct{}{} // start the files in order
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			results[i].Result, results[i].Err = Run(ctx, fileOpts)
+		}()
+	}
+	wg.Wait()
+
+	return results, nil
+}
+
+// subStore stores the assets of one of several files in a directory of their common store.
+type subStore struct {
+	store imager.AssetStore
+	dir   string
+}
+
+func (s subStore) Put(ctx context.Context, name string, r io.Reader) error {
+	return s.store.Put(ctx, path.Join(s.dir, name), r)
+}
+
+func (s subStore) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	return s.store.Open(ctx, path.Join(s.dir, name))
+}
+
+func (s subStore) Delete(ctx context.Context, name string) error {
+	return s.store.Delete(ctx, path.Join(s.dir, name))
+}
+
+func (s subStore) String() string {
+	if st, ok := s.store.(fmt.Stringer); ok {
+		return st.String() + "/" + s.dir
+	}
+	return s.dir
+}
@@ -0,0 +1,131 @@
+package figmaextractor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/hellenic-development/figma-extractor/pkg/extractor"
+	"github.com/hellenic-development/figma-extractor/pkg/figma"
+)
+
+// RunFromFileJSON runs the extraction pipeline like Run on a previously saved response of the
+// Figma file endpoint (GET /v1/files/:key) instead of fetching the file, so extractions can be
+// repeated offline and tested deterministically.
+//
+// No API request is made: AccessToken is not needed, and options that need the API (image
+// export, variables, comments, library styles, component usage and dev resources) are ignored with a warning.
+// Nodes are selected by NodeIDs, or by the node IDs of FileURL when it is set.
+func RunFromFileJSON(r io.Reader, opts Options) (*Result, error) {
+	formats, err := opts.prepare(false)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := opts.extractorConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	var fileResp figma.FileResponse
+	if err := json.NewDecoder(r).Decode(&fileResp); err != nil {
+		return nil, fmt.Errorf("parse file JSON: %w", err)
+	}
+	if fileResp.Document.Type != "DOCUMENT" {
+		return nil, fmt.Errorf("parse file JSON: not a Figma file response (no document)")
+	}
+	opts.logInfo("File: %s", fileResp.Name)
+
+	for _, online := range []struct {
+		option string
+		set    bool
+	}{
+		{"image export", opts.ExportImages},
+		{"variables", opts.Variables},
+		{"comments", opts.Comments},
+		{"library styles", opts.LibraryStyles},
+		{"component usage", opts.ComponentUsage},
+		{"dev resources", opts.DevResources},
+	} {
+		if online.set {
+			opts.warnf(WarningOption, "Ignoring %s: it needs the Figma API", online.option)
+		}
+	}
+
+	targetNodeIDs := opts.NodeIDs
+	if len(targetNodeIDs) == 0 && opts.FileURL != "" {
+		targetNodeIDs, err = figma.ExtractNodeIDs(opts.FileURL)
+		if err != nil {
+			return nil, fmt.Errorf("extract node IDs from URL: %w", err)
+		}
+	}
+
+	var specs *extractor.DesignSpecs
+	opts.startPhase(phaseExtract)
+	if len(targetNodeIDs) > 0 {
+		opts.logInfo("Extracting %d specific node(s)...", len(targetNodeIDs))
+		nodesResp := nodesFromFile(&fileResp, targetNodeIDs)
+		for _, id := range targetNodeIDs {
+			if _, ok := nodesResp.Nodes[id]; !ok {
+				opts.warn(Warning{Category: WarningNode, Message: fmt.Sprintf("Node %s not found in the file", id), NodeID: id})
+			}
+		}
+		if len(nodesResp.Nodes) == 0 {
+			return nil, fmt.Errorf("none of the %d node(s) found in the file", len(targetNodeIDs))
+		}
+		opts.filterNodes(&fileResp, nodesResp)
+		specs = cfg.ExtractNodes(&fileResp, nodesResp, targetNodeIDs, opts.InheritFileContext)
+		if opts.PerFrame || opts.SplitNodes {
+			specs.Frames = cfg.ExtractFrames(&fileResp, nodesResp, targetNodeIDs)
+		}
+	} else {
+		opts.logInfo("Extracting design specifications...")
+		opts.filterNodes(&fileResp, nil)
+		specs = cfg.Extract(&fileResp)
+		if opts.PerFrame {
+			opts.warnf(WarningOption, "Per-frame sections need node IDs; writing a single report")
+		}
+		if opts.SplitNodes {
+			opts.warnf(WarningOption, "Per-node files need node IDs; writing a single report")
+		}
+	}
+
+	opts.completePhase(0)
+
+	return render(context.Background(), &opts, formats, specs, &fileResp)
+}
+
+// nodesFromFile builds the response of the file nodes endpoint for the given node IDs out of a
+// whole file. Nodes that are not in the file are left out.
+func nodesFromFile(fileResp *figma.FileResponse, nodeIDs []string) *figma.NodesResponse {
+	nodesResp := &figma.NodesResponse{
+		Name:         fileResp.Name,
+		LastModified: fileResp.LastModified,
+		Version:      fileResp.Version,
+		Nodes:        make(map[string]figma.NodeData),
+	}
+	for _, id := range nodeIDs {
+		if node := findNode(&fileResp.Document, id); node != nil {
+			nodesResp.Nodes[id] = figma.NodeData{
+				Document:      *node,
+				Components:    fileResp.Components,
+				ComponentSets: fileResp.ComponentSets,
+				Styles:        fileResp.Styles,
+			}
+		}
+	}
+	return nodesResp
+}
+
+// findNode returns the node with the given ID in the tree under node, or nil.
+func findNode(node *figma.Node, id string) *figma.Node {
+	if node.ID == id {
+		return node
+	}
+	for i := range node.Children {
+		if found := findNode(&node.Children[i], id); found != nil {
+			return found
+		}
+	}
+	return nil
+}
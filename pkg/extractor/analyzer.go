@@ -0,0 +1,230 @@
+package extractor
+
+import (
+	"strings"
+
+	"github.com/hellenic-development/figma-extractor/pkg/figma"
+)
+
+// Analyzer pulls design data out of the nodes of a design. Extraction runs a pipeline of
+// analyzers over every extracted node, parents before children, skipping the copies of
+// duplicate frames: the built-in analyzers collect the colors, typography, effects, radii,
+// blend modes, spacing and layout of DesignSpecs, then the analyzers of Config.Analyzers run,
+// e.g. to pull domain-specific data into DesignSpecs.Custom. The components, prototype flows,
+// themes, breakpoints, icons and node tree are collected from the whole tree afterwards.
+type Analyzer interface {
+	Analyze(node *figma.Node, a *Analysis)
+}
+
+// AnalyzerFunc adapts a function to the Analyzer interface.
+type AnalyzerFunc func(node *figma.Node, a *Analysis)
+
+// Analyze calls f.
+func (f AnalyzerFunc) Analyze(node *figma.Node, a *Analysis) {
+	f(node, a)
+}
+
+// Analysis is the extraction the analyzers of a pipeline contribute to.
+type Analysis struct {
+	Specs  *DesignSpecs
+	Styles map[string]figma.Style // published styles by ID, of the file and the extracted nodes
+
+	config Config
+	gaps   map[float64]int // gaps measured between siblings outside auto layout, see siblingGaps
+}
+
+// TokenName returns the name of the token a value of node is keyed by: the name of the style
+// of styleType ("fill", "stroke", "text", "effect" or "grid") the node applies, e.g.
+// "Brand/Primary/500", or else the layer name of the node.
+func (a *Analysis) TokenName(node *figma.Node, styleType string) string {
+	return tokenName(node, styleType, a.Styles)
+}
+
+// Set stores value in Specs.Custom under key, for the data of custom analyzers.
+func (a *Analysis) Set(key string, value any) {
+	if a.Specs.Custom == nil {
+		a.Specs.Custom = make(map[string]any)
+	}
+	a.Specs.Custom[key] = value
+}
+
+// builtinAnalyzers are the analyzers of every extraction, in order.
+var builtinAnalyzers = []Analyzer{
+	AnalyzerFunc(analyzeColors),
+	AnalyzerFunc(analyzeTypography),
+	AnalyzerFunc(analyzeEffects),
+	AnalyzerFunc(analyzeRadii),
+	AnalyzerFunc(analyzeBlendModes),
+	AnalyzerFunc(analyzeSpacing),
+	AnalyzerFunc(analyzeGaps),
+	AnalyzerFunc(analyzeLayout),
+}
+
+// analyze runs the pipeline of c over node and its descendants into specs. Subtrees whose ID is
+// in copies are skipped. Gaps between siblings measured more than once become spacing values.
+func (c Config) analyze(node *figma.Node, specs *DesignSpecs, styles map[string]figma.Style, copies map[string]bool) {
+	a := &Analysis{Specs: specs, Styles: styles, config: c, gaps: make(map[float64]int)}
+	pipeline := append(builtinAnalyzers[:len(builtinAnalyzers):len(builtinAnalyzers)], c.Analyzers...)
+	a.walk(node, pipeline, copies)
+	addInferredSpacing(specs.Spacing.Values, a.gaps)
+}
+
+// walk runs pipeline over node and its descendants.
+func (a *Analysis) walk(node *figma.Node, pipeline []Analyzer, copies map[string]bool) {
+	// Copies of duplicate frames only repeat the original's values under other names
+	if copies[node.ID] {
+		return
+	}
+	for _, analyzer := range pipeline {
+		analyzer.Analyze(node, a)
+	}
+	for i := range node.Children {
+		a.walk(&node.Children[i], pipeline, copies)
+	}
+}
+
+// analyzeColors collects the fill colors, categorized by the CategorizeColor hook and color
+// rules when given (see categorizeColor), the stroke colors and border styles, and the background colors.
+func analyzeColors(node *figma.Node, a *Analysis) {
+	specs := a.Specs
+	for _, fill := range node.Fills {
+		if fill.Type == "SOLID" && fill.Color != nil && fill.Visible {
+			colorHex := colorToHex(fill.Color)
+			specs.Colors.Usage[colorHex]++
+			a.config.categorizeColor(a.TokenName(node, "fill"), colorHex, specs)
+		}
+	}
+
+	for _, stroke := range node.Strokes {
+		if stroke.Type == "SOLID" && stroke.Color != nil && stroke.Visible {
+			colorHex := colorToHex(stroke.Color)
+			specs.Colors.Usage[colorHex]++
+			name := a.TokenName(node, "stroke")
+			specs.Colors.Border[name] = colorHex
+			if _, seen := specs.Borders[name]; !seen && strokeWeight(node) > 0 {
+				specs.Borders[name] = newBorder(node, name, colorHex)
+			}
+		}
+	}
+
+	if node.BackgroundColor != nil {
+		colorHex := colorToHex(node.BackgroundColor)
+		specs.Colors.Usage[colorHex]++
+		specs.Colors.Background[node.Name] = colorHex
+	}
+}
+
+// analyzeTypography collects the font properties of text nodes and their text styles.
+func analyzeTypography(node *figma.Node, a *Analysis) {
+	style, typography := node.Style, &a.Specs.Typography
+	if style == nil {
+		return
+	}
+	name := a.TokenName(node, "text")
+	if style.FontFamily != "" && typography.FontFamily == "" {
+		typography.FontFamily = style.FontFamily
+	}
+	addFont(typography, style)
+	if style.FontSize > 0 {
+		typography.FontSizes[name] = style.FontSize
+	}
+	if style.FontWeight > 0 {
+		typography.FontWeights[name] = style.FontWeight
+	}
+	if style.LineHeightPx > 0 {
+		typography.LineHeights[name] = style.LineHeightPx
+	}
+	if style.LetterSpacing != 0 {
+		typography.LetterSpacings[name] = style.LetterSpacing
+	}
+	if style.TextCase != "" && style.TextCase != "ORIGINAL" {
+		typography.TextCases[name] = style.TextCase
+	}
+	if style.TextDecoration != "" && style.TextDecoration != "NONE" {
+		typography.TextDecorations[name] = style.TextDecoration
+	}
+	if textStyle, ok := styleName(node, "text", a.Styles); ok {
+		if _, seen := a.Specs.TextStyles[textStyle]; !seen {
+			a.Specs.TextStyles[textStyle] = newTextStyle(textStyle, style)
+		}
+	}
+}
+
+// analyzeEffects collects the visible shadows and blurs.
+func analyzeEffects(node *figma.Node, a *Analysis) {
+	for _, effect := range node.Effects {
+		if (effect.Type == "DROP_SHADOW" || effect.Type == "INNER_SHADOW") && effect.Visible {
+			a.Specs.Shadows = append(a.Specs.Shadows, Shadow{
+				Name:   a.TokenName(node, "effect"),
+				Type:   effect.Type,
+				X:      effect.Offset.X,
+				Y:      effect.Offset.Y,
+				Blur:   effect.Radius,
+				Spread: effect.Spread,
+				Color:  colorToHex(effect.Color),
+			})
+		}
+		if (effect.Type == "LAYER_BLUR" || effect.Type == "BACKGROUND_BLUR") && effect.Visible {
+			a.Specs.Blurs = append(a.Specs.Blurs, Blur{
+				Name:   a.TokenName(node, "effect"),
+				Type:   effect.Type,
+				Radius: effect.Radius,
+			})
+		}
+	}
+}
+
+// analyzeRadii collects the corner radii.
+func analyzeRadii(node *figma.Node, a *Analysis) {
+	if node.CornerRadius > 0 {
+		a.Specs.Radii.Values[node.Name] = node.CornerRadius
+	}
+}
+
+// analyzeBlendModes records the blend modes that affect how colors must be reproduced.
+func analyzeBlendModes(node *figma.Node, a *Analysis) {
+	a.Specs.BlendModes = append(a.Specs.BlendModes, blendModes(node)...)
+}
+
+// analyzeSpacing collects the paddings and item spacing of auto-layout frames.
+func analyzeSpacing(node *figma.Node, a *Analysis) {
+	values := a.Specs.Spacing.Values
+	if node.PaddingLeft > 0 || node.PaddingRight > 0 || node.PaddingTop > 0 || node.PaddingBottom > 0 {
+		values[node.Name+"-paddingLeft"] = node.PaddingLeft
+		values[node.Name+"-paddingRight"] = node.PaddingRight
+		values[node.Name+"-paddingTop"] = node.PaddingTop
+		values[node.Name+"-paddingBottom"] = node.PaddingBottom
+	}
+	if node.ItemSpacing > 0 {
+		values[node.Name+"-itemSpacing"] = node.ItemSpacing
+	}
+}
+
+// analyzeGaps measures the gaps between the children of frames without auto layout, which
+// many files use for most of their screens.
+func analyzeGaps(node *figma.Node, a *Analysis) {
+	for _, gap := range siblingGaps(node) {
+		a.gaps[gap]++
+	}
+}
+
+// analyzeLayout collects the auto-layout specifications and the header height and sidebar
+// width.
+func analyzeLayout(node *figma.Node, a *Analysis) {
+	layout := &a.Specs.Layout
+	if node.LayoutMode == "HORIZONTAL" || node.LayoutMode == "VERTICAL" {
+		if _, seen := layout.AutoLayouts[node.Name]; !seen {
+			layout.AutoLayouts[node.Name] = newAutoLayout(node)
+		}
+	}
+
+	if node.AbsoluteBoundingBox != nil {
+		name := strings.ToLower(node.Name)
+		if strings.Contains(name, "header") {
+			layout.HeaderHeight = node.AbsoluteBoundingBox.Height
+		}
+		if strings.Contains(name, "sidebar") {
+			layout.SidebarWidth = node.AbsoluteBoundingBox.Width
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package extractor
+
+import "github.com/hellenic-development/figma-extractor/pkg/figma"
+
+// BlendModeUsage records a layer, fill or stroke using a blend mode other than NORMAL.
+// Such colors only look right when the blend mode is reproduced in code.
+type BlendModeUsage struct {
+	NodeID   string `json:"nodeId"`
+	NodeName string `json:"nodeName,omitempty"`
+	Target   string `json:"target,omitempty"` // "layer", "fill" or "stroke"
+	Mode     string `json:"mode,omitempty"`   // Figma blend mode, e.g. MULTIPLY
+}
+
+// isNormalBlendMode reports whether mode leaves colors unchanged. PASS_THROUGH is the default
+// of groups and frames and blends children as if the group were not there.
+func isNormalBlendMode(mode string) bool {
+	return mode == "" || mode == "NORMAL" || mode == "PASS_THROUGH"
+}
+
+// blendModes returns the non-normal blend modes of a node's layer and visible paints.
+func blendModes(node *figma.Node) []BlendModeUsage {
+	var usages []BlendModeUsage
+	add := func(target, mode string) {
+		if !isNormalBlendMode(mode) {
+			usages = append(usages, BlendModeUsage{NodeID: node.ID, NodeName: node.Name, Target: target, Mode: mode})
+		}
+	}
+
+	add("layer", node.BlendMode)
+	for _, fill := range node.Fills {
+		if fill.Visible {
+			add("fill", fill.BlendMode)
+		}
+	}
+	for _, stroke := range node.Strokes {
+		if stroke.Visible {
+			add("stroke", stroke.BlendMode)
+		}
+	}
+	return usages
+}
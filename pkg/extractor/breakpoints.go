@@ -0,0 +1,173 @@
+package extractor
+
+import (
+	"sort"
+	"unicode"
+
+	"github.com/hellenic-development/figma-extractor/pkg/figma"
+)
+
+// Breakpoint is a viewport width the design is laid out for, named after the device class of
+// the frames designed for it.
+type Breakpoint struct {
+	Name  string  `json:"name"`            // mobile, tablet, laptop, desktop or wide
+	Width float64 `json:"width,omitempty"` // width of the narrowest frame designed for it
+}
+
+// Screen is a screen designed at more than one breakpoint, e.g. "Home/Mobile" and
+// "Home/Desktop".
+type Screen struct {
+	Name     string          `json:"name"`               // frame name without the breakpoint, e.g. "Home"
+	Variants []ScreenVariant `json:"variants,omitempty"` // ordered by width, narrowest first
+}
+
+// ScreenVariant is the layout of a screen at one breakpoint.
+type ScreenVariant struct {
+	Breakpoint string      `json:"breakpoint,omitempty"`
+	NodeID     string      `json:"nodeId"`
+	NodeName   string      `json:"nodeName,omitempty"`
+	Width      float64     `json:"width,omitempty"`
+	Height     float64     `json:"height,omitempty"`
+	Layout     *AutoLayout `json:"layout,omitempty"` // nil when the frame does not use auto layout
+}
+
+// breakpointWords maps the words identifying a device class in a page or frame name, including
+// the device names of Figma's frame presets, to the breakpoint.
+var breakpointWords = map[string]string{
+	"mobile":     "mobile",
+	"phone":      "mobile",
+	"iphone":     "mobile",
+	"android":    "mobile",
+	"tablet":     "tablet",
+	"ipad":       "tablet",
+	"laptop":     "laptop",
+	"macbook":    "laptop",
+	"desktop":    "desktop",
+	"web":        "desktop",
+	"wide":       "wide",
+	"widescreen": "wide",
+}
+
+// breakpointOf returns the breakpoint name mentions (e.g. "Home / Mobile"), or "".
+func breakpointOf(name string) string {
+	for _, word := range nameWords(name) {
+		if bp, ok := breakpointWords[word]; ok {
+			return bp
+		}
+	}
+	return ""
+}
+
+// withoutBreakpoint removes breakpoint words from a slash-separated name so that "Home/Mobile"
+// and "Home/Desktop" both become "Home".
+func withoutBreakpoint(name string) string {
+	return withoutWords(name, func(word string) bool {
+		_, ok := breakpointWords[word]
+		return ok
+	})
+}
+
+// detectScreens groups frames depicting the same screen at different breakpoints: frames on
+// pages named after a device class ("Mobile", "Desktop") paired by frame name, or frames named
+// after one ("Home / Mobile", "Home / Desktop") paired by the rest of their name. Only screens
+// found at two or more breakpoints are returned, in document order. Each breakpoint gets the
+// width of its narrowest frame; breakpoints are ordered by width.
+func detectScreens(roots []*figma.Node) ([]Breakpoint, []Screen) {
+	var keys []string
+	variants := make(map[string][]ScreenVariant)
+	add := func(key, bp string, node *figma.Node) {
+		if !hasLetter(key) {
+			// "Desktop - 1" and "Mobile - 1" are numbered presets, not the same screen.
+			return
+		}
+		for _, v := range variants[key] {
+			if v.Breakpoint == bp {
+				return
+			}
+		}
+		if _, ok := variants[key]; !ok {
+			keys = append(keys, key)
+		}
+		variants[key] = append(variants[key], newScreenVariant(bp, node))
+	}
+
+	var visit func(node *figma.Node, pageBreakpoint string)
+	visit = func(node *figma.Node, pageBreakpoint string) {
+		switch node.Type {
+		case "DOCUMENT", "SECTION":
+			for i := range node.Children {
+				visit(&node.Children[i], pageBreakpoint)
+			}
+		case "CANVAS":
+			for i := range node.Children {
+				visit(&node.Children[i], breakpointOf(node.Name))
+			}
+		default:
+			if node.AbsoluteBoundingBox == nil {
+				return
+			}
+			if bp := breakpointOf(node.Name); bp != "" {
+				add(withoutBreakpoint(node.Name), bp, node)
+			} else if pageBreakpoint != "" {
+				add(node.Name, pageBreakpoint, node)
+			}
+		}
+	}
+	for _, root := range roots {
+		visit(root, "")
+	}
+
+	var screens []Screen
+	widths := make(map[string]float64)
+	for _, key := range keys {
+		vs := variants[key]
+		if len(vs) < 2 {
+			continue
+		}
+		sort.SliceStable(vs, func(i, j int) bool { return vs[i].Width < vs[j].Width })
+		screens = append(screens, Screen{Name: key, Variants: vs})
+		for _, v := range vs {
+			if w, ok := widths[v.Breakpoint]; !ok || v.Width < w {
+				widths[v.Breakpoint] = v.Width
+			}
+		}
+	}
+
+	var breakpoints []Breakpoint
+	for name, width := range widths {
+		breakpoints = append(breakpoints, Breakpoint{Name: name, Width: width})
+	}
+	sort.Slice(breakpoints, func(i, j int) bool {
+		if breakpoints[i].Width != breakpoints[j].Width {
+			return breakpoints[i].Width < breakpoints[j].Width
+		}
+		return breakpoints[i].Name < breakpoints[j].Name
+	})
+	return breakpoints, screens
+}
+
+// newScreenVariant describes the layout of a screen frame at breakpoint bp.
+func newScreenVariant(bp string, node *figma.Node) ScreenVariant {
+	v := ScreenVariant{
+		Breakpoint: bp,
+		NodeID:     node.ID,
+		NodeName:   node.Name,
+		Width:      node.AbsoluteBoundingBox.Width,
+		Height:     node.AbsoluteBoundingBox.Height,
+	}
+	if node.LayoutMode == "HORIZONTAL" || node.LayoutMode == "VERTICAL" {
+		l := newAutoLayout(node)
+		v.Layout = &l
+	}
+	return v
+}
+
+// hasLetter reports whether s contains a letter.
+func hasLetter(s string) bool {
+	for _, r := range s {
+		if unicode.IsLetter(r) {
+			return true
+		}
+	}
+	return false
+}
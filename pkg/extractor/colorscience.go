@@ -0,0 +1,142 @@
+package extractor
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// hueFamilies names the 30° hue sectors, starting at red (0°).
+var hueFamilies = []string{
+	"red", "orange", "yellow", "lime", "green", "emerald",
+	"cyan", "sky", "blue", "violet", "purple", "pink",
+}
+
+// classifyUncategorizedColors assigns every used color that no named category claimed to the
+// Neutral, Brand or Accent palette. Low-saturation, near-black and near-white colors are
+// neutral; the hue family used most often is the brand and every other saturated color is an
+// accent. Colors are named after their hue family and lightness step ("blue-500"), neutrals
+// after their lightness step alone ("100"); the most used color of a name gets it without a
+// numeric suffix.
+func classifyUncategorizedColors(p *ColorPalette) {
+	categorized := make(map[string]bool)
+	for _, colors := range []map[string]string{p.Primary, p.Secondary, p.Background, p.Text, p.Status, p.Border, p.Brand, p.Accent, p.Neutral} {
+		for _, hex := range colors {
+			categorized[hex] = true
+		}
+	}
+
+	var hexes []string
+	for hex := range p.Usage {
+		if !categorized[hex] {
+			hexes = append(hexes, hex)
+		}
+	}
+	if len(hexes) == 0 {
+		return
+	}
+	sort.Slice(hexes, func(i, j int) bool {
+		if p.Usage[hexes[i]] != p.Usage[hexes[j]] {
+			return p.Usage[hexes[i]] > p.Usage[hexes[j]]
+		}
+		return hexes[i] < hexes[j]
+	})
+
+	// The brand is the hue family with the highest total usage.
+	familyUsage := make(map[string]int)
+	for _, hex := range hexes {
+		if h, s, l, ok := hexToHSL(hex); ok && !isNeutral(s, l) {
+			familyUsage[hueFamily(h)] += p.Usage[hex]
+		}
+	}
+	brand := ""
+	for _, family := range hueFamilies {
+		if familyUsage[family] > familyUsage[brand] {
+			brand = family
+		}
+	}
+
+	for _, hex := range hexes {
+		h, s, l, ok := hexToHSL(hex)
+		if !ok {
+			continue
+		}
+		switch {
+		case isNeutral(s, l):
+			addUnique(p.Neutral, lightnessStep(l), hex)
+		case hueFamily(h) == brand:
+			addUnique(p.Brand, brand+"-"+lightnessStep(l), hex)
+		default:
+			addUnique(p.Accent, hueFamily(h)+"-"+lightnessStep(l), hex)
+		}
+	}
+}
+
+// isNeutral reports whether a color reads as gray: barely saturated, or so dark or light that
+// its hue is not perceptible.
+func isNeutral(s, l float64) bool {
+	return s < 0.15 || l < 0.06 || l > 0.96
+}
+
+// hueFamily returns the name of the 30° hue sector containing h (in degrees).
+func hueFamily(h float64) string {
+	return hueFamilies[int(math.Mod(h+15, 360)/30)%len(hueFamilies)]
+}
+
+// lightnessStep maps a lightness (0-1) onto the familiar 50-950 scale where 500 is the
+// mid tone and higher steps are darker.
+func lightnessStep(l float64) string {
+	step := int(math.Round((1-l)*10)) * 100
+	step = max(50, min(950, step))
+	return strconv.Itoa(step)
+}
+
+// addUnique adds hex under name, or under name-2, name-3, ... when name is taken.
+func addUnique(colors map[string]string, name, hex string) {
+	key := name
+	for i := 2; ; i++ {
+		if _, taken := colors[key]; !taken {
+			colors[key] = hex
+			return
+		}
+		key = fmt.Sprintf("%s-%d", name, i)
+	}
+}
+
+// hexToHSL converts a #RRGGBB color to hue (degrees), saturation and lightness (0-1).
+func hexToHSL(hex string) (h, s, l float64, ok bool) {
+	if len(hex) != 7 || hex[0] != '#' {
+		return 0, 0, 0, false
+	}
+	v, err := strconv.ParseUint(hex[1:], 16, 32)
+	if err != nil {
+		return 0, 0, 0, false
+	}
+	r := float64(v>>16&0xFF) / 255
+	g := float64(v>>8&0xFF) / 255
+	b := float64(v&0xFF) / 255
+
+	maxC := math.Max(r, math.Max(g, b))
+	minC := math.Min(r, math.Min(g, b))
+	l = (maxC + minC) / 2
+	d := maxC - minC
+	if d == 0 {
+		return 0, 0, l, true
+	}
+
+	s = d / (1 - math.Abs(2*l-1))
+	switch maxC {
+	case r:
+		h = math.Mod((g-b)/d, 6)
+	case g:
+		h = (b-r)/d + 2
+	default:
+		h = (r-g)/d + 4
+	}
+	h *= 60
+	if h < 0 {
+		h += 360
+	}
+	return h, s, l, true
+}
@@ -0,0 +1,92 @@
+package extractor
+
+import (
+	"sort"
+
+	"github.com/hellenic-development/figma-extractor/pkg/figma"
+)
+
+// Comment is an unresolved comment thread of the design: an open question or remark, anchored to
+// the node it was placed on.
+type Comment struct {
+	ID        string         `json:"id"`
+	Number    string         `json:"number,omitempty"` // the number shown on the canvas
+	NodeID    string         `json:"nodeId"`           // empty for comments placed on the canvas rather than on a node
+	NodeName  string         `json:"nodeName,omitempty"`
+	Author    string         `json:"author,omitempty"` // user handle
+	Message   string         `json:"message,omitempty"`
+	CreatedAt string         `json:"createdAt,omitempty"` // RFC 3339
+	Replies   []CommentReply `json:"replies,omitempty"`
+}
+
+// CommentReply is a reply in a comment thread.
+type CommentReply struct {
+	Author    string `json:"author,omitempty"`
+	Message   string `json:"message,omitempty"`
+	CreatedAt string `json:"createdAt,omitempty"`
+}
+
+// ExtractComments returns the unresolved comment threads of the nodes under roots, with their
+// replies, ordered by node name and then by creation time. Comments placed on the canvas rather
+// than on a node are only kept when roots is the whole document.
+func ExtractComments(resp *figma.CommentsResponse, roots []*figma.Node) []Comment {
+	names := make(map[string]string)
+	wholeDocument := false
+	for _, root := range roots {
+		nodeNames(root, names)
+		if root.Type == "DOCUMENT" {
+			wholeDocument = true
+		}
+	}
+
+	var comments []Comment
+	index := make(map[string]int) // comment ID -> position in comments
+	for _, c := range resp.Comments {
+		if c.ParentID != "" || c.ResolvedAt != "" {
+			continue
+		}
+		comment := Comment{
+			ID:        c.ID,
+			Number:    c.OrderID,
+			Author:    c.User.Handle,
+			Message:   c.Message,
+			CreatedAt: c.CreatedAt,
+		}
+		if c.ClientMeta != nil && c.ClientMeta.NodeID != "" {
+			name, ok := names[c.ClientMeta.NodeID]
+			if !ok {
+				continue
+			}
+			comment.NodeID, comment.NodeName = c.ClientMeta.NodeID, name
+		} else if !wholeDocument {
+			continue
+		}
+		index[c.ID] = len(comments)
+		comments = append(comments, comment)
+	}
+
+	for _, c := range resp.Comments {
+		if i, ok := index[c.ParentID]; ok {
+			comments[i].Replies = append(comments[i].Replies, CommentReply{
+				Author:    c.User.Handle,
+				Message:   c.Message,
+				CreatedAt: c.CreatedAt,
+			})
+		}
+	}
+
+	for i := range comments {
+		replies := comments[i].Replies
+		sort.SliceStable(replies, func(a, b int) bool { return replies[a].CreatedAt < replies[b].CreatedAt })
+	}
+	sort.SliceStable(comments, func(i, j int) bool {
+		if comments[i].NodeName != comments[j].NodeName {
+			return comments[i].NodeName < comments[j].NodeName
+		}
+		if comments[i].NodeID != comments[j].NodeID {
+			return comments[i].NodeID < comments[j].NodeID
+		}
+		return comments[i].CreatedAt < comments[j].CreatedAt
+	})
+	return comments
+}
@@ -0,0 +1,297 @@
+package extractor
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/hellenic-development/figma-extractor/pkg/figma"
+)
+
+// Component describes a reusable Figma component or component set found in the document.
+type Component struct {
+	ID          string  `json:"id"`
+	Key         string  `json:"key,omitempty"` // published component key, empty for unpublished components
+	Name        string  `json:"name"`
+	Type        string  `json:"type,omitempty"` // "COMPONENT" or "COMPONENT_SET"
+	Description string  `json:"description,omitempty"`
+	Page        string  `json:"page,omitempty"` // name of the page (canvas) the component lives on
+	Width       float64 `json:"width,omitempty"`
+	Height      float64 `json:"height,omitempty"`
+
+	// VariantCount is the number of variants of a component set; 0 for standalone components.
+	VariantCount int `json:"variantCount,omitempty"`
+
+	Properties []ComponentProperty `json:"properties,omitempty"` // sorted by name
+	Variants   []Variant           `json:"variants,omitempty"`   // variants of a component set, in document order
+
+	// Usage is the library analytics of a published component; nil when not requested or unknown.
+	Usage *ComponentUsage `json:"usage,omitempty"`
+
+	// Links are the dev resources (Storybook stories, GitHub sources, docs) attached to the
+	// component or its variants, populated from the Dev Resources API.
+	Links []ComponentLink `json:"links,omitempty"`
+}
+
+// ComponentLink is a dev resource linked to a component.
+type ComponentLink struct {
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+// ComponentUsage counts how often a published component is used across the organization. The
+// counts of a component set add up those of its variants.
+type ComponentUsage struct {
+	Instances int `json:"instances,omitempty"`
+	Teams     int `json:"teams,omitempty"` // teams using the component; the highest variant count for sets
+	Files     int `json:"files,omitempty"` // files using the component; the highest variant count for sets
+}
+
+// ComponentProperty is a property exposed by a component or component set.
+type ComponentProperty struct {
+	Name    string   `json:"name"`              // display name, without Figma's unique "#id" suffix
+	Type    string   `json:"type,omitempty"`    // "VARIANT", "BOOLEAN", "TEXT" or "INSTANCE_SWAP"
+	Default string   `json:"default,omitempty"` // default value; "true"/"false" for BOOLEAN properties
+	Options []string `json:"options,omitempty"` // possible values of VARIANT properties
+}
+
+// Variant is a single component of a component set together with its variant property values.
+type Variant struct {
+	ID         string            `json:"id"`
+	Name       string            `json:"name"`                 // Figma variant name, e.g. "Size=Large, State=Hover"
+	Properties map[string]string `json:"properties,omitempty"` // e.g. {"Size": "Large", "State": "Hover"}
+}
+
+// componentMeta is the file-level metadata the API returns alongside the document.
+type componentMeta struct {
+	components map[string]figma.Component
+	sets       map[string]figma.ComponentSet
+}
+
+// collectComponents walks node and appends every component set and every component that is
+// not a variant of a set. Variants are counted on their set instead of being listed.
+func collectComponents(node *figma.Node, page string, meta componentMeta, components *[]Component) {
+	if node.Type == "CANVAS" {
+		page = node.Name
+	}
+
+	switch node.Type {
+	case "COMPONENT_SET":
+		c := newComponent(node, page)
+		if set, ok := meta.sets[node.ID]; ok {
+			c.Key = set.Key
+			c.Description = set.Description
+		}
+		for _, child := range node.Children {
+			if child.Type == "COMPONENT" {
+				c.Variants = append(c.Variants, Variant{
+					ID:         child.ID,
+					Name:       child.Name,
+					Properties: ParseVariantName(child.Name),
+				})
+			}
+		}
+		c.VariantCount = len(c.Variants)
+		c.Properties = componentProperties(node.ComponentPropertyDefinitions, c.Variants)
+		*components = append(*components, c)
+		// Variants are summarized by the set.
+		return
+	case "COMPONENT":
+		c := newComponent(node, page)
+		if m, ok := meta.components[node.ID]; ok {
+			c.Key = m.Key
+			c.Description = m.Description
+		}
+		c.Properties = componentProperties(node.ComponentPropertyDefinitions, nil)
+		*components = append(*components, c)
+	}
+
+	for i := range node.Children {
+		collectComponents(&node.Children[i], page, meta, components)
+	}
+}
+
+// ParseVariantName parses a Figma variant name such as "Size=Large, State=Hover" into its
+// property values. Parts without "=" are ignored.
+func ParseVariantName(name string) map[string]string {
+	props := make(map[string]string)
+	for _, part := range strings.Split(name, ",") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			continue
+		}
+		if key = strings.TrimSpace(key); key != "" {
+			props[key] = strings.TrimSpace(value)
+		}
+	}
+	return props
+}
+
+// componentProperties converts Figma property definitions into sorted ComponentProperty values.
+// Files without definitions (older API responses) fall back to the variant properties found in
+// the variant names, with options in document order.
+func componentProperties(defs map[string]figma.ComponentPropertyDefinition, variants []Variant) []ComponentProperty {
+	var props []ComponentProperty
+
+	if len(defs) > 0 {
+		for _, name := range slices.Sorted(maps.Keys(defs)) {
+			def := defs[name]
+			prop := ComponentProperty{
+				Name:    propertyName(name),
+				Type:    def.Type,
+				Options: def.VariantOptions,
+			}
+			if def.DefaultValue != nil {
+				prop.Default = fmt.Sprint(def.DefaultValue)
+			}
+			props = append(props, prop)
+		}
+	} else {
+		index := make(map[string]int)
+		for _, v := range variants {
+			for _, key := range slices.Sorted(maps.Keys(v.Properties)) {
+				i, ok := index[key]
+				if !ok {
+					i = len(props)
+					index[key] = i
+					// The first variant is the default in Figma.
+					props = append(props, ComponentProperty{Name: key, Type: "VARIANT", Default: v.Properties[key]})
+				}
+				if !slices.Contains(props[i].Options, v.Properties[key]) {
+					props[i].Options = append(props[i].Options, v.Properties[key])
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(props, func(i, j int) bool { return props[i].Name < props[j].Name })
+	return props
+}
+
+// propertyName strips the unique "#id" suffix Figma appends to non-variant property names.
+func propertyName(name string) string {
+	if i := strings.LastIndex(name, "#"); i > 0 {
+		return name[:i]
+	}
+	return name
+}
+
+// newComponent creates a Component from its node without metadata.
+func newComponent(node *figma.Node, page string) Component {
+	c := Component{
+		ID:   node.ID,
+		Name: node.Name,
+		Type: node.Type,
+		Page: page,
+	}
+	if node.AbsoluteBoundingBox != nil {
+		c.Width = node.AbsoluteBoundingBox.Width
+		c.Height = node.AbsoluteBoundingBox.Height
+	}
+	return c
+}
+
+// sortComponents orders components by name, then ID, and drops duplicates.
+func sortComponents(components []Component) []Component {
+	sort.Slice(components, func(i, j int) bool {
+		if components[i].Name != components[j].Name {
+			return components[i].Name < components[j].Name
+		}
+		return components[i].ID < components[j].ID
+	})
+
+	result := components[:0]
+	for i, c := range components {
+		if i > 0 && c.ID == components[i-1].ID {
+			continue
+		}
+		result = append(result, c)
+	}
+	return result
+}
+
+// pageOf returns the name of the page containing the node with the given ID, or "" if the
+// node is not found in the document.
+func pageOf(document *figma.Node, nodeID string) string {
+	for i := range document.Children {
+		page := &document.Children[i]
+		if page.ID == nodeID || containsNode(page, nodeID) {
+			return page.Name
+		}
+	}
+	return ""
+}
+
+// containsNode reports whether the subtree of node contains a node with the given ID.
+func containsNode(node *figma.Node, nodeID string) bool {
+	for i := range node.Children {
+		if node.Children[i].ID == nodeID || containsNode(&node.Children[i], nodeID) {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyComponentUsages attaches the library analytics in usages to the published components,
+// matched by component key. Component sets get the usages of their variants summed up; since
+// the same team or file can use several variants, their team and file counts are lower bounds.
+func ApplyComponentUsages(components []Component, usages []figma.ComponentUsage) {
+	byKey := make(map[string]*ComponentUsage)
+	add := func(key string, u figma.ComponentUsage) {
+		if key == "" {
+			return
+		}
+		usage, ok := byKey[key]
+		if !ok {
+			usage = &ComponentUsage{}
+			byKey[key] = usage
+		}
+		usage.Instances += u.Usages
+		usage.Teams = max(usage.Teams, u.TeamsUsing)
+		usage.Files = max(usage.Files, u.FilesUsing)
+	}
+	for _, u := range usages {
+		add(u.ComponentKey, u)
+		add(u.ComponentSetKey, u)
+	}
+
+	for i := range components {
+		if usage, ok := byKey[components[i].Key]; ok {
+			u := *usage
+			components[i].Usage = &u
+		}
+	}
+}
+
+// ApplyDevResources attaches the dev resources linked to the components, or to the variants of
+// component sets, as their Links, in the order of resources and without repeated URLs.
+func ApplyDevResources(components []Component, resources []figma.DevResource) {
+	byNode := make(map[string][]figma.DevResource)
+	for _, r := range resources {
+		byNode[r.NodeID] = append(byNode[r.NodeID], r)
+	}
+
+	for i := range components {
+		c := &components[i]
+		ids := []string{c.ID}
+		for _, v := range c.Variants {
+			ids = append(ids, v.ID)
+		}
+		seen := make(map[string]bool)
+		for _, id := range ids {
+			for _, r := range byNode[id] {
+				if r.URL == "" || seen[r.URL] {
+					continue
+				}
+				seen[r.URL] = true
+				name := r.Name
+				if name == "" {
+					name = r.URL
+				}
+				c.Links = append(c.Links, ComponentLink{Name: name, URL: r.URL})
+			}
+		}
+	}
+}
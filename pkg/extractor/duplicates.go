@@ -0,0 +1,157 @@
+package extractor
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hellenic-development/figma-extractor/pkg/figma"
+)
+
+// DuplicateGroup is a set of frames, groups or components that look the same: identical
+// structure, sizes, paints, effects, layout and text, regardless of layer names and position on
+// the canvas. Only the original contributes to the token maps.
+type DuplicateGroup struct {
+	Type     string          `json:"type,omitempty"`
+	Width    float64         `json:"width,omitempty"`
+	Height   float64         `json:"height,omitempty"`
+	Original DuplicateNode   `json:"original"`         // the first in document order
+	Copies   []DuplicateNode `json:"copies,omitempty"` // in document order
+}
+
+// DuplicateNode identifies one member of a DuplicateGroup.
+type DuplicateNode struct {
+	NodeID string `json:"nodeId"`
+	Name   string `json:"name"`
+	Page   string `json:"page,omitempty"`
+}
+
+// findDuplicates returns the groups of duplicate frames, groups and components under roots, in
+// document order. A copy is not searched for further duplicates, so its children, which
+// duplicate the original's, are not reported again. Instances are expected to repeat their main
+// component and are skipped. pages holds the page of each root, "" for the document.
+func findDuplicates(roots []*figma.Node, pages []string) []DuplicateGroup {
+	fingerprints := make(map[*figma.Node]string)
+	index := make(map[string]int) // fingerprint -> position in groups
+	var groups []DuplicateGroup
+
+	var visit func(node *figma.Node, page string)
+	visit = func(node *figma.Node, page string) {
+		switch node.Type {
+		case "CANVAS":
+			page = node.Name
+		case "INSTANCE":
+			return
+		case "FRAME", "GROUP", "COMPONENT":
+			if len(node.Children) == 0 {
+				break
+			}
+			fp := fingerprint(node, fingerprints)
+			member := DuplicateNode{NodeID: node.ID, Name: node.Name, Page: page}
+			if i, ok := index[fp]; ok {
+				groups[i].Copies = append(groups[i].Copies, member)
+				return
+			}
+			index[fp] = len(groups)
+			g := DuplicateGroup{Type: node.Type, Original: member}
+			if node.AbsoluteBoundingBox != nil {
+				g.Width, g.Height = node.AbsoluteBoundingBox.Width, node.AbsoluteBoundingBox.Height
+			}
+			groups = append(groups, g)
+		}
+		for i := range node.Children {
+			visit(&node.Children[i], page)
+		}
+	}
+	for i, root := range roots {
+		visit(root, pages[i])
+	}
+
+	// Keep only the fingerprints seen more than once.
+	duplicates := groups[:0]
+	for _, g := range groups {
+		if len(g.Copies) > 0 {
+			duplicates = append(duplicates, g)
+		}
+	}
+	return duplicates
+}
+
+// duplicateCopies returns the IDs of the copies of every group.
+func duplicateCopies(groups []DuplicateGroup) map[string]bool {
+	copies := make(map[string]bool)
+	for _, g := range groups {
+		for _, c := range g.Copies {
+			copies[c.NodeID] = true
+		}
+	}
+	return copies
+}
+
+// collapseDuplicates marks the copies of every group in the node tree with the name of their
+// original and drops their children, which repeat the original's.
+func collapseDuplicates(roots []*NodeDescription, groups []DuplicateGroup) {
+	originals := make(map[string]string) // copy ID -> original name
+	for _, g := range groups {
+		for _, c := range g.Copies {
+			originals[c.NodeID] = g.Original.Name
+		}
+	}
+	if len(originals) == 0 {
+		return
+	}
+
+	var visit func(nd *NodeDescription)
+	visit = func(nd *NodeDescription) {
+		if original, ok := originals[nd.ID]; ok {
+			nd.DuplicateOf = original
+			nd.Children = nil
+			return
+		}
+		for _, child := range nd.Children {
+			visit(child)
+		}
+	}
+	for _, root := range roots {
+		visit(root)
+	}
+}
+
+// fingerprint hashes everything that affects how node renders: its visual properties, size,
+// and the fingerprints and relative positions of its children. Identity (ID, name), position on
+// the canvas, prototype links and export settings are ignored. Results are memoized in cache.
+func fingerprint(node *figma.Node, cache map[*figma.Node]string) string {
+	if fp, ok := cache[node]; ok {
+		return fp
+	}
+
+	visual := *node
+	visual.ID, visual.Name = "", ""
+	visual.Children = nil
+	visual.AbsoluteBoundingBox = nil
+	visual.ExportSettings = nil
+	visual.Interactions = nil
+	visual.TransitionNodeID, visual.TransitionDuration, visual.TransitionEasing = "", 0, ""
+	visual.FlowStartingPoints = nil
+	visual.ComponentPropertyDefinitions = nil
+
+	h := sha1.New()
+	props, _ := json.Marshal(visual)
+	h.Write(props)
+	box := node.AbsoluteBoundingBox
+	if box != nil {
+		fmt.Fprintf(h, "|%.2fx%.2f", box.Width, box.Height)
+	}
+	for i := range node.Children {
+		child := &node.Children[i]
+		if box != nil && child.AbsoluteBoundingBox != nil {
+			fmt.Fprintf(h, "|%.2f,%.2f", child.AbsoluteBoundingBox.X-box.X, child.AbsoluteBoundingBox.Y-box.Y)
+		}
+		fmt.Fprintf(h, "|%s", fingerprint(child, cache))
+	}
+
+	fp := hex.EncodeToString(h.Sum(nil))
+	cache[node] = fp
+	return fp
+}
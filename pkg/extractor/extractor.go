@@ -11,123 +11,272 @@ import (
 
 // DesignSpecs represents the complete set of design specifications extracted from a Figma file.
 // It includes color palettes, typography settings, spacing values, shadows, border radii, layout measurements,
-// and optionally exported image assets.
+// and optionally Figma variables and exported image assets.
 type DesignSpecs struct {
-	Colors         ColorPalette
-	Typography     Typography
-	Spacing        Spacing
-	Shadows        []Shadow
-	Radii          BorderRadii
-	Layout         LayoutSpecs
-	ExportedAssets []ExportedAssetInfo
-	NodeTree       []*NodeDescription
+	Colors         ColorPalette         `json:"colors"`
+	Typography     Typography           `json:"typography"`
+	TextStyles     map[string]TextStyle `json:"textStyles,omitempty"`   // composite text styles keyed by Figma TEXT style name
+	Components     []Component          `json:"components,omitempty"`   // component inventory, sorted by name
+	Icons          []Icon               `json:"icons,omitempty"`        // small vector graphics as inline SVG, in document order; needs vector paths
+	Strings        []TextString         `json:"strings,omitempty"`      // copy of the text layers with their frame paths, in document order
+	Flows          []Flow               `json:"flows,omitempty"`        // prototype flows, in document order
+	Interactions   []Interaction        `json:"interactions,omitempty"` // prototype interactions, in document order
+	Comments       []Comment            `json:"comments,omitempty"`     // unresolved comment threads, populated from the Comments API
+	Spacing        Spacing              `json:"spacing"`
+	Shadows        []Shadow             `json:"shadows,omitempty"`
+	Blurs          []Blur               `json:"blurs,omitempty"`
+	Borders        map[string]Border    `json:"borders,omitempty"`    // border styles keyed by stroke token name
+	BlendModes     []BlendModeUsage     `json:"blendModes,omitempty"` // layers and paints with a non-NORMAL blend mode, in document order
+	Radii          BorderRadii          `json:"radii"`
+	Layout         LayoutSpecs          `json:"layout"`
+	Variables      []VariableCollection `json:"variables,omitempty"`  // populated from the Variables API, one token set per mode
+	Themes         *ColorThemes         `json:"themes,omitempty"`     // coordinated light and dark palettes, nil when the file has no dark theme
+	Duplicates     []DuplicateGroup     `json:"duplicates,omitempty"` // identical frames, groups and components, in document order
+	ExportedAssets []ExportedAssetInfo  `json:"exportedAssets,omitempty"`
+	NodeTree       []*NodeDescription   `json:"nodeTree,omitempty"`
+	Frames         []Frame              `json:"frames,omitempty"` // each extracted node on its own, see ExtractFrames; nil unless requested
+	Custom         map[string]any       `json:"custom,omitempty"` // data of custom analyzers, see Analysis.Set
 }
 
 // ExportedAssetInfo represents metadata about an exported image asset.
 type ExportedAssetInfo struct {
-	NodeID       string // Figma node ID this asset was exported from
-	NodeName     string
-	FileName     string
-	Format       string
-	Scale        float64
-	IsScreenshot bool // true for the complete design screenshot of the target node(s)
+	NodeID       string  `json:"nodeId"` // Figma node ID this asset was exported from
+	NodeName     string  `json:"nodeName,omitempty"`
+	FileName     string  `json:"fileName,omitempty"` // path relative to the image directory, slash-separated
+	Format       string  `json:"format,omitempty"`
+	Scale        float64 `json:"scale,omitempty"`
+	IsScreenshot bool    `json:"isScreenshot,omitempty"` // true for the complete design screenshot of the target node(s)
 }
 
 // NodeDescription describes a single node in the Figma design hierarchy with its visual properties.
 type NodeDescription struct {
-	ID   string
-	Name string
-	Type string // FRAME, TEXT, RECTANGLE, COMPONENT, INSTANCE, GROUP, etc.
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type,omitempty"` // FRAME, TEXT, RECTANGLE, COMPONENT, INSTANCE, GROUP, etc.
 
 	// Dimensions
-	Width, Height float64
+	Width  float64 `json:"width,omitempty"`
+	Height float64 `json:"height,omitempty"`
 
 	// Visual
-	FillColors   []string // hex from SOLID fills
-	ImageFills   []string // imageRef values from IMAGE fills
-	StrokeColors []string
-	StrokeWeight float64
-	CornerRadius float64
+	FillColors   []string  `json:"fillColors,omitempty"` // hex from SOLID fills
+	ImageFills   []string  `json:"imageFills,omitempty"` // imageRef values from IMAGE fills
+	StrokeColors []string  `json:"strokeColors,omitempty"`
+	StrokeWeight float64   `json:"strokeWeight,omitempty"`
+	StrokeDashes []float64 `json:"strokeDashes,omitempty"`
+	StrokeAlign  string    `json:"strokeAlign,omitempty"` // INSIDE, OUTSIDE, CENTER
+	CornerRadius float64   `json:"cornerRadius,omitempty"`
 
 	// Text (TEXT nodes only)
-	TextContent         string
-	FontFamily          string
-	FontSize            float64
-	FontWeight          float64
-	LineHeightPx        float64
-	TextAlignHorizontal string
+	TextContent         string  `json:"textContent,omitempty"`
+	FontFamily          string  `json:"fontFamily,omitempty"`
+	FontSize            float64 `json:"fontSize,omitempty"`
+	FontWeight          float64 `json:"fontWeight,omitempty"`
+	LineHeightPx        float64 `json:"lineHeightPx,omitempty"`
+	LetterSpacing       float64 `json:"letterSpacing,omitempty"`
+	TextAlignHorizontal string  `json:"textAlignHorizontal,omitempty"`
+	TextCase            string  `json:"textCase,omitempty"`
+	TextDecoration      string  `json:"textDecoration,omitempty"`
 
 	// Layout (auto-layout)
-	LayoutMode                                         string // "HORIZONTAL", "VERTICAL", ""
-	PaddingTop, PaddingRight, PaddingBottom, PaddingLeft float64
-	ItemSpacing                                        float64
+	LayoutMode            string  `json:"layoutMode,omitempty"` // "HORIZONTAL", "VERTICAL", ""
+	PaddingTop            float64 `json:"paddingTop,omitempty"`
+	PaddingRight          float64 `json:"paddingRight,omitempty"`
+	PaddingBottom         float64 `json:"paddingBottom,omitempty"`
+	PaddingLeft           float64 `json:"paddingLeft,omitempty"`
+	ItemSpacing           float64 `json:"itemSpacing,omitempty"`
+	CounterAxisSpacing    float64 `json:"counterAxisSpacing,omitempty"`
+	PrimaryAxisAlignItems string  `json:"primaryAxisAlignItems,omitempty"` // MIN, CENTER, MAX, SPACE_BETWEEN
+	CounterAxisAlignItems string  `json:"counterAxisAlignItems,omitempty"` // MIN, CENTER, MAX, BASELINE
+	PrimaryAxisSizingMode string  `json:"primaryAxisSizingMode,omitempty"` // FIXED or AUTO (hug contents)
+	CounterAxisSizingMode string  `json:"counterAxisSizingMode,omitempty"` // FIXED or AUTO (hug contents)
+	LayoutWrap            string  `json:"layoutWrap,omitempty"`            // NO_WRAP, WRAP
+
+	// Resizing: constraints (LEFT, RIGHT, CENTER, LEFT_RIGHT, SCALE / TOP, BOTTOM, CENTER,
+	// TOP_BOTTOM, SCALE) and size limits, 0 = unset
+	ConstraintHorizontal string  `json:"constraintHorizontal,omitempty"`
+	ConstraintVertical   string  `json:"constraintVertical,omitempty"`
+	MinWidth             float64 `json:"minWidth,omitempty"`
+	MaxWidth             float64 `json:"maxWidth,omitempty"`
+	MinHeight            float64 `json:"minHeight,omitempty"`
+	MaxHeight            float64 `json:"maxHeight,omitempty"`
+
+	// Layout as a child of an auto-layout frame
+	LayoutGrow             float64 `json:"layoutGrow,omitempty"`
+	LayoutAlign            string  `json:"layoutAlign,omitempty"`            // INHERIT, STRETCH
+	LayoutPositioning      string  `json:"layoutPositioning,omitempty"`      // AUTO, ABSOLUTE
+	LayoutSizingHorizontal string  `json:"layoutSizingHorizontal,omitempty"` // FIXED, HUG, FILL
+	LayoutSizingVertical   string  `json:"layoutSizingVertical,omitempty"`   // FIXED, HUG, FILL
 
 	// Effects
-	Shadows []Shadow
+	Shadows []Shadow `json:"shadows,omitempty"`
+	Blurs   []Blur   `json:"blurs,omitempty"`
+
+	// Blend modes other than NORMAL (and PASS_THROUGH), e.g. "MULTIPLY" for the layer itself
+	// and "fill:SCREEN" for paints
+	BlendMode       string   `json:"blendMode,omitempty"`
+	PaintBlendModes []string `json:"paintBlendModes,omitempty"`
+
+	// Name of the node this one duplicates; the children of a duplicate are omitted
+	DuplicateOf string `json:"duplicateOf,omitempty"`
+
+	// Data stored on the node by plugins, keyed "<plugin ID or namespace>.<key>"; only set for
+	// the plugins whose data was requested
+	PluginData map[string]string `json:"pluginData,omitempty"`
 
 	// Linked exported assets (populated after image export)
-	ExportedAssets []ExportedAssetInfo
+	ExportedAssets []ExportedAssetInfo `json:"exportedAssets,omitempty"`
 
 	// Recursive children
-	Children []*NodeDescription
+	Children []*NodeDescription `json:"children,omitempty"`
 }
 
 // ColorPalette organizes colors into semantic categories for easier reference and usage.
 // Colors are categorized as Primary, Secondary, Background, Text, Status (success/error/warning), and Border colors.
+// Colors whose names give no hint are classified by hue and lightness into Brand, Accent and Neutral.
 type ColorPalette struct {
-	Primary    map[string]string
-	Secondary  map[string]string
-	Background map[string]string
-	Text       map[string]string
-	Status     map[string]string
-	Border     map[string]string
+	Primary    map[string]string `json:"primary,omitempty"`
+	Secondary  map[string]string `json:"secondary,omitempty"`
+	Background map[string]string `json:"background,omitempty"`
+	Text       map[string]string `json:"text,omitempty"`
+	Status     map[string]string `json:"status,omitempty"`
+	Border     map[string]string `json:"border,omitempty"`
+
+	// Inferred from color science, keyed by hue family and lightness step (e.g. "blue-500";
+	// neutrals by lightness step only, e.g. "100").
+	Brand   map[string]string `json:"brand,omitempty"`   // the most used hue family
+	Accent  map[string]string `json:"accent,omitempty"`  // other saturated colors
+	Neutral map[string]string `json:"neutral,omitempty"` // grays, near-black and near-white
+
+	Usage map[string]int `json:"usage,omitempty"` // hex -> number of fills, strokes and backgrounds using the color
 }
 
-// Typography holds all font-related specifications including font family, sizes, weights, and line heights.
+// Typography holds all font-related specifications including font family, sizes, weights, line heights,
+// letter spacing, text case and text decoration.
 // Font sizes and other values are normalized to a standard scale for consistency across the design system.
 type Typography struct {
-	FontFamily  string
-	FontSizes   map[string]float64
-	FontWeights map[string]float64
-	LineHeights map[string]float64
+	FontFamily      string             `json:"fontFamily,omitempty"`
+	FontSizes       map[string]float64 `json:"fontSizes,omitempty"`
+	FontWeights     map[string]float64 `json:"fontWeights,omitempty"`
+	LineHeights     map[string]float64 `json:"lineHeights,omitempty"`
+	LetterSpacings  map[string]float64 `json:"letterSpacings,omitempty"`  // in px, only non-zero values
+	TextCases       map[string]string  `json:"textCases,omitempty"`       // Figma text case: UPPER, LOWER, TITLE, SMALL_CAPS, SMALL_CAPS_FORCED
+	TextDecorations map[string]string  `json:"textDecorations,omitempty"` // Figma text decoration: UNDERLINE, STRIKETHROUGH
+	Fonts           []Font             `json:"fonts,omitempty"`           // every font family of the text layers, most used first
+}
+
+// TextStyle is a composite text style: every typographic property of a Figma TEXT style
+// (e.g. "Heading/H1") kept together, as opposed to the per-property maps of Typography.
+type TextStyle struct {
+	Name           string  `json:"name"`
+	FontFamily     string  `json:"fontFamily,omitempty"`
+	FontSize       float64 `json:"fontSize,omitempty"`
+	FontWeight     float64 `json:"fontWeight,omitempty"`
+	LineHeight     float64 `json:"lineHeight,omitempty"`     // in px, 0 = auto
+	LetterSpacing  float64 `json:"letterSpacing,omitempty"`  // in px
+	TextCase       string  `json:"textCase,omitempty"`       // Figma text case, empty = original
+	TextDecoration string  `json:"textDecoration,omitempty"` // Figma text decoration, empty = none
 }
 
 // Spacing defines the spacing scale used throughout the design.
 // Values are normalized to a standard scale, typically in multiples of 4 pixels for consistency.
 type Spacing struct {
-	Values map[string]float64
+	Values map[string]float64 `json:"values,omitempty"`
 }
 
 // Shadow represents a visual shadow effect with its positioning, blur, spread, and color properties.
 // Supports both DROP_SHADOW and INNER_SHADOW types from Figma.
 type Shadow struct {
-	Name   string
-	Type   string
-	X      float64
-	Y      float64
-	Blur   float64
-	Spread float64
-	Color  string
+	Name   string  `json:"name"`
+	Type   string  `json:"type,omitempty"`
+	X      float64 `json:"x,omitempty"`
+	Y      float64 `json:"y,omitempty"`
+	Blur   float64 `json:"blur,omitempty"`
+	Spread float64 `json:"spread,omitempty"`
+	Color  string  `json:"color,omitempty"`
+}
+
+// Blur represents a LAYER_BLUR (blurs the node itself) or BACKGROUND_BLUR (blurs what is
+// behind the node) effect.
+type Blur struct {
+	Name   string  `json:"name"`
+	Type   string  `json:"type,omitempty"`
+	Radius float64 `json:"radius,omitempty"` // Figma blur radius, twice the CSS blur() radius
+}
+
+// Border is the complete stroke of a node expressed as a border style: color, width, line style
+// (solid, dashed or dotted) and where the stroke is drawn relative to the node's edge.
+type Border struct {
+	Name   string    `json:"name"`
+	Color  string    `json:"color,omitempty"`
+	Width  float64   `json:"width,omitempty"`  // uniform weight; the largest side when sides differ
+	Style  string    `json:"style,omitempty"`  // "solid", "dashed" or "dotted"
+	Align  string    `json:"align,omitempty"`  // Figma stroke align: INSIDE, OUTSIDE, CENTER
+	Dashes []float64 `json:"dashes,omitempty"` // dash and gap lengths of dashed strokes
+	Cap    string    `json:"cap,omitempty"`    // Figma stroke cap, empty = NONE
+	Join   string    `json:"join,omitempty"`   // Figma stroke join, empty = MITER
+
+	// Sides holds the top, right, bottom and left weights when they differ, nil otherwise.
+	Sides []float64 `json:"sides,omitempty"`
 }
 
 // BorderRadii defines the border radius values used in the design system.
 // Values are normalized to standard sizes (sm, md, lg, xl, 2xl) for consistent rounded corners.
 type BorderRadii struct {
-	Values map[string]float64
+	Values map[string]float64 `json:"values,omitempty"`
 }
 
 // LayoutSpecs captures common layout dimensions such as header heights, sidebar widths, and content padding.
 // These measurements are automatically detected from nodes with relevant names in the Figma file.
 type LayoutSpecs struct {
-	HeaderHeight   float64
-	SidebarWidth   float64
-	ContentPadding float64
+	HeaderHeight   float64 `json:"headerHeight,omitempty"`
+	SidebarWidth   float64 `json:"sidebarWidth,omitempty"`
+	ContentPadding float64 `json:"contentPadding,omitempty"`
+
+	// Resizing lists the layers with non-default constraints or size limits, in document order.
+	Resizing []ResizeBehavior `json:"resizing,omitempty"`
+
+	// AutoLayouts holds the full auto-layout specification of every auto-layout frame,
+	// keyed by layer name. The first frame with a given name wins.
+	AutoLayouts map[string]AutoLayout `json:"autoLayouts,omitempty"`
+
+	// Breakpoints are the viewport widths of screens designed at several device classes,
+	// ordered by width; Screens holds the layout of each such screen per breakpoint.
+	Breakpoints []Breakpoint `json:"breakpoints,omitempty"`
+	Screens     []Screen     `json:"screens,omitempty"`
+
+	// Grids are the column grids of the top-level frames, declared as layout grids or inferred
+	// from their content, in document order.
+	Grids []ColumnGrid `json:"grids,omitempty"`
 }
 
 // Extract analyzes a Figma file response and extracts all design specifications including colors,
 // typography, spacing, shadows, border radii, and layout measurements. The extracted values are
 // normalized and deduplicated for consistency in the final design system.
 func Extract(fileResp *figma.FileResponse) *DesignSpecs {
+	return Config{}.Extract(fileResp)
+}
+
+// Config customizes an extraction. The zero Config extracts like the package-level functions.
+type Config struct {
+	ColorRules *ColorRules // categorizes colors by name patterns instead of keywords; nil = keywords
+	Analyzers  []Analyzer  // run over every node after the built-in analyzers, see Analyzer
+
+	// CategorizeColor, when set, picks the palette category of every fill color by the style or
+	// layer name and hex value of the color, before the color rules or keywords. Returning ""
+	// (or an unknown category) leaves the color to them, e.g.:
+	//
+	//	func(name, hex string) extractor.Category {
+	//		if strings.Contains(strings.ToLower(name), "κύριο") {
+	//			return extractor.CategoryPrimary
+	//		}
+	//		return ""
+	//	}
+	CategorizeColor func(nodeName, hex string) Category
+}
+
+// Extract is like the package-level Extract, with the customizations of c.
+func (c Config) Extract(fileResp *figma.FileResponse) *DesignSpecs {
 	specs := &DesignSpecs{
 		Colors: ColorPalette{
 			Primary:    make(map[string]string),
@@ -136,12 +285,20 @@ func Extract(fileResp *figma.FileResponse) *DesignSpecs {
 			Text:       make(map[string]string),
 			Status:     make(map[string]string),
 			Border:     make(map[string]string),
+			Brand:      make(map[string]string),
+			Accent:     make(map[string]string),
+			Neutral:    make(map[string]string),
+			Usage:      make(map[string]int),
 		},
 		Typography: Typography{
-			FontSizes:   make(map[string]float64),
-			FontWeights: make(map[string]float64),
-			LineHeights: make(map[string]float64),
+			FontSizes:       make(map[string]float64),
+			FontWeights:     make(map[string]float64),
+			LineHeights:     make(map[string]float64),
+			LetterSpacings:  make(map[string]float64),
+			TextCases:       make(map[string]string),
+			TextDecorations: make(map[string]string),
 		},
+		TextStyles: make(map[string]TextStyle),
 		Spacing: Spacing{
 			Values: make(map[string]float64),
 		},
@@ -149,14 +306,50 @@ func Extract(fileResp *figma.FileResponse) *DesignSpecs {
 			Values: make(map[string]float64),
 		},
 		Shadows: []Shadow{},
-		Layout:  LayoutSpecs{},
+		Borders: make(map[string]Border),
+		Layout: LayoutSpecs{
+			AutoLayouts: make(map[string]AutoLayout),
+		},
 	}
 
-	// Extract colors, typography, and other specs
-	extractFromNode(&fileResp.Document, specs)
+	// Find duplicate frames; only the original of each contributes tokens
+	specs.Duplicates = findDuplicates([]*figma.Node{&fileResp.Document}, []string{""})
+	copies := duplicateCopies(specs.Duplicates)
+
+	// Run the analyzers over the document: colors, typography, and other specs
+	c.analyze(&fileResp.Document, specs, fileResp.Styles, copies)
+
+	// Collect the component inventory
+	meta := componentMeta{components: fileResp.Components, sets: fileResp.ComponentSets}
+	collectComponents(&fileResp.Document, "", meta, &specs.Components)
+	specs.Components = sortComponents(specs.Components)
+
+	// Collect responsive resize behavior
+	collectResizing(&fileResp.Document, nil, &specs.Layout.Resizing)
+
+	// Collect prototype flows and interactions
+	names := make(map[string]string)
+	nodeNames(&fileResp.Document, names)
+	collectInteractions(&fileResp.Document, "", names, &specs.Flows, &specs.Interactions)
+
+	// Detect parallel light and dark frames
+	specs.Themes = detectFrameThemes([]*figma.Node{&fileResp.Document}, fileResp.Styles)
+
+	// Group screens designed at several breakpoints
+	specs.Layout.Breakpoints, specs.Layout.Screens = detectScreens([]*figma.Node{&fileResp.Document})
+
+	// Report the column grids of the frames, inferring those without a layout grid
+	specs.Layout.Grids = collectGrids([]*figma.Node{&fileResp.Document}, copies)
+
+	// Draw icons from their vector paths
+	specs.Icons = collectIcons([]*figma.Node{&fileResp.Document})
 
-	// Build hierarchical node tree
+	// Collect the copy of the text layers
+	specs.Strings = collectStrings([]*figma.Node{&fileResp.Document}, nil, copies)
+
+	// Build hierarchical node tree, collapsing duplicate copies
 	specs.NodeTree = []*NodeDescription{buildNodeTree(&fileResp.Document)}
+	collapseDuplicates(specs.NodeTree, specs.Duplicates)
 
 	// Normalize and categorize extracted values
 	normalizeSpecs(specs)
@@ -176,6 +369,11 @@ func Extract(fileResp *figma.FileResponse) *DesignSpecs {
 //
 // Returns a DesignSpecs containing specifications from the target nodes, optionally merged with file-level context.
 func ExtractNodes(fileResp *figma.FileResponse, nodesResp *figma.NodesResponse, nodeIDs []string, inheritFileContext bool) *DesignSpecs {
+	return Config{}.ExtractNodes(fileResp, nodesResp, nodeIDs, inheritFileContext)
+}
+
+// ExtractNodes is like the package-level ExtractNodes, with the customizations of c.
+func (c Config) ExtractNodes(fileResp *figma.FileResponse, nodesResp *figma.NodesResponse, nodeIDs []string, inheritFileContext bool) *DesignSpecs {
 	specs := &DesignSpecs{
 		Colors: ColorPalette{
 			Primary:    make(map[string]string),
@@ -184,12 +382,20 @@ func ExtractNodes(fileResp *figma.FileResponse, nodesResp *figma.NodesResponse,
 			Text:       make(map[string]string),
 			Status:     make(map[string]string),
 			Border:     make(map[string]string),
+			Brand:      make(map[string]string),
+			Accent:     make(map[string]string),
+			Neutral:    make(map[string]string),
+			Usage:      make(map[string]int),
 		},
 		Typography: Typography{
-			FontSizes:   make(map[string]float64),
-			FontWeights: make(map[string]float64),
-			LineHeights: make(map[string]float64),
+			FontSizes:       make(map[string]float64),
+			FontWeights:     make(map[string]float64),
+			LineHeights:     make(map[string]float64),
+			LetterSpacings:  make(map[string]float64),
+			TextCases:       make(map[string]string),
+			TextDecorations: make(map[string]string),
 		},
+		TextStyles: make(map[string]TextStyle),
 		Spacing: Spacing{
 			Values: make(map[string]float64),
 		},
@@ -197,28 +403,85 @@ func ExtractNodes(fileResp *figma.FileResponse, nodesResp *figma.NodesResponse,
 			Values: make(map[string]float64),
 		},
 		Shadows: []Shadow{},
-		Layout:  LayoutSpecs{},
+		Borders: make(map[string]Border),
+		Layout: LayoutSpecs{
+			AutoLayouts: make(map[string]AutoLayout),
+		},
 	}
 
 	// Optionally extract file-level context from the document root
 	// This includes published styles, global colors, and typography definitions
 	if inheritFileContext {
-		extractFileContext(&fileResp.Document, specs)
+		extractFileContext(&fileResp.Document, specs, fileResp.Styles, c)
 	}
 
+	// Find duplicate frames among the target nodes; only the original of each contributes tokens
+	var roots []*figma.Node
+	var pages []string
+	styles := fileResp.Styles
+	for _, nodeID := range nodeIDs {
+		if nodeData, exists := nodesResp.Nodes[nodeID]; exists {
+			roots = append(roots, &nodeData.Document)
+			pages = append(pages, pageOf(&fileResp.Document, nodeID))
+			styles = mergeLookups(styles, nodeData.Styles)
+		}
+	}
+	specs.Duplicates = findDuplicates(roots, pages)
+	copies := duplicateCopies(specs.Duplicates)
+
 	// Extract specifications from each target node
 	for _, nodeID := range nodeIDs {
 		if nodeData, exists := nodesResp.Nodes[nodeID]; exists {
-			extractFromNode(&nodeData.Document, specs)
+			c.analyze(&nodeData.Document, specs, mergeLookups(fileResp.Styles, nodeData.Styles), copies)
+
+			meta := componentMeta{
+				components: mergeLookups(fileResp.Components, nodeData.Components),
+				sets:       mergeLookups(fileResp.ComponentSets, nodeData.ComponentSets),
+			}
+			collectComponents(&nodeData.Document, pageOf(&fileResp.Document, nodeID), meta, &specs.Components)
+			collectResizing(&nodeData.Document, nil, &specs.Layout.Resizing)
 		}
 	}
+	specs.Components = sortComponents(specs.Components)
+
+	// Collect prototype interactions of the target nodes. Destinations are resolved against
+	// the whole document, so links leaving the extracted nodes keep their names.
+	names := make(map[string]string)
+	nodeNames(&fileResp.Document, names)
+	for _, nodeID := range nodeIDs {
+		if nodeData, exists := nodesResp.Nodes[nodeID]; exists {
+			nodeNames(&nodeData.Document, names)
+		}
+	}
+	for _, nodeID := range nodeIDs {
+		if nodeData, exists := nodesResp.Nodes[nodeID]; exists {
+			collectInteractions(&nodeData.Document, pageOf(&fileResp.Document, nodeID), names, &specs.Flows, &specs.Interactions)
+			specs.Flows = append(specs.Flows, flowsStartingIn(&fileResp.Document, &nodeData.Document, names)...)
+		}
+	}
+
+	// Detect parallel light and dark frames among the target nodes
+	specs.Themes = detectFrameThemes(roots, styles)
+
+	// Group screens designed at several breakpoints among the target nodes
+	specs.Layout.Breakpoints, specs.Layout.Screens = detectScreens(roots)
+
+	// Report the column grids of the target frames, inferring those without a layout grid
+	specs.Layout.Grids = collectGrids(roots, copies)
+
+	// Draw icons among the target nodes from their vector paths
+	specs.Icons = collectIcons(roots)
+
+	// Collect the copy of the text layers of the target nodes
+	specs.Strings = collectStrings(roots, pages, copies)
 
-	// Build hierarchical node tree for each target node
+	// Build hierarchical node tree for each target node, collapsing duplicate copies
 	for _, nodeID := range nodeIDs {
 		if nodeData, exists := nodesResp.Nodes[nodeID]; exists {
 			specs.NodeTree = append(specs.NodeTree, buildNodeTree(&nodeData.Document))
 		}
 	}
+	collapseDuplicates(specs.NodeTree, specs.Duplicates)
 
 	// Normalize and categorize extracted values (deduplicates automatically)
 	normalizeSpecs(specs)
@@ -230,23 +493,24 @@ func ExtractNodes(fileResp *figma.FileResponse, nodesResp *figma.NodesResponse,
 // This includes document-level colors, styles, and typography that should be preserved even when
 // extracting specific nodes. It processes the root node and its direct children (typically pages/frames
 // that contain design system definitions), but doesn't recurse deeper to avoid extracting the entire file.
-func extractFileContext(node *figma.Node, specs *DesignSpecs) {
+func extractFileContext(node *figma.Node, specs *DesignSpecs, styles map[string]figma.Style, c Config) {
 	// Extract properties from the document root itself
-	extractNodeProperties(node, specs)
+	extractNodeProperties(node, specs, styles, c)
 
 	// Also process immediate children (one level deep)
 	// These often contain style pages, color palettes, or design system definitions
 	for _, child := range node.Children {
-		extractNodeProperties(&child, specs)
+		extractNodeProperties(&child, specs, styles, c)
 	}
 }
 
 // extractNodeProperties extracts design properties from a single node without recursing.
 // This is used by extractFileContext to gather file-level context without processing entire subtrees.
-func extractNodeProperties(node *figma.Node, specs *DesignSpecs) {
+func extractNodeProperties(node *figma.Node, specs *DesignSpecs, styles map[string]figma.Style, c Config) {
 	// Extract background colors
 	if node.BackgroundColor != nil {
 		colorHex := colorToHex(node.BackgroundColor)
+		specs.Colors.Usage[colorHex]++
 		specs.Colors.Background[node.Name] = colorHex
 	}
 
@@ -254,7 +518,8 @@ func extractNodeProperties(node *figma.Node, specs *DesignSpecs) {
 	for _, fill := range node.Fills {
 		if fill.Type == "SOLID" && fill.Color != nil && fill.Visible {
 			colorHex := colorToHex(fill.Color)
-			categorizeColor(node.Name, colorHex, specs)
+			specs.Colors.Usage[colorHex]++
+			c.categorizeColor(tokenName(node, "fill", styles), colorHex, specs)
 		}
 	}
 
@@ -262,7 +527,12 @@ func extractNodeProperties(node *figma.Node, specs *DesignSpecs) {
 	for _, stroke := range node.Strokes {
 		if stroke.Type == "SOLID" && stroke.Color != nil && stroke.Visible {
 			colorHex := colorToHex(stroke.Color)
-			specs.Colors.Border[node.Name] = colorHex
+			specs.Colors.Usage[colorHex]++
+			name := tokenName(node, "stroke", styles)
+			specs.Colors.Border[name] = colorHex
+			if _, seen := specs.Borders[name]; !seen && strokeWeight(node) > 0 {
+				specs.Borders[name] = newBorder(node, name, colorHex)
+			}
 		}
 	}
 
@@ -271,22 +541,37 @@ func extractNodeProperties(node *figma.Node, specs *DesignSpecs) {
 		if node.Style.FontFamily != "" && specs.Typography.FontFamily == "" {
 			specs.Typography.FontFamily = node.Style.FontFamily
 		}
+		addFont(&specs.Typography, node.Style)
 		if node.Style.FontSize > 0 {
-			specs.Typography.FontSizes[node.Name] = node.Style.FontSize
+			specs.Typography.FontSizes[tokenName(node, "text", styles)] = node.Style.FontSize
 		}
 		if node.Style.FontWeight > 0 {
-			specs.Typography.FontWeights[node.Name] = node.Style.FontWeight
+			specs.Typography.FontWeights[tokenName(node, "text", styles)] = node.Style.FontWeight
 		}
 		if node.Style.LineHeightPx > 0 {
-			specs.Typography.LineHeights[node.Name] = node.Style.LineHeightPx
+			specs.Typography.LineHeights[tokenName(node, "text", styles)] = node.Style.LineHeightPx
+		}
+		if node.Style.LetterSpacing != 0 {
+			specs.Typography.LetterSpacings[tokenName(node, "text", styles)] = node.Style.LetterSpacing
+		}
+		if node.Style.TextCase != "" && node.Style.TextCase != "ORIGINAL" {
+			specs.Typography.TextCases[tokenName(node, "text", styles)] = node.Style.TextCase
+		}
+		if node.Style.TextDecoration != "" && node.Style.TextDecoration != "NONE" {
+			specs.Typography.TextDecorations[tokenName(node, "text", styles)] = node.Style.TextDecoration
+		}
+		if name, ok := styleName(node, "text", styles); ok {
+			if _, seen := specs.TextStyles[name]; !seen {
+				specs.TextStyles[name] = newTextStyle(name, node.Style)
+			}
 		}
 	}
 
-	// Extract shadows
+	// Extract shadows and blurs
 	for _, effect := range node.Effects {
 		if (effect.Type == "DROP_SHADOW" || effect.Type == "INNER_SHADOW") && effect.Visible {
 			shadow := Shadow{
-				Name:   node.Name,
+				Name:   tokenName(node, "effect", styles),
 				Type:   effect.Type,
 				X:      effect.Offset.X,
 				Y:      effect.Offset.Y,
@@ -296,6 +581,13 @@ func extractNodeProperties(node *figma.Node, specs *DesignSpecs) {
 			}
 			specs.Shadows = append(specs.Shadows, shadow)
 		}
+		if (effect.Type == "LAYER_BLUR" || effect.Type == "BACKGROUND_BLUR") && effect.Visible {
+			specs.Blurs = append(specs.Blurs, Blur{
+				Name:   tokenName(node, "effect", styles),
+				Type:   effect.Type,
+				Radius: effect.Radius,
+			})
+		}
 	}
 
 	// Extract border radii
@@ -304,101 +596,125 @@ func extractNodeProperties(node *figma.Node, specs *DesignSpecs) {
 	}
 }
 
-// extractFromNode recursively traverses the Figma document tree and extracts design specifications
-// from each node. It processes fills, strokes, background colors, typography, shadows, border radii,
-// spacing from layout properties, and layout dimensions.
-func extractFromNode(node *figma.Node, specs *DesignSpecs) {
-	// Extract colors from fills
-	for _, fill := range node.Fills {
-		if fill.Type == "SOLID" && fill.Color != nil && fill.Visible {
-			colorHex := colorToHex(fill.Color)
-			categorizeColor(node.Name, colorHex, specs)
-		}
+// tokenName returns the name of the style a node applies for the given style type
+// ("fill", "stroke", "text", "effect", "grid"), e.g. "Brand/Primary/500".
+// It falls back to the node's layer name when the node does not use a style of that type
+// or the style is unknown.
+func tokenName(node *figma.Node, styleType string, styles map[string]figma.Style) string {
+	if name, ok := styleName(node, styleType, styles); ok {
+		return name
 	}
+	return node.Name
+}
 
-	// Extract colors from strokes
-	for _, stroke := range node.Strokes {
-		if stroke.Type == "SOLID" && stroke.Color != nil && stroke.Visible {
-			colorHex := colorToHex(stroke.Color)
-			specs.Colors.Border[node.Name] = colorHex
+// styleName returns the name of the published style of the given type applied to node, if any.
+func styleName(node *figma.Node, styleType string, styles map[string]figma.Style) (string, bool) {
+	id, ok := node.Styles[styleType]
+	if !ok {
+		// Some API responses use the plural keys for paints.
+		id, ok = node.Styles[styleType+"s"]
+	}
+	if ok {
+		if style, found := styles[id]; found && style.Name != "" {
+			return style.Name, true
 		}
 	}
+	return "", false
+}
 
-	// Extract background colors
-	if node.BackgroundColor != nil {
-		colorHex := colorToHex(node.BackgroundColor)
-		specs.Colors.Background[node.Name] = colorHex
+// newTextStyle builds a composite text style from the type style of a node using it.
+func newTextStyle(name string, style *figma.TypeStyle) TextStyle {
+	ts := TextStyle{
+		Name:          name,
+		FontFamily:    style.FontFamily,
+		FontSize:      style.FontSize,
+		FontWeight:    style.FontWeight,
+		LineHeight:    style.LineHeightPx,
+		LetterSpacing: style.LetterSpacing,
+	}
+	if style.TextCase != "ORIGINAL" {
+		ts.TextCase = style.TextCase
 	}
+	if style.TextDecoration != "NONE" {
+		ts.TextDecoration = style.TextDecoration
+	}
+	return ts
+}
 
-	// Extract typography
-	if node.Style != nil {
-		if node.Style.FontFamily != "" && specs.Typography.FontFamily == "" {
-			specs.Typography.FontFamily = node.Style.FontFamily
-		}
-		if node.Style.FontSize > 0 {
-			specs.Typography.FontSizes[node.Name] = node.Style.FontSize
-		}
-		if node.Style.FontWeight > 0 {
-			specs.Typography.FontWeights[node.Name] = node.Style.FontWeight
-		}
-		if node.Style.LineHeightPx > 0 {
-			specs.Typography.LineHeights[node.Name] = node.Style.LineHeightPx
-		}
+// newBorder builds the border style of a node's stroke.
+func newBorder(node *figma.Node, name, color string) Border {
+	b := Border{
+		Name:   name,
+		Color:  color,
+		Width:  strokeWeight(node),
+		Style:  "solid",
+		Align:  node.StrokeAlign,
+		Dashes: node.StrokeDashes,
+		Cap:    node.StrokeCap,
+		Join:   node.StrokeJoin,
+	}
+	if b.Cap == "NONE" {
+		b.Cap = ""
+	}
+	if b.Join == "MITER" {
+		b.Join = ""
 	}
 
-	// Extract shadows
-	for _, effect := range node.Effects {
-		if (effect.Type == "DROP_SHADOW" || effect.Type == "INNER_SHADOW") && effect.Visible {
-			shadow := Shadow{
-				Name:   node.Name,
-				Type:   effect.Type,
-				X:      effect.Offset.X,
-				Y:      effect.Offset.Y,
-				Blur:   effect.Radius,
-				Spread: effect.Spread,
-				Color:  colorToHex(effect.Color),
-			}
-			specs.Shadows = append(specs.Shadows, shadow)
+	// Figma draws dotted lines as dashes no longer than the stroke is wide.
+	if len(b.Dashes) > 0 {
+		b.Style = "dashed"
+		if b.Dashes[0] <= b.Width {
+			b.Style = "dotted"
 		}
 	}
 
-	// Extract border radii
-	if node.CornerRadius > 0 {
-		specs.Radii.Values[node.Name] = node.CornerRadius
+	if w := node.IndividualStrokeWeights; w != nil && !(w.Top == w.Right && w.Top == w.Bottom && w.Top == w.Left) {
+		b.Sides = []float64{w.Top, w.Right, w.Bottom, w.Left}
 	}
+	return b
+}
 
-	// Extract spacing from layout properties
-	if node.PaddingLeft > 0 || node.PaddingRight > 0 || node.PaddingTop > 0 || node.PaddingBottom > 0 {
-		specs.Spacing.Values[node.Name+"-paddingLeft"] = node.PaddingLeft
-		specs.Spacing.Values[node.Name+"-paddingRight"] = node.PaddingRight
-		specs.Spacing.Values[node.Name+"-paddingTop"] = node.PaddingTop
-		specs.Spacing.Values[node.Name+"-paddingBottom"] = node.PaddingBottom
+// strokeWeight returns the stroke weight of a node, or the largest side weight when the
+// sides have individual weights.
+func strokeWeight(node *figma.Node) float64 {
+	w := node.IndividualStrokeWeights
+	if w == nil {
+		return node.StrokeWeight
 	}
+	return math.Max(math.Max(w.Top, w.Right), math.Max(w.Bottom, w.Left))
+}
 
-	if node.ItemSpacing > 0 {
-		specs.Spacing.Values[node.Name+"-itemSpacing"] = node.ItemSpacing
+// mergeLookups combines ID-keyed lookups such as styles or components, with later maps
+// taking precedence.
+func mergeLookups[V any](lookups ...map[string]V) map[string]V {
+	merged := make(map[string]V)
+	for _, lookup := range lookups {
+		for id, v := range lookup {
+			merged[id] = v
+		}
 	}
+	return merged
+}
 
-	// Extract layout dimensions
-	if node.AbsoluteBoundingBox != nil {
-		name := strings.ToLower(node.Name)
-		if strings.Contains(name, "header") {
-			specs.Layout.HeaderHeight = node.AbsoluteBoundingBox.Height
-		}
-		if strings.Contains(name, "sidebar") {
-			specs.Layout.SidebarWidth = node.AbsoluteBoundingBox.Width
+// categorizeColor intelligently categorizes a color into the appropriate palette category
+// (Primary, Secondary, Background, Text, Status, or Border) based on keywords in the node name.
+// The CategorizeColor hook of c is asked first; when color rules are given they replace the
+// keywords.
+func (c Config) categorizeColor(nodeName, colorHex string, specs *DesignSpecs) {
+	if c.CategorizeColor != nil {
+		if colors := specs.Colors.colors(c.CategorizeColor(nodeName, colorHex)); colors != nil {
+			colors[nodeName] = colorHex
+			return
 		}
 	}
 
-	// Recursively process children
-	for _, child := range node.Children {
-		extractFromNode(&child, specs)
+	if rules := c.ColorRules; rules != nil {
+		if category, name, ok := rules.Match(nodeName); ok {
+			specs.Colors.colors(category)[name] = colorHex
+		}
+		return
 	}
-}
 
-// categorizeColor intelligently categorizes a color into the appropriate palette category
-// (Primary, Secondary, Background, Text, Status, or Border) based on keywords in the node name.
-func categorizeColor(nodeName, colorHex string, specs *DesignSpecs) {
 	name := strings.ToLower(nodeName)
 
 	if strings.Contains(name, "primary") {
@@ -443,8 +759,12 @@ func normalizeSpecs(specs *DesignSpecs) {
 	specs.Colors.Status = deduplicateColors(specs.Colors.Status)
 	specs.Colors.Border = deduplicateColors(specs.Colors.Border)
 
+	// Classify colors no category claimed by their hue, lightness and usage
+	classifyUncategorizedColors(&specs.Colors)
+
 	// Normalize font sizes to a standard scale
 	specs.Typography.FontSizes = normalizeFontSizes(specs.Typography.FontSizes)
+	sortFonts(specs.Typography.Fonts)
 
 	// Normalize spacing to a standard scale
 	specs.Spacing.Values = normalizeSpacing(specs.Spacing.Values)
@@ -454,12 +774,19 @@ func normalizeSpecs(specs *DesignSpecs) {
 }
 
 // deduplicateColors removes duplicate color values from a color map, keeping only the first
-// occurrence of each unique color. This prevents redundancy in the final color palette.
+// occurrence of each unique color in name order. This prevents redundancy in the final color
+// palette.
 func deduplicateColors(colors map[string]string) map[string]string {
 	seen := make(map[string]bool)
 	result := make(map[string]string)
 
-	for name, color := range colors {
+	names := make([]string, 0, len(colors))
+	for name := range colors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		color := colors[name]
 		if !seen[color] {
 			result[name] = color
 			seen[color] = true
@@ -602,19 +929,36 @@ func buildNodeTree(node *figma.Node) *NodeDescription {
 			nd.StrokeColors = append(nd.StrokeColors, colorToHex(stroke.Color))
 		}
 	}
-	nd.StrokeWeight = node.StrokeWeight
+	nd.StrokeWeight = strokeWeight(node)
+	nd.StrokeDashes = node.StrokeDashes
+	nd.StrokeAlign = node.StrokeAlign
 	nd.CornerRadius = node.CornerRadius
 
 	// Text properties
 	if node.Type == "TEXT" {
 		nd.TextContent = node.Characters
 	}
+
+	// Plugin data
+	for _, data := range []map[string]map[string]string{node.PluginData, node.SharedPluginData} {
+		for owner, entries := range data {
+			for key, value := range entries {
+				if nd.PluginData == nil {
+					nd.PluginData = make(map[string]string)
+				}
+				nd.PluginData[owner+"."+key] = value
+			}
+		}
+	}
 	if node.Style != nil {
 		nd.FontFamily = node.Style.FontFamily
 		nd.FontSize = node.Style.FontSize
 		nd.FontWeight = node.Style.FontWeight
 		nd.LineHeightPx = node.Style.LineHeightPx
+		nd.LetterSpacing = node.Style.LetterSpacing
 		nd.TextAlignHorizontal = node.Style.TextAlignHorizontal
+		nd.TextCase = node.Style.TextCase
+		nd.TextDecoration = node.Style.TextDecoration
 	}
 
 	// Layout
@@ -624,8 +968,27 @@ func buildNodeTree(node *figma.Node) *NodeDescription {
 	nd.PaddingBottom = node.PaddingBottom
 	nd.PaddingLeft = node.PaddingLeft
 	nd.ItemSpacing = node.ItemSpacing
+	nd.CounterAxisSpacing = node.CounterAxisSpacing
+	nd.PrimaryAxisAlignItems = node.PrimaryAxisAlignItems
+	nd.CounterAxisAlignItems = node.CounterAxisAlignItems
+	nd.PrimaryAxisSizingMode = node.PrimaryAxisSizingMode
+	nd.CounterAxisSizingMode = node.CounterAxisSizingMode
+	nd.LayoutWrap = node.LayoutWrap
+	nd.LayoutGrow = node.LayoutGrow
+	nd.LayoutAlign = node.LayoutAlign
+	nd.LayoutPositioning = node.LayoutPositioning
+	nd.LayoutSizingHorizontal = node.LayoutSizingHorizontal
+	nd.LayoutSizingVertical = node.LayoutSizingVertical
+	if node.Constraints != nil {
+		nd.ConstraintHorizontal = node.Constraints.Horizontal
+		nd.ConstraintVertical = node.Constraints.Vertical
+	}
+	nd.MinWidth = node.MinWidth
+	nd.MaxWidth = node.MaxWidth
+	nd.MinHeight = node.MinHeight
+	nd.MaxHeight = node.MaxHeight
 
-	// Effects (shadows)
+	// Effects (shadows and blurs)
 	for _, effect := range node.Effects {
 		if (effect.Type == "DROP_SHADOW" || effect.Type == "INNER_SHADOW") && effect.Visible {
 			nd.Shadows = append(nd.Shadows, Shadow{
@@ -638,6 +1001,18 @@ func buildNodeTree(node *figma.Node) *NodeDescription {
 				Color:  colorToHex(effect.Color),
 			})
 		}
+		if (effect.Type == "LAYER_BLUR" || effect.Type == "BACKGROUND_BLUR") && effect.Visible {
+			nd.Blurs = append(nd.Blurs, Blur{Name: node.Name, Type: effect.Type, Radius: effect.Radius})
+		}
+	}
+
+	// Blend modes
+	for _, usage := range blendModes(node) {
+		if usage.Target == "layer" {
+			nd.BlendMode = usage.Mode
+		} else {
+			nd.PaintBlendModes = append(nd.PaintBlendModes, usage.Target+":"+usage.Mode)
+		}
 	}
 
 	// Recurse into children
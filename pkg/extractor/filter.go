@@ -0,0 +1,86 @@
+package extractor
+
+import (
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/hellenic-development/figma-extractor/pkg/figma"
+)
+
+// NodeFilter selects the nodes of a design that extraction and image export take into account,
+// so that noise layers such as scratch areas and archives don't pollute the tokens or exports.
+type NodeFilter struct {
+	Include []string // path.Match patterns of the names of the nodes to keep, with their descendants, e.g. "Tokens/*"; empty = all
+	Exclude []string // path.Match patterns of the names of the nodes to drop, with their descendants, e.g. "scratch"
+	Types   []string // node types to keep, e.g. FRAME, COMPONENT, TEXT, case-insensitive; empty = all
+}
+
+// IsZero reports whether f keeps every node.
+func (f NodeFilter) IsZero() bool {
+	return len(f.Include) == 0 && len(f.Exclude) == 0 && len(f.Types) == 0
+}
+
+// Apply filters the descendants of root in place and returns the number of nodes it removed.
+// Excluded nodes are dropped with their descendants. Nodes that are not included, or whose type
+// is not one of Types, are replaced by their own kept children, so that a frame of Include or
+// a text of Types keeps being extracted wherever it is nested. root itself, the document and its
+// pages are always kept. The children slices of the original tree are not modified, so trees
+// sharing them, e.g. a file and its nodes, can be filtered one after the other.
+func (f NodeFilter) Apply(root *figma.Node) int {
+	if f.IsZero() {
+		return 0
+	}
+	included := len(f.Include) == 0 || matchName(root.Name, f.Include)
+	var removed int
+	root.Children = f.filter(root.Children, included, &removed)
+	return removed
+}
+
+// filter returns the kept nodes of children, whose parent is included, adding the number of
+// removed nodes to removed.
+func (f NodeFilter) filter(children []figma.Node, included bool, removed *int) []figma.Node {
+	var kept []figma.Node
+	for _, child := range children {
+		if matchName(child.Name, f.Exclude) {
+			*removed += countNodes(&child)
+			continue
+		}
+		childIncluded := included || matchName(child.Name, f.Include)
+		child.Children = f.filter(child.Children, childIncluded, removed)
+		if child.Type == "DOCUMENT" || child.Type == "CANVAS" || (childIncluded && f.keepsType(child.Type)) {
+			kept = append(kept, child)
+			continue
+		}
+		*removed++
+		kept = append(kept, child.Children...)
+	}
+	return kept
+}
+
+// keepsType reports whether nodes of type t pass the Types of f.
+func (f NodeFilter) keepsType(t string) bool {
+	return len(f.Types) == 0 || slices.ContainsFunc(f.Types, func(s string) bool {
+		return strings.EqualFold(strings.TrimSpace(s), t)
+	})
+}
+
+// matchName reports whether name matches one of the path.Match patterns. Malformed patterns
+// match nothing.
+func matchName(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// countNodes returns the number of nodes of the subtree of node.
+func countNodes(node *figma.Node) int {
+	n := 1
+	for i := range node.Children {
+		n += countNodes(&node.Children[i])
+	}
+	return n
+}
@@ -0,0 +1,136 @@
+package extractor
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/hellenic-development/figma-extractor/pkg/figma"
+)
+
+// Font is a font family the text layers of the design are set in, with every weight and style
+// of it they use.
+type Font struct {
+	Family      string     `json:"family"`
+	Faces       []FontFace `json:"faces"`                 // by weight, upright before italic
+	Uses        int        `json:"uses"`                  // text layers set in the family
+	GoogleFonts bool       `json:"googleFonts,omitempty"` // available from Google Fonts; other families must be self-hosted or licensed
+	Fallback    string     `json:"fallback"`              // CSS font stack to fall back on while the font loads, e.g. "system-ui, -apple-system, sans-serif"
+}
+
+// FontFace is a weight and style of a font family.
+type FontFace struct {
+	Weight         float64 `json:"weight"`
+	Italic         bool    `json:"italic,omitempty"`
+	PostScriptName string  `json:"postScriptName,omitempty"` // e.g. "Inter-SemiBold", the name of the font file to self-host
+}
+
+// addFont records the font face of a text layer set in style.
+func addFont(typography *Typography, style *figma.TypeStyle) {
+	if style.FontFamily == "" {
+		return
+	}
+	face := FontFace{Weight: style.FontWeight, PostScriptName: style.FontPostScriptName}
+	if face.Weight <= 0 {
+		face.Weight = 400
+	}
+	postScript := strings.ToLower(style.FontPostScriptName)
+	face.Italic = style.Italic || strings.Contains(postScript, "italic") || strings.Contains(postScript, "oblique")
+
+	i := slices.IndexFunc(typography.Fonts, func(f Font) bool { return f.Family == style.FontFamily })
+	if i < 0 {
+		typography.Fonts = append(typography.Fonts, Font{
+			Family:      style.FontFamily,
+			GoogleFonts: isGoogleFont(style.FontFamily),
+			Fallback:    fontFallback(style.FontFamily),
+		})
+		i = len(typography.Fonts) - 1
+	}
+	font := &typography.Fonts[i]
+	font.Uses++
+	if !slices.ContainsFunc(font.Faces, func(f FontFace) bool { return f.Weight == face.Weight && f.Italic == face.Italic }) {
+		font.Faces = append(font.Faces, face)
+	}
+}
+
+// sortFonts orders fonts by the number of text layers set in them, and the faces of every
+// font by weight.
+func sortFonts(fonts []Font) {
+	slices.SortStableFunc(fonts, func(a, b Font) int {
+		if a.Uses != b.Uses {
+			return b.Uses - a.Uses
+		}
+		return strings.Compare(a.Family, b.Family)
+	})
+	for _, font := range fonts {
+		slices.SortFunc(font.Faces, func(a, b FontFace) int {
+			if c := cmp.Compare(a.Weight, b.Weight); c != 0 || a.Italic == b.Italic {
+				return c
+			}
+			if b.Italic {
+				return -1
+			}
+			return 1
+		})
+	}
+}
+
+// fontFallback returns the CSS font stack to fall back on for family, by its generic family.
+func fontFallback(family string) string {
+	name := strings.ToLower(family)
+	words := strings.Fields(name)
+	switch {
+	case slices.Contains(words, "mono") || slices.Contains(words, "code") || slices.Contains(words, "courier") || monospaceFonts[name]:
+		return "ui-monospace, SFMono-Regular, Menlo, Consolas, monospace"
+	case slices.Contains(words, "serif") && !slices.Contains(words, "sans"), serifFonts[name]:
+		return "Georgia, 'Times New Roman', serif"
+	}
+	return "system-ui, -apple-system, sans-serif"
+}
+
+// monospaceFonts and serifFonts are families whose names don't tell their generic family,
+// lowercased.
+var (
+	monospaceFonts = map[string]bool{"inconsolata": true, "menlo": true, "consolas": true, "monaco": true, "sf mono": true}
+	serifFonts     = map[string]bool{
+		"georgia": true, "times": true, "times new roman": true, "garamond": true, "eb garamond": true,
+		"playfair display": true, "merriweather": true, "lora": true, "libre baskerville": true,
+		"crimson text": true, "crimson pro": true, "cormorant garamond": true, "bitter": true,
+		"domine": true, "spectral": true, "fraunces": true, "newsreader": true, "literata": true,
+		"prata": true, "cardo": true, "arvo": true, "zilla slab": true, "roboto slab": true,
+		"vollkorn": true, "alegreya": true, "old standard tt": true, "dm serif display": true,
+		"abril fatface": true, "cinzel": true, "new york": true,
+	}
+)
+
+// isGoogleFont reports whether family is one of the most used families of Google Fonts.
+func isGoogleFont(family string) bool {
+	return slices.ContainsFunc(googleFonts, func(f string) bool { return strings.EqualFold(f, family) })
+}
+
+// googleFonts are the most used families of Google Fonts.
+var googleFonts = []string{
+	"Roboto", "Open Sans", "Noto Sans", "Montserrat", "Poppins", "Lato", "Inter", "Roboto Condensed",
+	"Oswald", "Roboto Mono", "Raleway", "Nunito", "Nunito Sans", "Ubuntu", "Rubik", "Playfair Display",
+	"Roboto Slab", "Merriweather", "Noto Serif", "Work Sans", "PT Sans", "Lora", "Mulish", "Kanit",
+	"Fira Sans", "DM Sans", "Quicksand", "Barlow", "Manrope", "IBM Plex Sans", "IBM Plex Mono",
+	"IBM Plex Serif", "Titillium Web", "Inconsolata", "Heebo", "Karla", "Libre Franklin", "Josefin Sans",
+	"Hind", "Arimo", "Source Sans 3", "Source Serif 4", "Source Code Pro", "PT Serif", "Bebas Neue",
+	"Jost", "Outfit", "Space Grotesk", "Space Mono", "Plus Jakarta Sans", "Cabin", "Dosis", "Bitter",
+	"Anton", "Oxygen", "Archivo", "Archivo Black", "Assistant", "Exo 2", "Figtree", "Lexend", "Urbanist",
+	"Sora", "Red Hat Display", "Red Hat Text", "Public Sans", "Overpass", "Prompt", "Cairo", "Tajawal",
+	"Almarai", "EB Garamond", "Cormorant Garamond", "Crimson Text", "Crimson Pro", "Libre Baskerville",
+	"DM Serif Display", "DM Mono", "Fira Code", "Fira Mono", "JetBrains Mono", "Ubuntu Mono",
+	"Abril Fatface", "Cinzel", "Pacifico", "Dancing Script", "Lobster", "Caveat", "Satisfy",
+	"Shadows Into Light", "Permanent Marker", "Comfortaa", "Righteous", "Fredoka", "Varela Round",
+	"Chivo", "Signika", "Asap", "Catamaran", "Maven Pro", "Questrial", "Arvo", "Domine", "Spectral",
+	"Fraunces", "Newsreader", "Literata", "Prata", "Cardo", "Zilla Slab", "Vollkorn", "Alegreya",
+	"Alegreya Sans", "Old Standard TT", "Noto Sans Greek", "Noto Serif Greek", "Noto Sans JP",
+	"Noto Sans KR", "Noto Sans SC", "Noto Sans TC", "Noto Sans Arabic", "Noto Sans Hebrew",
+	"Noto Color Emoji", "Geologica", "Onest", "Instrument Sans", "Instrument Serif", "Bricolage Grotesque",
+	"Schibsted Grotesk", "Albert Sans", "Be Vietnam Pro", "Epilogue", "Syne", "Unbounded", "Geist",
+	"Geist Mono", "Commissioner", "Encode Sans", "Sarabun", "Mukta", "Play", "Teko", "Rajdhani",
+	"Barlow Condensed", "Barlow Semi Condensed", "Saira", "Exo", "Orbitron", "M PLUS Rounded 1c",
+	"Zen Kaku Gothic New", "Noto Sans Display", "Didact Gothic", "Gothic A1", "Sen", "Kumbh Sans",
+	"Lexend Deca", "Hanken Grotesk", "Golos Text", "Wix Madefor Display", "Wix Madefor Text",
+}
@@ -0,0 +1,35 @@
+package extractor
+
+import "github.com/hellenic-development/figma-extractor/pkg/figma"
+
+// Frame holds the specifications of a single extracted node, as opposed to the merged
+// specifications of all of them.
+type Frame struct {
+	NodeID string       `json:"nodeId"`
+	Name   string       `json:"name"`
+	Specs  *DesignSpecs `json:"specs,omitempty"` // tokens, components and node tree of the frame alone
+}
+
+// ExtractFrames extracts the specifications of every target node on its own, in the order of
+// nodeIDs, so that reports can show each frame with the tokens it uses. Nodes missing from
+// nodesResp are skipped.
+func ExtractFrames(fileResp *figma.FileResponse, nodesResp *figma.NodesResponse, nodeIDs []string) []Frame {
+	return Config{}.ExtractFrames(fileResp, nodesResp, nodeIDs)
+}
+
+// ExtractFrames is like the package-level ExtractFrames, with the customizations of c.
+func (c Config) ExtractFrames(fileResp *figma.FileResponse, nodesResp *figma.NodesResponse, nodeIDs []string) []Frame {
+	var frames []Frame
+	for _, id := range nodeIDs {
+		nodeData, ok := nodesResp.Nodes[id]
+		if !ok {
+			continue
+		}
+		frames = append(frames, Frame{
+			NodeID: id,
+			Name:   nodeData.Document.Name,
+			Specs:  c.ExtractNodes(fileResp, nodesResp, []string{id}, false),
+		})
+	}
+	return frames
+}
@@ -0,0 +1,58 @@
+package extractor
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/hellenic-development/figma-extractor/pkg/figma"
+)
+
+// maxInferredGap is the widest gap between siblings taken for spacing, in px. Wider gaps are
+// whitespace of the layout rather than a step of the spacing scale.
+const maxInferredGap = 96
+
+// siblingGaps returns the gaps between the children of a frame without auto layout, measured
+// from their bounding boxes and rounded to whole pixels: the distance from every child to the
+// nearest sibling below it that it overlaps horizontally, and to the nearest sibling right of it
+// that it overlaps vertically. Auto-layout frames declare their spacing instead, and the frames
+// of a page are laid out freely.
+func siblingGaps(node *figma.Node) []float64 {
+	if len(node.Children) < 2 || node.Type == "DOCUMENT" || node.Type == "CANVAS" || (node.LayoutMode != "" && node.LayoutMode != "NONE") {
+		return nil
+	}
+	var boxes []*figma.Rectangle
+	for i := range node.Children {
+		if box := node.Children[i].AbsoluteBoundingBox; box != nil && box.Width > 0 && box.Height > 0 {
+			boxes = append(boxes, box)
+		}
+	}
+
+	var gaps []float64
+	for _, a := range boxes {
+		below, right := math.Inf(1), math.Inf(1)
+		for _, b := range boxes {
+			if a.X < b.X+b.Width && b.X < a.X+a.Width && b.Y >= a.Y+a.Height {
+				below = min(below, b.Y-(a.Y+a.Height))
+			}
+			if a.Y < b.Y+b.Height && b.Y < a.Y+a.Height && b.X >= a.X+a.Width {
+				right = min(right, b.X-(a.X+a.Width))
+			}
+		}
+		for _, gap := range []float64{below, right} {
+			if gap = math.Round(gap); gap > 0 && gap <= maxInferredGap {
+				gaps = append(gaps, gap)
+			}
+		}
+	}
+	return gaps
+}
+
+// addInferredSpacing adds the gaps measured between siblings more than once, by gap, to the
+// spacing values. Gaps measured once are more likely a nudge than a step of the scale.
+func addInferredSpacing(values map[string]float64, gaps map[float64]int) {
+	for gap, n := range gaps {
+		if n > 1 {
+			values[fmt.Sprintf("gap-%g", gap)] = gap
+		}
+	}
+}
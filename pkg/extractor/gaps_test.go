@@ -0,0 +1,85 @@
+package extractor
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hellenic-development/figma-extractor/pkg/figma"
+)
+
+func TestSiblingGaps(t *testing.T) {
+	tests := []struct {
+		name string
+		node figma.Node
+		want []float64
+	}{
+		{
+			name: "nearest below and right",
+			node: boxNode("1:1", "FRAME", 0, 0, 400, 400,
+				boxNode("a", "RECTANGLE", 0, 0, 100, 100),
+				boxNode("b", "RECTANGLE", 116, 0, 100, 100),   // 16 right of a
+				boxNode("c", "RECTANGLE", 300, 0, 100, 100),   // further right of a, 84 right of b
+				boxNode("d", "RECTANGLE", 0, 124.4, 100, 100), // 24 below a
+				boxNode("e", "RECTANGLE", 0, 300, 100, 100),   // further below a, 76 below d
+			),
+			want: []float64{24, 16, 84, 76},
+		},
+		{
+			name: "gaps over the maximum",
+			node: boxNode("1:1", "FRAME", 0, 0, 600, 100,
+				boxNode("a", "RECTANGLE", 0, 0, 100, 100),
+				boxNode("b", "RECTANGLE", 100+maxInferredGap, 0, 100, 100),
+				boxNode("c", "RECTANGLE", 400, 0, 100, 100), // 104 right of b
+			),
+			want: []float64{maxInferredGap},
+		},
+		{
+			name: "touching and hidden layers",
+			node: boxNode("1:1", "FRAME", 0, 0, 400, 100,
+				boxNode("a", "RECTANGLE", 0, 0, 100, 100),
+				boxNode("b", "RECTANGLE", 100, 0, 100, 100),
+				boxNode("c", "RECTANGLE", 210, 0, 0, 100),
+			),
+		},
+		{
+			name: "auto layout",
+			node: func() figma.Node {
+				n := boxNode("1:1", "FRAME", 0, 0, 400, 100,
+					boxNode("a", "RECTANGLE", 0, 0, 100, 100),
+					boxNode("b", "RECTANGLE", 116, 0, 100, 100),
+				)
+				n.LayoutMode = "HORIZONTAL"
+				return n
+			}(),
+		},
+		{
+			name: "page",
+			node: boxNode("0:1", "CANVAS", 0, 0, 400, 100,
+				boxNode("a", "FRAME", 0, 0, 100, 100),
+				boxNode("b", "FRAME", 116, 0, 100, 100),
+			),
+		},
+		{
+			name: "single child",
+			node: boxNode("1:1", "FRAME", 0, 0, 400, 100, boxNode("a", "RECTANGLE", 0, 0, 100, 100)),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := siblingGaps(&tt.node); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("siblingGaps() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddInferredSpacing(t *testing.T) {
+	values := map[string]float64{"4": 16}
+	addInferredSpacing(values, map[float64]int{16: 3, 24: 2, 13: 1})
+
+	want := map[string]float64{"4": 16, "gap-16": 16, "gap-24": 24}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("addInferredSpacing() = %v, want %v", values, want)
+	}
+}
@@ -0,0 +1,222 @@
+package extractor
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	"github.com/hellenic-development/figma-extractor/pkg/figma"
+)
+
+// ColumnGrid is the column grid of a top-level frame: its column layout grid, or the grid implied
+// by the widths and gutters of its content when it has none.
+type ColumnGrid struct {
+	NodeID      string  `json:"nodeId"`
+	NodeName    string  `json:"nodeName"`
+	Width       float64 `json:"width"` // width of the frame
+	Columns     int     `json:"columns"`
+	Gutter      float64 `json:"gutter"`             // space between the columns
+	Margin      float64 `json:"margin"`             // space between the outer columns and the frame edges
+	ColumnWidth float64 `json:"columnWidth"`        // width of a column
+	Inferred    bool    `json:"inferred,omitempty"` // implied by the content; the frame has no column layout grid
+}
+
+// maxGridColumns is the largest number of columns an inferred grid has.
+const maxGridColumns = 12
+
+// gridTolerance is how far, in px, a layer edge may be off a column edge and still align to it.
+const gridTolerance = 2
+
+// collectGrids returns the column grids of the top-level frames under roots, the frames of their
+// pages and sections or the roots themselves, in document order. Frames with neither a column
+// layout grid nor content implying one are left out. Subtrees whose ID is in copies are skipped.
+func collectGrids(roots []*figma.Node, copies map[string]bool) []ColumnGrid {
+	var grids []ColumnGrid
+	var visit func(node *figma.Node)
+	visit = func(node *figma.Node) {
+		if copies[node.ID] {
+			return
+		}
+		switch node.Type {
+		case "DOCUMENT", "CANVAS", "SECTION":
+			for i := range node.Children {
+				visit(&node.Children[i])
+			}
+		case "FRAME", "COMPONENT", "INSTANCE":
+			if grid, ok := columnGrid(node); ok {
+				grids = append(grids, grid)
+			}
+		}
+	}
+	for _, root := range roots {
+		visit(root)
+	}
+	return grids
+}
+
+// columnGrid returns the column grid of frame: its first column layout grid, or else the grid
+// its content implies, see inferColumnGrid.
+func columnGrid(frame *figma.Node) (ColumnGrid, bool) {
+	box := frame.AbsoluteBoundingBox
+	if box == nil || box.Width <= 0 {
+		return ColumnGrid{}, false
+	}
+	grid := ColumnGrid{NodeID: frame.ID, NodeName: frame.Name, Width: box.Width}
+	for _, g := range frame.LayoutGrids {
+		if g.Pattern != "COLUMNS" || g.Count <= 0 {
+			continue
+		}
+		grid.Columns, grid.Gutter = g.Count, g.GutterSize
+		gutters := float64(g.Count-1) * g.GutterSize
+		switch g.Alignment {
+		case "STRETCH":
+			grid.Margin = g.Offset
+			grid.ColumnWidth = (box.Width - 2*g.Offset - gutters) / float64(g.Count)
+		case "CENTER":
+			grid.ColumnWidth = g.SectionSize
+			grid.Margin = (box.Width - float64(g.Count)*g.SectionSize - gutters) / 2
+		default: // MIN and MAX grids are offset from the left and right edge
+			grid.ColumnWidth, grid.Margin = g.SectionSize, g.Offset
+		}
+		grid.ColumnWidth, grid.Margin = roundGrid(grid.ColumnWidth), roundGrid(grid.Margin)
+		return grid, true
+	}
+
+	columns, gutter, margin, ok := inferColumnGrid(frame)
+	if !ok {
+		return ColumnGrid{}, false
+	}
+	grid.Columns, grid.Gutter, grid.Margin, grid.Inferred = columns, gutter, margin, true
+	grid.ColumnWidth = roundGrid((box.Width - 2*margin - float64(columns-1)*gutter) / float64(columns))
+	return grid, true
+}
+
+// gridRow is a row of side-by-side layers evenly spaced by gutter.
+type gridRow struct {
+	gutter float64
+	spans  [][2]float64 // left and right edge of every layer, relative to the frame
+}
+
+// inferColumnGrid infers the column grid of a frame from the rows of side-by-side, evenly
+// spaced layers within it, e.g. a row of cards: the most common gutter of the rows, weighted by
+// their width, the narrowest outer margin of the rows spaced by it, and the fewest columns, up
+// to maxGridColumns, the layers of those rows start and end on. It needs three such layers,
+// three quarters of which align to the columns.
+func inferColumnGrid(frame *figma.Node) (columns int, gutter, margin float64, ok bool) {
+	box := frame.AbsoluteBoundingBox
+	var rows []gridRow
+	collectGridRows(frame, box.X, &rows)
+
+	weights := make(map[float64]float64) // gutter -> width of its rows
+	for _, row := range rows {
+		weights[row.gutter] += row.spans[len(row.spans)-1][1] - row.spans[0][0]
+	}
+	for g, w := range weights {
+		if w > weights[gutter] || (w == weights[gutter] && g < gutter) {
+			gutter = g
+		}
+	}
+
+	var spans [][2]float64
+	left, right := box.Width, box.Width
+	for _, row := range rows {
+		if row.gutter == gutter {
+			spans = append(spans, row.spans...)
+			left = min(left, row.spans[0][0])
+			right = min(right, box.Width-row.spans[len(row.spans)-1][1])
+		}
+	}
+	if len(spans) < 3 {
+		return 0, 0, 0, false
+	}
+	margin = math.Max(0, math.Round(min(left, right)))
+
+	content := box.Width - 2*margin
+	best := 0.0
+	for n := 2; n <= maxGridColumns; n++ {
+		column := (content - float64(n-1)*gutter) / float64(n)
+		if column <= 0 {
+			break
+		}
+		step := column + gutter
+		aligned := 0
+		for _, span := range spans {
+			start, end := (span[0]-margin)/step, (span[1]-margin+gutter)/step
+			if onGrid(start, step) && onGrid(end, step) && math.Round(end) > math.Round(start) {
+				aligned++
+			}
+		}
+		if share := float64(aligned) / float64(len(spans)); share > best {
+			best, columns = share, n
+		}
+	}
+	if best < 0.75 {
+		return 0, 0, 0, false
+	}
+	return columns, gutter, margin, true
+}
+
+// onGrid reports whether the position, in columns of step px, is within gridTolerance of a column
+// edge.
+func onGrid(position, step float64) bool {
+	return position > -0.5 && math.Abs(position-math.Round(position))*step <= gridTolerance
+}
+
+// collectGridRows adds the rows of evenly spaced, side-by-side children of node and of its
+// descendants to rows, with edges relative to origin. Children overlapping the first child of a
+// row vertically are in the row.
+func collectGridRows(node *figma.Node, origin float64, rows *[]gridRow) {
+	var boxes []*figma.Rectangle
+	for i := range node.Children {
+		if box := node.Children[i].AbsoluteBoundingBox; box != nil && box.Width > 0 && box.Height > 0 {
+			boxes = append(boxes, box)
+		}
+	}
+	slices.SortStableFunc(boxes, func(a, b *figma.Rectangle) int { return cmp.Compare(a.Y, b.Y) })
+
+	grouped := make([]bool, len(boxes))
+	for i, first := range boxes {
+		if grouped[i] {
+			continue
+		}
+		var row []*figma.Rectangle
+		for j := i; j < len(boxes); j++ {
+			if b := boxes[j]; !grouped[j] && b.Y < first.Y+first.Height && first.Y < b.Y+b.Height {
+				grouped[j] = true
+				row = append(row, b)
+			}
+		}
+		if r, ok := evenRow(row, origin); ok {
+			*rows = append(*rows, r)
+		}
+	}
+
+	for i := range node.Children {
+		collectGridRows(&node.Children[i], origin, rows)
+	}
+}
+
+// evenRow returns the layers of row as a gridRow when there are two or more, side by side and
+// spaced by the same gap, rounded to whole pixels, of up to maxInferredGap.
+func evenRow(row []*figma.Rectangle, origin float64) (gridRow, bool) {
+	if len(row) < 2 {
+		return gridRow{}, false
+	}
+	slices.SortFunc(row, func(a, b *figma.Rectangle) int { return cmp.Compare(a.X, b.X) })
+	r := gridRow{gutter: math.Round(row[1].X - (row[0].X + row[0].Width))}
+	if r.gutter <= 0 || r.gutter > maxInferredGap {
+		return gridRow{}, false
+	}
+	for i, b := range row {
+		if i > 0 && math.Abs(b.X-(row[i-1].X+row[i-1].Width)-r.gutter) > 1 {
+			return gridRow{}, false
+		}
+		r.spans = append(r.spans, [2]float64{b.X - origin, b.X + b.Width - origin})
+	}
+	return r, true
+}
+
+// roundGrid rounds a grid measurement to hundredths of a pixel.
+func roundGrid(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,169 @@
+package extractor
+
+import (
+	"testing"
+
+	"github.com/hellenic-development/figma-extractor/pkg/figma"
+)
+
+// boxNode returns a node of type typ with the given bounding box and children.
+func boxNode(id, typ string, x, y, width, height float64, children ...figma.Node) figma.Node {
+	return figma.Node{
+		ID:                  id,
+		Name:                id,
+		Type:                typ,
+		AbsoluteBoundingBox: &figma.Rectangle{X: x, Y: y, Width: width, Height: height},
+		Children:            children,
+	}
+}
+
+func TestColumnGrid(t *testing.T) {
+	// A 1280px frame with a 40px margin, laid out on 12 columns of 78px spaced by 24px: a row of
+	// three cards four columns wide and a row of four cards three columns wide.
+	cards := []figma.Node{
+		boxNode("a", "FRAME", 40, 100, 384, 200),
+		boxNode("b", "FRAME", 448, 100, 384, 200),
+		boxNode("c", "FRAME", 856, 100, 384, 200),
+		boxNode("d", "FRAME", 40, 400, 282, 200),
+		boxNode("e", "FRAME", 346, 400, 282, 200),
+		boxNode("f", "FRAME", 652, 400, 282, 200),
+		boxNode("g", "FRAME", 958, 400, 282, 200),
+	}
+
+	tests := []struct {
+		name  string
+		frame figma.Node
+		grids []figma.LayoutGrid
+		want  ColumnGrid
+		ok    bool
+	}{
+		{
+			name:  "declared stretch grid",
+			frame: boxNode("1:1", "FRAME", 0, 0, 1440, 900),
+			grids: []figma.LayoutGrid{
+				{Pattern: "ROWS", Count: 8, GutterSize: 16, Alignment: "STRETCH"},
+				{Pattern: "COLUMNS", Count: 12, GutterSize: 24, Offset: 80, Alignment: "STRETCH"},
+			},
+			want: ColumnGrid{NodeID: "1:1", NodeName: "1:1", Width: 1440, Columns: 12, Gutter: 24, Margin: 80, ColumnWidth: 84.67},
+			ok:   true,
+		},
+		{
+			name:  "declared center grid",
+			frame: boxNode("1:1", "FRAME", 0, 0, 1440, 900),
+			grids: []figma.LayoutGrid{{Pattern: "COLUMNS", Count: 12, GutterSize: 24, SectionSize: 72, Alignment: "CENTER"}},
+			want:  ColumnGrid{NodeID: "1:1", NodeName: "1:1", Width: 1440, Columns: 12, Gutter: 24, Margin: 156, ColumnWidth: 72},
+			ok:    true,
+		},
+		{
+			name:  "declared min grid",
+			frame: boxNode("1:1", "FRAME", 0, 0, 375, 812),
+			grids: []figma.LayoutGrid{{Pattern: "COLUMNS", Count: 4, GutterSize: 16, SectionSize: 74.75, Offset: 16, Alignment: "MIN"}},
+			want:  ColumnGrid{NodeID: "1:1", NodeName: "1:1", Width: 375, Columns: 4, Gutter: 16, Margin: 16, ColumnWidth: 74.75},
+			ok:    true,
+		},
+		{
+			name:  "inferred from rows of cards",
+			frame: boxNode("1:1", "FRAME", 200, 0, 1280, 700, offsetNodes(cards, 200)...),
+			want:  ColumnGrid{NodeID: "1:1", NodeName: "1:1", Width: 1280, Columns: 12, Gutter: 24, Margin: 40, ColumnWidth: 78, Inferred: true},
+			ok:    true,
+		},
+		{
+			name: "uneven gaps",
+			frame: boxNode("1:1", "FRAME", 0, 0, 1280, 700,
+				boxNode("a", "FRAME", 40, 100, 384, 200),
+				boxNode("b", "FRAME", 448, 100, 384, 200),
+				boxNode("c", "FRAME", 872, 100, 368, 200),
+			),
+		},
+		{
+			name: "two cards",
+			frame: boxNode("1:1", "FRAME", 0, 0, 1280, 700,
+				boxNode("a", "FRAME", 40, 100, 588, 200),
+				boxNode("b", "FRAME", 652, 100, 588, 200),
+			),
+		},
+		{
+			name: "layers off the columns",
+			frame: boxNode("1:1", "FRAME", 0, 0, 1280, 700,
+				boxNode("a", "FRAME", 40, 100, 100, 200),
+				boxNode("b", "FRAME", 164, 100, 517, 200),
+				boxNode("c", "FRAME", 705, 100, 133, 200),
+				boxNode("d", "FRAME", 862, 100, 378, 200),
+			),
+		},
+		{name: "empty frame", frame: boxNode("1:1", "FRAME", 0, 0, 1280, 700)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.frame.LayoutGrids = tt.grids
+			got, ok := columnGrid(&tt.frame)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("columnGrid() = %+v, %v, want %+v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+// offsetNodes returns copies of nodes moved right by dx.
+func offsetNodes(nodes []figma.Node, dx float64) []figma.Node {
+	moved := make([]figma.Node, len(nodes))
+	for i, n := range nodes {
+		box := *n.AbsoluteBoundingBox
+		box.X += dx
+		n.AbsoluteBoundingBox = &box
+		moved[i] = n
+	}
+	return moved
+}
+
+func TestEvenRow(t *testing.T) {
+	tests := []struct {
+		name       string
+		row        []*figma.Rectangle
+		wantGutter float64
+		ok         bool
+	}{
+		{
+			name:       "evenly spaced, out of order",
+			row:        []*figma.Rectangle{{X: 220, Width: 100}, {X: 100, Width: 100}, {X: 340.4, Width: 100}},
+			wantGutter: 20,
+			ok:         true,
+		},
+		{name: "single layer", row: []*figma.Rectangle{{X: 0, Width: 100}}},
+		{name: "overlapping", row: []*figma.Rectangle{{X: 0, Width: 100}, {X: 80, Width: 100}}},
+		{name: "gap too wide", row: []*figma.Rectangle{{X: 0, Width: 100}, {X: 100 + maxInferredGap + 1, Width: 100}}},
+		{name: "uneven", row: []*figma.Rectangle{{X: 0, Width: 100}, {X: 120, Width: 100}, {X: 250, Width: 100}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := evenRow(tt.row, 100)
+			if ok != tt.ok || got.gutter != tt.wantGutter {
+				t.Errorf("evenRow() gutter = %g, %v, want %g, %v", got.gutter, ok, tt.wantGutter, tt.ok)
+			}
+			if ok && got.spans[0] != [2]float64{0, 100} {
+				t.Errorf("evenRow() first span = %v, want [0 100] relative to the origin", got.spans[0])
+			}
+		})
+	}
+}
+
+func TestOnGrid(t *testing.T) {
+	tests := []struct {
+		position, step float64
+		want           bool
+	}{
+		{3, 100, true},
+		{3.015, 100, true}, // 1.5px off
+		{2.97, 100, false}, // 3px off
+		{-0.01, 100, true},
+		{-1, 100, false}, // left of the first column
+	}
+
+	for _, tt := range tests {
+		if got := onGrid(tt.position, tt.step); got != tt.want {
+			t.Errorf("onGrid(%g, %g) = %v, want %v", tt.position, tt.step, got, tt.want)
+		}
+	}
+}
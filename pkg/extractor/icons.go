@@ -0,0 +1,171 @@
+package extractor
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/hellenic-development/figma-extractor/pkg/figma"
+)
+
+// maxIconSize is the largest width or height of a node considered an icon.
+const maxIconSize = 128
+
+// Icon is a small vector graphic of the design, drawn as inline SVG from the vector paths of
+// the file, so it can be used without rendering it through the images API.
+type Icon struct {
+	NodeID string  `json:"nodeId"`
+	Name   string  `json:"name"`
+	Width  float64 `json:"width,omitempty"`
+	Height float64 `json:"height,omitempty"`
+	SVG    string  `json:"svg,omitempty"` // a complete <svg> element
+}
+
+// vectorTypes are the node types icons are drawn with.
+var vectorTypes = map[string]bool{
+	"VECTOR":            true,
+	"BOOLEAN_OPERATION": true,
+	"ELLIPSE":           true,
+	"RECTANGLE":         true,
+	"LINE":              true,
+	"STAR":              true,
+	"REGULAR_POLYGON":   true,
+	"GROUP":             true,
+}
+
+// collectIcons returns the icons under roots, in document order and without repeated names.
+// Icons are components, and frames, groups and instances named like an icon, made of vector
+// shapes only and at most 128px in size. Only files fetched with vector paths
+// (geometry=paths) have icons.
+func collectIcons(roots []*figma.Node) []Icon {
+	var icons []Icon
+	seen := make(map[string]bool)
+
+	var walk func(node *figma.Node)
+	walk = func(node *figma.Node) {
+		if isIcon(node) {
+			if svg := iconSVG(node); svg != "" && !seen[node.Name] {
+				seen[node.Name] = true
+				icons = append(icons, Icon{
+					NodeID: node.ID,
+					Name:   node.Name,
+					Width:  node.AbsoluteBoundingBox.Width,
+					Height: node.AbsoluteBoundingBox.Height,
+					SVG:    svg,
+				})
+			}
+			return
+		}
+		for i := range node.Children {
+			walk(&node.Children[i])
+		}
+	}
+	for _, root := range roots {
+		walk(root)
+	}
+	return icons
+}
+
+// isIcon reports whether node is an icon: a small component, or a small frame, group or
+// instance named like an icon, containing nothing but vector shapes.
+func isIcon(node *figma.Node) bool {
+	box := node.AbsoluteBoundingBox
+	if box == nil || box.Width <= 0 || box.Height <= 0 || box.Width > maxIconSize || box.Height > maxIconSize {
+		return false
+	}
+	switch node.Type {
+	case "COMPONENT":
+	case "FRAME", "GROUP", "INSTANCE":
+		if !strings.Contains(strings.ToLower(node.Name), "icon") {
+			return false
+		}
+	default:
+		return false
+	}
+	if len(node.Children) == 0 {
+		return false
+	}
+
+	var vectorsOnly func(n *figma.Node) bool
+	vectorsOnly = func(n *figma.Node) bool {
+		for i := range n.Children {
+			child := &n.Children[i]
+			if !vectorTypes[child.Type] || !vectorsOnly(child) {
+				return false
+			}
+		}
+		return true
+	}
+	return vectorsOnly(node)
+}
+
+// iconSVG draws the vector paths under icon as an SVG element, or returns "" when no node has
+// paths with a solid paint. Paths are positioned by the bounding boxes of their nodes, which is
+// exact for unrotated shapes.
+func iconSVG(icon *figma.Node) string {
+	box := icon.AbsoluteBoundingBox
+	var paths strings.Builder
+
+	var draw func(node *figma.Node)
+	draw = func(node *figma.Node) {
+		var dx, dy float64
+		if node.AbsoluteBoundingBox != nil {
+			dx, dy = node.AbsoluteBoundingBox.X-box.X, node.AbsoluteBoundingBox.Y-box.Y
+		}
+		writePaths(&paths, node.FillGeometry, node.Fills, dx, dy)
+		writePaths(&paths, node.StrokeGeometry, node.Strokes, dx, dy)
+
+		// The paths of a boolean operation already combine those of its children.
+		if node.Type == "BOOLEAN_OPERATION" && len(node.FillGeometry) > 0 {
+			return
+		}
+		for i := range node.Children {
+			draw(&node.Children[i])
+		}
+	}
+	draw(icon)
+
+	if paths.Len() == 0 {
+		return ""
+	}
+	w, h := svgNumber(box.Width), svgNumber(box.Height)
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%s" height="%s" viewBox="0 0 %s %s" fill="none">%s</svg>`,
+		w, h, w, h, paths.String())
+}
+
+// writePaths writes a <path> element per outline, filled with the first visible solid paint.
+func writePaths(sb *strings.Builder, geometry []figma.Path, paints []figma.Paint, dx, dy float64) {
+	var paint *figma.Paint
+	for i := range paints {
+		if paints[i].Type == "SOLID" && paints[i].Color != nil && paints[i].Visible {
+			paint = &paints[i]
+			break
+		}
+	}
+	if paint == nil {
+		return
+	}
+
+	for _, p := range geometry {
+		if p.Path == "" {
+			continue
+		}
+		fmt.Fprintf(sb, `<path d="%s" fill="%s"`, p.Path, colorToHex(paint.Color))
+		if paint.Color.A < 1 {
+			fmt.Fprintf(sb, ` fill-opacity="%s"`, svgNumber(paint.Color.A))
+		}
+		if p.WindingRule == "EVENODD" {
+			sb.WriteString(` fill-rule="evenodd" clip-rule="evenodd"`)
+		}
+		if dx != 0 || dy != 0 {
+			fmt.Fprintf(sb, ` transform="translate(%s %s)"`, svgNumber(dx), svgNumber(dy))
+		}
+		sb.WriteString("/>")
+	}
+}
+
+// svgNumber formats a coordinate with at most two decimals.
+func svgNumber(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
@@ -0,0 +1,134 @@
+package extractor
+
+import "github.com/hellenic-development/figma-extractor/pkg/figma"
+
+// AutoLayout is the complete auto-layout specification of a Figma frame: everything needed to
+// rebuild it as a CSS flexbox container. Empty enum values mean Figma's default.
+type AutoLayout struct {
+	NodeID string `json:"nodeId"`
+	Name   string `json:"name"`
+
+	Direction          string  `json:"direction,omitempty"`         // "HORIZONTAL" or "VERTICAL"
+	PrimaryAxisAlign   string  `json:"primaryAxisAlign,omitempty"`  // MIN, CENTER, MAX, SPACE_BETWEEN
+	CounterAxisAlign   string  `json:"counterAxisAlign,omitempty"`  // MIN, CENTER, MAX, BASELINE
+	PrimaryAxisSizing  string  `json:"primaryAxisSizing,omitempty"` // FIXED or AUTO (hug contents)
+	CounterAxisSizing  string  `json:"counterAxisSizing,omitempty"` // FIXED or AUTO (hug contents)
+	Wrap               bool    `json:"wrap,omitempty"`
+	AlignContent       string  `json:"alignContent,omitempty"` // wrapped rows: AUTO or SPACE_BETWEEN
+	ItemSpacing        float64 `json:"itemSpacing,omitempty"`
+	CounterAxisSpacing float64 `json:"counterAxisSpacing,omitempty"` // gap between wrapped rows
+
+	PaddingTop    float64 `json:"paddingTop,omitempty"`
+	PaddingRight  float64 `json:"paddingRight,omitempty"`
+	PaddingBottom float64 `json:"paddingBottom,omitempty"`
+	PaddingLeft   float64 `json:"paddingLeft,omitempty"`
+	Width         float64 `json:"width,omitempty"`
+	Height        float64 `json:"height,omitempty"`
+
+	// Children lists the children whose layout differs from the default (fixed size, aligned by
+	// the parent, in flow), in document order.
+	Children []AutoLayoutChild `json:"children,omitempty"`
+}
+
+// AutoLayoutChild describes how a child is laid out inside its auto-layout parent.
+type AutoLayoutChild struct {
+	Name             string  `json:"name"`
+	Grow             float64 `json:"grow,omitempty"`             // 1 = fills the parent's primary axis
+	Align            string  `json:"align,omitempty"`            // INHERIT or STRETCH (fills the parent's counter axis)
+	Absolute         bool    `json:"absolute,omitempty"`         // ignores auto layout and is positioned absolutely
+	SizingHorizontal string  `json:"sizingHorizontal,omitempty"` // FIXED, HUG, FILL
+	SizingVertical   string  `json:"sizingVertical,omitempty"`   // FIXED, HUG, FILL
+}
+
+// newAutoLayout creates the AutoLayout of an auto-layout frame.
+func newAutoLayout(node *figma.Node) AutoLayout {
+	l := AutoLayout{
+		NodeID:             node.ID,
+		Name:               node.Name,
+		Direction:          node.LayoutMode,
+		PrimaryAxisAlign:   node.PrimaryAxisAlignItems,
+		CounterAxisAlign:   node.CounterAxisAlignItems,
+		PrimaryAxisSizing:  node.PrimaryAxisSizingMode,
+		CounterAxisSizing:  node.CounterAxisSizingMode,
+		Wrap:               node.LayoutWrap == "WRAP",
+		AlignContent:       node.CounterAxisAlignContent,
+		ItemSpacing:        node.ItemSpacing,
+		CounterAxisSpacing: node.CounterAxisSpacing,
+		PaddingTop:         node.PaddingTop,
+		PaddingRight:       node.PaddingRight,
+		PaddingBottom:      node.PaddingBottom,
+		PaddingLeft:        node.PaddingLeft,
+	}
+	if node.AbsoluteBoundingBox != nil {
+		l.Width = node.AbsoluteBoundingBox.Width
+		l.Height = node.AbsoluteBoundingBox.Height
+	}
+
+	for _, child := range node.Children {
+		c := AutoLayoutChild{
+			Name:             child.Name,
+			Grow:             child.LayoutGrow,
+			Align:            child.LayoutAlign,
+			Absolute:         child.LayoutPositioning == "ABSOLUTE",
+			SizingHorizontal: child.LayoutSizingHorizontal,
+			SizingVertical:   child.LayoutSizingVertical,
+		}
+		if c.Grow > 0 || c.Align == "STRETCH" || c.Absolute ||
+			c.SizingHorizontal == "FILL" || c.SizingVertical == "FILL" {
+			l.Children = append(l.Children, c)
+		}
+	}
+
+	return l
+}
+
+// ResizeBehavior describes how a layer responds when its parent frame is resized: its
+// constraints and any minimum or maximum size.
+type ResizeBehavior struct {
+	NodeID string `json:"nodeId"`
+	Name   string `json:"name"`
+	Parent string `json:"parent,omitempty"` // name of the parent frame
+
+	Horizontal string `json:"horizontal,omitempty"` // LEFT, RIGHT, CENTER, LEFT_RIGHT, SCALE
+	Vertical   string `json:"vertical,omitempty"`   // TOP, BOTTOM, CENTER, TOP_BOTTOM, SCALE
+
+	// Size limits, 0 = unset
+	MinWidth  float64 `json:"minWidth,omitempty"`
+	MaxWidth  float64 `json:"maxWidth,omitempty"`
+	MinHeight float64 `json:"minHeight,omitempty"`
+	MaxHeight float64 `json:"maxHeight,omitempty"`
+}
+
+// collectResizing walks node and appends the resize behavior of every layer that does not
+// simply stay pinned to the top left of its parent or has a minimum or maximum size.
+// Constraints of children laid out by auto layout, and of top-level frames on a page, have
+// no effect and are ignored.
+func collectResizing(node *figma.Node, parent *figma.Node, behaviors *[]ResizeBehavior) {
+	b := ResizeBehavior{
+		NodeID:    node.ID,
+		Name:      node.Name,
+		MinWidth:  node.MinWidth,
+		MaxWidth:  node.MaxWidth,
+		MinHeight: node.MinHeight,
+		MaxHeight: node.MaxHeight,
+	}
+	if parent != nil {
+		b.Parent = parent.Name
+		inFlow := parent.LayoutMode != "" && parent.LayoutMode != "NONE" && node.LayoutPositioning != "ABSOLUTE"
+		if node.Constraints != nil && !inFlow && parent.Type != "CANVAS" {
+			if node.Constraints.Horizontal != "LEFT" {
+				b.Horizontal = node.Constraints.Horizontal
+			}
+			if node.Constraints.Vertical != "TOP" {
+				b.Vertical = node.Constraints.Vertical
+			}
+		}
+	}
+	if b.Horizontal != "" || b.Vertical != "" || b.MinWidth > 0 || b.MaxWidth > 0 || b.MinHeight > 0 || b.MaxHeight > 0 {
+		*behaviors = append(*behaviors, b)
+	}
+
+	for i := range node.Children {
+		collectResizing(&node.Children[i], node, behaviors)
+	}
+}
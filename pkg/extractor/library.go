@@ -0,0 +1,103 @@
+package extractor
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/hellenic-development/figma-extractor/pkg/figma"
+)
+
+// LibraryStyleKeys returns the keys of the team library styles used by the nodes under roots, in
+// order: the styles listed as remote in styles, plus styles the nodes reference that styles does
+// not list at all. Resolve them with MergeLibraryStyles.
+func LibraryStyleKeys(styles map[string]figma.Style, roots []*figma.Node) []string {
+	seen := make(map[string]bool)
+	for _, id := range referencedStyles(roots) {
+		style, listed := styles[id]
+		key := style.Key
+		switch {
+		case listed && !style.Remote:
+			continue
+		case key == "":
+			key = remoteStyleKey(id)
+		}
+		if key != "" {
+			seen[key] = true
+		}
+	}
+	return sortedStrings(seen)
+}
+
+// MergeLibraryStyles updates styles with the published names of the team library styles used by
+// the nodes under roots, so tokens are named after the library rather than after the layers
+// using them. library maps style keys to their published metadata. Remote styles missing from
+// styles are added. It returns the number of styles that were resolved.
+func MergeLibraryStyles(styles map[string]figma.Style, roots []*figma.Node, library map[string]figma.StyleMetadata) int {
+	resolved := 0
+	for _, id := range referencedStyles(roots) {
+		style, listed := styles[id]
+		if listed && !style.Remote {
+			continue
+		}
+		key := style.Key
+		if key == "" {
+			key = remoteStyleKey(id)
+		}
+		meta, ok := library[key]
+		if !ok || meta.Name == "" {
+			continue
+		}
+
+		style.Key = key
+		style.Name = meta.Name
+		style.Remote = true
+		if meta.Description != "" {
+			style.Description = meta.Description
+		}
+		if meta.StyleType != "" {
+			style.StyleType = meta.StyleType
+		}
+		styles[id] = style
+		resolved++
+	}
+	return resolved
+}
+
+// referencedStyles returns the IDs of the styles applied by the nodes under roots, in order.
+func referencedStyles(roots []*figma.Node) []string {
+	seen := make(map[string]bool)
+	var walk func(node *figma.Node)
+	walk = func(node *figma.Node) {
+		for _, id := range node.Styles {
+			seen[id] = true
+		}
+		for i := range node.Children {
+			walk(&node.Children[i])
+		}
+	}
+	for _, root := range roots {
+		walk(root)
+	}
+	return sortedStrings(seen)
+}
+
+// remoteStyleKey returns the library style key embedded in the ID of a remote style,
+// e.g. "S:0a1b2c…,12:34" -> "0a1b2c…", or "" for IDs of local styles.
+func remoteStyleKey(id string) string {
+	rest, ok := strings.CutPrefix(id, "S:")
+	if !ok {
+		return ""
+	}
+	key, _, _ := strings.Cut(rest, ",")
+	return key
+}
+
+// sortedStrings returns the members of a set in order.
+func sortedStrings(set map[string]bool) []string {
+	list := make([]string, 0, len(set))
+	for s := range set {
+		list = append(list, s)
+	}
+	sort.Strings(list)
+	return list
+}
@@ -0,0 +1,133 @@
+package extractor
+
+import "github.com/hellenic-development/figma-extractor/pkg/figma"
+
+// Flow is a prototype flow: a named starting point of a clickable prototype.
+type Flow struct {
+	Name          string `json:"name"`
+	Page          string `json:"page,omitempty"`
+	StartNodeID   string `json:"startNodeId,omitempty"`
+	StartNodeName string `json:"startNodeName,omitempty"`
+}
+
+// Interaction is a single prototype action of a node, flattened from its trigger.
+type Interaction struct {
+	NodeID   string `json:"nodeId"`
+	NodeName string `json:"nodeName,omitempty"`
+	Page     string `json:"page,omitempty"`
+
+	Trigger string  `json:"trigger,omitempty"` // Figma trigger, e.g. ON_CLICK, AFTER_TIMEOUT
+	Delay   float64 `json:"delay,omitempty"`   // trigger delay in ms
+
+	// Action is the Figma navigation of NODE actions (NAVIGATE, OVERLAY, SWAP, SCROLL_TO,
+	// CHANGE_TO) and the action type otherwise (BACK, CLOSE, URL).
+	Action          string `json:"action,omitempty"`
+	DestinationID   string `json:"destinationId,omitempty"`
+	DestinationName string `json:"destinationName,omitempty"` // empty when the destination is outside the extracted nodes
+	URL             string `json:"url,omitempty"`
+
+	Transition string  `json:"transition,omitempty"` // e.g. SMART_ANIMATE, DISSOLVE; empty for instant
+	Easing     string  `json:"easing,omitempty"`     // e.g. EASE_IN_AND_OUT
+	Duration   float64 `json:"duration,omitempty"`   // in ms
+	Direction  string  `json:"direction,omitempty"`  // LEFT, RIGHT, TOP, BOTTOM for directional transitions
+}
+
+// collectInteractions walks node and appends its prototype flows and interactions.
+// names resolves destination node IDs to layer names.
+func collectInteractions(node *figma.Node, page string, names map[string]string, flows *[]Flow, interactions *[]Interaction) {
+	if node.Type == "CANVAS" {
+		page = node.Name
+		for _, start := range node.FlowStartingPoints {
+			*flows = append(*flows, Flow{
+				Name:          start.Name,
+				Page:          page,
+				StartNodeID:   start.NodeID,
+				StartNodeName: names[start.NodeID],
+			})
+		}
+	}
+
+	for _, reaction := range node.Interactions {
+		var trigger string
+		var delay float64
+		if reaction.Trigger != nil {
+			trigger, delay = reaction.Trigger.Type, reaction.Trigger.Delay
+		}
+		for _, action := range reaction.Actions {
+			in := Interaction{
+				NodeID:   node.ID,
+				NodeName: node.Name,
+				Page:     page,
+				Trigger:  trigger,
+				Delay:    delay,
+				Action:   action.Type,
+				URL:      action.URL,
+			}
+			if action.Type == "NODE" {
+				in.Action = action.Navigation
+				in.DestinationID = action.DestinationID
+				in.DestinationName = names[action.DestinationID]
+			}
+			if t := action.Transition; t != nil {
+				in.Transition = t.Type
+				in.Duration = t.Duration
+				in.Direction = t.Direction
+				if t.Easing != nil {
+					in.Easing = t.Easing.Type
+				}
+			}
+			*interactions = append(*interactions, in)
+		}
+	}
+
+	// Files saved before interactions existed only carry the legacy on-click transition.
+	if len(node.Interactions) == 0 && node.TransitionNodeID != "" {
+		*interactions = append(*interactions, Interaction{
+			NodeID:          node.ID,
+			NodeName:        node.Name,
+			Page:            page,
+			Trigger:         "ON_CLICK",
+			Action:          "NAVIGATE",
+			DestinationID:   node.TransitionNodeID,
+			DestinationName: names[node.TransitionNodeID],
+			Easing:          node.TransitionEasing,
+			Duration:        node.TransitionDuration,
+		})
+	}
+
+	for i := range node.Children {
+		collectInteractions(&node.Children[i], page, names, flows, interactions)
+	}
+}
+
+// flowsStartingIn returns the flows of the document whose starting frame is target or lies
+// inside it. Flows are defined on pages, which node-scoped extraction does not visit.
+func flowsStartingIn(document, target *figma.Node, names map[string]string) []Flow {
+	if target.Type == "CANVAS" {
+		// collectInteractions already found the flows of a page.
+		return nil
+	}
+
+	var flows []Flow
+	for _, page := range document.Children {
+		for _, start := range page.FlowStartingPoints {
+			if start.NodeID == target.ID || containsNode(target, start.NodeID) {
+				flows = append(flows, Flow{
+					Name:          start.Name,
+					Page:          page.Name,
+					StartNodeID:   start.NodeID,
+					StartNodeName: names[start.NodeID],
+				})
+			}
+		}
+	}
+	return flows
+}
+
+// nodeNames maps the ID of every node in the subtree of node to its name.
+func nodeNames(node *figma.Node, names map[string]string) {
+	names[node.ID] = node.Name
+	for i := range node.Children {
+		nodeNames(&node.Children[i], names)
+	}
+}
@@ -0,0 +1,190 @@
+package extractor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hellenic-development/figma-extractor/internal/yaml"
+)
+
+// Category is a palette category of ColorPalette.
+type Category string
+
+// Palette categories.
+const (
+	CategoryPrimary    Category = "primary"
+	CategorySecondary  Category = "secondary"
+	CategoryBackground Category = "background"
+	CategoryText       Category = "text"
+	CategoryStatus     Category = "status"
+	CategoryBorder     Category = "border"
+	CategoryBrand      Category = "brand"
+	CategoryAccent     Category = "accent"
+	CategoryNeutral    Category = "neutral"
+)
+
+// colors returns the map of the palette holding the colors of category c, or nil for an
+// unknown category.
+func (p *ColorPalette) colors(c Category) map[string]string {
+	switch c {
+	case CategoryPrimary:
+		return p.Primary
+	case CategorySecondary:
+		return p.Secondary
+	case CategoryBackground:
+		return p.Background
+	case CategoryText:
+		return p.Text
+	case CategoryStatus:
+		return p.Status
+	case CategoryBorder:
+		return p.Border
+	case CategoryBrand:
+		return p.Brand
+	case CategoryAccent:
+		return p.Accent
+	case CategoryNeutral:
+		return p.Neutral
+	}
+	return nil
+}
+
+// ColorRule assigns the colors whose names match a pattern to a palette category.
+type ColorRule struct {
+	Pattern  string   // glob on the style or layer name of the color, case-insensitive: "*" matches any text (including "/"), "?" one character
+	Category Category // palette category the matching colors go to
+	Name     string   // token name of the matching colors within the category; empty = their own name
+
+	re *regexp.Regexp
+}
+
+// ColorRules categorizes colors by name patterns, replacing the built-in keyword heuristics
+// ("primary", "bg", "error", ...). The first matching rule wins; colors no rule matches are
+// left to the hue and lightness inference of the Brand, Accent and Neutral palettes.
+type ColorRules struct {
+	rules []ColorRule
+}
+
+// NewColorRules compiles rules, in order of precedence.
+func NewColorRules(rules []ColorRule) (*ColorRules, error) {
+	compiled := make([]ColorRule, len(rules))
+	for i, rule := range rules {
+		if !isCategory(rule.Category) {
+			return nil, fmt.Errorf("color rule %q: unknown category %q", rule.Pattern, rule.Category)
+		}
+		pattern := regexp.QuoteMeta(strings.TrimSpace(rule.Pattern))
+		pattern = strings.NewReplacer(`\*`, `.*`, `\?`, `.`).Replace(pattern)
+		rule.re = regexp.MustCompile(`(?i)^` + pattern + `$`)
+		compiled[i] = rule
+	}
+	return &ColorRules{rules: compiled}, nil
+}
+
+// isCategory reports whether c is one of the palette categories.
+func isCategory(c Category) bool {
+	switch c {
+	case CategoryPrimary, CategorySecondary, CategoryBackground, CategoryText, CategoryStatus,
+		CategoryBorder, CategoryBrand, CategoryAccent, CategoryNeutral:
+		return true
+	}
+	return false
+}
+
+// Rules returns the rules in order of precedence.
+func (r *ColorRules) Rules() []ColorRule {
+	return append([]ColorRule(nil), r.rules...)
+}
+
+// Match returns the category and token name of a color named name, and whether a rule
+// matched it.
+func (r *ColorRules) Match(name string) (Category, string, bool) {
+	for _, rule := range r.rules {
+		if rule.re.MatchString(strings.TrimSpace(name)) {
+			if rule.Name != "" {
+				return rule.Category, rule.Name, true
+			}
+			return rule.Category, name, true
+		}
+	}
+	return "", "", false
+}
+
+// ParseColorRules parses a rules file mapping name patterns onto targets, in JSON (an object)
+// or YAML (a mapping of plain or quoted scalars), keeping the order of the file:
+//
+//	"Brand/*": color.primary
+//	"Gray/*": color.neutral
+//	"Red/500": color.status.error
+//
+// A target is "color.<category>", or "color.<category>.<name>" to also rename the color.
+func ParseColorRules(data []byte) (*ColorRules, error) {
+	var pairs [][2]string
+	var err error
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		pairs, err = parseJSONPairs(trimmed)
+	} else {
+		pairs, err = parseYAMLPairs(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make([]ColorRule, 0, len(pairs))
+	for _, pair := range pairs {
+		parts := strings.SplitN(pair[1], ".", 3)
+		if len(parts) < 2 || parts[0] != "color" {
+			return nil, fmt.Errorf("rule %q: target %q must be color.<category> or color.<category>.<name>", pair[0], pair[1])
+		}
+		rule := ColorRule{Pattern: pair[0], Category: Category(parts[1])}
+		if len(parts) == 3 {
+			rule.Name = parts[2]
+		}
+		rules = append(rules, rule)
+	}
+	return NewColorRules(rules)
+}
+
+// parseJSONPairs returns the members of a JSON object of strings in document order.
+func parseJSONPairs(data []byte) ([][2]string, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, fmt.Errorf("parse rules: expected a JSON object")
+	}
+	var pairs [][2]string
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("parse rules: %w", err)
+		}
+		var value string
+		if err := dec.Decode(&value); err != nil {
+			return nil, fmt.Errorf("parse rules: value of %q: %w", key, err)
+		}
+		pairs = append(pairs, [2]string{key.(string), value})
+	}
+	return pairs, nil
+}
+
+// parseYAMLPairs returns the entries of a flat YAML mapping of strings in document order.
+func parseYAMLPairs(data []byte) ([][2]string, error) {
+	lines, err := yaml.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse rules: %w", err)
+	}
+	pairs := make([][2]string, 0, len(lines))
+	for _, line := range lines {
+		switch {
+		case line.Item || !line.Pair:
+			return nil, fmt.Errorf("parse rules: line %d: expected \"pattern: target\"", line.Num)
+		case line.Items != nil:
+			return nil, fmt.Errorf("parse rules: line %d: target of %q is a list", line.Num, line.Key)
+		case line.Value == "":
+			return nil, fmt.Errorf("parse rules: line %d: %q has no target", line.Num, line.Key)
+		}
+		pairs = append(pairs, [2]string{line.Key, line.Value})
+	}
+	return pairs, nil
+}
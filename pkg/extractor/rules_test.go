@@ -0,0 +1,105 @@
+package extractor
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseColorRules(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []ColorRule // Pattern, Category and Name only
+	}{
+		{
+			name: "yaml plain and quoted",
+			data: `# palette rules
+"Brand/*": color.primary
+
+'Gray/*': "color.neutral" # greys
+Red/500: color.status.error
+it's: 'color.accent.it''s'
+`,
+			want: []ColorRule{
+				{Pattern: "Brand/*", Category: CategoryPrimary},
+				{Pattern: "Gray/*", Category: CategoryNeutral},
+				{Pattern: "Red/500", Category: CategoryStatus, Name: "error"},
+				{Pattern: "it's", Category: CategoryAccent, Name: "it's"},
+			},
+		},
+		{
+			name: "json",
+			data: `{"Gray/*": "color.neutral", "Brand/*": "color.primary.brand"}`,
+			want: []ColorRule{
+				{Pattern: "Gray/*", Category: CategoryNeutral},
+				{Pattern: "Brand/*", Category: CategoryPrimary, Name: "brand"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := ParseColorRules([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("ParseColorRules() error = %v", err)
+			}
+			var got []ColorRule
+			for _, rule := range rules.Rules() {
+				got = append(got, ColorRule{Pattern: rule.Pattern, Category: rule.Category, Name: rule.Name})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseColorRules() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseColorRulesErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"unterminated quote", "a: color.text\n\n\"Brand/*: color.primary\n", "parse rules: line 3: unterminated string"},
+		{"missing colon", "# rules\nBrand/* color.primary\n", `parse rules: line 2: expected "pattern: target"`},
+		{"list item", "- Brand/*: color.primary\n", `parse rules: line 1: expected "pattern: target"`},
+		{"missing target", "a: color.text\nBrand/*: # none\n", `parse rules: line 2: "Brand/*" has no target`},
+		{"list target", "Brand/*: [color.primary]\n", `parse rules: line 1: target of "Brand/*" is a list`},
+		{"trailing text", "'Brand/*': 'color.primary' x\n", `parse rules: line 1: unexpected "x"`},
+		{"bad target", "Brand/*: primary\n", `target "primary" must be color.<category>`},
+		{"unknown category", "Brand/*: color.fancy\n", `unknown category "fancy"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseColorRules([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ParseColorRules() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestColorRulesMatch(t *testing.T) {
+	rules, err := ParseColorRules([]byte("Brand/*: color.primary\nGray/?00: color.neutral.gray\n"))
+	if err != nil {
+		t.Fatalf("ParseColorRules() error = %v", err)
+	}
+	tests := []struct {
+		name     string
+		category Category
+		token    string
+		ok       bool
+	}{
+		{"brand/Blue/500", CategoryPrimary, "brand/Blue/500", true},
+		{"Gray/500", CategoryNeutral, "gray", true},
+		{"Gray/50", "", "", false},
+	}
+	for _, tt := range tests {
+		category, token, ok := rules.Match(tt.name)
+		if category != tt.category || token != tt.token || ok != tt.ok {
+			t.Errorf("Match(%q) = %q, %q, %v, want %q, %q, %v", tt.name, category, token, ok, tt.category, tt.token, tt.ok)
+		}
+	}
+}
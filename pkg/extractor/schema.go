@@ -0,0 +1,116 @@
+package extractor
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// SchemaVersion is the version of the JSON encoding of DesignSpecs. It is written as the
+// "schemaVersion" member of every encoded DesignSpecs and increased whenever a member is
+// renamed, removed or changes meaning; new members do not change it.
+const SchemaVersion = 1
+
+// designSpecs has the fields of DesignSpecs without its methods, so they can be encoded with
+// the default encoding.
+type designSpecs DesignSpecs
+
+// MarshalJSON encodes the specifications as a JSON object of their fields, named in
+// lowerCamelCase with empty ones omitted, and the SchemaVersion as "schemaVersion". Maps are
+// encoded with sorted keys, so identical specifications encode to identical bytes.
+func (s DesignSpecs) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		SchemaVersion int `json:"schemaVersion"`
+		designSpecs
+	}{SchemaVersion, designSpecs(s)})
+}
+
+// UnmarshalJSON decodes specifications encoded by MarshalJSON. Documents without a
+// "schemaVersion" are accepted; documents of a newer schema version are rejected.
+func (s *DesignSpecs) UnmarshalJSON(data []byte) error {
+	v := struct {
+		SchemaVersion int `json:"schemaVersion"`
+		*designSpecs
+	}{designSpecs: (*designSpecs)(s)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if v.SchemaVersion > SchemaVersion {
+		return fmt.Errorf("design specs schema version %d is newer than the supported version %d", v.SchemaVersion, SchemaVersion)
+	}
+	return nil
+}
+
+// JSONSchema returns the JSON Schema (draft 2020-12) of the JSON encoding of DesignSpecs, with
+// a definition per struct type. Members encoded even when empty are required.
+func JSONSchema() ([]byte, error) {
+	g := schemaGenerator{defs: make(map[string]map[string]any)}
+	root := g.schema(reflect.TypeFor[DesignSpecs]())
+
+	specs := g.defs["DesignSpecs"]
+	specs["properties"].(map[string]any)["schemaVersion"] = map[string]any{"const": SchemaVersion}
+	specs["required"] = append([]string{"schemaVersion"}, specs["required"].([]string)...)
+
+	return json.MarshalIndent(map[string]any{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$id":     fmt.Sprintf("urn:figma-extractor:design-specs:v%d", SchemaVersion),
+		"title":   "DesignSpecs",
+		"$ref":    root["$ref"],
+		"$defs":   g.defs,
+	}, "", "  ")
+}
+
+// schemaGenerator builds JSON Schemas of Go types, collecting struct types as definitions.
+type schemaGenerator struct {
+	defs map[string]map[string]any
+}
+
+// schema returns the schema of values of type t, a reference for struct types.
+func (g schemaGenerator) schema(t reflect.Type) map[string]any {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return g.schema(t.Elem())
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.Struct:
+		if _, ok := g.defs[t.Name()]; !ok {
+			def := make(map[string]any)
+			g.defs[t.Name()] = def // before the fields, for recursive types
+			properties := make(map[string]any)
+			required := []string{}
+			for i := range t.NumField() {
+				field := t.Field(i)
+				if !field.IsExported() {
+					continue
+				}
+				name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+				if name == "-" {
+					continue
+				}
+				if name == "" {
+					name = field.Name
+				}
+				properties[name] = g.schema(field.Type)
+				if !strings.Contains(opts, "omitempty") && !strings.Contains(opts, "omitzero") {
+					required = append(required, name)
+				}
+			}
+			def["type"] = "object"
+			def["properties"] = properties
+			def["required"] = required
+		}
+		return map[string]any{"$ref": "#/$defs/" + t.Name()}
+	}
+	return map[string]any{}
+}
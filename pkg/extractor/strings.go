@@ -0,0 +1,86 @@
+package extractor
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/hellenic-development/figma-extractor/pkg/figma"
+)
+
+// TextString is the copy of a text layer of the design, for a copy deck.
+type TextString struct {
+	NodeID      string `json:"nodeId"`
+	Name        string `json:"name"`                  // layer name
+	Text        string `json:"text"`                  // the characters of the layer
+	Path        string `json:"path,omitempty"`        // the page and frames containing the layer, e.g. "Onboarding / Welcome / Card"
+	Placeholder bool   `json:"placeholder,omitempty"` // the text looks like placeholder copy, e.g. "Lorem ipsum" or "Button"
+}
+
+// pathTypes are the node types the path of a string is made of.
+var pathTypes = map[string]bool{
+	"CANVAS":        true,
+	"SECTION":       true,
+	"FRAME":         true,
+	"COMPONENT":     true,
+	"COMPONENT_SET": true,
+	"INSTANCE":      true,
+}
+
+// placeholderTexts are texts left as they were when a designer added the layer or dragged it
+// out of a template, lowercased.
+var placeholderTexts = map[string]bool{
+	"text": true, "label": true, "title": true, "heading": true, "subtitle": true, "subheading": true,
+	"description": true, "body": true, "body text": true, "caption": true, "button": true,
+	"placeholder": true, "placeholder text": true, "sample text": true, "text here": true,
+	"your text here": true, "enter text": true, "type something": true, "tbd": true, "todo": true,
+}
+
+// placeholderPattern matches placeholder copy beyond placeholderTexts: lorem ipsum, and runs of
+// x's such as "XXX" or "xx:xx".
+var placeholderPattern = regexp.MustCompile(`(?i)^(.*\blorem ipsum\b.*|lorem\b.*|[x\s.,:/-]*x{2,}[x\s.,:/-]*)$`)
+
+// isPlaceholder reports whether text looks like placeholder copy.
+func isPlaceholder(text string) bool {
+	text = strings.Join(strings.Fields(text), " ")
+	return placeholderTexts[strings.ToLower(text)] || placeholderPattern.MatchString(text)
+}
+
+// collectStrings returns the copy of the text layers under roots, in document order, with the
+// path of each layer starting at the page of its root (see pageOf). Subtrees whose ID is in
+// copies are skipped: copies of duplicate frames only repeat the strings of the original.
+func collectStrings(roots []*figma.Node, pages []string, copies map[string]bool) []TextString {
+	var texts []TextString
+
+	var walk func(node *figma.Node, path []string)
+	walk = func(node *figma.Node, path []string) {
+		if copies[node.ID] {
+			return
+		}
+		if node.Type == "TEXT" {
+			if strings.TrimSpace(node.Characters) != "" {
+				texts = append(texts, TextString{
+					NodeID:      node.ID,
+					Name:        node.Name,
+					Text:        node.Characters,
+					Path:        strings.Join(path, " / "),
+					Placeholder: isPlaceholder(node.Characters),
+				})
+			}
+			return
+		}
+		if pathTypes[node.Type] {
+			path = append(path[:len(path):len(path)], node.Name)
+		}
+		for i := range node.Children {
+			walk(&node.Children[i], path)
+		}
+	}
+	for i, root := range roots {
+		var path []string
+		if i < len(pages) && pages[i] != "" && root.Type != "CANVAS" {
+			path = []string{pages[i]}
+		}
+		walk(root, path)
+	}
+	return texts
+}
@@ -0,0 +1,215 @@
+package extractor
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/hellenic-development/figma-extractor/pkg/figma"
+)
+
+// ColorThemes holds two coordinated palettes for a file designed in both a light and a dark
+// theme. Light and Dark share their keys, so every color has a value in both themes.
+type ColorThemes struct {
+	// Source is "frames" when the themes come from parallel light and dark frames or pages, or
+	// "variables" when they come from the light and dark modes of color variables.
+	Source string            `json:"source,omitempty"`
+	Light  map[string]string `json:"light,omitempty"` // color name -> hex
+	Dark   map[string]string `json:"dark,omitempty"`  // color name -> hex
+}
+
+// themeWords maps the words identifying a theme in a page, frame or mode name to the theme.
+var themeWords = map[string]string{
+	"light": "light",
+	"day":   "light",
+	"dark":  "dark",
+	"night": "dark",
+}
+
+// themeOf returns "light" or "dark" when name mentions a theme (e.g. "Home / Dark", "Light
+// mode"), and "" otherwise.
+func themeOf(name string) string {
+	for _, word := range nameWords(name) {
+		if theme, ok := themeWords[word]; ok {
+			return theme
+		}
+	}
+	return ""
+}
+
+// withoutTheme removes theme words, and the "mode" or "theme" they come with, from a
+// slash-separated name so that "Light/Surface" and "Dark/Surface" both become "Surface".
+func withoutTheme(name string) string {
+	return withoutWords(name, func(word string) bool {
+		_, ok := themeWords[word]
+		return ok || word == "mode" || word == "theme"
+	})
+}
+
+// withoutWords removes the words for which drop (given the lowercase word) reports true from
+// every segment of a slash-separated name, dropping segments left empty.
+func withoutWords(name string, drop func(word string) bool) string {
+	var segments []string
+	for _, segment := range strings.Split(name, "/") {
+		var kept []string
+		for _, word := range strings.FieldsFunc(segment, isNameSeparator) {
+			if !drop(strings.ToLower(word)) {
+				kept = append(kept, word)
+			}
+		}
+		if len(kept) > 0 {
+			segments = append(segments, strings.Join(kept, " "))
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// nameWords splits a name into lowercase words.
+func nameWords(name string) []string {
+	words := strings.FieldsFunc(name, isNameSeparator)
+	for i, w := range words {
+		words[i] = strings.ToLower(w)
+	}
+	return words
+}
+
+// isNameSeparator reports whether r separates words in a Figma name.
+func isNameSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+// themeFrame is a frame that belongs to a theme, keyed by what it depicts regardless of theme.
+type themeFrame struct {
+	theme string
+	key   string
+	node  *figma.Node
+}
+
+// detectFrameThemes looks for parallel light and dark frames: frames on pages named after a
+// theme ("Light", "Dark mode") paired by frame name, or frames named after a theme ("Home /
+// Light", "Home / Dark") paired by the rest of their name. The fill colors of every pair are
+// matched by style or layer name into coordinated palettes. It returns nil when no pair is found.
+func detectFrameThemes(roots []*figma.Node, styles map[string]figma.Style) *ColorThemes {
+	var frames []themeFrame
+	var visit func(node *figma.Node, pageTheme, pageKey string)
+	visit = func(node *figma.Node, pageTheme, pageKey string) {
+		switch node.Type {
+		case "DOCUMENT":
+			for i := range node.Children {
+				visit(&node.Children[i], "", "")
+			}
+		case "CANVAS":
+			theme := themeOf(node.Name)
+			for i := range node.Children {
+				visit(&node.Children[i], theme, withoutTheme(node.Name))
+			}
+		default:
+			if pageTheme != "" {
+				frames = append(frames, themeFrame{theme: pageTheme, key: pageKey + "/" + node.Name, node: node})
+			} else if theme := themeOf(node.Name); theme != "" {
+				frames = append(frames, themeFrame{theme: theme, key: withoutTheme(node.Name), node: node})
+			}
+		}
+	}
+	for _, root := range roots {
+		visit(root, "", "")
+	}
+
+	light := make(map[string]*figma.Node)
+	dark := make(map[string]*figma.Node)
+	var keys []string
+	for _, f := range frames {
+		if light[f.key] == nil && dark[f.key] == nil {
+			keys = append(keys, f.key)
+		}
+		side := light
+		if f.theme == "dark" {
+			side = dark
+		}
+		if side[f.key] == nil {
+			side[f.key] = f.node
+		}
+	}
+
+	themes := &ColorThemes{Source: "frames", Light: make(map[string]string), Dark: make(map[string]string)}
+	for _, key := range keys {
+		l, d := light[key], dark[key]
+		if l == nil || d == nil {
+			continue
+		}
+		lightColors := make(map[string]string)
+		darkColors := make(map[string]string)
+		themeFillColors(l, styles, lightColors)
+		themeFillColors(d, styles, darkColors)
+		for name, hex := range lightColors {
+			if darkHex, ok := darkColors[name]; ok {
+				if _, seen := themes.Light[name]; !seen {
+					themes.Light[name] = hex
+					themes.Dark[name] = darkHex
+				}
+			}
+		}
+	}
+	if len(themes.Light) == 0 {
+		return nil
+	}
+	return themes
+}
+
+// themeFillColors collects the visible solid fill colors of the subtree of node, keyed by their
+// style or layer name without theme words. The first color of a name wins.
+func themeFillColors(node *figma.Node, styles map[string]figma.Style, colors map[string]string) {
+	for _, fill := range node.Fills {
+		if fill.Type == "SOLID" && fill.Color != nil && fill.Visible {
+			name := withoutTheme(tokenName(node, "fill", styles))
+			if _, seen := colors[name]; !seen && name != "" {
+				colors[name] = colorToHex(fill.Color)
+			}
+		}
+	}
+	for i := range node.Children {
+		themeFillColors(&node.Children[i], styles, colors)
+	}
+}
+
+// ThemesFromVariables builds coordinated light and dark palettes from the color variables of
+// collections that have both a light and a dark mode. It returns nil when there are none.
+func ThemesFromVariables(collections []VariableCollection) *ColorThemes {
+	themes := &ColorThemes{Source: "variables", Light: make(map[string]string), Dark: make(map[string]string)}
+	for _, coll := range collections {
+		var light, dark *VariableMode
+		for i := range coll.Modes {
+			switch themeOf(coll.Modes[i].Name) {
+			case "light":
+				if light == nil {
+					light = &coll.Modes[i]
+				}
+			case "dark":
+				if dark == nil {
+					dark = &coll.Modes[i]
+				}
+			}
+		}
+		if light == nil || dark == nil {
+			continue
+		}
+
+		darkColors := make(map[string]string)
+		for _, v := range dark.Variables {
+			if v.Type == "COLOR" {
+				darkColors[v.Name] = v.Color
+			}
+		}
+		for _, v := range light.Variables {
+			darkHex, ok := darkColors[v.Name]
+			if _, seen := themes.Light[v.Name]; v.Type != "COLOR" || !ok || seen {
+				continue
+			}
+			themes.Light[v.Name] = v.Color
+			themes.Dark[v.Name] = darkHex
+		}
+	}
+	if len(themes.Light) == 0 {
+		return nil
+	}
+	return themes
+}
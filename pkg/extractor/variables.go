@@ -0,0 +1,226 @@
+package extractor
+
+import (
+	"maps"
+	"slices"
+	"sort"
+
+	"github.com/hellenic-development/figma-extractor/pkg/figma"
+)
+
+// VariableCollection is a Figma variable collection resolved into one token set per mode.
+type VariableCollection struct {
+	Name  string         `json:"name"`
+	Modes []VariableMode `json:"modes,omitempty"` // in the order defined in Figma
+}
+
+// VariableMode holds the resolved value of every variable of a collection for a single mode,
+// such as "Light" or "Dark". Variables are sorted by name.
+type VariableMode struct {
+	Name      string     `json:"name"`
+	Default   bool       `json:"default,omitempty"` // true for the collection's default mode
+	Variables []Variable `json:"variables,omitempty"`
+}
+
+// Variable is a single resolved variable value. Only the field matching Type is meaningful.
+type Variable struct {
+	Name string `json:"name"`
+	Type string `json:"type,omitempty"` // "COLOR", "FLOAT", "STRING", "BOOLEAN"
+
+	Color  string  `json:"color,omitempty"`  // hex, COLOR variables
+	Number float64 `json:"number,omitempty"` // FLOAT variables
+	String string  `json:"string,omitempty"` // STRING variables
+	Bool   bool    `json:"bool,omitempty"`   // BOOLEAN variables
+
+	// Dimension is true for FLOAT variables scoped to sizes, spacing, radii or font metrics,
+	// i.e. values that should be rendered with a length unit.
+	Dimension bool `json:"dimension,omitempty"`
+	// TextMetric is true for dimensions scoped only to font sizes, line heights or letter
+	// spacings, which scale with the user's font size on Android (sp rather than dp).
+	TextMetric bool `json:"textMetric,omitempty"`
+
+	// Aliases is the chain of variables the value was resolved through, nearest first: the
+	// variable this one aliases, the variable that one aliases, and so on down to the
+	// variable holding the raw value (e.g. a semantic "button/bg" aliasing a primitive
+	// "primary/500"). Empty for raw values and for aliases of library variables, which are
+	// not part of the extracted collections.
+	Aliases []VariableRef `json:"aliases,omitempty"`
+}
+
+// VariableRef identifies a variable of an extracted collection.
+type VariableRef struct {
+	Collection string `json:"collection,omitempty"`
+	Name       string `json:"name"`
+}
+
+// maxAliasDepth bounds alias resolution so that accidental alias cycles cannot loop forever.
+const maxAliasDepth = 16
+
+// dimensionScopes lists the variable scopes whose FLOAT values represent lengths.
+var dimensionScopes = map[string]bool{
+	"WIDTH_HEIGHT":      true,
+	"GAP":               true,
+	"CORNER_RADIUS":     true,
+	"STROKE_FLOAT":      true,
+	"EFFECT_FLOAT":      true,
+	"FONT_SIZE":         true,
+	"LINE_HEIGHT":       true,
+	"LETTER_SPACING":    true,
+	"PARAGRAPH_SPACING": true,
+	"PARAGRAPH_INDENT":  true,
+}
+
+// textMetricScopes lists the dimension scopes of font metrics that scale with the text size.
+var textMetricScopes = map[string]bool{
+	"FONT_SIZE":      true,
+	"LINE_HEIGHT":    true,
+	"LETTER_SPACING": true,
+}
+
+// ExtractVariables converts a Figma variables response into mode-aware token sets.
+// Remote collections (consumed from libraries) are skipped, aliases are resolved to the
+// value of the referenced variable (keeping the chain in Variable.Aliases), and collections
+// are sorted by name.
+func ExtractVariables(resp *figma.VariablesResponse) []VariableCollection {
+	if resp == nil {
+		return nil
+	}
+
+	var collections []VariableCollection
+
+	// Collections are visited in ID order so that equally named ones keep a stable order.
+	for _, id := range slices.Sorted(maps.Keys(resp.Meta.VariableCollections)) {
+		coll := resp.Meta.VariableCollections[id]
+		if coll.Remote {
+			continue
+		}
+
+		vc := VariableCollection{Name: coll.Name}
+
+		for _, mode := range coll.Modes {
+			vm := VariableMode{
+				Name:    mode.Name,
+				Default: mode.ModeID == coll.DefaultModeID,
+			}
+
+			for _, id := range coll.VariableIDs {
+				v, ok := resp.Meta.Variables[id]
+				if !ok {
+					continue
+				}
+
+				value, chain, ok := resolveVariableValue(resp, v, mode.Name, mode.ModeID, 0)
+				if !ok {
+					continue
+				}
+
+				variable := newVariable(v, value)
+				variable.Aliases = chain
+				vm.Variables = append(vm.Variables, variable)
+			}
+
+			sort.SliceStable(vm.Variables, func(i, j int) bool {
+				return vm.Variables[i].Name < vm.Variables[j].Name
+			})
+			vc.Modes = append(vc.Modes, vm)
+		}
+
+		collections = append(collections, vc)
+	}
+
+	sort.SliceStable(collections, func(i, j int) bool {
+		return collections[i].Name < collections[j].Name
+	})
+
+	return collections
+}
+
+// resolveVariableValue returns the raw value of v for the given mode, following aliases, and
+// the chain of local variables it went through.
+// An aliased variable may live in another collection with different modes; in that case the
+// mode with the same name is used, falling back to that collection's default mode.
+func resolveVariableValue(resp *figma.VariablesResponse, v figma.Variable, modeName, modeID string, depth int) (figma.VariableValue, []VariableRef, bool) {
+	value, ok := v.ValuesByMode[modeID]
+	if !ok {
+		return figma.VariableValue{}, nil, false
+	}
+
+	if value.Alias == nil {
+		return value, nil, true
+	}
+
+	if depth >= maxAliasDepth {
+		return figma.VariableValue{}, nil, false
+	}
+
+	target, ok := resp.Meta.Variables[value.Alias.ID]
+	if !ok {
+		return figma.VariableValue{}, nil, false
+	}
+
+	resolved, chain, ok := resolveVariableValue(resp, target, modeName, targetModeID(resp, target, modeName), depth+1)
+	if !ok {
+		return figma.VariableValue{}, nil, false
+	}
+
+	// Library variables are not extracted, so nothing could refer to them.
+	coll, local := resp.Meta.VariableCollections[target.VariableCollectionID]
+	if !local || coll.Remote {
+		return resolved, nil, true
+	}
+	return resolved, append([]VariableRef{{Collection: coll.Name, Name: target.Name}}, chain...), true
+}
+
+// targetModeID picks the mode of v's collection that corresponds to modeName.
+func targetModeID(resp *figma.VariablesResponse, v figma.Variable, modeName string) string {
+	coll, ok := resp.Meta.VariableCollections[v.VariableCollectionID]
+	if !ok {
+		// Unknown collection (e.g. a remote library): use the first value available.
+		ids := slices.Sorted(maps.Keys(v.ValuesByMode))
+		if len(ids) == 0 {
+			return ""
+		}
+		return ids[0]
+	}
+
+	for _, mode := range coll.Modes {
+		if mode.Name == modeName {
+			return mode.ModeID
+		}
+	}
+
+	return coll.DefaultModeID
+}
+
+// newVariable builds a Variable from a Figma variable and its resolved raw value.
+func newVariable(v figma.Variable, value figma.VariableValue) Variable {
+	out := Variable{
+		Name: v.Name,
+		Type: v.ResolvedType,
+	}
+
+	switch {
+	case value.Color != nil:
+		out.Type = "COLOR"
+		out.Color = colorToHex(value.Color)
+	case value.Float != nil:
+		out.Type = "FLOAT"
+		out.Number = *value.Float
+		out.TextMetric = true
+		for _, scope := range v.Scopes {
+			if dimensionScopes[scope] {
+				out.Dimension = true
+				out.TextMetric = out.TextMetric && textMetricScopes[scope]
+			}
+		}
+		out.TextMetric = out.TextMetric && out.Dimension
+	case value.String != nil:
+		out.Type = "STRING"
+		out.String = *value.String
+	case value.Bool != nil:
+		out.Type = "BOOLEAN"
+		out.Bool = *value.Bool
+	}
+
+	return out
+}
@@ -0,0 +1,32 @@
+package extractor
+
+import (
+	"testing"
+
+	"github.com/hellenic-development/figma-extractor/pkg/figma"
+)
+
+func TestNewVariableDimensionScopes(t *testing.T) {
+	tests := []struct {
+		scopes         []string
+		wantDimension  bool
+		wantTextMetric bool
+	}{
+		{nil, false, false},
+		{[]string{"ALL_SCOPES"}, false, false},
+		{[]string{"GAP", "CORNER_RADIUS"}, true, false},
+		{[]string{"FONT_SIZE"}, true, true},
+		{[]string{"LINE_HEIGHT", "LETTER_SPACING"}, true, true},
+		{[]string{"FONT_SIZE", "WIDTH_HEIGHT"}, true, false},
+		{[]string{"FONT_WEIGHT", "LINE_HEIGHT"}, true, true},
+	}
+
+	value := 16.0
+	for _, tt := range tests {
+		v := newVariable(figma.Variable{Name: "size", ResolvedType: "FLOAT", Scopes: tt.scopes}, figma.VariableValue{Float: &value})
+		if v.Dimension != tt.wantDimension || v.TextMetric != tt.wantTextMetric {
+			t.Errorf("newVariable(scopes %v) Dimension, TextMetric = %v, %v, want %v, %v",
+				tt.scopes, v.Dimension, v.TextMetric, tt.wantDimension, tt.wantTextMetric)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package figma
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// diskCacheMaxAge is how long DiskCache keeps responses. It matches the lifetime of the image
+// download URLs returned by the images endpoints, so that cached URLs are still valid.
+const diskCacheMaxAge = 14 * 24 * time.Hour
+
+// ResponseCache stores API responses together with their ETag, so that requests for unchanged
+// files are revalidated with If-None-Match instead of downloading the file again.
+// Implementations must be safe for concurrent use.
+type ResponseCache interface {
+	// Get returns the cached response body and ETag for key, if any.
+	Get(key string) (etag string, body []byte, ok bool)
+	// Put stores a response body and its ETag under key.
+	Put(key, etag string, body []byte)
+}
+
+// MemoryCache is a ResponseCache keeping responses in memory for the life of the process, e.g.
+// across the runs of a long-lived server.
+type MemoryCache struct {
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	etag string
+	body []byte
+}
+
+// NewMemoryCache returns an empty in-memory response cache.
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: make(map[string]cacheEntry)}
+}
+
+// Get implements ResponseCache.
+func (m *MemoryCache) Get(key string) (string, []byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.entries[key]
+	return entry.etag, entry.body, ok
+}
+
+// Put implements ResponseCache.
+func (m *MemoryCache) Put(key, etag string, body []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries[key] = cacheEntry{etag: etag, body: body}
+}
+
+// DiskCache is a ResponseCache storing responses as files in a directory, so that they survive
+// between runs. Entries older than 14 days are ignored.
+type DiskCache struct {
+	dir string
+}
+
+// NewDiskCache returns a response cache storing its entries in dir, which is created on demand.
+func NewDiskCache(dir string) *DiskCache {
+	return &DiskCache{dir: dir}
+}
+
+// path returns the path of the entry for key.
+func (d *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(d.dir, hex.EncodeToString(sum[:16])+".cache")
+}
+
+// Get implements ResponseCache.
+func (d *DiskCache) Get(key string) (string, []byte, bool) {
+	path := d.path(key)
+	info, err := os.Stat(path)
+	if err != nil || time.Since(info.ModTime()) > diskCacheMaxAge {
+		return "", nil, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", nil, false
+	}
+	etag, body, ok := bytes.Cut(data, []byte("\n"))
+	if !ok {
+		return "", nil, false
+	}
+	return string(etag), body, true
+}
+
+// Put implements ResponseCache. Failures to write are ignored: the response is simply not cached.
+// An entry is a single file holding the ETag on its first line and the body after it, so that
+// the ETag never describes another body than the one stored with it.
+func (d *DiskCache) Put(key, etag string, body []byte) {
+	if err := os.MkdirAll(d.dir, 0755); err != nil {
+		return
+	}
+	// Write through a temporary file so that concurrent runs never read a partial entry.
+	tmp, err := os.CreateTemp(d.dir, "*.tmp")
+	if err != nil {
+		return
+	}
+	_, err = tmp.WriteString(etag + "\n")
+	if err == nil {
+		_, err = tmp.Write(body)
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), d.path(key))
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+}
+
+// WithCache returns a copy of the client that caches file, node and image URL responses in
+// cache and revalidates them with their ETag, skipping the download of files that did not
+// change. Responses of a pinned version (see WithVersion) never change and are served from the
+// cache without any request. A nil cache disables caching.
+func (c *Client) WithCache(cache ResponseCache) *Client {
+	cached := *c
+	cached.cache = cache
+	return &cached
+}
+
+// getCached performs a GET like get, answered from the client's cache when the API reports
+// that the cached response is still current. Entries are keyed by the client's token as well:
+// pinned versions are served without any request, so the API never checks that the token may
+// read the file.
+func (c *Client) getCached(ctx context.Context, url string) ([]byte, error) {
+	if c.cache == nil {
+		return c.get(ctx, url)
+	}
+
+	key := url
+	if c.version != "" {
+		key += "@" + c.version
+	}
+	key += "#" + c.auth.scope()
+	etag, cached, ok := c.cache.Get(key)
+	if ok && c.version != "" {
+		return cached, nil
+	}
+	var header http.Header
+	if ok && etag != "" {
+		header = http.Header{"If-None-Match": {etag}}
+	}
+
+	resp, err := c.send(ctx, http.MethodGet, url, nil, header)
+	if err != nil {
+		return nil, err
+	}
+	if resp.status == http.StatusNotModified && ok {
+		return cached, nil
+	}
+	if etag := resp.header.Get("ETag"); etag != "" || c.version != "" {
+		c.cache.Put(key, etag, resp.body)
+	}
+	return resp.body, nil
+}
@@ -0,0 +1,101 @@
+package figma
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+)
+
+// redacted replaces credentials in captured responses.
+const redacted = "REDACTED"
+
+// capture writes the API responses of a client to a directory.
+type capture struct {
+	dir string
+	seq atomic.Int64
+}
+
+// capturedRequest describes a captured response, next to its body.
+type capturedRequest struct {
+	Method          string      `json:"method"`
+	URL             string      `json:"url"`
+	Status          int         `json:"status"`
+	RequestHeaders  http.Header `json:"requestHeaders"`
+	ResponseHeaders http.Header `json:"responseHeaders"`
+}
+
+// WithCapture returns a copy of the client that writes every API response it receives to dir,
+// for attaching reproducible fixtures to bug reports. Each response is stored as
+// "NNN-<endpoint>.json", replayable with the offline mode, next to a "NNN-<endpoint>.meta.json"
+// describing the request. Access tokens are redacted from both.
+func (c *Client) WithCapture(dir string) *Client {
+	capturing := *c
+	capturing.capture = &capture{dir: dir}
+	return &capturing
+}
+
+// record writes a response. Failures are ignored: capturing must never break a request.
+func (cp *capture) record(token string, req *http.Request, resp *http.Response, body []byte) {
+	if err := os.MkdirAll(cp.dir, 0755); err != nil {
+		return
+	}
+	name := fmt.Sprintf("%03d-%s", cp.seq.Add(1), endpointName(req.URL))
+
+	redact := func(s string) string {
+		if token == "" {
+			return s
+		}
+		return strings.ReplaceAll(s, token, redacted)
+	}
+	headers := func(h http.Header) http.Header {
+		clean := make(http.Header, len(h))
+		for key, values := range h {
+			for _, v := range values {
+				clean.Add(key, redact(v))
+			}
+		}
+		return clean
+	}
+
+	meta, err := json.MarshalIndent(capturedRequest{
+		Method:          req.Method,
+		URL:             redact(req.URL.String()),
+		Status:          resp.StatusCode,
+		RequestHeaders:  headers(req.Header),
+		ResponseHeaders: headers(resp.Header),
+	}, "", "  ")
+	if err != nil {
+		return
+	}
+	if token != "" {
+		body = bytes.ReplaceAll(body, []byte(token), []byte(redacted))
+	}
+	os.WriteFile(filepath.Join(cp.dir, name+".meta.json"), meta, 0644)
+	os.WriteFile(filepath.Join(cp.dir, name+".json"), body, 0644)
+}
+
+// endpointName turns the path of an API URL into a file name,
+// e.g. "/v1/files/ABC/nodes" -> "files-ABC-nodes".
+func endpointName(u *url.URL) string {
+	path := strings.Trim(u.Path, "/")
+	if version, rest, ok := strings.Cut(path, "/"); ok && (version == "v1" || version == "v2") {
+		path = rest
+	}
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		}
+		return '-'
+	}, path)
+	if name == "" {
+		return "response"
+	}
+	return name
+}
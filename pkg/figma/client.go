@@ -1,32 +1,87 @@
 package figma
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
 const (
-	figmaAPIBase = "https://api.figma.com/v1"
+	figmaAPIBase   = "https://api.figma.com/v1"
+	figmaAPIBaseV2 = "https://api.figma.com/v2" // webhooks
 )
 
 // Client represents a Figma API client with configured HTTP settings for reliable communication
 // with the Figma API. It includes retry logic and optimized transport settings for handling large files.
 type Client struct {
-	accessToken string
-	httpClient  *http.Client
+	auth       *credentials
+	limiter    *rateLimiter // shared by copies of the client
+	httpClient *http.Client
+	version    string        // file version read by the file, nodes and images endpoints; empty = current
+	cache      ResponseCache // caches file, node and image URL responses; nil = no caching
+	capture    *capture      // writes every response to a directory; nil = no capture
+	geometry   bool          // request vector paths with files and nodes
+	pluginData []string      // plugin IDs (or "shared") whose node data is requested with files and nodes
+	depth      int           // levels of the tree requested with files and nodes; 0 = all
+}
+
+// TokenRefresher returns a new access token after the current one was rejected, typically by
+// exchanging an OAuth refresh token. It is called at most once per request.
+type TokenRefresher func(ctx context.Context) (string, error)
+
+// credentials holds the access token of a client. Copies of the client share it, so a token
+// refreshed by one request is used by all later ones.
+type credentials struct {
+	mu      sync.Mutex
+	token   string
+	bearer  bool // send the token as an OAuth bearer token instead of X-Figma-Token
+	refresh TokenRefresher
 }
 
 // NewClient creates a new Figma API client with the provided personal access token.
 // The client is configured with optimized HTTP transport settings including connection pooling,
 // disabled HTTP/2 (for large file stability), and a 10-minute timeout for very large files.
 func NewClient(accessToken string) *Client {
+	return newClient(&credentials{token: accessToken})
+}
+
+// NewOAuthClient creates a new Figma API client authenticating with an OAuth access token, sent
+// as "Authorization: Bearer". When refresh is not nil, a request rejected as unauthorized asks it
+// for a new token and is retried once with that token, so long-running integrations can keep
+// working past the token's expiry.
+func NewOAuthClient(accessToken string, refresh TokenRefresher) *Client {
+	return newClient(&credentials{token: accessToken, bearer: true, refresh: refresh})
+}
+
+func newClient(auth *credentials) *Client {
+	return &Client{
+		auth:    auth,
+		limiter: &rateLimiter{},
+		httpClient: &http.Client{
+			Timeout:   10 * time.Minute, // Increased timeout for very large files
+			Transport: newTransport(),
+		},
+	}
+}
+
+// newTransport returns the default transport of clients, which honors the HTTPS_PROXY,
+// HTTP_PROXY and NO_PROXY environment variables.
+func newTransport() *http.Transport {
 	// Configure transport for better handling of large files
-	transport := &http.Transport{
+	return &http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
 		MaxIdleConns:        10,
 		IdleConnTimeout:     90 * time.Second,
 		DisableCompression:  false,
@@ -35,14 +90,177 @@ func NewClient(accessToken string) *Client {
 		// Disable HTTP/2 to avoid stream errors with large files
 		ForceAttemptHTTP2: false,
 	}
+}
 
-	return &Client{
-		accessToken: accessToken,
-		httpClient: &http.Client{
-			Timeout:   10 * time.Minute, // Increased timeout for very large files
-			Transport: transport,
-		},
+// authorize sets the authentication header of req and returns the token it used.
+func (a *credentials) authorize(req *http.Request) string {
+	a.mu.Lock()
+	token, bearer := a.token, a.bearer
+	a.mu.Unlock()
+
+	if bearer {
+		req.Header.Set("Authorization", "Bearer "+token)
+	} else {
+		req.Header.Set("X-Figma-Token", token)
+	}
+	return token
+}
+
+// scope returns a digest of the current token, which keys cached responses so that callers
+// with different tokens never read each other's cached files.
+func (a *credentials) scope() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(a.token))
+	return hex.EncodeToString(sum[:8])
+}
+
+// renew replaces the rejected token with a new one from the refresher. When another request
+// already replaced it, the newer token is kept. It reports whether a new token is available.
+func (a *credentials) renew(ctx context.Context, rejected string) (bool, error) {
+	if a.refresh == nil {
+		return false, nil
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.token != rejected {
+		return true, nil
+	}
+	token, err := a.refresh(ctx)
+	if err != nil {
+		return false, fmt.Errorf("refresh access token: %w", err)
+	}
+	if token == "" || token == rejected {
+		return false, nil
+	}
+	a.token = token
+	return true, nil
+}
+
+// WithVersion returns a copy of the client that reads the given version of files (a version ID
+// from GetVersions) instead of their current state. It applies to GetFile, GetFileMetadata,
+// GetFileNodes and GetImages, which makes extractions of a pinned version reproducible.
+func (c *Client) WithVersion(version string) *Client {
+	versioned := *c
+	versioned.version = version
+	return &versioned
+}
+
+// WithHTTPClient returns a copy of the client that sends its requests with hc, e.g. to add
+// custom TLS settings, tracing or corporate middleware. hc replaces the default client
+// entirely, including its 10-minute timeout.
+func (c *Client) WithHTTPClient(hc *http.Client) *Client {
+	custom := *c
+	custom.httpClient = hc
+	return &custom
+}
+
+// WithTransport returns a copy of the client that sends its requests through rt, keeping the
+// default timeout. Wrap http.DefaultTransport, or the transport of another client, to
+// instrument requests without rebuilding the connection settings.
+func (c *Client) WithTransport(rt http.RoundTripper) *Client {
+	hc := *c.httpClient
+	hc.Transport = rt
+	return c.WithHTTPClient(&hc)
+}
+
+// WithProxy returns a copy of the client that sends its requests through the given HTTP, HTTPS
+// or SOCKS5 proxy instead of the one configured by the environment. A custom transport that is
+// not an *http.Transport is replaced by the default one.
+func (c *Client) WithProxy(proxyURL *url.URL) *Client {
+	transport := newTransport()
+	if t, ok := c.httpClient.Transport.(*http.Transport); ok {
+		transport = t.Clone()
+	}
+	transport.Proxy = http.ProxyURL(proxyURL)
+	return c.WithTransport(transport)
+}
+
+// ParseProxy parses a proxy URL such as "http://proxy.corp:3128" or "socks5://127.0.0.1:1080".
+// URLs without a scheme are HTTP proxies.
+func ParseProxy(rawURL string) (*url.URL, error) {
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "http://" + rawURL
 	}
+	proxyURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	}
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q (must be http, https, socks5 or socks5h)", proxyURL.Scheme)
+	}
+	if proxyURL.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL %q: missing host", rawURL)
+	}
+	return proxyURL, nil
+}
+
+// HTTPClient returns the HTTP client the client sends its requests with.
+func (c *Client) HTTPClient() *http.Client {
+	return c.httpClient
+}
+
+// versioned adds the client's file version, if any, to the query of an API URL.
+func (c *Client) versioned(apiURL string) string {
+	if c.version == "" {
+		return apiURL
+	}
+	return withQuery(apiURL, "version", c.version)
+}
+
+// WithGeometry returns a copy of the client that requests files and nodes with geometry=paths,
+// so that vector nodes carry their outlines in FillGeometry and StrokeGeometry. Responses grow
+// accordingly, so only ask for it when the paths are needed, e.g. to inline icons as SVG.
+func (c *Client) WithGeometry() *Client {
+	withPaths := *c
+	withPaths.geometry = true
+	return &withPaths
+}
+
+// WithPluginData returns a copy of the client that requests files and nodes with the data the
+// given plugins stored on the nodes, in Node.PluginData. Pass "shared" to also get the shared
+// plugin data of every plugin, in Node.SharedPluginData.
+func (c *Client) WithPluginData(pluginIDs ...string) *Client {
+	withData := *c
+	withData.pluginData = append([]string(nil), pluginIDs...)
+	return &withData
+}
+
+// WithDepth returns a copy of the client that requests files and nodes only depth levels deep:
+// with depth 1 a file holds its pages, with 2 also their top-level frames. Shallow requests are
+// much faster on large files, e.g. to list the pages and frames of a file.
+func (c *Client) WithDepth(depth int) *Client {
+	shallow := *c
+	shallow.depth = depth
+	return &shallow
+}
+
+// withNodeOptions adds the optional node content the client requests, vector paths, plugin
+// data and depth, to the query of a file or nodes API URL.
+func (c *Client) withNodeOptions(apiURL string) string {
+	if c.geometry {
+		apiURL = withQuery(apiURL, "geometry", "paths")
+	}
+	if len(c.pluginData) > 0 {
+		apiURL = withQuery(apiURL, "plugin_data", strings.Join(c.pluginData, ","))
+	}
+	if c.depth > 0 {
+		apiURL = withQuery(apiURL, "depth", strconv.Itoa(c.depth))
+	}
+	return apiURL
+}
+
+// withQuery adds a query parameter to an API URL.
+func withQuery(apiURL, key, value string) string {
+	sep := "?"
+	if strings.Contains(apiURL, "?") {
+		sep = "&"
+	}
+	return apiURL + sep + key + "=" + url.QueryEscape(value)
 }
 
 // ExtractFileKey extracts the unique file identifier from a Figma URL.
@@ -119,6 +337,12 @@ func ExtractNodeIDs(figmaURL string) ([]string, error) {
 	return nodeIDs, nil
 }
 
+// NodeURL returns the link that opens a node of a file in Figma,
+// e.g. https://www.figma.com/design/ABC123?node-id=1-2 for node 1:2.
+func NodeURL(fileKey, nodeID string) string {
+	return fmt.Sprintf("https://www.figma.com/design/%s?node-id=%s", fileKey, url.QueryEscape(strings.ReplaceAll(nodeID, ":", "-")))
+}
+
 // deduplicateNodeIDs removes duplicate node IDs while preserving order.
 func deduplicateNodeIDs(nodeIDs []string) []string {
 	seen := make(map[string]bool)
@@ -137,156 +361,99 @@ func deduplicateNodeIDs(nodeIDs []string) []string {
 // GetFile retrieves complete file data from the Figma API including document structure, styles, and metadata.
 // Implements automatic retry logic (up to 3 attempts) with exponential backoff for handling rate limits
 // and temporary failures. The request automatically retries on 429 (rate limit) and 5xx (server error) responses.
-func (c *Client) GetFile(fileKey string) (*FileResponse, error) {
-	url := fmt.Sprintf("%s/files/%s", figmaAPIBase, fileKey)
-
-	var lastErr error
-	maxRetries := 3
+// With a cache (see WithCache), a file that did not change is revalidated by its ETag instead of downloaded again.
+func (c *Client) GetFile(ctx context.Context, fileKey string) (*FileResponse, error) {
+	url := c.withNodeOptions(c.versioned(fmt.Sprintf("%s/files/%s", figmaAPIBase, fileKey)))
 
-	for attempt := 1; attempt <= maxRetries; attempt++ {
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create request: %w", err)
-		}
-
-		req.Header.Set("X-Figma-Token", c.accessToken)
-		// Disable HTTP/2 to avoid stream errors with large files
-		req.Header.Set("Connection", "close")
+	body, err := c.getCached(ctx, url)
+	if err != nil {
+		return nil, err
+	}
 
-		resp, err := c.httpClient.Do(req)
-		if err != nil {
-			lastErr = fmt.Errorf("attempt %d failed to execute request: %w", attempt, err)
-			if attempt < maxRetries {
-				time.Sleep(time.Duration(attempt) * 2 * time.Second)
-				continue
-			}
-			return nil, lastErr
-		}
-		defer resp.Body.Close()
+	var fileResp FileResponse
+	if err := json.Unmarshal(body, &fileResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
 
-		if resp.StatusCode != http.StatusOK {
-			body, _ := io.ReadAll(resp.Body)
-			lastErr = fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
-			if attempt < maxRetries && (resp.StatusCode == 429 || resp.StatusCode >= 500) {
-				time.Sleep(time.Duration(attempt) * 2 * time.Second)
-				continue
-			}
-			return nil, lastErr
-		}
+	return &fileResp, nil
+}
 
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			lastErr = fmt.Errorf("attempt %d failed to read response body: %w", attempt, err)
-			if attempt < maxRetries {
-				time.Sleep(time.Duration(attempt) * 2 * time.Second)
-				continue
-			}
-			return nil, lastErr
-		}
+// GetFileMetadata retrieves the name, version and last modification time of a Figma file without
+// its document tree, by requesting the file with depth=1 (the document and its pages only).
+// It is a cheap way to find out whether a file changed since it was last fetched.
+func (c *Client) GetFileMetadata(ctx context.Context, fileKey string) (*FileResponse, error) {
+	url := c.versioned(fmt.Sprintf("%s/files/%s?depth=1", figmaAPIBase, fileKey))
 
-		var fileResp FileResponse
-		if err := json.Unmarshal(body, &fileResp); err != nil {
-			return nil, fmt.Errorf("failed to parse response: %w", err)
-		}
+	body, err := c.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
 
-		return &fileResp, nil
+	var fileResp FileResponse
+	if err := json.Unmarshal(body, &fileResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
-	return nil, lastErr
+	return &fileResp, nil
 }
 
 // GetFileNodes retrieves specific nodes from a Figma file by their node IDs.
 // This is more efficient than fetching the entire file when you only need specific elements.
 // Implements automatic retry logic (up to 3 attempts) with exponential backoff for handling rate limits.
 // Parameters:
+//   - ctx: Controls cancellation and deadlines for the request, including retry waits
 //   - fileKey: The Figma file identifier
 //   - nodeIDs: Slice of node IDs to fetch (e.g., ["123:456", "789:012"])
 //
 // Returns a NodesResponse containing the requested nodes with their complete structure.
-func (c *Client) GetFileNodes(fileKey string, nodeIDs []string) (*NodesResponse, error) {
+func (c *Client) GetFileNodes(ctx context.Context, fileKey string, nodeIDs []string) (*NodesResponse, error) {
 	if len(nodeIDs) == 0 {
 		return nil, fmt.Errorf("no node IDs provided")
 	}
 
 	// Join node IDs with comma for the API request
 	idsParam := strings.Join(nodeIDs, ",")
-	url := fmt.Sprintf("%s/files/%s/nodes?ids=%s", figmaAPIBase, fileKey, idsParam)
-
-	var lastErr error
-	maxRetries := 3
-
-	for attempt := 1; attempt <= maxRetries; attempt++ {
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create request: %w", err)
-		}
-
-		req.Header.Set("X-Figma-Token", c.accessToken)
-		req.Header.Set("Connection", "close")
-
-		resp, err := c.httpClient.Do(req)
-		if err != nil {
-			lastErr = fmt.Errorf("attempt %d failed to execute request: %w", attempt, err)
-			if attempt < maxRetries {
-				time.Sleep(time.Duration(attempt) * 2 * time.Second)
-				continue
-			}
-			return nil, lastErr
-		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode != http.StatusOK {
-			body, _ := io.ReadAll(resp.Body)
-			lastErr = fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
-			if attempt < maxRetries && (resp.StatusCode == 429 || resp.StatusCode >= 500) {
-				time.Sleep(time.Duration(attempt) * 2 * time.Second)
-				continue
-			}
-			return nil, lastErr
-		}
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			lastErr = fmt.Errorf("attempt %d failed to read response body: %w", attempt, err)
-			if attempt < maxRetries {
-				time.Sleep(time.Duration(attempt) * 2 * time.Second)
-				continue
-			}
-			return nil, lastErr
-		}
+	url := c.withNodeOptions(c.versioned(fmt.Sprintf("%s/files/%s/nodes?ids=%s", figmaAPIBase, fileKey, idsParam)))
 
-		var nodesResp NodesResponse
-		if err := json.Unmarshal(body, &nodesResp); err != nil {
-			return nil, fmt.Errorf("failed to parse response: %w", err)
-		}
+	body, err := c.getCached(ctx, url)
+	if err != nil {
+		return nil, err
+	}
 
-		// Verify that all requested nodes were returned
-		if len(nodesResp.Nodes) == 0 {
-			return nil, fmt.Errorf("no nodes found for the provided IDs: %s", idsParam)
-		}
+	var nodesResp NodesResponse
+	if err := json.Unmarshal(body, &nodesResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
 
-		// Check for nodes that weren't found
-		missingNodes := make([]string, 0)
-		for _, id := range nodeIDs {
-			if _, exists := nodesResp.Nodes[id]; !exists {
-				missingNodes = append(missingNodes, id)
-			}
-		}
+	// Verify that all requested nodes were returned
+	if len(nodesResp.Nodes) == 0 {
+		return nil, fmt.Errorf("no nodes found for the provided IDs: %s", idsParam)
+	}
 
-		if len(missingNodes) > 0 {
-			return nil, fmt.Errorf("nodes not found: %s", strings.Join(missingNodes, ", "))
+	// Check for nodes that weren't found
+	missingNodes := make([]string, 0)
+	for _, id := range nodeIDs {
+		if _, exists := nodesResp.Nodes[id]; !exists {
+			missingNodes = append(missingNodes, id)
 		}
+	}
 
-		return &nodesResp, nil
+	if len(missingNodes) > 0 {
+		return nil, fmt.Errorf("nodes not found: %s", strings.Join(missingNodes, ", "))
 	}
 
-	return nil, lastErr
+	return &nodesResp, nil
 }
 
 // GetImages retrieves rendered images for the specified nodes from the Figma Images API.
 // Supports format (png, svg, jpg, pdf) and scale factor for raster formats.
 // Implements automatic retry logic (up to 3 attempts) with exponential backoff.
-func (c *Client) GetImages(fileKey string, nodeIDs []string, format string, scale float64) (*ImageResponse, error) {
+func (c *Client) GetImages(ctx context.Context, fileKey string, nodeIDs []string, format string, scale float64) (*ImageResponse, error) {
+	return c.GetImagesWithOptions(ctx, fileKey, nodeIDs, format, scale, RenderOptions{})
+}
+
+// GetImagesWithOptions is like GetImages, rendering the nodes with the given render options.
+func (c *Client) GetImagesWithOptions(ctx context.Context, fileKey string, nodeIDs []string, format string, scale float64, opts RenderOptions) (*ImageResponse, error) {
 	if len(nodeIDs) == 0 {
 		return nil, fmt.Errorf("no node IDs provided")
 	}
@@ -299,162 +466,476 @@ func (c *Client) GetImages(fileKey string, nodeIDs []string, format string, scal
 	}
 
 	idsParam := strings.Join(nodeIDs, ",")
-	url := fmt.Sprintf("%s/images/%s?ids=%s&format=%s&scale=%g", figmaAPIBase, fileKey, idsParam, format, scale)
+	url := c.versioned(opts.apply(fmt.Sprintf("%s/images/%s?ids=%s&format=%s&scale=%g", figmaAPIBase, fileKey, idsParam, format, scale)))
 
-	var lastErr error
-	maxRetries := 3
+	body, err := c.getCached(ctx, url)
+	if err != nil {
+		return nil, err
+	}
 
-	for attempt := 1; attempt <= maxRetries; attempt++ {
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create request: %w", err)
-		}
+	var imgResp ImageResponse
+	if err := json.Unmarshal(body, &imgResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
 
-		req.Header.Set("X-Figma-Token", c.accessToken)
-		req.Header.Set("Connection", "close")
+	if imgResp.Err != nil {
+		return nil, fmt.Errorf("Figma images API error: %s", *imgResp.Err)
+	}
 
-		resp, err := c.httpClient.Do(req)
-		if err != nil {
-			lastErr = fmt.Errorf("attempt %d failed to execute request: %w", attempt, err)
-			if attempt < maxRetries {
-				time.Sleep(time.Duration(attempt) * 2 * time.Second)
-				continue
-			}
-			return nil, lastErr
-		}
-		defer resp.Body.Close()
+	return &imgResp, nil
+}
 
-		if resp.StatusCode != http.StatusOK {
-			body, _ := io.ReadAll(resp.Body)
-			lastErr = fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
-			if attempt < maxRetries && (resp.StatusCode == 429 || resp.StatusCode >= 500) {
-				time.Sleep(time.Duration(attempt) * 2 * time.Second)
-				continue
-			}
-			return nil, lastErr
-		}
+// apply adds the query parameters of the options that differ from Figma's defaults to apiURL.
+func (o RenderOptions) apply(apiURL string) string {
+	if o.SVGIncludeID {
+		apiURL = withQuery(apiURL, "svg_include_id", "true")
+	}
+	if o.SVGSimplifyStroke != nil && !*o.SVGSimplifyStroke {
+		apiURL = withQuery(apiURL, "svg_simplify_stroke", "false")
+	}
+	if o.UseAbsoluteBounds {
+		apiURL = withQuery(apiURL, "use_absolute_bounds", "true")
+	}
+	if o.ContentsOnly != nil && !*o.ContentsOnly {
+		apiURL = withQuery(apiURL, "contents_only", "false")
+	}
+	return apiURL
+}
 
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			lastErr = fmt.Errorf("attempt %d failed to read response body: %w", attempt, err)
-			if attempt < maxRetries {
-				time.Sleep(time.Duration(attempt) * 2 * time.Second)
-				continue
-			}
-			return nil, lastErr
-		}
+// GetFileImages retrieves download URLs for all embedded images in a Figma file.
+// Calls GET /v1/files/:key/images and returns a map of imageRef -> download URL.
+// Implements automatic retry logic (up to 3 attempts) with exponential backoff.
+func (c *Client) GetFileImages(ctx context.Context, fileKey string) (*FileImagesResponse, error) {
+	url := fmt.Sprintf("%s/files/%s/images", figmaAPIBase, fileKey)
 
-		var imgResp ImageResponse
-		if err := json.Unmarshal(body, &imgResp); err != nil {
-			return nil, fmt.Errorf("failed to parse response: %w", err)
-		}
+	body, err := c.getCached(ctx, url)
+	if err != nil {
+		return nil, err
+	}
 
-		if imgResp.Err != nil {
-			return nil, fmt.Errorf("Figma images API error: %s", *imgResp.Err)
-		}
+	var imgResp FileImagesResponse
+	if err := json.Unmarshal(body, &imgResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
 
-		return &imgResp, nil
+	if imgResp.Err != nil {
+		return nil, fmt.Errorf("Figma file images API error: %s", *imgResp.Err)
 	}
 
-	return nil, lastErr
+	return &imgResp, nil
 }
 
-// GetFileImages retrieves download URLs for all embedded images in a Figma file.
-// Calls GET /v1/files/:key/images and returns a map of imageRef -> download URL.
-// Implements automatic retry logic (up to 3 attempts) with exponential backoff.
-func (c *Client) GetFileImages(fileKey string) (*FileImagesResponse, error) {
-	url := fmt.Sprintf("%s/files/%s/images", figmaAPIBase, fileKey)
+// GetFileStyles retrieves all published styles (colors, text, effects, grids) from a Figma file.
+// This includes style metadata such as names, descriptions, and type information.
+func (c *Client) GetFileStyles(ctx context.Context, fileKey string) (*StylesResponse, error) {
+	url := fmt.Sprintf("%s/files/%s/styles", figmaAPIBase, fileKey)
+
+	body, err := c.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	var stylesResp StylesResponse
+	if err := json.Unmarshal(body, &stylesResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &stylesResp, nil
+}
+
+// GetStyle retrieves the metadata of a published style by its key, including the name it is
+// published under. Calls GET /v1/styles/:key.
+func (c *Client) GetStyle(ctx context.Context, key string) (*StyleResponse, error) {
+	url := fmt.Sprintf("%s/styles/%s", figmaAPIBase, key)
+
+	body, err := c.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	var styleResp StyleResponse
+	if err := json.Unmarshal(body, &styleResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &styleResp, nil
+}
+
+// GetTeamStyles retrieves a page of the styles published to a team library, starting after the
+// given cursor (0 for the first page). Calls GET /v1/teams/:team_id/styles; follow
+// Meta.Cursor.After until it is 0 to read every style.
+func (c *Client) GetTeamStyles(ctx context.Context, teamID string, after int) (*TeamStylesResponse, error) {
+	url := fmt.Sprintf("%s/teams/%s/styles?page_size=1000", figmaAPIBase, teamID)
+	if after > 0 {
+		url += fmt.Sprintf("&after=%d", after)
+	}
+
+	body, err := c.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
 
+	var stylesResp TeamStylesResponse
+	if err := json.Unmarshal(body, &stylesResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &stylesResp, nil
+}
+
+// GetComponentUsages retrieves a page of the usage counts of the components published by a
+// library file, starting at cursor (empty for the first page). Calls
+// GET /v1/analytics/libraries/:file_key/component/usages; follow Cursor while NextPage is true.
+// The Library Analytics API is only available on Enterprise plans and requires the
+// library_analytics:read scope.
+func (c *Client) GetComponentUsages(ctx context.Context, fileKey, cursor string) (*ComponentUsagesResponse, error) {
+	apiURL := fmt.Sprintf("%s/analytics/libraries/%s/component/usages?group_by=component", figmaAPIBase, fileKey)
+	if cursor != "" {
+		apiURL += "&cursor=" + url.QueryEscape(cursor)
+	}
+
+	body, err := c.get(ctx, apiURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var usagesResp ComponentUsagesResponse
+	if err := json.Unmarshal(body, &usagesResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &usagesResp, nil
+}
+
+// GetLocalVariables retrieves all local variables and variable collections of a Figma file, together
+// with the remote variables it consumes. Calls GET /v1/files/:key/variables/local.
+// The Variables API is only available to full members of Enterprise organizations and requires
+// a token with the file_variables:read scope; other callers receive a 403 error.
+func (c *Client) GetLocalVariables(ctx context.Context, fileKey string) (*VariablesResponse, error) {
+	url := fmt.Sprintf("%s/files/%s/variables/local", figmaAPIBase, fileKey)
+
+	body, err := c.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	var varsResp VariablesResponse
+	if err := json.Unmarshal(body, &varsResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &varsResp, nil
+}
+
+// GetPublishedVariables retrieves the variables and variable collections published from a library file.
+// Calls GET /v1/files/:key/variables/published. The same plan and scope restrictions as
+// GetLocalVariables apply.
+func (c *Client) GetPublishedVariables(ctx context.Context, fileKey string) (*PublishedVariablesResponse, error) {
+	url := fmt.Sprintf("%s/files/%s/variables/published", figmaAPIBase, fileKey)
+
+	body, err := c.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	var varsResp PublishedVariablesResponse
+	if err := json.Unmarshal(body, &varsResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &varsResp, nil
+}
+
+// GetVersions retrieves the most recent versions of a Figma file's version history, newest
+// first: autosaves as well as versions saved with a label. Calls GET /v1/files/:key/versions.
+// Older versions are available through the URLs in the response's Pagination.
+func (c *Client) GetVersions(ctx context.Context, fileKey string) (*VersionsResponse, error) {
+	url := fmt.Sprintf("%s/files/%s/versions?page_size=50", figmaAPIBase, fileKey)
+
+	body, err := c.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	var versionsResp VersionsResponse
+	if err := json.Unmarshal(body, &versionsResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &versionsResp, nil
+}
+
+// GetComments retrieves every comment and reply of a Figma file, resolved or not.
+// Calls GET /v1/files/:key/comments; the token needs the file_comments:read scope.
+func (c *Client) GetComments(ctx context.Context, fileKey string) (*CommentsResponse, error) {
+	url := fmt.Sprintf("%s/files/%s/comments", figmaAPIBase, fileKey)
+
+	body, err := c.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	var commentsResp CommentsResponse
+	if err := json.Unmarshal(body, &commentsResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &commentsResp, nil
+}
+
+// CreateWebhook registers a webhook that posts events of req.EventType for the files of a team
+// to req.Endpoint. Calls POST /v2/webhooks; the token needs the webhooks:write scope.
+// Figma sends a PING event to the endpoint right after registration.
+func (c *Client) CreateWebhook(ctx context.Context, req WebhookRequest) (*Webhook, error) {
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	body, err := c.do(ctx, http.MethodPost, figmaAPIBaseV2+"/webhooks", payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var webhook Webhook
+	if err := json.Unmarshal(body, &webhook); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &webhook, nil
+}
+
+// DeleteWebhook deletes a webhook so that it stops sending events. Calls DELETE /v2/webhooks/:id.
+func (c *Client) DeleteWebhook(ctx context.Context, webhookID string) error {
+	_, err := c.do(ctx, http.MethodDelete, fmt.Sprintf("%s/webhooks/%s", figmaAPIBaseV2, webhookID), nil)
+	return err
+}
+
+// GetDevResources retrieves the dev resources (links such as Storybook stories or GitHub
+// sources) attached to nodes of a file, limited to the given nodes when nodeIDs is not empty.
+// Calls GET /v1/files/:key/dev_resources; requires the file_dev_resources:read scope.
+func (c *Client) GetDevResources(ctx context.Context, fileKey string, nodeIDs []string) (*DevResourcesResponse, error) {
+	apiURL := fmt.Sprintf("%s/files/%s/dev_resources", figmaAPIBase, fileKey)
+	if len(nodeIDs) > 0 {
+		apiURL = withQuery(apiURL, "node_ids", strings.Join(nodeIDs, ","))
+	}
+
+	body, err := c.get(ctx, apiURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var resourcesResp DevResourcesResponse
+	if err := json.Unmarshal(body, &resourcesResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &resourcesResp, nil
+}
+
+// CreateDevResources attaches links to nodes, e.g. to point components at their implementation.
+// Each resource needs a name, URL, file key and node ID. Calls POST /v1/dev_resources; requires
+// the file_dev_resources:write scope.
+func (c *Client) CreateDevResources(ctx context.Context, resources []DevResource) (*DevResourcesCreateResponse, error) {
+	payload, err := json.Marshal(map[string][]DevResource{"dev_resources": resources})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	body, err := c.do(ctx, http.MethodPost, figmaAPIBase+"/dev_resources", payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var createResp DevResourcesCreateResponse
+	if err := json.Unmarshal(body, &createResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &createResp, nil
+}
+
+// DeleteDevResource removes a dev resource from a file.
+// Calls DELETE /v1/files/:key/dev_resources/:id; requires the file_dev_resources:write scope.
+func (c *Client) DeleteDevResource(ctx context.Context, fileKey, resourceID string) error {
+	_, err := c.do(ctx, http.MethodDelete, fmt.Sprintf("%s/files/%s/dev_resources/%s", figmaAPIBase, fileKey, resourceID), nil)
+	return err
+}
+
+// get performs an authenticated GET request against the Figma API and returns the response body.
+// It retries up to 3 times on transport errors and 5xx (server error) responses, waiting 2s, 4s,
+// ... between attempts. 429 (rate limit) responses are waited out as long as their Retry-After
+// asks, up to 5 minutes, and pause the other requests of the client meanwhile. Waits are aborted
+// as soon as ctx is done.
+func (c *Client) get(ctx context.Context, url string) ([]byte, error) {
+	return c.do(ctx, http.MethodGet, url, nil)
+}
+
+// do performs an authenticated request against the Figma API with an optional JSON body and
+// returns the response body, retrying like send.
+func (c *Client) do(ctx context.Context, method, url string, payload []byte) ([]byte, error) {
+	resp, err := c.send(ctx, method, url, payload, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.body, nil
+}
+
+// ErrRateLimited matches the errors of requests that Figma kept rate limiting longer than the
+// client waits for.
+var ErrRateLimited = errors.New("rate limited by Figma")
+
+// APIError is the error of a request the Figma API answered with an error status, e.g. 403 for
+// a rejected token or 404 for a file that does not exist.
+type APIError struct {
+	StatusCode int
+	Body       string // usually a JSON {"status", "err"} object
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API request failed with status %d: %s", e.StatusCode, e.Body)
+}
+
+// Is reports 429 (rate limit) errors as ErrRateLimited.
+func (e *APIError) Is(target error) bool {
+	return target == ErrRateLimited && e.StatusCode == http.StatusTooManyRequests
+}
+
+// response is a successful (200 or 304) API response.
+type response struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+// send performs a request like do with additional request headers and returns the whole response.
+// Server errors and failed connections are retried for GET and DELETE requests only: a POST
+// failing after it took effect, e.g. the creation of a webhook, would take effect twice.
+func (c *Client) send(ctx context.Context, method, url string, payload []byte, header http.Header) (*response, error) {
 	var lastErr error
 	maxRetries := 3
+	if method != http.MethodGet && method != http.MethodDelete {
+		maxRetries = 1
+	}
+	refreshed := false
+	rateLimited := 0
 
 	for attempt := 1; attempt <= maxRetries; attempt++ {
-		req, err := http.NewRequest("GET", url, nil)
+		if err := c.limiter.wait(ctx); err != nil {
+			return nil, err
+		}
+
+		var reqBody io.Reader
+		if payload != nil {
+			reqBody = bytes.NewReader(payload)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create request: %w", err)
 		}
 
-		req.Header.Set("X-Figma-Token", c.accessToken)
+		for name, values := range header {
+			req.Header[name] = values
+		}
+		token := c.auth.authorize(req)
+		if payload != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		// Disable HTTP/2 to avoid stream errors with large files
 		req.Header.Set("Connection", "close")
 
 		resp, err := c.httpClient.Do(req)
 		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
 			lastErr = fmt.Errorf("attempt %d failed to execute request: %w", attempt, err)
 			if attempt < maxRetries {
-				time.Sleep(time.Duration(attempt) * 2 * time.Second)
+				if err := sleep(ctx, time.Duration(attempt)*2*time.Second); err != nil {
+					return nil, err
+				}
 				continue
 			}
 			return nil, lastErr
 		}
-		defer resp.Body.Close()
 
-		if resp.StatusCode != http.StatusOK {
+		if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotModified {
 			body, _ := io.ReadAll(resp.Body)
-			lastErr = fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
-			if attempt < maxRetries && (resp.StatusCode == 429 || resp.StatusCode >= 500) {
-				time.Sleep(time.Duration(attempt) * 2 * time.Second)
+			resp.Body.Close()
+			if c.capture != nil {
+				c.capture.record(token, req, resp, body)
+			}
+			lastErr = &APIError{StatusCode: resp.StatusCode, Body: string(body)}
+			// Expired OAuth tokens are rejected as unauthorized (Figma answers 403 for them).
+			if !refreshed && (resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden) {
+				refreshed = true
+				renewed, err := c.auth.renew(ctx, token)
+				if err != nil {
+					return nil, err
+				}
+				if renewed {
+					attempt-- // the retry with the new token does not count as a failed attempt
+					continue
+				}
+			}
+			// Wait out rate limits for as long as Figma asks, holding back the other requests too.
+			if resp.StatusCode == http.StatusTooManyRequests && rateLimited < maxRateLimitRetries {
+				wait, ok := retryAfter(resp.Header, time.Now())
+				if !ok {
+					wait = time.Duration(rateLimited+1) * 2 * time.Second
+				}
+				if wait > maxRetryAfter {
+					return nil, fmt.Errorf("%w (%s): retry after %s", ErrRateLimited, rateLimitType(resp.Header), wait)
+				}
+				rateLimited++
+				c.limiter.pause(wait)
+				attempt-- // waiting out a rate limit does not count as a failed attempt
+				continue
+			}
+			if attempt < maxRetries && resp.StatusCode >= 500 {
+				if err := sleep(ctx, time.Duration(attempt)*2*time.Second); err != nil {
+					return nil, err
+				}
 				continue
 			}
 			return nil, lastErr
 		}
 
 		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
 		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
 			lastErr = fmt.Errorf("attempt %d failed to read response body: %w", attempt, err)
 			if attempt < maxRetries {
-				time.Sleep(time.Duration(attempt) * 2 * time.Second)
+				if err := sleep(ctx, time.Duration(attempt)*2*time.Second); err != nil {
+					return nil, err
+				}
 				continue
 			}
 			return nil, lastErr
 		}
 
-		var imgResp FileImagesResponse
-		if err := json.Unmarshal(body, &imgResp); err != nil {
-			return nil, fmt.Errorf("failed to parse response: %w", err)
+		if c.capture != nil {
+			c.capture.record(token, req, resp, body)
 		}
-
-		if imgResp.Err != nil {
-			return nil, fmt.Errorf("Figma file images API error: %s", *imgResp.Err)
-		}
-
-		return &imgResp, nil
+		return &response{status: resp.StatusCode, header: resp.Header, body: body}, nil
 	}
 
 	return nil, lastErr
 }
 
-// GetFileStyles retrieves all published styles (colors, text, effects, grids) from a Figma file.
-// This includes style metadata such as names, descriptions, and type information.
-func (c *Client) GetFileStyles(fileKey string) (*StylesResponse, error) {
-	url := fmt.Sprintf("%s/files/%s/styles", figmaAPIBase, fileKey)
-
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("X-Figma-Token", c.accessToken)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+// sleep pauses for d or until ctx is done, whichever comes first.
+// It returns ctx.Err() if the wait was cut short.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
-	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
-
-	var stylesResp StylesResponse
-	if err := json.Unmarshal(body, &stylesResp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
-	}
-
-	return &stylesResp, nil
 }
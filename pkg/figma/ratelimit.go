@@ -0,0 +1,96 @@
+package figma
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// maxRateLimitRetries is how many 429 responses a single request waits out before failing.
+	maxRateLimitRetries = 5
+	// maxRetryAfter is the longest Retry-After a request waits for. Figma asks plans that ran out
+	// of their quota to come back much later; those requests fail instead of hanging.
+	maxRetryAfter = 5 * time.Minute
+)
+
+// rateLimiter queues the requests of a client, and of its copies, so that they start at most
+// once per interval and not before a pause requested by a 429 response is over.
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration // minimum time between request starts; 0 = unlimited
+	next     time.Time     // earliest start of the next request
+}
+
+// wait blocks until the next request may start, or until ctx is done.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	start := l.next
+	if start.Before(now) {
+		start = now
+	}
+	l.next = start.Add(l.interval)
+	l.mu.Unlock()
+
+	if d := start.Sub(now); d > 0 {
+		return sleep(ctx, d)
+	}
+	return ctx.Err()
+}
+
+// pause holds back every request for d.
+func (l *rateLimiter) pause(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if until := time.Now().Add(d); until.After(l.next) {
+		l.next = until
+	}
+}
+
+// WithRateLimit returns a copy of the client that sends at most requestsPerMinute requests per
+// minute, queuing the others, e.g. to stay below the quota of the plan during large batch
+// exports. A requestsPerMinute of 0 or less removes the limit. Pauses requested by Figma with
+// Retry-After are honored either way.
+func (c *Client) WithRateLimit(requestsPerMinute int) *Client {
+	limited := *c
+	limited.limiter = &rateLimiter{}
+	if requestsPerMinute > 0 {
+		limited.limiter.interval = time.Minute / time.Duration(requestsPerMinute)
+	}
+	return &limited
+}
+
+// retryAfter returns how long a 429 response asks to wait before the next request: the
+// Retry-After header in seconds or as an HTTP date.
+func retryAfter(header http.Header, now time.Time) (time.Duration, bool) {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(at.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// rateLimitType describes which Figma rate limit a 429 response hit, e.g. "low limit, starter plan".
+func rateLimitType(header http.Header) string {
+	limit, tier := header.Get("X-Figma-Rate-Limit-Type"), header.Get("X-Figma-Plan-Tier")
+	switch {
+	case limit != "" && tier != "":
+		return fmt.Sprintf("%s limit, %s plan", limit, tier)
+	case limit != "":
+		return limit + " limit"
+	case tier != "":
+		return tier + " plan"
+	}
+	return "rate limit"
+}
@@ -1,15 +1,19 @@
 package figma
 
+import "encoding/json"
+
 // FileResponse represents the complete response from the Figma file API endpoint.
-// It contains the file metadata, document structure, published styles, and schema version information.
+// It contains the file metadata, document structure, published styles, component metadata, and schema version information.
 type FileResponse struct {
-	Name          string           `json:"name"`
-	LastModified  string           `json:"lastModified"`
-	ThumbnailURL  string           `json:"thumbnailUrl"`
-	Version       string           `json:"version"`
-	Document      Node             `json:"document"`
-	Styles        map[string]Style `json:"styles"`
-	SchemaVersion int              `json:"schemaVersion"`
+	Name          string                  `json:"name"`
+	LastModified  string                  `json:"lastModified"`
+	ThumbnailURL  string                  `json:"thumbnailUrl"`
+	Version       string                  `json:"version"`
+	Document      Node                    `json:"document"`
+	Styles        map[string]Style        `json:"styles"`
+	Components    map[string]Component    `json:"components,omitempty"`    // node ID -> component metadata
+	ComponentSets map[string]ComponentSet `json:"componentSets,omitempty"` // node ID -> component set metadata
+	SchemaVersion int                     `json:"schemaVersion"`
 }
 
 // NodesResponse represents the response from the Figma nodes API endpoint when fetching specific nodes.
@@ -24,17 +28,29 @@ type NodesResponse struct {
 // NodeData wraps a node with its document structure and optional component/style information.
 // This is the structure returned for each requested node in a NodesResponse.
 type NodeData struct {
-	Document   Node                 `json:"document"`
-	Components map[string]Component `json:"components,omitempty"`
-	Styles     map[string]Style     `json:"styles,omitempty"`
+	Document      Node                    `json:"document"`
+	Components    map[string]Component    `json:"components,omitempty"`
+	ComponentSets map[string]ComponentSet `json:"componentSets,omitempty"`
+	Styles        map[string]Style        `json:"styles,omitempty"`
 }
 
 // Component represents a Figma component definition with its metadata.
 // Components are reusable design elements that can be instantiated throughout the file.
 type Component struct {
+	Key            string `json:"key"`
+	Name           string `json:"name"`
+	Description    string `json:"description"`
+	ComponentSetID string `json:"componentSetId,omitempty"` // set the component is a variant of, if any
+	Remote         bool   `json:"remote,omitempty"`         // true for components from a team library
+}
+
+// ComponentSet represents a Figma component set: a group of components that are variants
+// of one another (e.g. a Button with Size and State properties).
+type ComponentSet struct {
 	Key         string `json:"key"`
 	Name        string `json:"name"`
 	Description string `json:"description"`
+	Remote      bool   `json:"remote,omitempty"`
 }
 
 // StylesResponse represents the response from the Figma styles API endpoint.
@@ -68,6 +84,7 @@ type Style struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
 	StyleType   string `json:"style_type"`
+	Remote      bool   `json:"remote,omitempty"` // true for styles from a team library
 }
 
 // Node represents a single element in the Figma document tree hierarchy.
@@ -84,10 +101,15 @@ type Node struct {
 	StrokeWeight          float64           `json:"strokeWeight,omitempty"`
 	CornerRadius          float64           `json:"cornerRadius,omitempty"`
 	Effects               []Effect          `json:"effects,omitempty"`
+	BlendMode             string            `json:"blendMode,omitempty"` // PASS_THROUGH (groups and frames), NORMAL, MULTIPLY, ...
 	Characters            string            `json:"characters,omitempty"`
 	Style                 *TypeStyle        `json:"style,omitempty"`
 	AbsoluteBoundingBox   *Rectangle        `json:"absoluteBoundingBox,omitempty"`
 	Constraints           *LayoutConstraint `json:"constraints,omitempty"`
+	MinWidth              float64           `json:"minWidth,omitempty"`
+	MaxWidth              float64           `json:"maxWidth,omitempty"`
+	MinHeight             float64           `json:"minHeight,omitempty"`
+	MaxHeight             float64           `json:"maxHeight,omitempty"`
 	LayoutMode            string            `json:"layoutMode,omitempty"`
 	PrimaryAxisSizingMode string            `json:"primaryAxisSizingMode,omitempty"`
 	CounterAxisSizingMode string            `json:"counterAxisSizingMode,omitempty"`
@@ -97,6 +119,106 @@ type Node struct {
 	PaddingBottom         float64           `json:"paddingBottom,omitempty"`
 	ItemSpacing           float64           `json:"itemSpacing,omitempty"`
 	ExportSettings        []ExportSetting   `json:"exportSettings,omitempty"`
+	LayoutGrids           []LayoutGrid      `json:"layoutGrids,omitempty"`
+	Styles                map[string]string `json:"styles,omitempty"` // style type (fill, stroke, text, effect, grid) -> style ID
+
+	// Stroke details. IndividualStrokeWeights is set when the sides have different weights.
+	StrokeDashes            []float64      `json:"strokeDashes,omitempty"` // dash and gap lengths, empty for solid strokes
+	StrokeAlign             string         `json:"strokeAlign,omitempty"`  // INSIDE, OUTSIDE, CENTER
+	StrokeCap               string         `json:"strokeCap,omitempty"`    // NONE, ROUND, SQUARE, LINE_ARROW, TRIANGLE_ARROW
+	StrokeJoin              string         `json:"strokeJoin,omitempty"`   // MITER, BEVEL, ROUND
+	IndividualStrokeWeights *StrokeWeights `json:"individualStrokeWeights,omitempty"`
+
+	// Auto-layout alignment and wrapping of frames with a LayoutMode.
+	PrimaryAxisAlignItems   string  `json:"primaryAxisAlignItems,omitempty"`   // MIN, CENTER, MAX, SPACE_BETWEEN
+	CounterAxisAlignItems   string  `json:"counterAxisAlignItems,omitempty"`   // MIN, CENTER, MAX, BASELINE
+	LayoutWrap              string  `json:"layoutWrap,omitempty"`              // NO_WRAP, WRAP
+	CounterAxisSpacing      float64 `json:"counterAxisSpacing,omitempty"`      // gap between wrapped rows
+	CounterAxisAlignContent string  `json:"counterAxisAlignContent,omitempty"` // AUTO, SPACE_BETWEEN
+
+	// Properties of a child of an auto-layout frame.
+	LayoutAlign            string  `json:"layoutAlign,omitempty"`            // INHERIT, STRETCH
+	LayoutGrow             float64 `json:"layoutGrow,omitempty"`             // 1 = fills the primary axis
+	LayoutPositioning      string  `json:"layoutPositioning,omitempty"`      // AUTO, ABSOLUTE
+	LayoutSizingHorizontal string  `json:"layoutSizingHorizontal,omitempty"` // FIXED, HUG, FILL
+	LayoutSizingVertical   string  `json:"layoutSizingVertical,omitempty"`   // FIXED, HUG, FILL
+
+	// Prototyping. TransitionNodeID, TransitionDuration and TransitionEasing are the legacy
+	// single on-click transition, superseded by Interactions.
+	Interactions       []Interaction       `json:"interactions,omitempty"`
+	TransitionNodeID   string              `json:"transitionNodeID,omitempty"`
+	TransitionDuration float64             `json:"transitionDuration,omitempty"` // in milliseconds
+	TransitionEasing   string              `json:"transitionEasing,omitempty"`
+	FlowStartingPoints []FlowStartingPoint `json:"flowStartingPoints,omitempty"` // set on CANVAS nodes
+
+	// ComponentPropertyDefinitions is set on COMPONENT_SET nodes and standalone COMPONENT nodes.
+	ComponentPropertyDefinitions map[string]ComponentPropertyDefinition `json:"componentPropertyDefinitions,omitempty"`
+
+	// Data stored on the node by plugins, only returned for the plugins requested with the
+	// plugin_data parameter: plugin ID -> key -> value, and namespace -> key -> value for
+	// shared plugin data (e.g. Tokens Studio's "tokens" namespace).
+	PluginData       map[string]map[string]string `json:"pluginData,omitempty"`
+	SharedPluginData map[string]map[string]string `json:"sharedPluginData,omitempty"`
+
+	// Vector outlines in the node's own coordinates, only returned when requesting geometry=paths.
+	// StrokeGeometry outlines the stroke itself, so it is filled with the stroke paint.
+	FillGeometry   []Path `json:"fillGeometry,omitempty"`
+	StrokeGeometry []Path `json:"strokeGeometry,omitempty"`
+}
+
+// Path is an SVG path outline of a vector node.
+type Path struct {
+	Path        string `json:"path"`                 // SVG path data, e.g. "M0 0L24 0L24 24Z"
+	WindingRule string `json:"windingRule"`          // NONZERO or EVENODD
+	OverrideID  int    `json:"overrideID,omitempty"` // fill override of the region, for multi-fill vectors
+}
+
+// ComponentPropertyDefinition describes a property exposed by a component or component set.
+// Property names of non-variant properties carry a unique suffix, e.g. "Label#12:3".
+type ComponentPropertyDefinition struct {
+	Type           string   `json:"type"`         // BOOLEAN, TEXT, INSTANCE_SWAP, VARIANT
+	DefaultValue   any      `json:"defaultValue"` // bool for BOOLEAN, string otherwise
+	VariantOptions []string `json:"variantOptions,omitempty"`
+}
+
+// Interaction is a prototype reaction: a trigger and the actions it performs.
+type Interaction struct {
+	Trigger *Trigger `json:"trigger"`
+	Actions []Action `json:"actions"`
+}
+
+// Trigger is the user event starting an interaction.
+type Trigger struct {
+	Type  string  `json:"type"`            // ON_CLICK, ON_HOVER, ON_PRESS, ON_DRAG, AFTER_TIMEOUT, MOUSE_ENTER, ON_KEY_DOWN, ...
+	Delay float64 `json:"delay,omitempty"` // in milliseconds, for AFTER_TIMEOUT and mouse triggers
+}
+
+// Action is what an interaction does, e.g. navigating to another frame or opening a URL.
+type Action struct {
+	Type          string      `json:"type"`                    // BACK, CLOSE, URL, NODE, ...
+	DestinationID string      `json:"destinationId,omitempty"` // NODE actions
+	Navigation    string      `json:"navigation,omitempty"`    // NAVIGATE, SWAP, OVERLAY, SCROLL_TO, CHANGE_TO
+	Transition    *Transition `json:"transition,omitempty"`
+	URL           string      `json:"url,omitempty"` // URL actions
+}
+
+// Transition is the animation played by a NODE action.
+type Transition struct {
+	Type      string  `json:"type"` // DISSOLVE, SMART_ANIMATE, MOVE_IN, PUSH, SLIDE_IN, ...
+	Easing    *Easing `json:"easing,omitempty"`
+	Duration  float64 `json:"duration"` // in milliseconds
+	Direction string  `json:"direction,omitempty"`
+}
+
+// Easing is the timing curve of a transition.
+type Easing struct {
+	Type string `json:"type"` // EASE_IN, EASE_OUT, EASE_IN_AND_OUT, LINEAR, GENTLE, ...
+}
+
+// FlowStartingPoint marks the first frame of a prototype flow.
+type FlowStartingPoint struct {
+	NodeID string `json:"nodeId"`
+	Name   string `json:"name"`
 }
 
 // Color represents an RGBA color with float values ranging from 0 to 1.
@@ -118,6 +240,7 @@ type Paint struct {
 	Color     *Color  `json:"color,omitempty"`
 	ImageRef  string  `json:"imageRef,omitempty"`
 	ScaleMode string  `json:"scaleMode,omitempty"`
+	BlendMode string  `json:"blendMode,omitempty"`
 }
 
 // Effect represents a visual effect applied to a Figma node such as drop shadows, inner shadows, or blur effects.
@@ -145,12 +268,15 @@ type TypeStyle struct {
 	FontFamily          string  `json:"fontFamily"`
 	FontPostScriptName  string  `json:"fontPostScriptName"`
 	FontWeight          float64 `json:"fontWeight"`
+	Italic              bool    `json:"italic,omitempty"`
 	FontSize            float64 `json:"fontSize"`
 	LineHeightPx        float64 `json:"lineHeightPx"`
 	LineHeightPercent   float64 `json:"lineHeightPercent"`
 	LetterSpacing       float64 `json:"letterSpacing"`
 	TextAlignHorizontal string  `json:"textAlignHorizontal"`
 	TextAlignVertical   string  `json:"textAlignVertical"`
+	TextCase            string  `json:"textCase,omitempty"`       // ORIGINAL, UPPER, LOWER, TITLE, SMALL_CAPS, SMALL_CAPS_FORCED
+	TextDecoration      string  `json:"textDecoration,omitempty"` // NONE, STRIKETHROUGH, UNDERLINE
 }
 
 // Rectangle represents a bounding box with position (X, Y) and dimensions (Width, Height).
@@ -162,11 +288,31 @@ type Rectangle struct {
 	Height float64 `json:"height"`
 }
 
+// LayoutGrid is a column, row or square grid of a frame, drawn over it in the editor.
+type LayoutGrid struct {
+	Pattern     string  `json:"pattern"`     // COLUMNS, ROWS, GRID
+	SectionSize float64 `json:"sectionSize"` // width of the columns, height of the rows or size of the squares
+	Visible     bool    `json:"visible"`
+	Color       *Color  `json:"color,omitempty"`
+	Alignment   string  `json:"alignment,omitempty"`  // MIN, MAX, STRETCH, CENTER; columns and rows only
+	GutterSize  float64 `json:"gutterSize,omitempty"` // space between the columns or rows
+	Offset      float64 `json:"offset,omitempty"`     // space before the first column or row, the margin of STRETCH grids
+	Count       int     `json:"count,omitempty"`      // number of columns or rows
+}
+
+// StrokeWeights holds the stroke weight of each side of a rectangular node.
+type StrokeWeights struct {
+	Top    float64 `json:"top"`
+	Right  float64 `json:"right"`
+	Bottom float64 `json:"bottom"`
+	Left   float64 `json:"left"`
+}
+
 // LayoutConstraint defines how a node's position and size behave when its parent is resized.
 // Constraints can be set for both vertical (TOP, BOTTOM, CENTER, etc.) and horizontal directions.
 type LayoutConstraint struct {
-	Vertical   string `json:"vertical"`
-	Horizontal string `json:"horizontal"`
+	Vertical   string `json:"vertical"`   // TOP, BOTTOM, CENTER, TOP_BOTTOM, SCALE
+	Horizontal string `json:"horizontal"` // LEFT, RIGHT, CENTER, LEFT_RIGHT, SCALE
 }
 
 // ExportSetting represents an export configuration defined by the designer in Figma.
@@ -187,9 +333,323 @@ type ImageResponse struct {
 	Images map[string]string `json:"images"`
 }
 
+// RenderOptions are the rendering parameters of the Images API. The zero value renders like Figma
+// does by default.
+type RenderOptions struct {
+	// SVGIncludeID adds the layer names as id attributes to SVG elements (svg_include_id).
+	SVGIncludeID bool
+	// SVGSimplifyStroke simplifies inside and outside strokes to plain strokes where possible
+	// (svg_simplify_stroke); nil = Figma's default, true.
+	SVGSimplifyStroke *bool
+	// UseAbsoluteBounds renders the full dimensions of the node, including cropped content,
+	// instead of its visible bounds (use_absolute_bounds).
+	UseAbsoluteBounds bool
+	// ContentsOnly renders the node alone, without the content overlapping it (contents_only);
+	// nil = Figma's default, true.
+	ContentsOnly *bool
+}
+
 // FileImagesResponse represents the response from the Figma file images API endpoint (GET /v1/files/:key/images).
 // It contains a map of image references to their download URLs for all embedded images in the file.
 type FileImagesResponse struct {
 	Err    *string           `json:"err"`
 	Images map[string]string `json:"images"` // imageRef -> download URL
 }
+
+// VariablesResponse represents the response from the Figma local variables API endpoint
+// (GET /v1/files/:key/variables/local). It contains every local variable and variable collection
+// in the file, plus remote ones that are consumed by the file, keyed by their IDs.
+type VariablesResponse struct {
+	Status int           `json:"status"`
+	Error  bool          `json:"error"`
+	Meta   VariablesMeta `json:"meta"`
+}
+
+// VariablesMeta holds the variables and variable collections of a VariablesResponse.
+type VariablesMeta struct {
+	Variables           map[string]Variable           `json:"variables"`
+	VariableCollections map[string]VariableCollection `json:"variableCollections"`
+}
+
+// Variable represents a single Figma variable with a value for each mode of its collection.
+// ResolvedType is one of BOOLEAN, FLOAT, STRING or COLOR. Scopes restrict where the variable can be
+// applied in the Figma UI (e.g. GAP, CORNER_RADIUS, FONT_SIZE) and hint at its intended unit.
+type Variable struct {
+	ID                   string                   `json:"id"`
+	Name                 string                   `json:"name"`
+	Key                  string                   `json:"key"`
+	VariableCollectionID string                   `json:"variableCollectionId"`
+	ResolvedType         string                   `json:"resolvedType"`
+	ValuesByMode         map[string]VariableValue `json:"valuesByMode"`
+	Remote               bool                     `json:"remote"`
+	Description          string                   `json:"description"`
+	HiddenFromPublishing bool                     `json:"hiddenFromPublishing"`
+	Scopes               []string                 `json:"scopes"`
+}
+
+// VariableCollection groups related variables and defines the modes (e.g. Light and Dark) they have values for.
+type VariableCollection struct {
+	ID                   string         `json:"id"`
+	Name                 string         `json:"name"`
+	Key                  string         `json:"key"`
+	Modes                []VariableMode `json:"modes"`
+	DefaultModeID        string         `json:"defaultModeId"`
+	Remote               bool           `json:"remote"`
+	HiddenFromPublishing bool           `json:"hiddenFromPublishing"`
+	VariableIDs          []string       `json:"variableIds"`
+}
+
+// VariableMode is a single mode of a variable collection.
+type VariableMode struct {
+	ModeID string `json:"modeId"`
+	Name   string `json:"name"`
+}
+
+// VariableAlias references another variable by ID instead of holding a raw value.
+type VariableAlias struct {
+	Type string `json:"type"` // always "VARIABLE_ALIAS"
+	ID   string `json:"id"`
+}
+
+// VariableValue holds the value of a variable for one mode. Exactly one field is set,
+// depending on the variable's ResolvedType, or Alias when the value points at another variable.
+type VariableValue struct {
+	Bool   *bool
+	Float  *float64
+	String *string
+	Color  *Color
+	Alias  *VariableAlias
+}
+
+// UnmarshalJSON decodes the polymorphic valuesByMode entries returned by the Figma API:
+// a boolean, a number, a string, an RGBA color object or a VARIABLE_ALIAS object.
+func (v *VariableValue) UnmarshalJSON(data []byte) error {
+	*v = VariableValue{}
+
+	var raw any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	switch val := raw.(type) {
+	case bool:
+		v.Bool = &val
+	case float64:
+		v.Float = &val
+	case string:
+		v.String = &val
+	case map[string]any:
+		if val["type"] == "VARIABLE_ALIAS" {
+			var alias VariableAlias
+			if err := json.Unmarshal(data, &alias); err != nil {
+				return err
+			}
+			v.Alias = &alias
+			return nil
+		}
+
+		var color Color
+		if err := json.Unmarshal(data, &color); err != nil {
+			return err
+		}
+		v.Color = &color
+	}
+
+	return nil
+}
+
+// PublishedVariablesResponse represents the response from the Figma published variables API endpoint
+// (GET /v1/files/:key/variables/published). Published entries carry the subscribed IDs that
+// consuming files use to reference them, but no values.
+type PublishedVariablesResponse struct {
+	Status int                    `json:"status"`
+	Error  bool                   `json:"error"`
+	Meta   PublishedVariablesMeta `json:"meta"`
+}
+
+// PublishedVariablesMeta holds the published variables and variable collections of a file.
+type PublishedVariablesMeta struct {
+	Variables           map[string]PublishedVariable           `json:"variables"`
+	VariableCollections map[string]PublishedVariableCollection `json:"variableCollections"`
+}
+
+// PublishedVariable describes a variable published from a library file.
+type PublishedVariable struct {
+	ID                   string `json:"id"`
+	SubscribedID         string `json:"subscribed_id"`
+	Name                 string `json:"name"`
+	Key                  string `json:"key"`
+	VariableCollectionID string `json:"variableCollectionId"`
+	ResolvedDataType     string `json:"resolvedDataType"`
+	UpdatedAt            string `json:"updatedAt"`
+}
+
+// PublishedVariableCollection describes a variable collection published from a library file.
+type PublishedVariableCollection struct {
+	ID           string `json:"id"`
+	SubscribedID string `json:"subscribed_id"`
+	Name         string `json:"name"`
+	Key          string `json:"key"`
+	UpdatedAt    string `json:"updatedAt"`
+}
+
+// User is a Figma user, as referenced by webhooks and comments.
+type User struct {
+	ID     string `json:"id"`
+	Handle string `json:"handle"`
+	ImgURL string `json:"img_url"`
+}
+
+// WebhookRequest is the body of a webhook registration (POST /v2/webhooks).
+type WebhookRequest struct {
+	EventType   string `json:"event_type"` // FILE_UPDATE, FILE_VERSION_UPDATE, FILE_DELETE, LIBRARY_PUBLISH, FILE_COMMENT, PING
+	TeamID      string `json:"team_id"`
+	Endpoint    string `json:"endpoint"`              // URL receiving the events
+	Passcode    string `json:"passcode"`              // echoed in every event to prove it comes from Figma
+	Status      string `json:"status,omitempty"`      // ACTIVE (default) or PAUSED
+	Description string `json:"description,omitempty"` // up to 150 characters
+}
+
+// Webhook is a registered Figma webhook.
+type Webhook struct {
+	ID          string `json:"id"`
+	EventType   string `json:"event_type"`
+	TeamID      string `json:"team_id"`
+	Endpoint    string `json:"endpoint"`
+	Passcode    string `json:"passcode"`
+	Status      string `json:"status"`
+	Description string `json:"description"`
+}
+
+// WebhookEvent is the payload Figma posts to a webhook endpoint. Events of every type carry the
+// passcode, timestamp and webhook ID; file events also identify the file.
+type WebhookEvent struct {
+	EventType   string `json:"event_type"`
+	Passcode    string `json:"passcode"`
+	Timestamp   string `json:"timestamp"`
+	WebhookID   string `json:"webhook_id"`
+	FileKey     string `json:"file_key,omitempty"`
+	FileName    string `json:"file_name,omitempty"`
+	TriggeredBy *User  `json:"triggered_by,omitempty"`
+}
+
+// CommentsResponse represents the response from the Figma comments API endpoint
+// (GET /v1/files/:key/comments).
+type CommentsResponse struct {
+	Comments []Comment `json:"comments"`
+}
+
+// Comment is a comment or reply left on a Figma file.
+type Comment struct {
+	ID         string      `json:"id"`
+	FileKey    string      `json:"file_key"`
+	ParentID   string      `json:"parent_id,omitempty"` // set on replies, the ID of the comment replied to
+	User       User        `json:"user"`
+	CreatedAt  string      `json:"created_at"`
+	ResolvedAt string      `json:"resolved_at,omitempty"` // empty while the thread is open
+	Message    string      `json:"message"`
+	OrderID    string      `json:"order_id,omitempty"` // the number shown on the canvas, top-level comments only
+	ClientMeta *ClientMeta `json:"client_meta,omitempty"`
+}
+
+// ClientMeta is where a comment is placed: anchored to a node (NodeID, with NodeOffset
+// relative to it) or at absolute canvas coordinates (X, Y).
+type ClientMeta struct {
+	NodeID     string  `json:"node_id,omitempty"`
+	NodeOffset *Vector `json:"node_offset,omitempty"`
+	X          float64 `json:"x,omitempty"`
+	Y          float64 `json:"y,omitempty"`
+}
+
+// VersionsResponse represents the response from the Figma versions API endpoint
+// (GET /v1/files/:key/versions).
+type VersionsResponse struct {
+	Versions   []FileVersion `json:"versions"`
+	Pagination Pagination    `json:"pagination"`
+}
+
+// FileVersion is an entry of a file's version history.
+type FileVersion struct {
+	ID          string `json:"id"`
+	CreatedAt   string `json:"created_at"`
+	Label       string `json:"label"` // empty for autosaves
+	Description string `json:"description"`
+	User        User   `json:"user"`
+}
+
+// Pagination holds the URLs of the neighbouring pages of a paginated response.
+type Pagination struct {
+	PrevPage string `json:"prev_page,omitempty"`
+	NextPage string `json:"next_page,omitempty"`
+}
+
+// StyleResponse represents the response from the Figma style API endpoint (GET /v1/styles/:key).
+type StyleResponse struct {
+	Meta StyleMetadata `json:"meta"`
+}
+
+// TeamStylesResponse represents a page of the Figma team styles API endpoint
+// (GET /v1/teams/:team_id/styles): the styles published to the team library.
+type TeamStylesResponse struct {
+	Meta TeamStylesMeta `json:"meta"`
+}
+
+// TeamStylesMeta holds a page of published styles and the cursor to the next page.
+type TeamStylesMeta struct {
+	Styles []StyleMetadata `json:"styles"`
+	Cursor *Cursor         `json:"cursor,omitempty"`
+}
+
+// Cursor positions a page of a cursor-paginated response. After is 0 on the last page.
+type Cursor struct {
+	Before int `json:"before,omitempty"`
+	After  int `json:"after,omitempty"`
+}
+
+// ComponentUsagesResponse represents a page of the Figma Library Analytics component usages
+// endpoint (GET /v1/analytics/libraries/:file_key/component/usages).
+type ComponentUsagesResponse struct {
+	Rows     []ComponentUsage `json:"rows"`
+	NextPage bool             `json:"next_page"`
+	Cursor   string           `json:"cursor,omitempty"`
+}
+
+// ComponentUsage reports how often a published library component is used across the organization.
+type ComponentUsage struct {
+	ComponentKey     string `json:"component_key"`
+	ComponentName    string `json:"component_name"`
+	ComponentSetKey  string `json:"component_set_key,omitempty"`
+	ComponentSetName string `json:"component_set_name,omitempty"`
+	Usages           int    `json:"usages"`      // number of instances
+	TeamsUsing       int    `json:"teams_using"` // number of teams using the component
+	FilesUsing       int    `json:"files_using"` // number of files using the component
+}
+
+// DevResourcesResponse represents the response from the Figma dev resources API endpoint
+// (GET /v1/files/:file_key/dev_resources).
+type DevResourcesResponse struct {
+	DevResources []DevResource `json:"dev_resources"`
+}
+
+// DevResource is a link attached to a node in Dev Mode, e.g. a Storybook story or a GitHub source file.
+type DevResource struct {
+	ID      string `json:"id,omitempty"`
+	Name    string `json:"name"`
+	URL     string `json:"url"`
+	FileKey string `json:"file_key"`
+	NodeID  string `json:"node_id"`
+}
+
+// DevResourcesCreateResponse represents the response of creating dev resources
+// (POST /v1/dev_resources): the created links and the ones that failed.
+type DevResourcesCreateResponse struct {
+	LinksCreated []DevResource      `json:"links_created"`
+	Errors       []DevResourceError `json:"errors,omitempty"`
+}
+
+// DevResourceError describes a dev resource that could not be created.
+type DevResourceError struct {
+	FileKey string `json:"file_key,omitempty"`
+	NodeID  string `json:"node_id,omitempty"`
+	Error   string `json:"error"`
+}
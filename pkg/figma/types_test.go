@@ -0,0 +1,37 @@
+package figma
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestVariableValueUnmarshalJSON(t *testing.T) {
+	data := []byte(`{
+		"bool":  true,
+		"float": 16,
+		"str":   "Inter",
+		"color": {"r": 1, "g": 0.5, "b": 0, "a": 1},
+		"alias": {"type": "VARIABLE_ALIAS", "id": "VariableID:1:2"}
+	}`)
+
+	var values map[string]VariableValue
+	if err := json.Unmarshal(data, &values); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	if v := values["bool"]; v.Bool == nil || !*v.Bool {
+		t.Errorf("bool value = %+v, want Bool=true", v)
+	}
+	if v := values["float"]; v.Float == nil || *v.Float != 16 {
+		t.Errorf("float value = %+v, want Float=16", v)
+	}
+	if v := values["str"]; v.String == nil || *v.String != "Inter" {
+		t.Errorf("string value = %+v, want String=Inter", v)
+	}
+	if v := values["color"]; v.Color == nil || v.Color.R != 1 || v.Color.G != 0.5 || v.Alias != nil {
+		t.Errorf("color value = %+v, want Color={1 0.5 0 1}", v)
+	}
+	if v := values["alias"]; v.Alias == nil || v.Alias.ID != "VariableID:1:2" || v.Color != nil {
+		t.Errorf("alias value = %+v, want Alias.ID=VariableID:1:2", v)
+	}
+}
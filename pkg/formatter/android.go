@@ -0,0 +1,239 @@
+package formatter
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/hellenic-development/figma-extractor/pkg/extractor"
+)
+
+// ToAndroid renders design specifications as Android XML resources, laid out like a res/
+// directory: values/colors.xml, values/dimens.xml (text sizes in sp, everything else in dp)
+// and values/styles.xml with a TextAppearance per font size, font weight and composite text
+// style, whose letter spacing is in ems of its font size as Android expects. Figma variables
+// are added to colors.xml and dimens.xml using their default mode; a mode named "dark" or
+// "night" is written to values-night/ so Android applies it in dark theme automatically.
+//
+// Text appearances reference the font family as @font/<family>; add a matching font resource.
+func ToAndroid(specs *extractor.DesignSpecs, fileName string) []File {
+	var colors, nightColors, dimens, nightDimens []androidResource
+
+	// Colors
+	for _, group := range colorGroups(specs.Colors) {
+		for _, name := range sortedKeys(group.Colors) {
+			colors = append(colors, androidResource{"color", androidName("color_" + group.Prefix + name), group.Colors[name]})
+		}
+	}
+
+	// Dimensions
+	for _, name := range sortedKeys(specs.Typography.FontSizes) {
+		dimens = append(dimens, androidResource{"dimen", androidName("text_" + name), fmt.Sprintf("%gsp", specs.Typography.FontSizes[name])})
+	}
+	for _, name := range sortedKeys(specs.Typography.LineHeights) {
+		dimens = append(dimens, androidResource{"dimen", androidName("leading_" + name), fmt.Sprintf("%gsp", specs.Typography.LineHeights[name])})
+	}
+	for _, name := range sortedKeys(specs.Spacing.Values) {
+		dimens = append(dimens, androidResource{"dimen", androidName("space_" + name), fmt.Sprintf("%gdp", specs.Spacing.Values[name])})
+	}
+	for _, name := range sortedKeys(specs.Radii.Values) {
+		dimens = append(dimens, androidResource{"dimen", androidName("radius_" + name), fmt.Sprintf("%gdp", specs.Radii.Values[name])})
+	}
+
+	// Variables
+	for _, coll := range specs.Variables {
+		for _, mode := range coll.Modes {
+			night := isNightMode(mode.Name)
+			if !mode.Default && !night {
+				continue
+			}
+			for _, v := range mode.Variables {
+				res, ok := androidVariable(v)
+				if !ok {
+					continue
+				}
+				switch {
+				case night && res.kind == "color":
+					nightColors = append(nightColors, res)
+				case night:
+					nightDimens = append(nightDimens, res)
+				case res.kind == "color":
+					colors = append(colors, res)
+				default:
+					dimens = append(dimens, res)
+				}
+			}
+		}
+	}
+
+	header := fmt.Sprintf("Design tokens extracted from Figma: %s. Generated by figma-extractor. Do not edit by hand.", fileName)
+
+	var files []File
+	if len(colors) > 0 {
+		files = append(files, File{Name: "values/colors.xml", Content: androidResources(header, colors)})
+	}
+	if len(nightColors) > 0 {
+		files = append(files, File{Name: "values-night/colors.xml", Content: androidResources(header, nightColors)})
+	}
+	if len(dimens) > 0 {
+		files = append(files, File{Name: "values/dimens.xml", Content: androidResources(header, dimens)})
+	}
+	if len(nightDimens) > 0 {
+		files = append(files, File{Name: "values-night/dimens.xml", Content: androidResources(header, nightDimens)})
+	}
+	if styles := androidTextAppearances(specs); len(styles) > 0 {
+		files = append(files, File{Name: "values/styles.xml", Content: androidStyles(header, styles)})
+	}
+
+	return files
+}
+
+// renderAndroid adapts ToAndroid to the renderFunc signature.
+func renderAndroid(in Input) ([]File, error) {
+	return ToAndroid(in.Specs, in.FileName), nil
+}
+
+// androidResource is a single <color> or <dimen> value resource.
+type androidResource struct {
+	kind  string // "color" or "dimen"
+	name  string
+	value string
+}
+
+// androidStyle is a <style> resource with its items.
+type androidStyle struct {
+	name  string
+	items [][2]string // attribute, value
+}
+
+// androidTextAppearances builds one TextAppearance style per font size, per named font weight
+// and per composite text style.
+func androidTextAppearances(specs *extractor.DesignSpecs) []androidStyle {
+	var styles []androidStyle
+
+	family := ""
+	if specs.Typography.FontFamily != "" {
+		family = "@font/" + androidName(specs.Typography.FontFamily)
+	}
+
+	for _, name := range sortedKeys(specs.Typography.FontSizes) {
+		style := androidStyle{name: "TextAppearance.DesignTokens." + toPascalCase(name)}
+		if family != "" {
+			style.items = append(style.items, [2]string{"android:fontFamily", family})
+		}
+		style.items = append(style.items, [2]string{"android:textSize", "@dimen/" + androidName("text_"+name)})
+		styles = append(styles, style)
+	}
+
+	for _, name := range sortedKeys(specs.Typography.FontWeights) {
+		style := androidStyle{name: "TextAppearance.DesignTokens.Weight." + toPascalCase(name)}
+		if family != "" {
+			style.items = append(style.items, [2]string{"android:fontFamily", family})
+		}
+		style.items = append(style.items, [2]string{"android:textFontWeight", fmt.Sprintf("%g", specs.Typography.FontWeights[name])})
+		styles = append(styles, style)
+	}
+
+	for _, name := range sortedKeys(specs.TextStyles) {
+		ts := specs.TextStyles[name]
+		style := androidStyle{name: "TextAppearance.DesignTokens.Style." + toPascalCase(name)}
+		if ts.FontFamily != "" {
+			style.items = append(style.items, [2]string{"android:fontFamily", "@font/" + androidName(ts.FontFamily)})
+		}
+		if ts.FontSize > 0 {
+			style.items = append(style.items, [2]string{"android:textSize", fmt.Sprintf("%gsp", ts.FontSize)})
+		}
+		if ts.FontWeight > 0 {
+			style.items = append(style.items, [2]string{"android:textFontWeight", fmt.Sprintf("%g", ts.FontWeight)})
+		}
+		if ts.LineHeight > 0 {
+			style.items = append(style.items, [2]string{"android:lineHeight", fmt.Sprintf("%gsp", ts.LineHeight)})
+		}
+		if ts.LetterSpacing != 0 && ts.FontSize > 0 {
+			style.items = append(style.items, [2]string{"android:letterSpacing", fmt.Sprintf("%g", emSpacing(ts.LetterSpacing, ts.FontSize))})
+		}
+		if ts.TextCase == "UPPER" {
+			style.items = append(style.items, [2]string{"android:textAllCaps", "true"})
+		}
+		styles = append(styles, style)
+	}
+
+	return styles
+}
+
+// androidVariable converts a resolved variable into a color or dimension resource, in sp for
+// font metrics and dp otherwise. Only colors and dimension-scoped numbers have an Android
+// resource equivalent.
+func androidVariable(v extractor.Variable) (androidResource, bool) {
+	name := androidName(v.Name)
+	switch {
+	case v.Type == "COLOR":
+		return androidResource{"color", name, v.Color}, true
+	case v.Type == "FLOAT" && v.TextMetric:
+		return androidResource{"dimen", name, fmt.Sprintf("%gsp", v.Number)}, true
+	case v.Type == "FLOAT" && v.Dimension:
+		return androidResource{"dimen", name, fmt.Sprintf("%gdp", v.Number)}, true
+	}
+	return androidResource{}, false
+}
+
+// isNightMode reports whether a variable mode represents a dark theme.
+func isNightMode(name string) bool {
+	name = strings.ToLower(name)
+	return strings.Contains(name, "dark") || strings.Contains(name, "night")
+}
+
+// androidName converts a Figma name into a valid Android resource name (lowercase snake_case
+// starting with a letter).
+func androidName(s string) string {
+	name := strings.ReplaceAll(toKebabCase(s), "-", "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "res_" + name
+	}
+	return name
+}
+
+// androidResources renders a <resources> document of value resources.
+func androidResources(header string, resources []androidResource) []byte {
+	var buf bytes.Buffer
+	writeAndroidHeader(&buf, header)
+	for _, res := range resources {
+		buf.WriteString(fmt.Sprintf("    <%s name=\"%s\">%s</%s>\n", res.kind, res.name, xmlEscape(res.value), res.kind))
+	}
+	buf.WriteString("</resources>\n")
+	return buf.Bytes()
+}
+
+// androidStyles renders a <resources> document of style resources.
+func androidStyles(header string, styles []androidStyle) []byte {
+	var buf bytes.Buffer
+	writeAndroidHeader(&buf, header)
+	for i, style := range styles {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		buf.WriteString(fmt.Sprintf("    <style name=\"%s\">\n", style.name))
+		for _, item := range style.items {
+			buf.WriteString(fmt.Sprintf("        <item name=\"%s\">%s</item>\n", item[0], xmlEscape(item[1])))
+		}
+		buf.WriteString("    </style>\n")
+	}
+	buf.WriteString("</resources>\n")
+	return buf.Bytes()
+}
+
+// writeAndroidHeader writes the XML declaration, a comment and the opening <resources> tag.
+func writeAndroidHeader(buf *bytes.Buffer, header string) {
+	buf.WriteString("<?xml version=\"1.0\" encoding=\"utf-8\"?>\n")
+	// "--" is not allowed inside XML comments.
+	buf.WriteString(fmt.Sprintf("<!-- %s -->\n", strings.ReplaceAll(header, "--", "- -")))
+	buf.WriteString("<resources>\n")
+}
+
+// xmlEscape escapes text for use in XML character data.
+func xmlEscape(s string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
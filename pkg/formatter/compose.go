@@ -0,0 +1,247 @@
+package formatter
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/hellenic-development/figma-extractor/pkg/extractor"
+)
+
+// materialShapeSizes are the Material 3 Shapes slots with their default corner radii in dp.
+var materialShapeSizes = []struct {
+	name   string
+	radius float64
+}{
+	{"extraSmall", 4}, {"small", 8}, {"medium", 12}, {"large", 16}, {"extraLarge", 28},
+}
+
+// ToCompose renders design specifications as a Kotlin file for Jetpack Compose: objects with
+// Color, TextUnit, FontWeight and Dp constants, Material 3 Typography and Shapes instances,
+// a light ColorScheme and an AppTheme composable wiring them into MaterialTheme.
+// Figma variables become one object per collection and mode.
+//
+// Typography roles and shape slots use the extracted value closest to their Material 3
+// default, the same mapping the Flutter output uses. The generated file declares the
+// "designtokens" package; move it to your application's package as needed.
+func ToCompose(specs *extractor.DesignSpecs, fileName string) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("// Design tokens extracted from Figma: %s\n", fileName))
+	sb.WriteString("// Generated by figma-extractor. Do not edit by hand.\n\n")
+	sb.WriteString("package designtokens\n\n")
+	for _, imp := range []string{
+		"androidx.compose.foundation.shape.RoundedCornerShape",
+		"androidx.compose.material3.MaterialTheme",
+		"androidx.compose.material3.Shapes",
+		"androidx.compose.material3.Typography",
+		"androidx.compose.material3.lightColorScheme",
+		"androidx.compose.runtime.Composable",
+		"androidx.compose.ui.graphics.Color",
+		"androidx.compose.ui.text.TextStyle",
+		"androidx.compose.ui.text.font.FontWeight",
+		"androidx.compose.ui.unit.dp",
+		"androidx.compose.ui.unit.sp",
+	} {
+		sb.WriteString("import " + imp + "\n")
+	}
+	sb.WriteString("\n")
+
+	// Colors
+	var colorLines []string
+	for _, group := range colorGroups(specs.Colors) {
+		for _, name := range sortedKeys(group.Colors) {
+			ident := kotlinIdent(toCamelCase(group.Key+" "+name), "color")
+			colorLines = append(colorLines, fmt.Sprintf("val %s = %s", ident, kotlinColor(group.Colors[name])))
+		}
+	}
+	writeKotlinObject(&sb, "AppColors", colorLines)
+
+	// Typography
+	var sizeLines []string
+	for _, name := range sortedKeys(specs.Typography.FontSizes) {
+		ident := kotlinIdent(toCamelCase(name), "size")
+		sizeLines = append(sizeLines, fmt.Sprintf("val %s = %g.sp", ident, specs.Typography.FontSizes[name]))
+	}
+	writeKotlinObject(&sb, "AppFontSizes", sizeLines)
+
+	var weightLines []string
+	for _, name := range sortedKeys(specs.Typography.FontWeights) {
+		ident := kotlinIdent(toCamelCase(name), "weight")
+		weight := int(math.Round(specs.Typography.FontWeights[name]))
+		weightLines = append(weightLines, fmt.Sprintf("val %s = FontWeight(%d)", ident, weight))
+	}
+	writeKotlinObject(&sb, "AppFontWeights", weightLines)
+
+	var leadingLines []string
+	for _, name := range sortedKeys(specs.Typography.LineHeights) {
+		ident := kotlinIdent(toCamelCase(name), "leading")
+		leadingLines = append(leadingLines, fmt.Sprintf("val %s = %g.sp", ident, specs.Typography.LineHeights[name]))
+	}
+	writeKotlinObject(&sb, "AppLineHeights", leadingLines)
+
+	var trackingLines []string
+	for _, name := range sortedKeys(specs.Typography.LetterSpacings) {
+		ident := kotlinIdent(toCamelCase(name), "tracking")
+		trackingLines = append(trackingLines, fmt.Sprintf("val %s = %g.sp", ident, specs.Typography.LetterSpacings[name]))
+	}
+	writeKotlinObject(&sb, "AppLetterSpacings", trackingLines)
+
+	// Spacing and radii
+	var spaceLines []string
+	for _, name := range sortedKeys(specs.Spacing.Values) {
+		ident := kotlinIdent(toCamelCase("space "+name), "space")
+		spaceLines = append(spaceLines, fmt.Sprintf("val %s = %g.dp", ident, specs.Spacing.Values[name]))
+	}
+	writeKotlinObject(&sb, "AppSpacing", spaceLines)
+
+	var radiusLines []string
+	for _, name := range sortedKeys(specs.Radii.Values) {
+		ident := kotlinIdent(toCamelCase(name), "radius")
+		radiusLines = append(radiusLines, fmt.Sprintf("val %s = %g.dp", ident, specs.Radii.Values[name]))
+	}
+	if len(radiusLines) > 0 {
+		radiusLines = append(radiusLines, "val full = 9999.dp")
+	}
+	writeKotlinObject(&sb, "AppRadii", radiusLines)
+
+	// Variables: object <Collection><Mode>
+	for _, coll := range specs.Variables {
+		for _, mode := range coll.Modes {
+			var lines []string
+			for _, v := range mode.Variables {
+				lines = append(lines, fmt.Sprintf("val %s = %s", kotlinIdent(toCamelCase(v.Name), "value"), kotlinVariableValue(v)))
+			}
+			writeKotlinObject(&sb, toPascalCase(coll.Name+" "+mode.Name), lines)
+		}
+	}
+
+	// Material 3 Typography
+	sb.WriteString("val AppTypography = Typography(")
+	if len(specs.Typography.FontSizes) > 0 {
+		sb.WriteString("\n")
+		for _, role := range materialTextRoles {
+			name := closestValue(specs.Typography.FontSizes, role.size)
+			sb.WriteString(fmt.Sprintf("    %s = TextStyle(fontSize = AppFontSizes.%s),\n", role.name, kotlinIdent(toCamelCase(name), "size")))
+		}
+	}
+	sb.WriteString(")\n\n")
+
+	// Material 3 Shapes
+	sb.WriteString("val AppShapes = Shapes(")
+	if len(specs.Radii.Values) > 0 {
+		sb.WriteString("\n")
+		for _, slot := range materialShapeSizes {
+			name := closestValue(specs.Radii.Values, slot.radius)
+			sb.WriteString(fmt.Sprintf("    %s = RoundedCornerShape(AppRadii.%s),\n", slot.name, kotlinIdent(toCamelCase(name), "radius")))
+		}
+	}
+	sb.WriteString(")\n\n")
+
+	// Color scheme and theme
+	sb.WriteString("val AppColorScheme = lightColorScheme(")
+	var schemeLines []string
+	if len(specs.Colors.Primary) > 0 {
+		schemeLines = append(schemeLines, "primary = "+kotlinColor(firstColor(specs.Colors.Primary, "")))
+	}
+	if len(specs.Colors.Secondary) > 0 {
+		schemeLines = append(schemeLines, "secondary = "+kotlinColor(firstColor(specs.Colors.Secondary, "")))
+	}
+	if len(specs.Colors.Background) > 0 {
+		bg := kotlinColor(firstColor(specs.Colors.Background, ""))
+		schemeLines = append(schemeLines, "background = "+bg, "surface = "+bg)
+	}
+	if len(schemeLines) > 0 {
+		sb.WriteString("\n")
+		for _, line := range schemeLines {
+			sb.WriteString("    " + line + ",\n")
+		}
+	}
+	sb.WriteString(")\n\n")
+
+	sb.WriteString("@Composable\n")
+	sb.WriteString("fun AppTheme(content: @Composable () -> Unit) {\n")
+	sb.WriteString("    MaterialTheme(\n")
+	sb.WriteString("        colorScheme = AppColorScheme,\n")
+	sb.WriteString("        typography = AppTypography,\n")
+	sb.WriteString("        shapes = AppShapes,\n")
+	sb.WriteString("        content = content,\n")
+	sb.WriteString("    )\n")
+	sb.WriteString("}\n")
+
+	return sb.String()
+}
+
+// renderCompose adapts ToCompose to the renderFunc signature.
+func renderCompose(in Input) ([]File, error) {
+	return []File{{Name: "DesignTokens.kt", Content: []byte(ToCompose(in.Specs, in.FileName))}}, nil
+}
+
+// writeKotlinObject writes an object declaration holding vals.
+// Nothing is written when there are no members.
+func writeKotlinObject(sb *strings.Builder, name string, lines []string) {
+	if len(lines) == 0 {
+		return
+	}
+
+	sb.WriteString(fmt.Sprintf("object %s {\n", name))
+	for _, line := range lines {
+		sb.WriteString("    " + line + "\n")
+	}
+	sb.WriteString("}\n\n")
+}
+
+// kotlinColor formats a "#RRGGBB" hex color as an opaque Compose Color.
+func kotlinColor(hex string) string {
+	return fmt.Sprintf("Color(0xFF%s)", strings.ToUpper(strings.TrimPrefix(hex, "#")))
+}
+
+// kotlinVariableValue formats a resolved variable value as a Kotlin expression: font metrics
+// as TextUnit in sp, other dimensions as Dp.
+func kotlinVariableValue(v extractor.Variable) string {
+	switch v.Type {
+	case "COLOR":
+		return kotlinColor(v.Color)
+	case "FLOAT":
+		if v.TextMetric {
+			return fmt.Sprintf("%g.sp", v.Number)
+		}
+		if v.Dimension {
+			return fmt.Sprintf("%g.dp", v.Number)
+		}
+		return fmt.Sprintf("%gf", v.Number)
+	case "BOOLEAN":
+		return fmt.Sprintf("%t", v.Bool)
+	default:
+		return kotlinString(v.String)
+	}
+}
+
+// kotlinString formats s as a Kotlin string literal.
+func kotlinString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`, "\n", `\n`).Replace(s) + `"`
+}
+
+// kotlinKeywords lists hard keywords that must be escaped with backticks when used as identifiers.
+var kotlinKeywords = map[string]bool{
+	"as": true, "break": true, "class": true, "continue": true, "do": true, "else": true,
+	"false": true, "for": true, "fun": true, "if": true, "in": true, "interface": true,
+	"is": true, "null": true, "object": true, "package": true, "return": true, "super": true,
+	"this": true, "throw": true, "true": true, "try": true, "typealias": true, "typeof": true,
+	"val": true, "var": true, "when": true, "while": true,
+}
+
+// kotlinIdent makes a camelCase name usable as a Kotlin identifier: names starting with a digit
+// are prefixed and reserved words are escaped.
+func kotlinIdent(name, prefix string) string {
+	if name == "" {
+		return ""
+	}
+	if name[0] >= '0' && name[0] <= '9' {
+		name = prefix + name
+	}
+	if kotlinKeywords[name] {
+		return "`" + name + "`"
+	}
+	return name
+}
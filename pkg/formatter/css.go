@@ -0,0 +1,202 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hellenic-development/figma-extractor/pkg/extractor"
+)
+
+// ToCSS renders design specifications as a stylesheet of CSS custom properties, ready to be
+// imported into a project. All tokens are declared in a single :root block using the same
+// names as the markdown report. When Figma variables are present, the default mode of each
+// collection is part of :root and every other mode gets a [data-theme="<mode>"] block that
+// overrides it, so themes can be switched by setting the attribute on any ancestor element.
+// Variables aliasing other variables refer to them with var().
+// Composite text styles become .text-<style> utility classes. Detected light and dark themes
+// add a prefers-color-scheme: dark block.
+func ToCSS(specs *extractor.DesignSpecs, fileName string) string {
+	return ToCSSWithNaming(specs, fileName, nil)
+}
+
+// ToCSSWithNaming is like ToCSS but names the custom properties of the tokens with naming
+// (nil = KebabNaming).
+func ToCSSWithNaming(specs *extractor.DesignSpecs, fileName string, naming NamingStrategy) string {
+	return toCSS(specs, fileName, naming, Units{})
+}
+
+// toCSS is like ToCSSWithNaming but emits the typography, spacing and radius tokens in units.
+func toCSS(specs *extractor.DesignSpecs, fileName string, naming NamingStrategy, units Units) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("/* Design tokens extracted from Figma: %s */\n", fileName))
+	sb.WriteString("/* Generated by figma-extractor. Do not edit by hand. */\n\n")
+
+	sb.WriteString(":root {\n")
+	writeCSSDeclarations(&sb, specs, "  ", naming, units)
+
+	// Default mode of every variable collection.
+	themes := make(map[string][]extractor.Variable)
+	var themeOrder []string
+	for _, coll := range specs.Variables {
+		for _, mode := range coll.Modes {
+			if mode.Default {
+				sb.WriteString(fmt.Sprintf("\n  /* %s (%s) */\n", coll.Name, mode.Name))
+				for _, v := range mode.Variables {
+					sb.WriteString(fmt.Sprintf("  --%s: %s;\n", variableCSSName(v.Name), variableCSSReference(v)))
+				}
+				continue
+			}
+
+			theme := toKebabCase(mode.Name)
+			if _, ok := themes[theme]; !ok {
+				themeOrder = append(themeOrder, theme)
+			}
+			themes[theme] = append(themes[theme], mode.Variables...)
+		}
+	}
+	sb.WriteString("}\n")
+
+	// Composite text styles as utility classes.
+	if len(specs.TextStyles) > 0 {
+		sb.WriteString("\n/* Text Styles */\n")
+		writeTextStyleRules(&sb, specs.TextStyles, ".%s", units)
+	}
+
+	// Other modes as switchable themes.
+	for _, theme := range themeOrder {
+		sb.WriteString(fmt.Sprintf("\n[data-theme=\"%s\"] {\n", theme))
+		for _, v := range themes[theme] {
+			sb.WriteString(fmt.Sprintf("  --%s: %s;\n", variableCSSName(v.Name), variableCSSReference(v)))
+		}
+		sb.WriteString("}\n")
+	}
+
+	// Light and dark palettes following the OS color scheme.
+	if specs.Themes != nil {
+		sb.WriteString("\n/* Light & Dark Themes */\n")
+		writeThemeBlocks(&sb, specs.Themes)
+	}
+
+	return sb.String()
+}
+
+// renderCSS adapts ToCSS to the renderFunc signature.
+func renderCSS(in Input) ([]File, error) {
+	return []File{{Name: "tokens.css", Content: []byte(toCSS(in.Specs, in.FileName, in.Naming, in.Units))}}, nil
+}
+
+// writeCSSDeclarations writes every extracted token as a custom property declaration named by
+// naming, grouped by category with a comment per group, with typography, spacing and radius
+// values in units. Tokens the strategy gives an already declared name are left out.
+func writeCSSDeclarations(sb *strings.Builder, specs *extractor.DesignSpecs, indent string, naming NamingStrategy, units Units) {
+	first := true
+	section := func(label string) {
+		if !first {
+			sb.WriteString("\n")
+		}
+		first = false
+		sb.WriteString(fmt.Sprintf("%s/* %s */\n", indent, label))
+	}
+	declared := make(uniqueNames)
+	decl := func(name, value string) {
+		if declared.add(name) {
+			sb.WriteString(fmt.Sprintf("%s--%s: %s;\n", indent, name, value))
+		}
+	}
+	token := func(t Token, value string) {
+		decl(tokenName(naming, t), value)
+	}
+
+	for _, group := range colorGroups(specs.Colors) {
+		if len(group.Colors) == 0 {
+			continue
+		}
+		section(group.Label)
+		for _, name := range sortedKeys(group.Colors) {
+			token(Token{Category: "color", Name: group.Prefix + toKebabCase(name)}, group.Colors[name])
+		}
+	}
+
+	if specs.Typography.FontFamily != "" {
+		section("Font Family")
+		token(Token{Category: "font", Name: "primary"}, fmt.Sprintf("'%s', system-ui, -apple-system, sans-serif", specs.Typography.FontFamily))
+	}
+
+	if len(specs.Typography.FontSizes) > 0 {
+		section("Font Sizes")
+		for _, name := range sortedKeys(specs.Typography.FontSizes) {
+			token(Token{Category: "text", Name: name, Value: specs.Typography.FontSizes[name]}, units.size(specs.Typography.FontSizes[name]))
+		}
+	}
+
+	if len(specs.Typography.FontWeights) > 0 {
+		section("Font Weights")
+		for _, name := range sortedKeys(specs.Typography.FontWeights) {
+			token(Token{Category: "font", Name: toKebabCase(name)}, fmt.Sprintf("%g", specs.Typography.FontWeights[name]))
+		}
+	}
+
+	if len(specs.Typography.LineHeights) > 0 {
+		section("Line Heights")
+		for _, name := range sortedKeys(specs.Typography.LineHeights) {
+			token(Token{Category: "leading", Name: toKebabCase(name)}, units.size(specs.Typography.LineHeights[name]))
+		}
+	}
+
+	for _, detail := range textDetails(specs.Typography, naming, units) {
+		section(detail.Label)
+		for _, d := range detail.Decls {
+			decl(d[0], d[1])
+		}
+	}
+
+	if len(specs.Spacing.Values) > 0 {
+		section("Spacing Scale")
+		for _, name := range sortedKeys(specs.Spacing.Values) {
+			token(Token{Category: "space", Name: name, Value: specs.Spacing.Values[name]}, units.size(specs.Spacing.Values[name]))
+		}
+	}
+
+	if len(specs.Radii.Values) > 0 {
+		section("Border Radius")
+		for _, name := range sortedKeys(specs.Radii.Values) {
+			token(Token{Category: "radius", Name: name, Value: specs.Radii.Values[name]}, units.size(specs.Radii.Values[name]))
+		}
+		token(Token{Category: "radius", Name: "full", Value: 9999}, "9999px")
+	}
+
+	if len(specs.Borders) > 0 {
+		section("Borders")
+		for _, name := range sortedKeys(specs.Borders) {
+			b := specs.Borders[name]
+			token(Token{Category: "border", Name: toKebabCase(name)}, cssBorder(b))
+			if len(b.Sides) == 4 {
+				token(Token{Category: "border-width", Name: toKebabCase(name)}, cssBorderWidths(b.Sides))
+			}
+		}
+	}
+
+	if len(specs.Shadows) > 0 {
+		section("Shadows")
+		names, values := shadowTokens(specs.Shadows)
+		for _, name := range names {
+			token(Token{Category: "shadow", Name: name}, values[name])
+		}
+	}
+
+	if len(specs.Blurs) > 0 {
+		section("Blurs (filter / backdrop-filter)")
+		names, blurs := blurTokens(specs.Blurs)
+		for _, name := range names {
+			token(Token{Category: "blur", Name: name}, cssBlur(blurs[name]))
+		}
+	}
+
+	if len(specs.Layout.Breakpoints) > 0 {
+		section("Breakpoints")
+		for _, bp := range specs.Layout.Breakpoints {
+			token(Token{Category: "breakpoint", Name: bp.Name}, px(bp.Width))
+		}
+	}
+}
@@ -0,0 +1,286 @@
+package formatter
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/hellenic-development/figma-extractor/pkg/extractor"
+)
+
+// ToDTCG renders design specifications in the W3C Design Tokens Community Group format
+// (https://tr.designtokens.org/format/). Every token is an object with $type and $value;
+// slash-separated Figma names become nested groups. Figma variables are emitted with the
+// default mode as $value and the remaining modes under $extensions["com.figma"].modes;
+// variables aliasing other variables reference their tokens instead of repeating the value.
+func ToDTCG(specs *extractor.DesignSpecs) ([]byte, error) {
+	root := make(map[string]any)
+
+	// Colors
+	colors := map[string]map[string]string{
+		"primary":    specs.Colors.Primary,
+		"secondary":  specs.Colors.Secondary,
+		"background": specs.Colors.Background,
+		"text":       specs.Colors.Text,
+		"status":     specs.Colors.Status,
+		"border":     specs.Colors.Border,
+		"brand":      specs.Colors.Brand,
+		"accent":     specs.Colors.Accent,
+		"neutral":    specs.Colors.Neutral,
+	}
+	// Keys are visited in sorted order so that name collisions resolve the same way on every run.
+	for _, category := range sortedKeys(colors) {
+		for _, name := range sortedKeys(colors[category]) {
+			setToken(root, append([]string{"color", category}, tokenPath(name)...), dtcgToken("color", colors[category][name]))
+		}
+	}
+
+	// Typography
+	if specs.Typography.FontFamily != "" {
+		setToken(root, []string{"font", "family", "primary"},
+			dtcgToken("fontFamily", []string{specs.Typography.FontFamily, "system-ui", "sans-serif"}))
+	}
+	for _, name := range sortedKeys(specs.Typography.FontSizes) {
+		setToken(root, []string{"font", "size", name}, dtcgToken("dimension", px(specs.Typography.FontSizes[name])))
+	}
+	for _, name := range sortedKeys(specs.Typography.FontWeights) {
+		setToken(root, append([]string{"font", "weight"}, tokenPath(name)...), dtcgToken("fontWeight", specs.Typography.FontWeights[name]))
+	}
+	for _, name := range sortedKeys(specs.Typography.LineHeights) {
+		setToken(root, append([]string{"font", "lineHeight"}, tokenPath(name)...), dtcgToken("dimension", px(specs.Typography.LineHeights[name])))
+	}
+	for _, name := range sortedKeys(specs.Typography.LetterSpacings) {
+		setToken(root, append([]string{"font", "letterSpacing"}, tokenPath(name)...), dtcgToken("dimension", px(specs.Typography.LetterSpacings[name])))
+	}
+
+	// Composite text styles
+	for _, name := range sortedKeys(specs.TextStyles) {
+		setToken(root, append([]string{"typography"}, tokenPath(name)...), dtcgToken("typography", dtcgTypography(specs.TextStyles[name])))
+	}
+
+	// Spacing and radii
+	for _, name := range sortedKeys(specs.Spacing.Values) {
+		setToken(root, []string{"spacing", name}, dtcgToken("dimension", px(specs.Spacing.Values[name])))
+	}
+	for _, name := range sortedKeys(specs.Radii.Values) {
+		setToken(root, []string{"radius", name}, dtcgToken("dimension", px(specs.Radii.Values[name])))
+	}
+
+	// Border styles
+	for _, name := range sortedKeys(specs.Borders) {
+		setToken(root, append([]string{"border"}, tokenPath(name)...), dtcgToken("border", dtcgBorder(specs.Borders[name])))
+	}
+
+	// Shadows: layered shadows sharing a name become a single token with an array value.
+	shadows := make(map[string][]any)
+	var shadowOrder []string
+	for i, shadow := range specs.Shadows {
+		name := strings.Join(tokenPath(shadow.Name), "/")
+		if name == "" {
+			name = fmt.Sprintf("shadow-%d", i+1)
+		}
+		if _, ok := shadows[name]; !ok {
+			shadowOrder = append(shadowOrder, name)
+		}
+		shadows[name] = append(shadows[name], map[string]any{
+			"color":   shadow.Color,
+			"offsetX": px(shadow.X),
+			"offsetY": px(shadow.Y),
+			"blur":    px(shadow.Blur),
+			"spread":  px(shadow.Spread),
+			"inset":   shadow.Type == "INNER_SHADOW",
+		})
+	}
+	for _, name := range shadowOrder {
+		var value any = shadows[name]
+		if len(shadows[name]) == 1 {
+			value = shadows[name][0]
+		}
+		setToken(root, append([]string{"shadow"}, strings.Split(name, "/")...), dtcgToken("shadow", value))
+	}
+
+	// Blurs have no DTCG type of their own; the CSS blur radius is a dimension.
+	blurNames, blurs := blurTokens(specs.Blurs)
+	for _, name := range blurNames {
+		setToken(root, []string{"blur", name}, dtcgToken("dimension", px(blurs[name].Radius/2)))
+	}
+
+	// Breakpoints
+	for _, bp := range specs.Layout.Breakpoints {
+		setToken(root, []string{"breakpoint", bp.Name}, dtcgToken("dimension", px(bp.Width)))
+	}
+
+	// Variables
+	for _, coll := range specs.Variables {
+		for _, token := range dtcgVariableTokens(coll) {
+			setToken(root, append(tokenPath(coll.Name), token.path...), token.value)
+		}
+	}
+
+	return json.MarshalIndent(root, "", "  ")
+}
+
+// renderDTCG adapts ToDTCG to the renderFunc signature.
+func renderDTCG(in Input) ([]File, error) {
+	data, err := ToDTCG(in.Specs)
+	if err != nil {
+		return nil, fmt.Errorf("render dtcg: %w", err)
+	}
+	return []File{{Name: "tokens.json", Content: append(data, '\n')}}, nil
+}
+
+// dtcgToken builds a single DTCG token object.
+func dtcgToken(tokenType string, value any) map[string]any {
+	return map[string]any{
+		"$type":  tokenType,
+		"$value": value,
+	}
+}
+
+// dtcgTypography builds the value of a DTCG typography composite token.
+func dtcgTypography(ts extractor.TextStyle) map[string]any {
+	value := map[string]any{
+		"fontFamily":    []string{ts.FontFamily, "system-ui", "sans-serif"},
+		"fontSize":      px(ts.FontSize),
+		"fontWeight":    ts.FontWeight,
+		"letterSpacing": px(ts.LetterSpacing),
+	}
+	// DTCG line heights are unitless multipliers of the font size.
+	if ts.LineHeight > 0 && ts.FontSize > 0 {
+		value["lineHeight"] = math.Round(ts.LineHeight/ts.FontSize*1000) / 1000
+	} else {
+		value["lineHeight"] = 1.2
+	}
+	return value
+}
+
+// pathToken is a DTCG token together with the group path it is stored at.
+type pathToken struct {
+	path  []string
+	value map[string]any
+}
+
+// dtcgBorder converts a border style into a DTCG border value. Dashed strokes use the
+// object form of strokeStyle so that the exact dash pattern is kept.
+func dtcgBorder(b extractor.Border) map[string]any {
+	var style any = b.Style
+	if len(b.Dashes) > 0 {
+		dashes := make([]string, len(b.Dashes))
+		for i, d := range b.Dashes {
+			dashes[i] = px(d)
+		}
+		style = map[string]any{"dashArray": dashes, "lineCap": cssLineCap(b.Cap)}
+	}
+	return map[string]any{
+		"color": b.Color,
+		"width": px(b.Width),
+		"style": style,
+	}
+}
+
+// dtcgVariableTokens converts a variable collection into DTCG tokens. The default mode
+// provides $value; every other mode is recorded under $extensions["com.figma"].modes.
+func dtcgVariableTokens(coll extractor.VariableCollection) []pathToken {
+	var defaultMode *extractor.VariableMode
+	for i := range coll.Modes {
+		if coll.Modes[i].Default || defaultMode == nil {
+			defaultMode = &coll.Modes[i]
+		}
+	}
+	if defaultMode == nil {
+		return nil
+	}
+
+	var tokens []pathToken
+	for _, v := range defaultMode.Variables {
+		tokenType, value := dtcgVariableValue(v)
+		token := dtcgToken(tokenType, value)
+
+		modes := make(map[string]any)
+		for _, mode := range coll.Modes {
+			if mode.Name == defaultMode.Name {
+				continue
+			}
+			for _, mv := range mode.Variables {
+				if mv.Name == v.Name {
+					_, modes[mode.Name] = dtcgVariableValue(mv)
+					break
+				}
+			}
+		}
+		if len(modes) > 0 {
+			token["$extensions"] = map[string]any{"com.figma": map[string]any{"modes": modes}}
+		}
+
+		tokens = append(tokens, pathToken{path: tokenPath(v.Name), value: token})
+	}
+	return tokens
+}
+
+// dtcgVariableValue maps a resolved variable onto a DTCG type and value. Aliases become
+// references to the token of the variable they alias ("{primitives.primary.500}").
+func dtcgVariableValue(v extractor.Variable) (string, any) {
+	tokenType, value := dtcgRawVariableValue(v)
+	if len(v.Aliases) > 0 {
+		ref := v.Aliases[0]
+		value = "{" + strings.Join(append(tokenPath(ref.Collection), tokenPath(ref.Name)...), ".") + "}"
+	}
+	return tokenType, value
+}
+
+// dtcgRawVariableValue maps the resolved value of a variable onto a DTCG type and value.
+func dtcgRawVariableValue(v extractor.Variable) (string, any) {
+	switch v.Type {
+	case "COLOR":
+		return "color", v.Color
+	case "FLOAT":
+		if v.Dimension {
+			return "dimension", px(v.Number)
+		}
+		return "number", v.Number
+	case "BOOLEAN":
+		return "boolean", v.Bool
+	default:
+		return "string", v.String
+	}
+}
+
+// setToken stores token in the nested group tree at path, creating intermediate groups.
+// If a segment is already occupied by a token (or the leaf by a group), the remaining path is
+// joined with hyphens so that neither value is lost.
+func setToken(root map[string]any, path []string, token map[string]any) {
+	if len(path) == 0 {
+		return
+	}
+
+	group := root
+	for i, segment := range path[:len(path)-1] {
+		next, ok := group[segment].(map[string]any)
+		if ok && isToken(next) {
+			group[strings.Join(path[i:], "-")] = token
+			return
+		}
+		if !ok {
+			next = make(map[string]any)
+			group[segment] = next
+		}
+		group = next
+	}
+
+	leaf := path[len(path)-1]
+	if existing, ok := group[leaf].(map[string]any); ok && !isToken(existing) {
+		leaf += "-value"
+	}
+	group[leaf] = token
+}
+
+// isToken reports whether a token tree node is a token (as opposed to a group).
+// DTCG tokens carry $value, Style Dictionary tokens value.
+func isToken(node map[string]any) bool {
+	if _, ok := node["$value"]; ok {
+		return true
+	}
+	_, ok := node["value"]
+	return ok
+}
@@ -0,0 +1,239 @@
+package formatter
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/hellenic-development/figma-extractor/pkg/extractor"
+)
+
+// materialTextRoles are the Material 3 TextTheme roles with their default font sizes.
+var materialTextRoles = []struct {
+	name string
+	size float64
+}{
+	{"displayLarge", 57}, {"displayMedium", 45}, {"displaySmall", 36},
+	{"headlineLarge", 32}, {"headlineMedium", 28}, {"headlineSmall", 24},
+	{"titleLarge", 22}, {"titleMedium", 16}, {"titleSmall", 14},
+	{"bodyLarge", 16}, {"bodyMedium", 14}, {"bodySmall", 12},
+	{"labelLarge", 14}, {"labelMedium", 12}, {"labelSmall", 11},
+}
+
+// ToFlutter renders design specifications as a Dart library for Flutter: constant classes for
+// colors, font sizes, font weights, line heights, letter spacings, spacing, radii and shadows,
+// with dimensions in logical pixels, a TextTheme and a buildThemeData function returning a
+// Material 3 ThemeData. Figma variables become one class per collection and mode.
+//
+// The ColorScheme is seeded from the first primary color and overrides secondary and surface
+// when the palette has them. Each TextTheme role uses the font size closest to its Material 3
+// default, so the theme follows the file's type scale without naming conventions.
+func ToFlutter(specs *extractor.DesignSpecs, fileName string) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("// Design tokens extracted from Figma: %s\n", fileName))
+	sb.WriteString("// Generated by figma-extractor. Do not edit by hand.\n\n")
+	sb.WriteString("import 'package:flutter/material.dart';\n\n")
+
+	// Colors
+	var colorLines []string
+	for _, group := range colorGroups(specs.Colors) {
+		for _, name := range sortedKeys(group.Colors) {
+			ident := dartIdent(toCamelCase(group.Key+" "+name), "color")
+			colorLines = append(colorLines, fmt.Sprintf("static const Color %s = %s;", ident, dartColor(group.Colors[name])))
+		}
+	}
+	writeDartClass(&sb, "AppColors", colorLines)
+
+	// Typography
+	var sizeLines []string
+	for _, name := range sortedKeys(specs.Typography.FontSizes) {
+		ident := dartIdent(toCamelCase(name), "size")
+		sizeLines = append(sizeLines, fmt.Sprintf("static const double %s = %g;", ident, specs.Typography.FontSizes[name]))
+	}
+	writeDartClass(&sb, "AppFontSizes", sizeLines)
+
+	var weightLines []string
+	for _, name := range sortedKeys(specs.Typography.FontWeights) {
+		ident := dartIdent(toCamelCase(name), "weight")
+		weightLines = append(weightLines, fmt.Sprintf("static const FontWeight %s = %s;", ident, dartFontWeight(specs.Typography.FontWeights[name])))
+	}
+	writeDartClass(&sb, "AppFontWeights", weightLines)
+
+	var leadingLines []string
+	for _, name := range sortedKeys(specs.Typography.LineHeights) {
+		ident := dartIdent(toCamelCase(name), "leading")
+		leadingLines = append(leadingLines, fmt.Sprintf("static const double %s = %g;", ident, specs.Typography.LineHeights[name]))
+	}
+	writeDartClass(&sb, "AppLineHeights", leadingLines)
+
+	var trackingLines []string
+	for _, name := range sortedKeys(specs.Typography.LetterSpacings) {
+		ident := dartIdent(toCamelCase(name), "tracking")
+		trackingLines = append(trackingLines, fmt.Sprintf("static const double %s = %g;", ident, specs.Typography.LetterSpacings[name]))
+	}
+	writeDartClass(&sb, "AppLetterSpacings", trackingLines)
+
+	// Spacing and radii
+	var spaceLines []string
+	for _, name := range sortedKeys(specs.Spacing.Values) {
+		ident := dartIdent(toCamelCase("space "+name), "space")
+		spaceLines = append(spaceLines, fmt.Sprintf("static const double %s = %g;", ident, specs.Spacing.Values[name]))
+	}
+	writeDartClass(&sb, "AppSpacing", spaceLines)
+
+	var radiusLines []string
+	for _, name := range sortedKeys(specs.Radii.Values) {
+		ident := dartIdent(toCamelCase(name), "radius")
+		radiusLines = append(radiusLines, fmt.Sprintf("static const double %s = %g;", ident, specs.Radii.Values[name]))
+	}
+	if len(radiusLines) > 0 {
+		radiusLines = append(radiusLines, "static const double full = 9999;")
+	}
+	writeDartClass(&sb, "AppRadii", radiusLines)
+
+	// Shadows: layers with the same name form one List<BoxShadow>. Flutter has no inner shadows.
+	var shadowOrder []string
+	shadowLayers := make(map[string][]string)
+	for i, shadow := range specs.Shadows {
+		if shadow.Type == "INNER_SHADOW" {
+			continue
+		}
+		ident := dartIdent(toCamelCase(shadow.Name), "shadow")
+		if ident == "" {
+			ident = fmt.Sprintf("shadow%d", i+1)
+		}
+		if _, ok := shadowLayers[ident]; !ok {
+			shadowOrder = append(shadowOrder, ident)
+		}
+		shadowLayers[ident] = append(shadowLayers[ident], fmt.Sprintf("BoxShadow(color: %s, offset: Offset(%g, %g), blurRadius: %g, spreadRadius: %g)",
+			dartColor(shadow.Color), shadow.X, shadow.Y, shadow.Blur, shadow.Spread))
+	}
+	var shadowLines []string
+	for _, ident := range shadowOrder {
+		shadowLines = append(shadowLines, fmt.Sprintf("static const List<BoxShadow> %s = [%s];", ident, strings.Join(shadowLayers[ident], ", ")))
+	}
+	writeDartClass(&sb, "AppShadows", shadowLines)
+
+	// Variables: class <Collection><Mode>
+	for _, coll := range specs.Variables {
+		for _, mode := range coll.Modes {
+			var lines []string
+			for _, v := range mode.Variables {
+				typ, value := dartVariableValue(v)
+				lines = append(lines, fmt.Sprintf("static const %s %s = %s;", typ, dartIdent(toCamelCase(v.Name), "value"), value))
+			}
+			writeDartClass(&sb, toPascalCase(coll.Name+" "+mode.Name), lines)
+		}
+	}
+
+	// TextTheme
+	sb.WriteString("const TextTheme appTextTheme = TextTheme(\n")
+	if len(specs.Typography.FontSizes) > 0 {
+		for _, role := range materialTextRoles {
+			name := closestValue(specs.Typography.FontSizes, role.size)
+			sb.WriteString(fmt.Sprintf("  %s: TextStyle(fontSize: AppFontSizes.%s),\n", role.name, dartIdent(toCamelCase(name), "size")))
+		}
+	}
+	sb.WriteString(");\n\n")
+
+	// ThemeData
+	sb.WriteString("ThemeData buildThemeData({Brightness brightness = Brightness.light}) {\n")
+	sb.WriteString("  final colorScheme = ColorScheme.fromSeed(\n")
+	sb.WriteString(fmt.Sprintf("    seedColor: %s,\n", dartColor(firstColor(specs.Colors.Primary, "#6750A4"))))
+	sb.WriteString("    brightness: brightness,\n")
+	if len(specs.Colors.Secondary) > 0 {
+		sb.WriteString(fmt.Sprintf("    secondary: %s,\n", dartColor(firstColor(specs.Colors.Secondary, ""))))
+	}
+	if len(specs.Colors.Background) > 0 {
+		sb.WriteString(fmt.Sprintf("    surface: %s,\n", dartColor(firstColor(specs.Colors.Background, ""))))
+	}
+	sb.WriteString("  );\n\n")
+	sb.WriteString("  return ThemeData(\n")
+	sb.WriteString("    useMaterial3: true,\n")
+	sb.WriteString("    colorScheme: colorScheme,\n")
+	if specs.Typography.FontFamily != "" {
+		sb.WriteString(fmt.Sprintf("    fontFamily: '%s',\n", dartEscape(specs.Typography.FontFamily)))
+	}
+	sb.WriteString("    textTheme: appTextTheme,\n")
+	sb.WriteString("  );\n")
+	sb.WriteString("}\n")
+
+	return sb.String()
+}
+
+// renderFlutter adapts ToFlutter to the renderFunc signature.
+func renderFlutter(in Input) ([]File, error) {
+	return []File{{Name: "design_tokens.dart", Content: []byte(ToFlutter(in.Specs, in.FileName))}}, nil
+}
+
+// writeDartClass writes a non-instantiable class holding static constants.
+// Nothing is written when there are no members.
+func writeDartClass(sb *strings.Builder, name string, lines []string) {
+	if len(lines) == 0 {
+		return
+	}
+
+	sb.WriteString(fmt.Sprintf("class %s {\n", name))
+	sb.WriteString(fmt.Sprintf("  %s._();\n\n", name))
+	for _, line := range lines {
+		sb.WriteString("  " + line + "\n")
+	}
+	sb.WriteString("}\n\n")
+}
+
+// dartColor formats a "#RRGGBB" hex color as an opaque Dart Color.
+func dartColor(hex string) string {
+	return fmt.Sprintf("Color(0xFF%s)", strings.ToUpper(strings.TrimPrefix(hex, "#")))
+}
+
+// dartFontWeight maps a numeric CSS font weight onto the closest Flutter FontWeight constant.
+func dartFontWeight(weight float64) string {
+	w := int(math.Round(weight/100)) * 100
+	if w < 100 {
+		w = 100
+	}
+	if w > 900 {
+		w = 900
+	}
+	return fmt.Sprintf("FontWeight.w%d", w)
+}
+
+// dartVariableValue returns the Dart type and literal of a resolved variable value.
+func dartVariableValue(v extractor.Variable) (string, string) {
+	switch v.Type {
+	case "COLOR":
+		return "Color", dartColor(v.Color)
+	case "FLOAT":
+		return "double", fmt.Sprintf("%g", v.Number)
+	case "BOOLEAN":
+		return "bool", fmt.Sprintf("%t", v.Bool)
+	default:
+		return "String", "'" + dartEscape(v.String) + "'"
+	}
+}
+
+// dartEscape escapes a string for a single-quoted Dart literal.
+func dartEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `'`, `\'`, `$`, `\$`, "\n", `\n`).Replace(s)
+}
+
+// dartKeywords lists reserved words that cannot be used as identifiers.
+var dartKeywords = map[string]bool{
+	"class": true, "const": true, "default": true, "do": true, "else": true, "enum": true,
+	"extends": true, "false": true, "final": true, "for": true, "if": true, "in": true,
+	"is": true, "new": true, "null": true, "return": true, "super": true, "switch": true,
+	"this": true, "true": true, "var": true, "void": true, "while": true, "with": true,
+}
+
+// dartIdent makes a camelCase name usable as a Dart identifier: names starting with a digit
+// or clashing with a reserved word are prefixed.
+func dartIdent(name, prefix string) string {
+	if name == "" {
+		return ""
+	}
+	if (name[0] >= '0' && name[0] <= '9') || dartKeywords[name] {
+		return prefix + strings.ToUpper(name[:1]) + name[1:]
+	}
+	return name
+}
@@ -0,0 +1,831 @@
+package formatter
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hellenic-development/figma-extractor/pkg/extractor"
+)
+
+// File is a single rendered output artifact.
+type File struct {
+	Name    string // default file name, relative to the output directory
+	Content []byte
+}
+
+// Input carries everything an output format renders from.
+type Input struct {
+	Specs    *extractor.DesignSpecs
+	FileName string         // Figma file name
+	ImageDir string         // directory exported assets were written to, used for relative links
+	Template string         // text/template source, used by the "template" format
+	Naming   NamingStrategy // names the tokens of the "css" and "scss" formats; nil = KebabNaming
+	Units    Units          // unit of the typography, spacing and radius tokens of the "css", "scss", "typescript", "tailwind" and "markdown" formats
+
+	// Sections, FrontMatter and TOC configure the "markdown" report, see MarkdownOptions.
+	Sections    []string
+	FrontMatter bool
+	TOC         bool
+	Version     string    // Figma version ID of the file
+	ExtractedAt time.Time // when the specifications were extracted
+
+	// ReadAsset reads an exported asset by file name, for formats that embed images in
+	// standalone files, such as "html". When nil they link to ImageDir instead.
+	ReadAsset func(name string) ([]byte, error)
+}
+
+// Formatter renders the design specifications of an Input into one or more output files, see
+// Register.
+type Formatter interface {
+	Render(in Input) ([]File, error)
+}
+
+// FormatterFunc adapts a function to the Formatter interface.
+type FormatterFunc func(in Input) ([]File, error)
+
+// Render calls f.
+func (f FormatterFunc) Render(in Input) ([]File, error) {
+	return f(in)
+}
+
+// renderFunc renders an Input into one or more output files.
+type renderFunc func(in Input) ([]File, error)
+
+// formatsMu guards formats, which Register may extend while formats are rendered.
+var formatsMu sync.RWMutex
+
+// formats maps output format names to their renderers.
+var formats = map[string]renderFunc{
+	"markdown":        renderMarkdown,
+	"html":            renderHTML,
+	"dtcg":            renderDTCG,
+	"styledictionary": renderStyleDictionary,
+	"scss":            renderSCSS,
+	"css":             renderCSS,
+	"typescript":      renderTypeScript,
+	"swift":           renderSwift,
+	"android":         renderAndroid,
+	"flutter":         renderFlutter,
+	"compose":         renderCompose,
+	"storybook":       renderStorybook,
+	"tokensstudio":    renderTokensStudio,
+	"tailwind":        renderTailwind,
+	"json":            renderJSON,
+	"i18n":            renderI18nJSON,
+	"po":              renderPO,
+	"xliff":           renderXLIFF,
+	"template":        renderTemplate,
+}
+
+// Register adds an output format rendered by f under name, so that Render, Options.Formats and
+// the --format flag accept it like the built-in formats. Packages providing formats register
+// them from an init function and are imported for that side effect:
+//
+//	import _ "example.com/acme/figmaformats" // registers the "acme-tokens" format
+//
+// The CLI lists the formats of the packages imported by cmd/figma-extractor, e.g. from a
+// plugins.go file added to a custom build. Register panics when name is empty or already
+// registered.
+func Register(name string, f Formatter) {
+	formatsMu.Lock()
+	defer formatsMu.Unlock()
+	if name == "" || f == nil {
+		panic("formatter: Register needs a format name and a Formatter")
+	}
+	if _, dup := formats[name]; dup {
+		panic(fmt.Sprintf("formatter: Register called twice for format %q", name))
+	}
+	formats[name] = f.Render
+}
+
+// Formats returns the names of all supported output formats, built-in and registered, in
+// alphabetical order.
+func Formats() []string {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+	names := make([]string, 0, len(formats))
+	for name := range formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsFormat reports whether name is a supported output format.
+func IsFormat(name string) bool {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+	_, ok := formats[name]
+	return ok
+}
+
+// Render renders in using the named output format and returns the generated files.
+func Render(format string, in Input) ([]File, error) {
+	formatsMu.RLock()
+	render, ok := formats[format]
+	formatsMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown output format %q (must be one of %s)", format, strings.Join(Formats(), ", "))
+	}
+	return render(in)
+}
+
+// renderMarkdown adapts ToMarkdown to the renderFunc signature.
+func renderMarkdown(in Input) ([]File, error) {
+	md := ToMarkdownWithOptions(in.Specs, in.FileName, MarkdownOptions{
+		ImageDir:    in.ImageDir,
+		Sections:    in.Sections,
+		FrontMatter: in.FrontMatter,
+		Version:     in.Version,
+		ExtractedAt: in.ExtractedAt,
+		TOC:         in.TOC,
+		Units:       in.Units,
+	})
+	return []File{{Name: "FIGMA_DESIGN_SPECIFICATIONS.md", Content: []byte(md)}}, nil
+}
+
+// sortedKeys returns the keys of m in ascending order so that generated output is stable.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// tokenPath splits a slash-separated Figma name ("Brand/Primary/500") into kebab-case
+// path segments (["brand", "primary", "500"]), dropping empty segments.
+func tokenPath(name string) []string {
+	var path []string
+	for _, segment := range strings.Split(name, "/") {
+		if s := toKebabCase(segment); s != "" {
+			path = append(path, s)
+		}
+	}
+	return path
+}
+
+// toCamelCase converts a Figma name into a lowerCamelCase identifier
+// ("Brand / Primary 500" becomes "brandPrimary500").
+func toCamelCase(s string) string {
+	parts := strings.Split(toKebabCase(s), "-")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// toPascalCase converts a Figma name into an UpperCamelCase identifier
+// ("heading / h1" becomes "HeadingH1").
+func toPascalCase(s string) string {
+	ident := toCamelCase(s)
+	if ident == "" {
+		return ""
+	}
+	return strings.ToUpper(ident[:1]) + ident[1:]
+}
+
+// colorGroup is a palette category together with the naming used for its tokens.
+type colorGroup struct {
+	Label  string // human-readable label, e.g. "Primary Colors"
+	Key    string // category key, e.g. "primary"
+	Prefix string // token name prefix after "color-", e.g. "primary-" ("" for status colors)
+	Colors map[string]string
+
+	// Inferred marks categories classified by hue and lightness rather than by name.
+	Inferred bool
+}
+
+// colorGroups returns the palette categories in display order. Prefixes match the CSS
+// variable names used in the markdown report (--color-primary-*, --color-bg-*, ...).
+func colorGroups(p extractor.ColorPalette) []colorGroup {
+	return []colorGroup{
+		{Label: "Primary Colors", Key: "primary", Prefix: "primary-", Colors: p.Primary},
+		{Label: "Secondary Colors", Key: "secondary", Prefix: "secondary-", Colors: p.Secondary},
+		{Label: "Background Colors", Key: "background", Prefix: "bg-", Colors: p.Background},
+		{Label: "Text Colors", Key: "text", Prefix: "text-", Colors: p.Text},
+		{Label: "Status Colors", Key: "status", Prefix: "", Colors: p.Status},
+		{Label: "Border Colors", Key: "border", Prefix: "border-", Colors: p.Border},
+		{Label: "Brand Colors (inferred)", Key: "brand", Prefix: "brand-", Colors: p.Brand, Inferred: true},
+		{Label: "Accent Colors (inferred)", Key: "accent", Prefix: "accent-", Colors: p.Accent, Inferred: true},
+		{Label: "Neutral Colors (inferred)", Key: "neutral", Prefix: "neutral-", Colors: p.Neutral, Inferred: true},
+	}
+}
+
+// px formats a pixel value as a CSS dimension string.
+func px(v float64) string {
+	return fmt.Sprintf("%gpx", v)
+}
+
+// Units sets the CSS unit typography, spacing and radius tokens are emitted in. The zero value
+// emits px.
+//
+// Native formats ignore Units and emit the units of their platform, one per Figma pixel: points
+// in "swift", sp for text sizes and dp for other dimensions in "android" and "compose", and
+// logical pixels in "flutter". Android letter spacings are in ems, see emSpacing.
+type Units struct {
+	Rem          bool    // emit rem instead of px, relative to BaseFontSize
+	BaseFontSize float64 // root font size of the page in px, the size of 1rem; 0 = 16
+}
+
+// size formats a typography, spacing or radius value of v pixels as a CSS dimension string in
+// the unit of u, e.g. "24px" or "1.5rem".
+func (u Units) size(v float64) string {
+	if !u.Rem {
+		return px(v)
+	}
+	base := u.BaseFontSize
+	if base <= 0 {
+		base = 16
+	}
+	return fmt.Sprintf("%grem", math.Round(v/base*1e4)/1e4)
+}
+
+// emSpacing returns a letter spacing of spacing pixels in ems of a font size of size pixels,
+// e.g. 0.05 for 0.8px at 16px, as android:letterSpacing takes it.
+func emSpacing(spacing, size float64) float64 {
+	return math.Round(spacing/size*1e4) / 1e4
+}
+
+// joinFloats formats numbers with %g and joins them with sep.
+func joinFloats(values []float64, sep string) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = fmt.Sprintf("%g", v)
+	}
+	return strings.Join(parts, sep)
+}
+
+// textDetail is a group of letter spacing, text case or text decoration tokens, named after
+// the CSS property they set (e.g. "text-transform-heading: uppercase").
+type textDetail struct {
+	Label string
+	Decls [][2]string // custom property name without the leading dashes, value
+}
+
+// textDetails returns the letter spacing, text case and text decoration tokens of a type
+// system as CSS custom property declarations named by naming (nil = KebabNaming). Empty
+// groups are omitted. Letter spacings are emitted in units.
+func textDetails(t extractor.Typography, naming NamingStrategy, units Units) []textDetail {
+	var details []textDetail
+
+	spacing := textDetail{Label: "Letter Spacing"}
+	for _, name := range sortedKeys(t.LetterSpacings) {
+		spacing.Decls = append(spacing.Decls, [2]string{tokenName(naming, Token{Category: "tracking", Name: toKebabCase(name)}), units.size(t.LetterSpacings[name])})
+	}
+
+	textCase := textDetail{Label: "Text Case"}
+	for _, name := range sortedKeys(t.TextCases) {
+		if property, value := cssTextCase(t.TextCases[name]); property != "" {
+			textCase.Decls = append(textCase.Decls, [2]string{tokenName(naming, Token{Category: property, Name: toKebabCase(name)}), value})
+		}
+	}
+
+	decoration := textDetail{Label: "Text Decoration"}
+	for _, name := range sortedKeys(t.TextDecorations) {
+		if value := cssTextDecoration(t.TextDecorations[name]); value != "" {
+			decoration.Decls = append(decoration.Decls, [2]string{tokenName(naming, Token{Category: "text-decoration", Name: toKebabCase(name)}), value})
+		}
+	}
+
+	for _, d := range []textDetail{spacing, textCase, decoration} {
+		if len(d.Decls) > 0 {
+			details = append(details, d)
+		}
+	}
+	return details
+}
+
+// textStyleCSS returns the CSS declarations (property, value) reproducing a composite text style,
+// with its sizes in units.
+func textStyleCSS(ts extractor.TextStyle, units Units) [][2]string {
+	var decls [][2]string
+	if ts.FontFamily != "" {
+		decls = append(decls, [2]string{"font-family", fmt.Sprintf("'%s', system-ui, -apple-system, sans-serif", ts.FontFamily)})
+	}
+	if ts.FontSize > 0 {
+		decls = append(decls, [2]string{"font-size", units.size(ts.FontSize)})
+	}
+	if ts.FontWeight > 0 {
+		decls = append(decls, [2]string{"font-weight", fmt.Sprintf("%g", ts.FontWeight)})
+	}
+	if ts.LineHeight > 0 {
+		decls = append(decls, [2]string{"line-height", units.size(ts.LineHeight)})
+	}
+	if ts.LetterSpacing != 0 {
+		decls = append(decls, [2]string{"letter-spacing", units.size(ts.LetterSpacing)})
+	}
+	if property, value := cssTextCase(ts.TextCase); property != "" {
+		decls = append(decls, [2]string{property, value})
+	}
+	if value := cssTextDecoration(ts.TextDecoration); value != "" {
+		decls = append(decls, [2]string{"text-decoration-line", value})
+	}
+	return decls
+}
+
+// textStyleClass returns the CSS class name (without the dot) of a composite text style.
+func textStyleClass(name string) string {
+	return "text-" + strings.Join(tokenPath(name), "-")
+}
+
+// writeTextStyleRules writes one CSS rule per composite text style, with selector formatting
+// the class name (e.g. ".%s" for CSS or "@mixin %s" for Sass), and sizes in units.
+func writeTextStyleRules(sb *strings.Builder, styles map[string]extractor.TextStyle, selector string, units Units) {
+	for i, name := range sortedKeys(styles) {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(fmt.Sprintf(selector+" {\n", textStyleClass(name)))
+		for _, d := range textStyleCSS(styles[name], units) {
+			sb.WriteString(fmt.Sprintf("  %s: %s;\n", d[0], d[1]))
+		}
+		sb.WriteString("}\n")
+	}
+}
+
+// cssTextCase maps a Figma text case onto the CSS property and value reproducing it.
+// Small caps are a font variant in CSS rather than a text transform.
+func cssTextCase(textCase string) (property, value string) {
+	switch textCase {
+	case "UPPER":
+		return "text-transform", "uppercase"
+	case "LOWER":
+		return "text-transform", "lowercase"
+	case "TITLE":
+		return "text-transform", "capitalize"
+	case "SMALL_CAPS":
+		return "font-variant-caps", "small-caps"
+	case "SMALL_CAPS_FORCED":
+		return "font-variant-caps", "all-small-caps"
+	}
+	return "", ""
+}
+
+// cssTextDecoration maps a Figma text decoration onto a CSS text-decoration-line value.
+func cssTextDecoration(decoration string) string {
+	switch decoration {
+	case "UNDERLINE":
+		return "underline"
+	case "STRIKETHROUGH":
+		return "line-through"
+	}
+	return ""
+}
+
+// flexboxCSS returns the CSS declarations that rebuild an auto-layout frame as a flex container.
+func flexboxCSS(l extractor.AutoLayout) [][2]string {
+	horizontal := l.Direction == "HORIZONTAL"
+
+	decls := [][2]string{{"display", "flex"}}
+	if horizontal {
+		decls = append(decls, [2]string{"flex-direction", "row"})
+	} else {
+		decls = append(decls, [2]string{"flex-direction", "column"})
+	}
+	if l.Wrap {
+		decls = append(decls, [2]string{"flex-wrap", "wrap"})
+	}
+	decls = append(decls,
+		[2]string{"justify-content", cssFlexAlign(l.PrimaryAxisAlign)},
+		[2]string{"align-items", cssFlexAlign(l.CounterAxisAlign)},
+	)
+	if l.Wrap && l.AlignContent == "SPACE_BETWEEN" {
+		decls = append(decls, [2]string{"align-content", "space-between"})
+	}
+
+	// Figma ignores the item spacing of space-between frames.
+	mainGap, crossGap := l.ItemSpacing, 0.0
+	if l.PrimaryAxisAlign == "SPACE_BETWEEN" {
+		mainGap = 0
+	}
+	if l.Wrap {
+		crossGap = l.CounterAxisSpacing
+	}
+	rowGap, columnGap := crossGap, mainGap
+	if !horizontal {
+		rowGap, columnGap = mainGap, crossGap
+	}
+	switch {
+	case rowGap > 0 && rowGap == columnGap:
+		decls = append(decls, [2]string{"gap", px(rowGap)})
+	case rowGap > 0 && columnGap > 0:
+		decls = append(decls, [2]string{"gap", px(rowGap) + " " + px(columnGap)})
+	case rowGap > 0:
+		decls = append(decls, [2]string{"row-gap", px(rowGap)})
+	case columnGap > 0:
+		decls = append(decls, [2]string{"column-gap", px(columnGap)})
+	}
+
+	if l.PaddingTop > 0 || l.PaddingRight > 0 || l.PaddingBottom > 0 || l.PaddingLeft > 0 {
+		if l.PaddingTop == l.PaddingRight && l.PaddingTop == l.PaddingBottom && l.PaddingTop == l.PaddingLeft {
+			decls = append(decls, [2]string{"padding", px(l.PaddingTop)})
+		} else {
+			decls = append(decls, [2]string{"padding", fmt.Sprintf("%s %s %s %s",
+				px(l.PaddingTop), px(l.PaddingRight), px(l.PaddingBottom), px(l.PaddingLeft))})
+		}
+	}
+
+	widthSizing, heightSizing := l.PrimaryAxisSizing, l.CounterAxisSizing
+	if !horizontal {
+		widthSizing, heightSizing = heightSizing, widthSizing
+	}
+	if value := cssFlexSize(widthSizing, l.Width); value != "" {
+		decls = append(decls, [2]string{"width", value})
+	}
+	if value := cssFlexSize(heightSizing, l.Height); value != "" {
+		decls = append(decls, [2]string{"height", value})
+	}
+
+	for _, c := range l.Children {
+		if c.Absolute {
+			decls = append(decls, [2]string{"position", "relative"})
+			break
+		}
+	}
+	return decls
+}
+
+// flexItemCSS returns the CSS declarations of a child inside the flex container of l.
+func flexItemCSS(l extractor.AutoLayout, c extractor.AutoLayoutChild) [][2]string {
+	if c.Absolute {
+		return [][2]string{{"position", "absolute"}}
+	}
+
+	primarySizing, counterSizing := c.SizingHorizontal, c.SizingVertical
+	if l.Direction != "HORIZONTAL" {
+		primarySizing, counterSizing = counterSizing, primarySizing
+	}
+
+	var decls [][2]string
+	if c.Grow > 0 || primarySizing == "FILL" {
+		grow := c.Grow
+		if grow == 0 {
+			grow = 1
+		}
+		decls = append(decls, [2]string{"flex", fmt.Sprintf("%g 1 0", grow)})
+	}
+	if c.Align == "STRETCH" || counterSizing == "FILL" {
+		decls = append(decls, [2]string{"align-self", "stretch"})
+	}
+	return decls
+}
+
+// cssFlexAlign maps a Figma auto-layout alignment onto a justify-content or align-items value.
+func cssFlexAlign(align string) string {
+	switch align {
+	case "CENTER":
+		return "center"
+	case "MAX":
+		return "flex-end"
+	case "SPACE_BETWEEN":
+		return "space-between"
+	case "BASELINE":
+		return "baseline"
+	}
+	return "flex-start"
+}
+
+// cssFlexSize maps a Figma axis sizing mode onto a width or height value: a fixed size in px or
+// fit-content for frames hugging their contents.
+func cssFlexSize(sizing string, size float64) string {
+	switch {
+	case sizing == "AUTO":
+		return "fit-content"
+	case sizing == "FIXED" && size > 0:
+		return px(size)
+	}
+	return ""
+}
+
+// resizeHints describes how a layer should respond to its parent being resized, with the
+// CSS that implements each constraint.
+func resizeHints(b extractor.ResizeBehavior) []string {
+	var hints []string
+	switch b.Horizontal {
+	case "RIGHT":
+		hints = append(hints, "pinned to the right edge (`right`)")
+	case "CENTER":
+		hints = append(hints, "stays centered horizontally (`left: 50%; transform: translateX(-50%)`)")
+	case "LEFT_RIGHT":
+		hints = append(hints, "stretches with the parent's width (`left` + `right`)")
+	case "SCALE":
+		hints = append(hints, "scales with the parent's width (`left` and `width` in %)")
+	}
+	switch b.Vertical {
+	case "BOTTOM":
+		hints = append(hints, "pinned to the bottom edge (`bottom`)")
+	case "CENTER":
+		hints = append(hints, "stays centered vertically (`top: 50%; transform: translateY(-50%)`)")
+	case "TOP_BOTTOM":
+		hints = append(hints, "stretches with the parent's height (`top` + `bottom`)")
+	case "SCALE":
+		hints = append(hints, "scales with the parent's height (`top` and `height` in %)")
+	}
+	for _, limit := range []struct {
+		property string
+		value    float64
+	}{
+		{"min-width", b.MinWidth},
+		{"max-width", b.MaxWidth},
+		{"min-height", b.MinHeight},
+		{"max-height", b.MaxHeight},
+	} {
+		if limit.value > 0 {
+			hints = append(hints, fmt.Sprintf("`%s: %s`", limit.property, px(limit.value)))
+		}
+	}
+	return hints
+}
+
+// layoutClass returns the CSS class name (without the dot) of an auto-layout frame or child.
+func layoutClass(name string) string {
+	if path := tokenPath(name); len(path) > 0 {
+		return strings.Join(path, "-")
+	}
+	return "layout"
+}
+
+// writeAutoLayoutRules writes one flexbox rule per auto-layout frame, followed by the rules of
+// its children that grow, stretch or are positioned absolutely.
+func writeAutoLayoutRules(sb *strings.Builder, layouts map[string]extractor.AutoLayout) {
+	for i, name := range sortedKeys(layouts) {
+		l := layouts[name]
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		class := layoutClass(name)
+		sb.WriteString(fmt.Sprintf(".%s {\n", class))
+		for _, d := range flexboxCSS(l) {
+			sb.WriteString(fmt.Sprintf("  %s: %s;\n", d[0], d[1]))
+		}
+		sb.WriteString("}\n")
+
+		for _, c := range l.Children {
+			decls := flexItemCSS(l, c)
+			if len(decls) == 0 {
+				continue
+			}
+			sb.WriteString(fmt.Sprintf(".%s > .%s {\n", class, layoutClass(c.Name)))
+			for _, d := range decls {
+				sb.WriteString(fmt.Sprintf("  %s: %s;\n", d[0], d[1]))
+			}
+			sb.WriteString("}\n")
+		}
+	}
+}
+
+// flexResets holds the value that undoes each optional flexboxCSS declaration, for media
+// queries overriding a layout that had it.
+var flexResets = map[string]string{
+	"flex-wrap":     "nowrap",
+	"align-content": "normal",
+	"gap":           "0",
+	"row-gap":       "0",
+	"column-gap":    "0",
+	"padding":       "0",
+	"position":      "static",
+}
+
+// writeScreenRules writes mobile-first flexbox rules for a screen designed at several
+// breakpoints: the narrowest auto-layout variant as the base rule, then a min-width media query
+// per wider breakpoint overriding only the declarations that change. The frame size is left to
+// the viewport. widths maps breakpoint names to their token width.
+func writeScreenRules(sb *strings.Builder, s extractor.Screen, widths map[string]float64) {
+	class := layoutClass(s.Name)
+	var previous map[string]string
+	for _, v := range s.Variants {
+		if v.Layout == nil {
+			continue
+		}
+
+		current := make(map[string]string)
+		var decls [][2]string
+		for _, d := range flexboxCSS(*v.Layout) {
+			if d[0] == "width" || d[0] == "height" {
+				continue
+			}
+			current[d[0]] = d[1]
+			if value, ok := previous[d[0]]; !ok || value != d[1] {
+				decls = append(decls, d)
+			}
+		}
+		for _, name := range sortedKeys(previous) {
+			if _, ok := current[name]; !ok {
+				decls = append(decls, [2]string{name, flexResets[name]})
+			}
+		}
+
+		if previous == nil {
+			sb.WriteString(fmt.Sprintf(".%s {\n", class))
+			for _, d := range decls {
+				sb.WriteString(fmt.Sprintf("  %s: %s;\n", d[0], d[1]))
+			}
+			sb.WriteString("}\n")
+		} else if len(decls) > 0 {
+			sb.WriteString(fmt.Sprintf("\n@media (min-width: %s) {\n  .%s {\n", px(widths[v.Breakpoint]), class))
+			for _, d := range decls {
+				sb.WriteString(fmt.Sprintf("    %s: %s;\n", d[0], d[1]))
+			}
+			sb.WriteString("  }\n}\n")
+		}
+		previous = current
+	}
+}
+
+// cssBorder formats a border style as a CSS border shorthand value ("1px dashed #E0E0E0").
+func cssBorder(b extractor.Border) string {
+	return fmt.Sprintf("%s %s %s", px(b.Width), b.Style, b.Color)
+}
+
+// borderNotes describes the parts of a border style the CSS shorthand cannot express: where the
+// stroke is drawn, its dash pattern, cap and join, and individual side weights.
+func borderNotes(b extractor.Border) []string {
+	var notes []string
+	switch b.Align {
+	case "OUTSIDE":
+		notes = append(notes, "outside: use outline or box-shadow to keep the box size")
+	case "CENTER":
+		notes = append(notes, "centered on the edge")
+	case "INSIDE":
+		notes = append(notes, "inside")
+	}
+	if len(b.Dashes) > 0 {
+		notes = append(notes, "dashes "+joinFloats(b.Dashes, " "))
+	}
+	if b.Cap != "" {
+		notes = append(notes, "cap "+strings.ToLower(b.Cap))
+	}
+	if b.Join != "" {
+		notes = append(notes, "join "+strings.ToLower(b.Join))
+	}
+	if len(b.Sides) == 4 {
+		notes = append(notes, "border-width "+cssBorderWidths(b.Sides))
+	}
+	return notes
+}
+
+// cssBorderWidths formats top, right, bottom and left weights as a CSS border-width value.
+func cssBorderWidths(sides []float64) string {
+	widths := make([]string, len(sides))
+	for i, w := range sides {
+		widths[i] = px(w)
+	}
+	return strings.Join(widths, " ")
+}
+
+// cssLineCap maps a Figma stroke cap onto a CSS/SVG stroke-linecap value.
+func cssLineCap(strokeCap string) string {
+	switch strokeCap {
+	case "ROUND":
+		return "round"
+	case "SQUARE":
+		return "square"
+	}
+	return "butt"
+}
+
+// cssShadow formats a shadow as a CSS box-shadow value.
+func cssShadow(shadow extractor.Shadow) string {
+	value := fmt.Sprintf("%.0fpx %.0fpx %.0fpx", shadow.X, shadow.Y, shadow.Blur)
+	if shadow.Spread > 0 {
+		value += fmt.Sprintf(" %.0fpx", shadow.Spread)
+	}
+	value += " " + shadow.Color
+	if shadow.Type == "INNER_SHADOW" {
+		value = "inset " + value
+	}
+	return value
+}
+
+// shadowTokens groups shadows by kebab-case name, in first-seen order. Layered shadows
+// sharing a name are combined into a single comma-separated CSS value; unnamed shadows
+// are numbered.
+func shadowTokens(shadows []extractor.Shadow) (names []string, values map[string]string) {
+	values = make(map[string]string)
+	for i, shadow := range shadows {
+		name := toKebabCase(shadow.Name)
+		if name == "" {
+			name = fmt.Sprintf("shadow-%d", i+1)
+		}
+		if existing, ok := values[name]; ok {
+			values[name] = existing + ", " + cssShadow(shadow)
+			continue
+		}
+		names = append(names, name)
+		values[name] = cssShadow(shadow)
+	}
+	return names, values
+}
+
+// blurTokens names blurs by kebab-case name, in first-seen order; background blurs get a
+// "-backdrop" suffix so a node may have both kinds. Unnamed blurs are numbered and later
+// blurs with an already used name are dropped.
+func blurTokens(blurs []extractor.Blur) (names []string, tokens map[string]extractor.Blur) {
+	tokens = make(map[string]extractor.Blur)
+	for i, blur := range blurs {
+		name := toKebabCase(blur.Name)
+		if name == "" {
+			name = fmt.Sprintf("blur-%d", i+1)
+		}
+		if blur.Type == "BACKGROUND_BLUR" {
+			name += "-backdrop"
+		}
+		if _, ok := tokens[name]; ok {
+			continue
+		}
+		names = append(names, name)
+		tokens[name] = blur
+	}
+	return names, tokens
+}
+
+// cssBlur formats a blur as a CSS blur() function. Figma's blur radius is twice the radius
+// CSS expects.
+func cssBlur(blur extractor.Blur) string {
+	return fmt.Sprintf("blur(%s)", px(blur.Radius/2))
+}
+
+// cssBlurProperty returns the CSS property that applies a blur: backdrop-filter for
+// background blurs and filter for layer blurs.
+func cssBlurProperty(blur extractor.Blur) string {
+	if blur.Type == "BACKGROUND_BLUR" {
+		return "backdrop-filter"
+	}
+	return "filter"
+}
+
+// cssBlendMode maps a Figma blend mode onto a CSS <blend-mode> value. LINEAR_BURN and
+// LINEAR_DODGE have no standard CSS equivalent and return "".
+func cssBlendMode(mode string) string {
+	switch mode {
+	case "LINEAR_BURN", "LINEAR_DODGE":
+		return ""
+	}
+	return strings.ReplaceAll(strings.ToLower(mode), "_", "-")
+}
+
+// themeCSSName returns the custom property name (without the leading dashes) of a themed
+// color. Variable-based themes reuse the variable names so they override the same properties.
+func themeCSSName(themes *extractor.ColorThemes, name string) string {
+	if themes.Source == "variables" {
+		return variableCSSName(name)
+	}
+	return "theme-" + strings.Join(tokenPath(name), "-")
+}
+
+// writeThemeBlocks writes the coordinated light and dark palettes as CSS: the dark palette
+// applies when the OS prefers a dark color scheme unless a [data-theme] attribute picks one
+// explicitly. Frame-based themes also get their light :root block and a [data-theme="dark"]
+// block; variable-based themes already have both from their modes.
+func writeThemeBlocks(sb *strings.Builder, themes *extractor.ColorThemes) {
+	names := sortedKeys(themes.Light)
+	block := func(selector, indent string, colors map[string]string) {
+		sb.WriteString(fmt.Sprintf("%s%s {\n", indent, selector))
+		for _, name := range names {
+			sb.WriteString(fmt.Sprintf("%s  --%s: %s;\n", indent, themeCSSName(themes, name), colors[name]))
+		}
+		sb.WriteString(indent + "}\n")
+	}
+
+	if themes.Source == "frames" {
+		block(":root", "", themes.Light)
+		sb.WriteString("\n")
+	}
+	sb.WriteString("@media (prefers-color-scheme: dark) {\n")
+	block(":root:not([data-theme])", "  ", themes.Dark)
+	sb.WriteString("}\n")
+	if themes.Source == "frames" {
+		sb.WriteString("\n")
+		block(`[data-theme="dark"]`, "", themes.Dark)
+	}
+}
+
+// closestValue returns the name of the value closest to target, preferring the
+// alphabetically first name on ties.
+func closestValue(values map[string]float64, target float64) string {
+	best := ""
+	bestDiff := math.Inf(1)
+	for _, name := range sortedKeys(values) {
+		if diff := math.Abs(values[name] - target); diff < bestDiff {
+			best, bestDiff = name, diff
+		}
+	}
+	return best
+}
+
+// firstColor returns the color with the alphabetically first name, or fallback when empty.
+func firstColor(colors map[string]string, fallback string) string {
+	keys := sortedKeys(colors)
+	if len(keys) == 0 {
+		return fallback
+	}
+	return colors[keys[0]]
+}
@@ -0,0 +1,375 @@
+package formatter
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"mime"
+	"path"
+	"strings"
+
+	"github.com/hellenic-development/figma-extractor/pkg/extractor"
+)
+
+// htmlTokenGroup is a group of tokens of the HTML report; Kind selects their preview.
+type htmlTokenGroup struct {
+	Label  string
+	Kind   string // "color", "space", "radius", "shadow", "border" or "" for no preview
+	Tokens []htmlToken
+}
+
+// htmlToken is a token of the HTML report: clicking it copies Var, its CSS custom property.
+type htmlToken struct {
+	Name  string
+	Var   string
+	Value string
+	CSS   template.CSS // the value as trusted CSS for the preview
+}
+
+// htmlTextStyle is a composite text style rendered as a font preview.
+type htmlTextStyle struct {
+	Name  string
+	Class string
+	Style template.CSS
+	Specs string
+}
+
+// htmlImage is an exported image, embedded as a data URL when it could be read.
+type htmlImage struct {
+	Name   string
+	Path   string
+	Src    template.URL
+	Format string
+	Scale  float64
+}
+
+// htmlReport is the value the HTML report template is executed with.
+type htmlReport struct {
+	FileName    string
+	Screenshots []htmlImage
+	Colors      []htmlTokenGroup
+	FontFamily  string
+	TextStyles  []htmlTextStyle
+	Tokens      []htmlTokenGroup
+	Components  []extractor.Component
+	Icons       []htmlIcon
+	Assets      []htmlImage
+}
+
+// htmlIcon is an icon rendered inline.
+type htmlIcon struct {
+	Name string
+	SVG  template.HTML
+}
+
+// ToHTML renders design specifications as a standalone, interactive HTML report: color
+// swatches and token values that copy their CSS custom property on click, previews of the
+// text styles, spacing, radii and shadows, and the design screenshot. readAsset, when not nil,
+// reads an exported image by file name to embed it; otherwise images link to imageDir.
+func ToHTML(specs *extractor.DesignSpecs, fileName, imageDir string, readAsset func(name string) ([]byte, error)) (string, error) {
+	report := htmlReport{
+		FileName:   fileName,
+		FontFamily: specs.Typography.FontFamily,
+		Components: specs.Components,
+	}
+
+	for _, asset := range specs.ExportedAssets {
+		img := htmlImage{Name: asset.NodeName, Path: asset.FileName, Format: strings.ToUpper(asset.Format), Scale: asset.Scale}
+		img.Src = template.URL(path.Join(imageDir, asset.FileName))
+		if asset.IsScreenshot {
+			if readAsset != nil {
+				if data, err := readAsset(asset.FileName); err == nil {
+					img.Src = dataURL(asset.FileName, data)
+				}
+			}
+			report.Screenshots = append(report.Screenshots, img)
+			continue
+		}
+		report.Assets = append(report.Assets, img)
+	}
+
+	for _, group := range colorGroups(specs.Colors) {
+		if len(group.Colors) == 0 {
+			continue
+		}
+		g := htmlTokenGroup{Label: group.Label, Kind: "color"}
+		for _, name := range sortedKeys(group.Colors) {
+			g.Tokens = append(g.Tokens, htmlCSSToken(name, "color-"+group.Prefix+toKebabCase(name), group.Colors[name]))
+		}
+		report.Colors = append(report.Colors, g)
+	}
+
+	for _, name := range sortedKeys(specs.TextStyles) {
+		var decls []string
+		for _, d := range textStyleCSS(specs.TextStyles[name], Units{}) {
+			decls = append(decls, d[0]+": "+cssSafe(d[1]))
+		}
+		ts := specs.TextStyles[name]
+		report.TextStyles = append(report.TextStyles, htmlTextStyle{
+			Name:  name,
+			Class: textStyleClass(name),
+			Style: template.CSS(strings.Join(decls, "; ")),
+			Specs: fmt.Sprintf("%s %gpx / %g", ts.FontFamily, ts.FontSize, ts.FontWeight),
+		})
+	}
+
+	tokenGroup := func(label, kind, prefix string, values map[string]float64) {
+		if len(values) == 0 {
+			return
+		}
+		g := htmlTokenGroup{Label: label, Kind: kind}
+		for _, name := range sortedKeys(values) {
+			g.Tokens = append(g.Tokens, htmlCSSToken(name, prefix+toKebabCase(name), px(values[name])))
+		}
+		report.Tokens = append(report.Tokens, g)
+	}
+	tokenGroup("Font Sizes", "", "text-", specs.Typography.FontSizes)
+	tokenGroup("Spacing", "space", "space-", specs.Spacing.Values)
+	tokenGroup("Border Radius", "radius", "radius-", specs.Radii.Values)
+	if len(specs.Borders) > 0 {
+		g := htmlTokenGroup{Label: "Borders", Kind: "border"}
+		for _, name := range sortedKeys(specs.Borders) {
+			g.Tokens = append(g.Tokens, htmlCSSToken(name, "border-"+toKebabCase(name), cssBorder(specs.Borders[name])))
+		}
+		report.Tokens = append(report.Tokens, g)
+	}
+	if len(specs.Shadows) > 0 {
+		g := htmlTokenGroup{Label: "Shadows", Kind: "shadow"}
+		names, values := shadowTokens(specs.Shadows)
+		for _, name := range names {
+			g.Tokens = append(g.Tokens, htmlCSSToken(name, "shadow-"+name, values[name]))
+		}
+		report.Tokens = append(report.Tokens, g)
+	}
+	if len(specs.Layout.Breakpoints) > 0 {
+		g := htmlTokenGroup{Label: "Breakpoints"}
+		for _, bp := range specs.Layout.Breakpoints {
+			g.Tokens = append(g.Tokens, htmlCSSToken(bp.Name, "breakpoint-"+bp.Name, px(bp.Width)))
+		}
+		report.Tokens = append(report.Tokens, g)
+	}
+
+	for _, icon := range specs.Icons {
+		// Icons are generated from vector paths by the extractor, not taken from the file.
+		report.Icons = append(report.Icons, htmlIcon{Name: icon.Name, SVG: template.HTML(icon.SVG)})
+	}
+
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, report); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// renderHTML adapts ToHTML to the renderFunc signature.
+func renderHTML(in Input) ([]File, error) {
+	out, err := ToHTML(in.Specs, in.FileName, in.ImageDir, in.ReadAsset)
+	if err != nil {
+		return nil, err
+	}
+	return []File{{Name: "FIGMA_DESIGN_SPECIFICATIONS.html", Content: []byte(out)}}, nil
+}
+
+// htmlCSSToken returns a token whose value is previewed as CSS.
+func htmlCSSToken(name, variable, value string) htmlToken {
+	return htmlToken{Name: name, Var: "var(--" + variable + ")", Value: value, CSS: template.CSS(cssSafe(value))}
+}
+
+// cssSafe drops the characters that could end a CSS declaration or the style attribute holding
+// it from a value built from names in the Figma file, such as font families.
+func cssSafe(value string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(";{}<>\"\\", r) {
+			return -1
+		}
+		return r
+	}, value)
+}
+
+// dataURL returns an image as a data URL, typed by the extension of its file name.
+func dataURL(name string, data []byte) template.URL {
+	mediaType := mime.TypeByExtension(path.Ext(name))
+	if mediaType == "" {
+		mediaType = "application/octet-stream"
+	}
+	return template.URL("data:" + mediaType + ";base64," + base64.StdEncoding.EncodeToString(data))
+}
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.FileName}} – Design Specifications</title>
+<style>
+  :root { color-scheme: light dark; --fg: #1a1a1a; --muted: #6b6b6b; --line: #e4e4e4; --card: #fff; --bg: #f7f7f8; }
+  @media (prefers-color-scheme: dark) { :root { --fg: #f0f0f0; --muted: #a0a0a0; --line: #333; --card: #1e1e1e; --bg: #121212; } }
+  * { box-sizing: border-box; }
+  body { margin: 0; font: 14px/1.5 system-ui, -apple-system, sans-serif; color: var(--fg); background: var(--bg); }
+  header { padding: 32px 40px 8px; }
+  header p { color: var(--muted); margin: 4px 0 0; }
+  nav { position: sticky; top: 0; z-index: 1; display: flex; gap: 16px; padding: 12px 40px; background: var(--bg); border-bottom: 1px solid var(--line); }
+  nav a { color: var(--muted); text-decoration: none; }
+  nav a:hover { color: var(--fg); }
+  main { padding: 8px 40px 64px; }
+  section { margin-top: 32px; }
+  h2 { font-size: 20px; margin: 0 0 12px; }
+  h3 { font-size: 14px; margin: 20px 0 8px; color: var(--muted); text-transform: uppercase; letter-spacing: .04em; }
+  .grid { display: grid; grid-template-columns: repeat(auto-fill, minmax(160px, 1fr)); gap: 12px; }
+  .card { background: var(--card); border: 1px solid var(--line); border-radius: 8px; padding: 12px; }
+  button.token { all: unset; cursor: pointer; display: block; }
+  button.token:focus-visible, button.token:hover { outline: 2px solid #4c8bf5; outline-offset: 2px; border-radius: 8px; }
+  .swatch { height: 64px; border-radius: 6px; border: 1px solid var(--line); margin-bottom: 8px; }
+  .name { font-weight: 600; word-break: break-word; }
+  .value { font-family: ui-monospace, monospace; font-size: 12px; color: var(--muted); word-break: break-all; }
+  .preview { height: 48px; margin-bottom: 8px; display: flex; align-items: center; }
+  .preview .space { height: 12px; background: #4c8bf5; border-radius: 2px; }
+  .preview .box { width: 48px; height: 40px; background: var(--card); border: 1px solid var(--line); }
+  .text-style { display: flex; justify-content: space-between; gap: 24px; align-items: baseline; }
+  .text-style .sample { overflow: hidden; text-overflow: ellipsis; white-space: nowrap; }
+  .screenshot img { max-width: 100%; border: 1px solid var(--line); border-radius: 8px; }
+  .icon svg { max-width: 48px; max-height: 48px; }
+  table { border-collapse: collapse; width: 100%; background: var(--card); }
+  th, td { text-align: left; padding: 6px 10px; border-bottom: 1px solid var(--line); vertical-align: top; }
+  #toast { position: fixed; bottom: 24px; left: 50%; transform: translateX(-50%); background: #1a1a1a; color: #fff; padding: 8px 16px; border-radius: 6px; opacity: 0; transition: opacity .2s; pointer-events: none; }
+  #toast.show { opacity: 1; }
+</style>
+</head>
+<body>
+<header>
+  <h1>{{.FileName}}</h1>
+  <p>Design specifications extracted from Figma. Click a token to copy it.</p>
+</header>
+<nav>
+  {{- if .Screenshots}}<a href="#screenshot">Screenshot</a>{{end}}
+  {{- if .Colors}}<a href="#colors">Colors</a>{{end}}
+  {{- if or .FontFamily .TextStyles}}<a href="#typography">Typography</a>{{end}}
+  {{- if .Tokens}}<a href="#tokens">Tokens</a>{{end}}
+  {{- if .Icons}}<a href="#icons">Icons</a>{{end}}
+  {{- if .Components}}<a href="#components">Components</a>{{end}}
+  {{- if .Assets}}<a href="#assets">Assets</a>{{end}}
+</nav>
+<main>
+{{- if .Screenshots}}
+<section id="screenshot" class="screenshot">
+  <h2>Design Screenshot</h2>
+  {{- range .Screenshots}}
+  <img src="{{.Src}}" alt="{{.Name}}" loading="lazy">
+  {{- end}}
+</section>
+{{- end}}
+{{- if .Colors}}
+<section id="colors">
+  <h2>Colors</h2>
+  {{- range .Colors}}
+  <h3>{{.Label}}</h3>
+  <div class="grid">
+    {{- range .Tokens}}
+    <button class="token card" data-copy="{{.Value}}" title="Copy {{.Value}}">
+      <div class="swatch" style="background: {{.CSS}}"></div>
+      <div class="name">{{.Name}}</div>
+      <div class="value">{{.Value}}</div>
+      <div class="value">{{.Var}}</div>
+    </button>
+    {{- end}}
+  </div>
+  {{- end}}
+</section>
+{{- end}}
+{{- if or .FontFamily .TextStyles}}
+<section id="typography">
+  <h2>Typography</h2>
+  {{- if .FontFamily}}
+  <button class="token card" data-copy="{{.FontFamily}}" title="Copy the font family">
+    <div class="name" style="font-family: '{{.FontFamily}}', system-ui, sans-serif; font-size: 28px">{{.FontFamily}}</div>
+    <div class="value">Aa Bb Cc Dd Ee Ff Gg 0123456789</div>
+  </button>
+  {{- end}}
+  {{- if .TextStyles}}
+  <h3>Text Styles</h3>
+  {{- range .TextStyles}}
+  <button class="token card text-style" data-copy="{{.Class}}" title="Copy the class name">
+    <span class="sample" style="{{.Style}}">{{.Name}}: The quick brown fox jumps over the lazy dog</span>
+    <span class="value">.{{.Class}} · {{.Specs}}</span>
+  </button>
+  {{- end}}
+  {{- end}}
+</section>
+{{- end}}
+{{- if .Tokens}}
+<section id="tokens">
+  <h2>Tokens</h2>
+  {{- range .Tokens}}
+  {{- $kind := .Kind}}
+  <h3>{{.Label}}</h3>
+  <div class="grid">
+    {{- range .Tokens}}
+    <button class="token card" data-copy="{{.Var}}" title="Copy {{.Var}}">
+      {{- if eq $kind "space"}}<div class="preview"><div class="space" style="width: {{.CSS}}"></div></div>{{end}}
+      {{- if eq $kind "radius"}}<div class="preview"><div class="box" style="border-radius: {{.CSS}}"></div></div>{{end}}
+      {{- if eq $kind "shadow"}}<div class="preview"><div class="box" style="box-shadow: {{.CSS}}"></div></div>{{end}}
+      {{- if eq $kind "border"}}<div class="preview"><div class="box" style="border: {{.CSS}}"></div></div>{{end}}
+      <div class="name">{{.Name}}</div>
+      <div class="value">{{.Value}}</div>
+      <div class="value">{{.Var}}</div>
+    </button>
+    {{- end}}
+  </div>
+  {{- end}}
+</section>
+{{- end}}
+{{- if .Icons}}
+<section id="icons">
+  <h2>Icons</h2>
+  <div class="grid">
+    {{- range .Icons}}
+    <div class="card icon">{{.SVG}}<div class="name">{{.Name}}</div></div>
+    {{- end}}
+  </div>
+</section>
+{{- end}}
+{{- if .Components}}
+<section id="components">
+  <h2>Components</h2>
+  <table>
+    <thead><tr><th>Name</th><th>Page</th><th>Size</th><th>Variants</th><th>Description</th></tr></thead>
+    <tbody>
+    {{- range .Components}}
+    <tr><td>{{.Name}}</td><td>{{.Page}}</td><td>{{.Width}} × {{.Height}}</td><td>{{if .VariantCount}}{{.VariantCount}}{{end}}</td><td>{{.Description}}</td></tr>
+    {{- end}}
+    </tbody>
+  </table>
+</section>
+{{- end}}
+{{- if .Assets}}
+<section id="assets">
+  <h2>Exported Assets</h2>
+  <table>
+    <thead><tr><th>Name</th><th>File</th><th>Format</th><th>Scale</th></tr></thead>
+    <tbody>
+    {{- range .Assets}}
+    <tr><td>{{.Name}}</td><td><a href="{{.Src}}">{{.Path}}</a></td><td>{{.Format}}</td><td>{{.Scale}}x</td></tr>
+    {{- end}}
+    </tbody>
+  </table>
+</section>
+{{- end}}
+</main>
+<div id="toast" role="status"></div>
+<script>
+  document.addEventListener("click", function (e) {
+    var token = e.target.closest("[data-copy]");
+    if (!token) return;
+    navigator.clipboard.writeText(token.dataset.copy).then(function () {
+      var toast = document.getElementById("toast");
+      toast.textContent = "Copied " + token.dataset.copy;
+      toast.classList.add("show");
+      clearTimeout(toast.timer);
+      toast.timer = setTimeout(function () { toast.classList.remove("show"); }, 1200);
+    });
+  });
+</script>
+</body>
+</html>
+`))
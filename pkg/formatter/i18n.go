@@ -0,0 +1,156 @@
+package formatter
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/hellenic-development/figma-extractor/pkg/extractor"
+)
+
+// i18nString is a string of the design with its localization key.
+type i18nString struct {
+	Key string
+	extractor.TextString
+}
+
+// i18nStrings returns the strings of specs with keys made of their frame path and layer name,
+// e.g. "onboarding.welcome.card.title", in document order. Repeated keys holding the same text
+// are merged; repeated keys holding other texts are numbered ("title-2").
+func i18nStrings(specs *extractor.DesignSpecs) []i18nString {
+	var strs []i18nString
+	texts := make(map[string]string) // key -> text
+	for _, t := range specs.Strings {
+		base := strings.Join(tokenPath(t.Path+"/"+t.Name), ".")
+		if base == "" {
+			base = "text-" + toKebabCase(t.NodeID)
+		}
+		key := base
+		for n := 2; ; n++ {
+			text, seen := texts[key]
+			if !seen {
+				break
+			}
+			if text == t.Text {
+				key = ""
+				break
+			}
+			key = fmt.Sprintf("%s-%d", base, n)
+		}
+		if key == "" {
+			continue
+		}
+		texts[key] = t.Text
+		strs = append(strs, i18nString{Key: key, TextString: t})
+	}
+	return strs
+}
+
+// ToI18nJSON renders the strings of the design as a flat JSON object mapping their keys, made of
+// their frame path and layer name (e.g. "onboarding.welcome.card.title"), onto their text,
+// sorted by key, as loaded by react-intl, i18next (with keySeparator: false) and most
+// translation management systems.
+func ToI18nJSON(specs *extractor.DesignSpecs) ([]byte, error) {
+	messages := make(map[string]string)
+	for _, s := range i18nStrings(specs) {
+		messages[s.Key] = s.Text
+	}
+	return json.MarshalIndent(messages, "", "  ")
+}
+
+// renderI18nJSON adapts ToI18nJSON to the renderFunc signature.
+func renderI18nJSON(in Input) ([]File, error) {
+	data, err := ToI18nJSON(in.Specs)
+	if err != nil {
+		return nil, fmt.Errorf("render i18n: %w", err)
+	}
+	return []File{{Name: "strings.json", Content: append(data, '\n')}}, nil
+}
+
+// ToPO renders the strings of the design as a gettext PO template, in document order: an entry
+// per string with its key, made of its frame path and layer name, as the message context, its
+// text as the message ID, and its path and node as extracted comments for translators.
+// Placeholder copy is pointed out to translators. Start a catalog per language from it with
+// msginit.
+func ToPO(specs *extractor.DesignSpecs, fileName string) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# Strings of the Figma file %s.\n", poComment(fileName)))
+	sb.WriteString("msgid \"\"\n")
+	sb.WriteString("msgstr \"\"\n")
+	sb.WriteString(fmt.Sprintf("\"Project-Id-Version: %s\\n\"\n", poEscape(fileName)))
+	sb.WriteString("\"MIME-Version: 1.0\\n\"\n")
+	sb.WriteString("\"Content-Type: text/plain; charset=UTF-8\\n\"\n")
+	sb.WriteString("\"Content-Transfer-Encoding: 8bit\\n\"\n")
+
+	for _, s := range i18nStrings(specs) {
+		sb.WriteString("\n")
+		if s.Path != "" {
+			sb.WriteString(fmt.Sprintf("#. %s / %s\n", poComment(s.Path), poComment(s.Name)))
+		} else {
+			sb.WriteString(fmt.Sprintf("#. %s\n", poComment(s.Name)))
+		}
+		sb.WriteString(fmt.Sprintf("#. Figma node %s\n", s.NodeID))
+		if s.Placeholder {
+			sb.WriteString("#. Placeholder copy, to be replaced by the final text\n")
+		}
+		sb.WriteString(fmt.Sprintf("msgctxt \"%s\"\n", poEscape(s.Key)))
+		sb.WriteString(fmt.Sprintf("msgid \"%s\"\n", poEscape(s.Text)))
+		sb.WriteString("msgstr \"\"\n")
+	}
+	return sb.String()
+}
+
+// poReplacer escapes the characters of quoted PO strings.
+var poReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+
+// poEscape escapes s for a quoted PO string.
+func poEscape(s string) string {
+	return poReplacer.Replace(s)
+}
+
+// poCommentReplacer folds the line breaks of PO comments into spaces.
+var poCommentReplacer = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ")
+
+// poComment returns s on a single line, as PO comments end at the line break.
+func poComment(s string) string {
+	return poCommentReplacer.Replace(s)
+}
+
+// renderPO adapts ToPO to the renderFunc signature.
+func renderPO(in Input) ([]File, error) {
+	return []File{{Name: "strings.pot", Content: []byte(ToPO(in.Specs, in.FileName))}}, nil
+}
+
+// ToXLIFF renders the strings of the design as an XLIFF 1.2 document with English as the source
+// language, in document order: a trans-unit per string with its key (see ToPO) as ID, its text
+// as source, and its path and node as notes; placeholder copy gets a high-priority note.
+func ToXLIFF(specs *extractor.DesignSpecs, fileName string) string {
+	var sb strings.Builder
+	sb.WriteString(xml.Header)
+	sb.WriteString("<xliff version=\"1.2\" xmlns=\"urn:oasis:names:tc:xliff:document:1.2\">\n")
+	sb.WriteString(fmt.Sprintf("  <file original=\"%s\" source-language=\"en\" datatype=\"plaintext\">\n", xmlEscape(fileName)))
+	sb.WriteString("    <body>\n")
+	for _, s := range i18nStrings(specs) {
+		sb.WriteString(fmt.Sprintf("      <trans-unit id=\"%s\" resname=\"%s\">\n", xmlEscape(s.Key), xmlEscape(s.Key)))
+		sb.WriteString(fmt.Sprintf("        <source xml:space=\"preserve\">%s</source>\n", xmlEscape(s.Text)))
+		location := s.Name
+		if s.Path != "" {
+			location = s.Path + " / " + s.Name
+		}
+		sb.WriteString(fmt.Sprintf("        <note from=\"figma\">%s (node %s)</note>\n", xmlEscape(location), xmlEscape(s.NodeID)))
+		if s.Placeholder {
+			sb.WriteString("        <note from=\"figma\" priority=\"1\">Placeholder copy, to be replaced by the final text</note>\n")
+		}
+		sb.WriteString("      </trans-unit>\n")
+	}
+	sb.WriteString("    </body>\n")
+	sb.WriteString("  </file>\n")
+	sb.WriteString("</xliff>\n")
+	return sb.String()
+}
+
+// renderXLIFF adapts ToXLIFF to the renderFunc signature.
+func renderXLIFF(in Input) ([]File, error) {
+	return []File{{Name: "strings.xliff", Content: []byte(ToXLIFF(in.Specs, in.FileName))}}, nil
+}
@@ -0,0 +1,61 @@
+package formatter
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hellenic-development/figma-extractor/pkg/extractor"
+)
+
+func TestI18nStrings(t *testing.T) {
+	specs := &extractor.DesignSpecs{Strings: []extractor.TextString{
+		{NodeID: "1:1", Name: "Title", Text: "Welcome", Path: "Onboarding / Welcome"},
+		{NodeID: "1:2", Name: "Title", Text: "Welcome", Path: "Onboarding / Welcome"},
+		{NodeID: "1:3", Name: "Title", Text: "Get started", Path: "Onboarding / Welcome"},
+		{NodeID: "1:4", Name: "Title", Text: "Sign in", Path: "Onboarding / Welcome"},
+		{NodeID: "1:5", Name: "Title", Text: "Get started", Path: "Onboarding / Welcome"},
+		{NodeID: "1:6", Name: "Body Copy", Text: "Hello", Path: "Onboarding / Welcome"},
+		{NodeID: "2:7", Name: "🙂", Text: "Smile"},
+	}}
+
+	var got []string
+	for _, s := range i18nStrings(specs) {
+		got = append(got, s.Key+"="+s.Text+"@"+s.NodeID)
+	}
+	want := []string{
+		"onboarding.welcome.title=Welcome@1:1",
+		"onboarding.welcome.title-2=Get started@1:3",
+		"onboarding.welcome.title-3=Sign in@1:4",
+		"onboarding.welcome.body-copy=Hello@1:6",
+		"text-27=Smile@2:7",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("i18nStrings() = %v, want %v", got, want)
+	}
+}
+
+func TestToPOEscapesLineBreaks(t *testing.T) {
+	specs := &extractor.DesignSpecs{Strings: []extractor.TextString{
+		{NodeID: "1:1", Name: "Title\nline", Text: "Say \"hi\"\nthere", Path: "Home\r\nHero"},
+		{NodeID: "1:2", Name: "Body\r", Text: "Hello"},
+	}}
+
+	po := ToPO(specs, "Design\nSystem")
+	for _, want := range []string{
+		"# Strings of the Figma file Design System.\n",
+		"\"Project-Id-Version: Design\\nSystem\\n\"\n",
+		"#. Home Hero / Title line\n",
+		"#. Body \n",
+		"msgid \"Say \\\"hi\\\"\\nthere\"\n",
+	} {
+		if !strings.Contains(po, want) {
+			t.Errorf("ToPO() = %q, want it to contain %q", po, want)
+		}
+	}
+	for i, line := range strings.Split(strings.TrimSuffix(po, "\n"), "\n") {
+		if line != "" && !strings.HasPrefix(line, "#") && !strings.HasPrefix(line, "msg") && !strings.HasPrefix(line, `"`) {
+			t.Errorf("ToPO() line %d = %q, not a comment, keyword or string", i+1, line)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package formatter
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hellenic-development/figma-extractor/pkg/extractor"
+)
+
+// ToJSON renders the complete design specifications as indented JSON in the versioned encoding
+// of extractor.DesignSpecs (see extractor.SchemaVersion and extractor.JSONSchema), for tools
+// that process the extracted values themselves.
+func ToJSON(specs *extractor.DesignSpecs) ([]byte, error) {
+	return json.MarshalIndent(specs, "", "  ")
+}
+
+// renderJSON adapts ToJSON to the renderFunc signature.
+func renderJSON(in Input) ([]File, error) {
+	data, err := ToJSON(in.Specs)
+	if err != nil {
+		return nil, fmt.Errorf("render json: %w", err)
+	}
+	return []File{{Name: "design-specs.json", Content: append(data, '\n')}}, nil
+}
@@ -2,18 +2,116 @@ package formatter
 
 import (
 	"fmt"
+	"html"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/hellenic-development/figma-extractor/pkg/extractor"
 )
 
+// markdownSections are the sections of the markdown report, in document order.
+var markdownSections = []string{
+	"screenshot", "colors", "typography", "fonts", "spacing", "radii", "borders", "shadows", "blurs",
+	"blend-modes", "variables", "layout", "components", "icons", "strings", "comments",
+	"interactions", "assets", "duplicates", "frames", "tree",
+}
+
+// MarkdownSections returns the names of the sections of the markdown report, in document order.
+func MarkdownSections() []string {
+	return append([]string(nil), markdownSections...)
+}
+
+// MarkdownOptions configures the markdown report.
+type MarkdownOptions struct {
+	ImageDir string // directory exported assets were written to, used for relative links
+
+	// Sections selects the sections of the report by name (see MarkdownSections). Names
+	// prefixed with "-" are left out instead: a list of exclusions keeps every other section.
+	// Empty includes every section.
+	Sections []string
+
+	// FrontMatter starts the report with YAML front matter: its title, the Figma file name,
+	// Version and ExtractedAt, for static-site generators such as Docusaurus and Hugo.
+	FrontMatter bool
+	Version     string    // Figma version ID of the file, written to the front matter
+	ExtractedAt time.Time // written to the front matter unless zero
+
+	// TOC adds a table of contents of the sections before the first one, and an anchor before
+	// every heading. Anchors are derived from the heading text, so links to them keep working
+	// between extractions.
+	TOC bool
+
+	Units Units // unit of the typography, spacing and radius tokens; zero = px
+}
+
+// sectionFilter returns whether the report includes a section. Unknown names are ignored; see
+// ValidateSections.
+func (o MarkdownOptions) sectionFilter() func(section string) bool {
+	if len(o.Sections) == 0 {
+		return func(string) bool { return true }
+	}
+	included := make(map[string]bool)
+	onlyExclusions := true
+	for _, name := range o.Sections {
+		if !strings.HasPrefix(name, "-") {
+			onlyExclusions = false
+		}
+	}
+	if onlyExclusions {
+		for _, name := range markdownSections {
+			included[name] = true
+		}
+	}
+	for _, name := range o.Sections {
+		if excluded, ok := strings.CutPrefix(name, "-"); ok {
+			delete(included, excluded)
+		} else {
+			included[name] = true
+		}
+	}
+	return func(section string) bool { return included[section] }
+}
+
+// ValidateSections reports an error for section names that are not sections of the markdown
+// report, with or without a leading "-".
+func ValidateSections(sections []string) error {
+	for _, name := range sections {
+		if !slices.Contains(markdownSections, strings.TrimPrefix(name, "-")) {
+			return fmt.Errorf("unknown report section %q (must be one of %s)", name, strings.Join(markdownSections, ", "))
+		}
+	}
+	return nil
+}
+
 // ToMarkdown transforms extracted design specifications into a well-formatted markdown document.
 // The output includes CSS variable definitions for colors, typography, spacing, shadows, border radii,
 // and layout specifications, ready to be integrated into a design system or CSS framework.
 func ToMarkdown(specs *extractor.DesignSpecs, fileName string, imageDir ...string) string {
+	var opts MarkdownOptions
+	if len(imageDir) > 0 {
+		opts.ImageDir = imageDir[0]
+	}
+	return ToMarkdownWithOptions(specs, fileName, opts)
+}
+
+// ToMarkdownWithOptions is like ToMarkdown, configured by opts.
+func ToMarkdownWithOptions(specs *extractor.DesignSpecs, fileName string, opts MarkdownOptions) string {
 	assetDir := ""
-	if len(imageDir) > 0 && imageDir[0] != "" {
-		assetDir = imageDir[0] + "/"
+	if opts.ImageDir != "" {
+		assetDir = opts.ImageDir + "/"
+	}
+	include := opts.sectionFilter()
+
+	// Sizes are rounded to whole pixels unless emitted in rem.
+	dimension := func(v float64) string {
+		if opts.Units.Rem {
+			return opts.Units.size(v)
+		}
+		return fmt.Sprintf("%.0fpx", v)
 	}
 
 	var sb strings.Builder
@@ -23,132 +121,219 @@ func ToMarkdown(specs *extractor.DesignSpecs, fileName string, imageDir ...strin
 
 	// Include the complete design screenshot at the top so AI vision models can reference it.
 	for _, asset := range specs.ExportedAssets {
-		if asset.IsScreenshot {
+		// Per-frame reports show the screenshot of every frame in its section instead.
+		if asset.IsScreenshot && include("screenshot") && len(specs.Frames) == 0 {
 			sb.WriteString("## Complete Design Screenshot\n\n")
 			sb.WriteString(fmt.Sprintf("![Complete Design Screenshot](%s%s)\n\n", assetDir, asset.FileName))
 			break
 		}
 	}
 
+	if include("colors") || include("typography") || include("fonts") || include("spacing") || include("radii") || include("borders") ||
+		include("shadows") || include("blurs") || include("blend-modes") || include("variables") {
+		sb.WriteString("## Design System\n\n")
+	}
+
 	// Colors
-	sb.WriteString("## Design System\n\n")
-	sb.WriteString("### Color Palette\n\n")
-	sb.WriteString("```css\n")
+	if include("colors") {
+		sb.WriteString("### Color Palette\n\n")
+		sb.WriteString("```css\n")
 
-	if len(specs.Colors.Primary) > 0 {
-		sb.WriteString("/* Primary Colors */\n")
-		for name, color := range specs.Colors.Primary {
-			cssName := toKebabCase(name)
-			sb.WriteString(fmt.Sprintf("--color-primary-%s: %s;\n", cssName, color))
+		if len(specs.Colors.Primary) > 0 {
+			sb.WriteString("/* Primary Colors */\n")
+			for _, name := range sortedKeys(specs.Colors.Primary) {
+				color := specs.Colors.Primary[name]
+				cssName := toKebabCase(name)
+				sb.WriteString(fmt.Sprintf("--color-primary-%s: %s;\n", cssName, color))
+			}
+			sb.WriteString("\n")
 		}
-		sb.WriteString("\n")
-	}
 
-	if len(specs.Colors.Secondary) > 0 {
-		sb.WriteString("/* Secondary Colors */\n")
-		for name, color := range specs.Colors.Secondary {
-			cssName := toKebabCase(name)
-			sb.WriteString(fmt.Sprintf("--color-secondary-%s: %s;\n", cssName, color))
+		if len(specs.Colors.Secondary) > 0 {
+			sb.WriteString("/* Secondary Colors */\n")
+			for _, name := range sortedKeys(specs.Colors.Secondary) {
+				color := specs.Colors.Secondary[name]
+				cssName := toKebabCase(name)
+				sb.WriteString(fmt.Sprintf("--color-secondary-%s: %s;\n", cssName, color))
+			}
+			sb.WriteString("\n")
 		}
-		sb.WriteString("\n")
-	}
 
-	if len(specs.Colors.Background) > 0 {
-		sb.WriteString("/* Background Colors */\n")
-		for name, color := range specs.Colors.Background {
-			cssName := toKebabCase(name)
-			sb.WriteString(fmt.Sprintf("--color-bg-%s: %s;\n", cssName, color))
+		if len(specs.Colors.Background) > 0 {
+			sb.WriteString("/* Background Colors */\n")
+			for _, name := range sortedKeys(specs.Colors.Background) {
+				color := specs.Colors.Background[name]
+				cssName := toKebabCase(name)
+				sb.WriteString(fmt.Sprintf("--color-bg-%s: %s;\n", cssName, color))
+			}
+			sb.WriteString("\n")
 		}
-		sb.WriteString("\n")
-	}
 
-	if len(specs.Colors.Text) > 0 {
-		sb.WriteString("/* Text Colors */\n")
-		for name, color := range specs.Colors.Text {
-			cssName := toKebabCase(name)
-			sb.WriteString(fmt.Sprintf("--color-text-%s: %s;\n", cssName, color))
+		if len(specs.Colors.Text) > 0 {
+			sb.WriteString("/* Text Colors */\n")
+			for _, name := range sortedKeys(specs.Colors.Text) {
+				color := specs.Colors.Text[name]
+				cssName := toKebabCase(name)
+				sb.WriteString(fmt.Sprintf("--color-text-%s: %s;\n", cssName, color))
+			}
+			sb.WriteString("\n")
 		}
-		sb.WriteString("\n")
-	}
 
-	if len(specs.Colors.Status) > 0 {
-		sb.WriteString("/* Status Colors */\n")
-		for name, color := range specs.Colors.Status {
-			cssName := toKebabCase(name)
-			sb.WriteString(fmt.Sprintf("--color-%s: %s;\n", cssName, color))
+		if len(specs.Colors.Status) > 0 {
+			sb.WriteString("/* Status Colors */\n")
+			for _, name := range sortedKeys(specs.Colors.Status) {
+				color := specs.Colors.Status[name]
+				cssName := toKebabCase(name)
+				sb.WriteString(fmt.Sprintf("--color-%s: %s;\n", cssName, color))
+			}
+			sb.WriteString("\n")
 		}
-		sb.WriteString("\n")
-	}
 
-	if len(specs.Colors.Border) > 0 {
-		sb.WriteString("/* Border Colors */\n")
-		for name, color := range specs.Colors.Border {
-			cssName := toKebabCase(name)
-			sb.WriteString(fmt.Sprintf("--color-border-%s: %s;\n", cssName, color))
+		if len(specs.Colors.Border) > 0 {
+			sb.WriteString("/* Border Colors */\n")
+			for _, name := range sortedKeys(specs.Colors.Border) {
+				color := specs.Colors.Border[name]
+				cssName := toKebabCase(name)
+				sb.WriteString(fmt.Sprintf("--color-border-%s: %s;\n", cssName, color))
+			}
+			sb.WriteString("\n")
 		}
-		sb.WriteString("\n")
-	}
 
-	sb.WriteString("```\n\n")
+		// Colors without a meaningful name, classified by hue and lightness.
+		for _, group := range colorGroups(specs.Colors) {
+			if !group.Inferred || len(group.Colors) == 0 {
+				continue
+			}
+			sb.WriteString(fmt.Sprintf("/* %s */\n", group.Label))
+			for _, name := range sortedKeys(group.Colors) {
+				color := group.Colors[name]
+				sb.WriteString(fmt.Sprintf("--color-%s%s: %s; /* used %d× */\n", group.Prefix, name, color, specs.Colors.Usage[color]))
+			}
+			sb.WriteString("\n")
+		}
 
-	// Typography
-	sb.WriteString("### Typography\n\n")
-	sb.WriteString("```css\n")
+		sb.WriteString("```\n\n")
 
-	if specs.Typography.FontFamily != "" {
-		sb.WriteString(fmt.Sprintf("/* Font Family */\n--font-primary: '%s', system-ui, -apple-system, sans-serif;\n\n", specs.Typography.FontFamily))
+		// Light and dark themes
+		if specs.Themes != nil {
+			sb.WriteString("### Light & Dark Themes\n\n")
+			source := "parallel light and dark frames"
+			if specs.Themes.Source == "variables" {
+				source = "the light and dark modes of the file's color variables"
+			}
+			sb.WriteString(fmt.Sprintf("Detected from %s. The dark palette follows the operating system's color scheme unless `data-theme` is set:\n\n", source))
+			sb.WriteString("| Color | Light | Dark |\n")
+			sb.WriteString("|-------|-------|------|\n")
+			for _, name := range sortedKeys(specs.Themes.Light) {
+				sb.WriteString(fmt.Sprintf("| %s | %s | %s |\n", markdownCell(name), specs.Themes.Light[name], specs.Themes.Dark[name]))
+			}
+			sb.WriteString("\n```css\n")
+			writeThemeBlocks(&sb, specs.Themes)
+			sb.WriteString("```\n\n")
+		}
 	}
 
-	if len(specs.Typography.FontSizes) > 0 {
-		sb.WriteString("/* Font Sizes */\n")
-		for name, size := range specs.Typography.FontSizes {
-			sb.WriteString(fmt.Sprintf("--text-%s: %.0fpx;\n", name, size))
+	// Typography
+	if include("typography") {
+		sb.WriteString("### Typography\n\n")
+		sb.WriteString("```css\n")
+
+		if specs.Typography.FontFamily != "" {
+			sb.WriteString(fmt.Sprintf("/* Font Family */\n--font-primary: '%s', system-ui, -apple-system, sans-serif;\n\n", specs.Typography.FontFamily))
 		}
-		sb.WriteString("\n")
-	}
 
-	if len(specs.Typography.FontWeights) > 0 {
-		sb.WriteString("/* Font Weights */\n")
-		for name, weight := range specs.Typography.FontWeights {
-			sb.WriteString(fmt.Sprintf("--font-%s: %.0f;\n", toKebabCase(name), weight))
+		if len(specs.Typography.FontSizes) > 0 {
+			sb.WriteString("/* Font Sizes */\n")
+			for _, name := range sortedKeys(specs.Typography.FontSizes) {
+				size := specs.Typography.FontSizes[name]
+				sb.WriteString(fmt.Sprintf("--text-%s: %s;\n", name, dimension(size)))
+			}
+			sb.WriteString("\n")
+		}
+
+		if len(specs.Typography.FontWeights) > 0 {
+			sb.WriteString("/* Font Weights */\n")
+			for _, name := range sortedKeys(specs.Typography.FontWeights) {
+				weight := specs.Typography.FontWeights[name]
+				sb.WriteString(fmt.Sprintf("--font-%s: %.0f;\n", toKebabCase(name), weight))
+			}
+			sb.WriteString("\n")
 		}
-		sb.WriteString("\n")
-	}
 
-	if len(specs.Typography.LineHeights) > 0 {
-		sb.WriteString("/* Line Heights */\n")
-		for name, height := range specs.Typography.LineHeights {
-			sb.WriteString(fmt.Sprintf("--leading-%s: %.0fpx;\n", toKebabCase(name), height))
+		if len(specs.Typography.LineHeights) > 0 {
+			sb.WriteString("/* Line Heights */\n")
+			for _, name := range sortedKeys(specs.Typography.LineHeights) {
+				height := specs.Typography.LineHeights[name]
+				sb.WriteString(fmt.Sprintf("--leading-%s: %s;\n", toKebabCase(name), dimension(height)))
+			}
+			sb.WriteString("\n")
+		}
+
+		for _, detail := range textDetails(specs.Typography, nil, opts.Units) {
+			sb.WriteString(fmt.Sprintf("/* %s */\n", detail.Label))
+			for _, d := range detail.Decls {
+				sb.WriteString(fmt.Sprintf("--%s: %s;\n", d[0], d[1]))
+			}
+			sb.WriteString("\n")
+		}
+
+		sb.WriteString("```\n\n")
+
+		// Text styles
+		if len(specs.TextStyles) > 0 {
+			sb.WriteString("### Text Styles\n\n")
+			sb.WriteString("```css\n")
+			writeTextStyleRules(&sb, specs.TextStyles, ".%s", opts.Units)
+			sb.WriteString("```\n\n")
 		}
-		sb.WriteString("\n")
 	}
 
-	sb.WriteString("```\n\n")
+	// Fonts
+	if len(specs.Typography.Fonts) > 0 && include("fonts") {
+		writeFonts(&sb, specs.Typography.Fonts)
+	}
 
 	// Spacing
-	if len(specs.Spacing.Values) > 0 {
+	if len(specs.Spacing.Values) > 0 && include("spacing") {
 		sb.WriteString("### Spacing\n\n")
 		sb.WriteString("```css\n")
 		sb.WriteString("/* Spacing Scale */\n")
-		for name, value := range specs.Spacing.Values {
-			sb.WriteString(fmt.Sprintf("--space-%s: %.0fpx;\n", name, value))
+		for _, name := range sortedKeys(specs.Spacing.Values) {
+			value := specs.Spacing.Values[name]
+			sb.WriteString(fmt.Sprintf("--space-%s: %s;\n", name, dimension(value)))
 		}
 		sb.WriteString("```\n\n")
 	}
 
 	// Border Radii
-	if len(specs.Radii.Values) > 0 {
+	if len(specs.Radii.Values) > 0 && include("radii") {
 		sb.WriteString("### Border Radius\n\n")
 		sb.WriteString("```css\n")
-		for name, radius := range specs.Radii.Values {
-			sb.WriteString(fmt.Sprintf("--radius-%s: %.0fpx;\n", name, radius))
+		for _, name := range sortedKeys(specs.Radii.Values) {
+			radius := specs.Radii.Values[name]
+			sb.WriteString(fmt.Sprintf("--radius-%s: %s;\n", name, dimension(radius)))
 		}
 		sb.WriteString("--radius-full: 9999px; /* Full radius (circles) */\n")
 		sb.WriteString("```\n\n")
 	}
 
+	// Border styles
+	if len(specs.Borders) > 0 && include("borders") {
+		sb.WriteString("### Borders\n\n")
+		sb.WriteString("```css\n")
+		for _, name := range sortedKeys(specs.Borders) {
+			b := specs.Borders[name]
+			sb.WriteString(fmt.Sprintf("--border-%s: %s;", toKebabCase(name), cssBorder(b)))
+			if notes := borderNotes(b); len(notes) > 0 {
+				sb.WriteString(" /* " + strings.Join(notes, "; ") + " */")
+			}
+			sb.WriteString("\n")
+		}
+		sb.WriteString("```\n\n")
+	}
+
 	// Shadows
-	if len(specs.Shadows) > 0 {
+	if len(specs.Shadows) > 0 && include("shadows") {
 		sb.WriteString("### Shadows\n\n")
 		sb.WriteString("```css\n")
 		for i, shadow := range specs.Shadows {
@@ -168,23 +353,122 @@ func ToMarkdown(specs *extractor.DesignSpecs, fileName string, imageDir ...strin
 		sb.WriteString("```\n\n")
 	}
 
+	// Blurs
+	if len(specs.Blurs) > 0 && include("blurs") {
+		sb.WriteString("### Blurs\n\n")
+		sb.WriteString("```css\n")
+		names, blurs := blurTokens(specs.Blurs)
+		for _, name := range names {
+			sb.WriteString(fmt.Sprintf("--blur-%s: %s; /* %s: var(--blur-%s) */\n",
+				name, cssBlur(blurs[name]), cssBlurProperty(blurs[name]), name))
+		}
+		sb.WriteString("```\n\n")
+	}
+
+	// Blend modes change how colors look; warn so they are not reproduced as plain colors.
+	if len(specs.BlendModes) > 0 && include("blend-modes") {
+		writeBlendModes(&sb, specs.BlendModes)
+	}
+
+	// Variables, one token set per mode.
+	if len(specs.Variables) > 0 && include("variables") {
+		sb.WriteString("### Variables\n\n")
+		for _, coll := range specs.Variables {
+			sb.WriteString(fmt.Sprintf("#### %s\n\n", coll.Name))
+			sb.WriteString("```css\n")
+			for i, mode := range coll.Modes {
+				if i > 0 {
+					sb.WriteString("\n")
+				}
+				writeVariableMode(&sb, mode)
+			}
+			sb.WriteString("```\n\n")
+		}
+	}
+
 	// Layout
-	sb.WriteString("## Layout Specifications\n\n")
-	sb.WriteString("### Main Layout\n\n")
+	if include("layout") {
+		sb.WriteString("## Layout Specifications\n\n")
+		sb.WriteString("### Main Layout\n\n")
+
+		if specs.Layout.HeaderHeight > 0 {
+			sb.WriteString(fmt.Sprintf("- **Header Height**: %.0fpx\n", specs.Layout.HeaderHeight))
+		}
 
-	if specs.Layout.HeaderHeight > 0 {
-		sb.WriteString(fmt.Sprintf("- **Header Height**: %.0fpx\n", specs.Layout.HeaderHeight))
+		if specs.Layout.SidebarWidth > 0 {
+			sb.WriteString(fmt.Sprintf("- **Sidebar Width**: %.0fpx\n", specs.Layout.SidebarWidth))
+		}
+
+		if specs.Layout.ContentPadding > 0 {
+			sb.WriteString(fmt.Sprintf("- **Content Padding**: %.0fpx\n", specs.Layout.ContentPadding))
+		}
+
+		sb.WriteString("\n")
+
+		if len(specs.Layout.AutoLayouts) > 0 {
+			sb.WriteString("### Auto Layout\n\n")
+			sb.WriteString("Flexbox reconstruction of the auto-layout frames, one class per frame (named after the layer):\n\n")
+			sb.WriteString("```css\n")
+			writeAutoLayoutRules(&sb, specs.Layout.AutoLayouts)
+			sb.WriteString("```\n\n")
+		}
+
+		if len(specs.Layout.Resizing) > 0 {
+			writeResizing(&sb, specs.Layout.Resizing)
+		}
+
+		if len(specs.Layout.Screens) > 0 {
+			writeBreakpoints(&sb, specs.Layout.Breakpoints, specs.Layout.Screens)
+		}
+
+		if len(specs.Layout.Grids) > 0 {
+			writeGrids(&sb, specs.Layout.Grids)
+		}
 	}
 
-	if specs.Layout.SidebarWidth > 0 {
-		sb.WriteString(fmt.Sprintf("- **Sidebar Width**: %.0fpx\n", specs.Layout.SidebarWidth))
+	// Component catalog
+	if len(specs.Components) > 0 && include("components") {
+		writeComponentCatalog(&sb, specs.Components)
+
+		// Prop tables
+		for _, c := range specs.Components {
+			if len(c.Properties) == 0 {
+				continue
+			}
+			sb.WriteString(fmt.Sprintf("### %s\n\n", c.Name))
+			sb.WriteString("| Property | Type | Default | Options |\n")
+			sb.WriteString("|----------|------|---------|---------|\n")
+			for _, p := range c.Properties {
+				options := "-"
+				if len(p.Options) > 0 {
+					options = strings.Join(p.Options, ", ")
+				}
+				sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n",
+					markdownCell(p.Name), strings.ToLower(p.Type), markdownCell(p.Default), markdownCell(options)))
+			}
+			sb.WriteString("\n")
+		}
 	}
 
-	if specs.Layout.ContentPadding > 0 {
-		sb.WriteString(fmt.Sprintf("- **Content Padding**: %.0fpx\n", specs.Layout.ContentPadding))
+	// Inline SVG icons
+	if len(specs.Icons) > 0 && include("icons") {
+		writeIcons(&sb, specs.Icons)
 	}
 
-	sb.WriteString("\n")
+	// Copy deck
+	if len(specs.Strings) > 0 && include("strings") {
+		writeStrings(&sb, specs.Strings)
+	}
+
+	// Open design questions
+	if len(specs.Comments) > 0 && include("comments") {
+		writeComments(&sb, specs.Comments)
+	}
+
+	// Prototype flows and interactions
+	if (len(specs.Flows) > 0 || len(specs.Interactions) > 0) && include("interactions") {
+		writeInteractions(&sb, specs.Flows, specs.Interactions)
+	}
 
 	// Exported Assets (exclude screenshots, they are shown at the top).
 	var exportedAssets []extractor.ExportedAssetInfo
@@ -193,7 +477,7 @@ func ToMarkdown(specs *extractor.DesignSpecs, fileName string, imageDir ...strin
 			exportedAssets = append(exportedAssets, asset)
 		}
 	}
-	if len(exportedAssets) > 0 {
+	if len(exportedAssets) > 0 && include("assets") {
 		sb.WriteString("## Exported Assets\n\n")
 		sb.WriteString("| Asset | File | Format | Scale |\n")
 		sb.WriteString("|-------|------|--------|-------|\n")
@@ -207,8 +491,18 @@ func ToMarkdown(specs *extractor.DesignSpecs, fileName string, imageDir ...strin
 		sb.WriteString("\n")
 	}
 
+	// Duplicate frames
+	if len(specs.Duplicates) > 0 && include("duplicates") {
+		writeDuplicates(&sb, specs.Duplicates)
+	}
+
 	// Component Tree
-	if len(specs.NodeTree) > 0 {
+	// One section per extracted frame
+	if len(specs.Frames) > 0 && include("frames") {
+		writeFrames(&sb, specs.Frames, specs.ExportedAssets, assetDir)
+	}
+
+	if len(specs.NodeTree) > 0 && include("tree") {
 		sb.WriteString("## Component Tree\n\n")
 		sb.WriteString("Hierarchical node descriptions. Each indented line is a child.\n")
 		sb.WriteString("Format: `[TYPE] Name WxH | property:value ...`\n\n")
@@ -219,7 +513,141 @@ func ToMarkdown(specs *extractor.DesignSpecs, fileName string, imageDir ...strin
 		sb.WriteString("```\n\n")
 	}
 
-	return sanitizeLineTerminators(sb.String())
+	out := sanitizeLineTerminators(sb.String())
+	if opts.TOC {
+		out = addTableOfContents(out)
+	}
+	if opts.FrontMatter {
+		out = markdownFrontMatter(fileName, opts) + out
+	}
+	return out
+}
+
+// markdownFrontMatter returns the YAML front matter of a report.
+func markdownFrontMatter(fileName string, opts MarkdownOptions) string {
+	var sb strings.Builder
+	sb.WriteString("---\n")
+	sb.WriteString("title: " + strconv.Quote("Figma Design Specifications - "+fileName) + "\n")
+	sb.WriteString("figma_file: " + strconv.Quote(fileName) + "\n")
+	if opts.Version != "" {
+		sb.WriteString("figma_version: " + strconv.Quote(opts.Version) + "\n")
+	}
+	if !opts.ExtractedAt.IsZero() {
+		sb.WriteString("extracted_at: " + opts.ExtractedAt.UTC().Format(time.RFC3339) + "\n")
+	}
+	sb.WriteString("---\n\n")
+	return sb.String()
+}
+
+// addTableOfContents inserts an anchor before every second- and third-level heading of a
+// report, outside code blocks, and a table of contents linking to them before the first one.
+func addTableOfContents(md string) string {
+	lines := strings.Split(md, "\n")
+	var body, toc []string
+	first := -1 // index in body of the first section
+	used := make(map[string]int)
+	inCode := false
+	for _, line := range lines {
+		if strings.HasPrefix(line, "```") {
+			inCode = !inCode
+		}
+		level := 0
+		switch {
+		case inCode:
+		case strings.HasPrefix(line, "## "):
+			level = 2
+		case strings.HasPrefix(line, "### "):
+			level = 3
+		}
+		if level == 0 {
+			body = append(body, line)
+			continue
+		}
+
+		title := strings.TrimSpace(line[level+1:])
+		anchor := toKebabCase(title)
+		if anchor == "" {
+			anchor = "section"
+		}
+		if used[anchor]++; used[anchor] > 1 {
+			anchor = fmt.Sprintf("%s-%d", anchor, used[anchor])
+		}
+		if first < 0 {
+			first = len(body)
+		}
+		toc = append(toc, fmt.Sprintf("%s- [%s](#%s)", strings.Repeat("  ", level-2), strings.ReplaceAll(title, "]", "\\]"), anchor))
+		body = append(body, fmt.Sprintf(`<a id="%s"></a>`, anchor), "", line)
+	}
+	if first < 0 {
+		return md
+	}
+
+	out := make([]string, 0, len(body)+len(toc)+3)
+	out = append(out, body[:first]...)
+	out = append(out, "## Contents", "")
+	out = append(out, toc...)
+	out = append(out, "")
+	out = append(out, body[first:]...)
+	return strings.Join(out, "\n")
+}
+
+// writeVariableMode renders the variables of a single mode as a CSS rule. The default mode
+// targets :root, other modes a [data-theme="<mode>"] selector so they can be switched at runtime.
+// Aliases refer to the variable they alias, with the resolved value in a comment.
+func writeVariableMode(sb *strings.Builder, mode extractor.VariableMode) {
+	selector := fmt.Sprintf("[data-theme=\"%s\"]", toKebabCase(mode.Name))
+	label := mode.Name
+	if mode.Default {
+		selector = ":root"
+		label += " (default)"
+	}
+
+	sb.WriteString(fmt.Sprintf("/* %s */\n", label))
+	sb.WriteString(selector + " {\n")
+	for _, v := range mode.Variables {
+		if len(v.Aliases) > 0 {
+			sb.WriteString(fmt.Sprintf("  --%s: %s; /* %s */\n", variableCSSName(v.Name), variableCSSReference(v), variableCSSValue(v)))
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("  --%s: %s;\n", variableCSSName(v.Name), variableCSSValue(v)))
+	}
+	sb.WriteString("}\n")
+}
+
+// variableCSSName converts a slash-separated Figma variable name ("bg/primary") into a
+// CSS custom property name without the leading dashes ("bg-primary").
+func variableCSSName(name string) string {
+	return toKebabCase(name)
+}
+
+// variableCSSReference formats a variable value for use in CSS: a var() reference to the
+// variable it aliases, so semantic tokens follow their primitives, or its resolved value.
+func variableCSSReference(v extractor.Variable) string {
+	if len(v.Aliases) > 0 {
+		return fmt.Sprintf("var(--%s)", variableCSSName(v.Aliases[0].Name))
+	}
+	return variableCSSValue(v)
+}
+
+// variableCSSValue formats a resolved variable value for use in CSS.
+func variableCSSValue(v extractor.Variable) string {
+	switch v.Type {
+	case "COLOR":
+		return v.Color
+	case "FLOAT":
+		if v.Dimension {
+			return fmt.Sprintf("%gpx", v.Number)
+		}
+		return fmt.Sprintf("%g", v.Number)
+	case "STRING":
+		return fmt.Sprintf("%q", v.String)
+	case "BOOLEAN":
+		if v.Bool {
+			return "1"
+		}
+		return "0"
+	}
+	return ""
 }
 
 // sanitizeLineTerminators replaces Unicode Line Separator (U+2028) and
@@ -268,6 +696,12 @@ func renderNodeDescription(sb *strings.Builder, node *extractor.NodeDescription,
 		if node.StrokeWeight > 0 {
 			s += fmt.Sprintf(" %.0fpx", node.StrokeWeight)
 		}
+		if len(node.StrokeDashes) > 0 {
+			s += " dashed(" + joinFloats(node.StrokeDashes, ",") + ")"
+		}
+		if node.StrokeAlign != "" {
+			s += " " + strings.ToLower(node.StrokeAlign)
+		}
 		parts = append(parts, s)
 	}
 
@@ -297,9 +731,18 @@ func renderNodeDescription(sb *strings.Builder, node *extractor.NodeDescription,
 		}
 		parts = append(parts, f)
 	}
+	if node.LetterSpacing != 0 {
+		parts = append(parts, fmt.Sprintf("tracking:%gpx", node.LetterSpacing))
+	}
 	if node.TextAlignHorizontal != "" {
 		parts = append(parts, "align:"+node.TextAlignHorizontal)
 	}
+	if node.TextCase != "" && node.TextCase != "ORIGINAL" {
+		parts = append(parts, "case:"+node.TextCase)
+	}
+	if node.TextDecoration != "" && node.TextDecoration != "NONE" {
+		parts = append(parts, "decoration:"+node.TextDecoration)
+	}
 
 	// Layout
 	if node.LayoutMode != "" {
@@ -309,8 +752,56 @@ func renderNodeDescription(sb *strings.Builder, node *extractor.NodeDescription,
 		parts = append(parts, fmt.Sprintf("pad:%.0f,%.0f,%.0f,%.0f",
 			node.PaddingTop, node.PaddingRight, node.PaddingBottom, node.PaddingLeft))
 	}
-	if node.ItemSpacing > 0 {
-		parts = append(parts, fmt.Sprintf("gap:%.0f", node.ItemSpacing))
+	if node.ItemSpacing > 0 || node.CounterAxisSpacing > 0 {
+		gap := fmt.Sprintf("gap:%.0f", node.ItemSpacing)
+		if node.CounterAxisSpacing > 0 {
+			gap += fmt.Sprintf("/%.0f", node.CounterAxisSpacing)
+		}
+		parts = append(parts, gap)
+	}
+	if node.LayoutMode != "" {
+		if node.PrimaryAxisAlignItems != "" || node.CounterAxisAlignItems != "" {
+			parts = append(parts, fmt.Sprintf("axis-align:%s/%s",
+				orDefault(node.PrimaryAxisAlignItems, "MIN"), orDefault(node.CounterAxisAlignItems, "MIN")))
+		}
+		if node.PrimaryAxisSizingMode != "" || node.CounterAxisSizingMode != "" {
+			parts = append(parts, fmt.Sprintf("sizing:%s/%s",
+				orDefault(node.PrimaryAxisSizingMode, "AUTO"), orDefault(node.CounterAxisSizingMode, "AUTO")))
+		}
+	}
+	if node.LayoutWrap == "WRAP" {
+		parts = append(parts, "wrap")
+	}
+	if node.LayoutGrow > 0 {
+		parts = append(parts, fmt.Sprintf("grow:%g", node.LayoutGrow))
+	}
+	if node.LayoutAlign == "STRETCH" {
+		parts = append(parts, "stretch")
+	}
+	if node.LayoutSizingHorizontal != "" || node.LayoutSizingVertical != "" {
+		parts = append(parts, fmt.Sprintf("resize:%s/%s",
+			orDefault(node.LayoutSizingHorizontal, "FIXED"), orDefault(node.LayoutSizingVertical, "FIXED")))
+	}
+	if node.LayoutPositioning == "ABSOLUTE" {
+		parts = append(parts, "absolute")
+	}
+	if (node.ConstraintHorizontal != "" && node.ConstraintHorizontal != "LEFT") ||
+		(node.ConstraintVertical != "" && node.ConstraintVertical != "TOP") {
+		parts = append(parts, fmt.Sprintf("constraints:%s/%s",
+			orDefault(node.ConstraintHorizontal, "LEFT"), orDefault(node.ConstraintVertical, "TOP")))
+	}
+	for _, limit := range []struct {
+		label string
+		value float64
+	}{
+		{"min-w", node.MinWidth},
+		{"max-w", node.MaxWidth},
+		{"min-h", node.MinHeight},
+		{"max-h", node.MaxHeight},
+	} {
+		if limit.value > 0 {
+			parts = append(parts, fmt.Sprintf("%s:%.0f", limit.label, limit.value))
+		}
 	}
 
 	// Shadows
@@ -319,6 +810,30 @@ func renderNodeDescription(sb *strings.Builder, node *extractor.NodeDescription,
 			s.Type, s.X, s.Y, s.Blur, s.Color))
 	}
 
+	if node.BlendMode != "" {
+		parts = append(parts, "blend:"+node.BlendMode)
+	}
+	for _, mode := range node.PaintBlendModes {
+		parts = append(parts, "blend:"+mode)
+	}
+	for _, b := range node.Blurs {
+		parts = append(parts, fmt.Sprintf("blur:%s/%g", b.Type, b.Radius))
+	}
+
+	// Duplicates
+	if node.DuplicateOf != "" {
+		parts = append(parts, "duplicate-of:"+node.DuplicateOf)
+	}
+
+	// Plugin data, long values (often JSON) shortened
+	for _, key := range sortedKeys(node.PluginData) {
+		value := node.PluginData[key]
+		if r := []rune(value); len(r) > 40 {
+			value = string(r[:40]) + "…"
+		}
+		parts = append(parts, fmt.Sprintf("data:%s=%s", key, strings.ReplaceAll(value, "\n", " ")))
+	}
+
 	// Assets
 	for _, a := range node.ExportedAssets {
 		parts = append(parts, "asset:"+assetDir+a.FileName)
@@ -337,22 +852,617 @@ func renderNodeDescription(sb *strings.Builder, node *extractor.NodeDescription,
 	}
 }
 
+// writeGrids writes the "Column Grids" section: the column grid of every top-level frame.
+func writeGrids(sb *strings.Builder, grids []extractor.ColumnGrid) {
+	sb.WriteString("### Column Grids\n\n")
+	sb.WriteString("Column grids of the frames, declared as Figma layout grids or inferred from the widths and gutters of their content. ")
+	sb.WriteString("Reproduce one with `display: grid; grid-template-columns: repeat(<columns>, 1fr); column-gap: <gutter>; padding-inline: <margin>`:\n\n")
+	sb.WriteString("| Frame | Width | Columns | Gutter | Margin | Column Width | Source |\n")
+	sb.WriteString("|-------|-------|---------|--------|--------|--------------|--------|\n")
+	for _, g := range grids {
+		source := "Layout grid"
+		if g.Inferred {
+			source = "Inferred from content"
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %s | %d | %s | %s | %s | %s |\n",
+			markdownCell(g.NodeName), px(g.Width), g.Columns, px(g.Gutter), px(g.Margin), px(g.ColumnWidth), source))
+	}
+	sb.WriteString("\n")
+}
+
+// writeResizing writes the "Responsive Behavior" section: the resize hints of every layer,
+// grouped by parent frame in document order.
+func writeResizing(sb *strings.Builder, behaviors []extractor.ResizeBehavior) {
+	sb.WriteString("### Responsive Behavior\n\n")
+	sb.WriteString("How layers respond when their frame is resized (Figma constraints and size limits):\n\n")
+
+	var parents []string
+	byParent := make(map[string][]extractor.ResizeBehavior)
+	for _, b := range behaviors {
+		if _, ok := byParent[b.Parent]; !ok {
+			parents = append(parents, b.Parent)
+		}
+		byParent[b.Parent] = append(byParent[b.Parent], b)
+	}
+
+	for _, parent := range parents {
+		label := parent
+		if label == "" {
+			label = "Top level"
+		}
+		sb.WriteString(fmt.Sprintf("**%s**\n\n", label))
+		for _, b := range byParent[parent] {
+			sb.WriteString(fmt.Sprintf("- `%s`: %s\n", b.Name, strings.Join(resizeHints(b), "; ")))
+		}
+		sb.WriteString("\n")
+	}
+}
+
+// writeBreakpoints writes the "Breakpoints" section: the breakpoint tokens, then the layout of
+// every screen designed at several breakpoints with its mobile-first CSS.
+func writeBreakpoints(sb *strings.Builder, breakpoints []extractor.Breakpoint, screens []extractor.Screen) {
+	sb.WriteString("### Breakpoints\n\n")
+	sb.WriteString("Viewport widths of the screens designed at several sizes, each the width of its narrowest frame (CSS custom properties cannot be used in media queries, so use the values directly):\n\n")
+	sb.WriteString("```css\n:root {\n")
+	widths := make(map[string]float64)
+	for _, bp := range breakpoints {
+		widths[bp.Name] = bp.Width
+		sb.WriteString(fmt.Sprintf("  --breakpoint-%s: %s;\n", bp.Name, px(bp.Width)))
+	}
+	sb.WriteString("}\n```\n\n")
+
+	for _, s := range screens {
+		sb.WriteString(fmt.Sprintf("**%s**\n\n", s.Name))
+		sb.WriteString("| Breakpoint | Frame | Size | Layout |\n")
+		sb.WriteString("|------------|-------|------|--------|\n")
+		hasLayout := false
+		for _, v := range s.Variants {
+			layout := "-"
+			if v.Layout != nil {
+				hasLayout = true
+				var parts []string
+				for _, d := range flexboxCSS(*v.Layout) {
+					switch d[0] {
+					case "flex-direction":
+						parts = append(parts, d[1])
+					case "flex-wrap", "gap", "row-gap", "column-gap", "padding":
+						parts = append(parts, d[0]+" "+d[1])
+					}
+				}
+				layout = strings.Join(parts, ", ")
+			}
+			sb.WriteString(fmt.Sprintf("| %s | %s | %.0f×%.0f | %s |\n",
+				v.Breakpoint, markdownCell(v.NodeName), v.Width, v.Height, layout))
+		}
+		sb.WriteString("\n")
+
+		if hasLayout {
+			sb.WriteString("```css\n")
+			writeScreenRules(sb, s, widths)
+			sb.WriteString("```\n\n")
+		}
+	}
+}
+
+// writeFonts writes the "Fonts" section: every font family with its weights and styles, whether
+// Google Fonts serves it, and the snippets loading it: a Google Fonts stylesheet link, or
+// @font-face rules and a preload link of the regular face to self-host it.
+func writeFonts(sb *strings.Builder, fonts []extractor.Font) {
+	sb.WriteString("### Fonts\n\n")
+	sb.WriteString("| Family | Weights | Text Layers | Google Fonts | Fallback |\n")
+	sb.WriteString("|--------|---------|-------------|--------------|----------|\n")
+	var google, selfHosted []extractor.Font
+	for _, font := range fonts {
+		var faces []string
+		for _, face := range font.Faces {
+			if face.Italic {
+				faces = append(faces, fmt.Sprintf("%g italic", face.Weight))
+			} else {
+				faces = append(faces, fmt.Sprintf("%g", face.Weight))
+			}
+		}
+		available := "No, self-host it"
+		if font.GoogleFonts {
+			available = "Yes"
+			google = append(google, font)
+		} else {
+			selfHosted = append(selfHosted, font)
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %s | %d | %s | `%s` |\n",
+			markdownCell(font.Family), strings.Join(faces, ", "), font.Uses, available, font.Fallback))
+	}
+	sb.WriteString("\n")
+
+	if len(google) > 0 {
+		sb.WriteString("Load the Google Fonts families:\n\n")
+		sb.WriteString("```html\n")
+		sb.WriteString("<link rel=\"preconnect\" href=\"https://fonts.googleapis.com\">\n")
+		sb.WriteString("<link rel=\"preconnect\" href=\"https://fonts.gstatic.com\" crossorigin>\n")
+		sb.WriteString(fmt.Sprintf("<link href=\"%s\" rel=\"stylesheet\">\n", googleFontsURL(google)))
+		sb.WriteString("```\n\n")
+	}
+
+	if len(selfHosted) > 0 {
+		sb.WriteString("Self-host the other families, with the font files named after their PostScript names:\n\n")
+		sb.WriteString("```css\n")
+		for i, font := range selfHosted {
+			for j, face := range font.Faces {
+				if i > 0 || j > 0 {
+					sb.WriteString("\n")
+				}
+				style := "normal"
+				if face.Italic {
+					style = "italic"
+				}
+				sb.WriteString("@font-face {\n")
+				sb.WriteString(fmt.Sprintf("  font-family: '%s';\n", font.Family))
+				sb.WriteString(fmt.Sprintf("  src: url('/fonts/%s.woff2') format('woff2');\n", fontFileName(font, face)))
+				sb.WriteString(fmt.Sprintf("  font-weight: %g;\n", face.Weight))
+				sb.WriteString(fmt.Sprintf("  font-style: %s;\n", style))
+				sb.WriteString("  font-display: swap;\n")
+				sb.WriteString("}\n")
+			}
+		}
+		sb.WriteString("```\n\n")
+		sb.WriteString("Preload the regular face of each, which most text renders with:\n\n")
+		sb.WriteString("```html\n")
+		for _, font := range selfHosted {
+			sb.WriteString(fmt.Sprintf("<link rel=\"preload\" href=\"/fonts/%s.woff2\" as=\"font\" type=\"font/woff2\" crossorigin>\n", fontFileName(font, regularFace(font))))
+		}
+		sb.WriteString("```\n\n")
+	}
+}
+
+// googleFontsURL returns the URL of the Google Fonts stylesheet serving the faces of fonts.
+func googleFontsURL(fonts []extractor.Font) string {
+	var families []string
+	for _, font := range fonts {
+		family := strings.ReplaceAll(font.Family, " ", "+")
+		italic := slices.ContainsFunc(font.Faces, func(f extractor.FontFace) bool { return f.Italic })
+		var upright, italics []string
+		for _, face := range font.Faces {
+			weight := strconv.Itoa(int(math.Round(face.Weight)))
+			switch {
+			case !italic:
+				upright = append(upright, weight)
+			case face.Italic:
+				italics = append(italics, "1,"+weight)
+			default:
+				upright = append(upright, "0,"+weight)
+			}
+		}
+		if italic {
+			families = append(families, "family="+family+":ital,wght@"+strings.Join(slices.Compact(append(upright, italics...)), ";"))
+		} else {
+			families = append(families, "family="+family+":wght@"+strings.Join(slices.Compact(upright), ";"))
+		}
+	}
+	return "https://fonts.googleapis.com/css2?" + strings.Join(families, "&") + "&display=swap"
+}
+
+// regularFace returns the face of font closest to the upright 400 weight.
+func regularFace(font extractor.Font) extractor.FontFace {
+	best := font.Faces[0]
+	for _, face := range font.Faces[1:] {
+		if best.Italic && !face.Italic || best.Italic == face.Italic && math.Abs(face.Weight-400) < math.Abs(best.Weight-400) {
+			best = face
+		}
+	}
+	return best
+}
+
+// fontFileName returns the name, without extension, of the font file of a face of font: its
+// PostScript name, or else the family and weight, e.g. "acme-sans-700-italic".
+func fontFileName(font extractor.Font, face extractor.FontFace) string {
+	if face.PostScriptName != "" {
+		return face.PostScriptName
+	}
+	name := fmt.Sprintf("%s-%g", toKebabCase(font.Family), face.Weight)
+	if face.Italic {
+		name += "-italic"
+	}
+	return name
+}
+
+// writeStrings writes the "Strings" section: the copy of every text layer with its frame path,
+// placeholder copy marked.
+func writeStrings(sb *strings.Builder, texts []extractor.TextString) {
+	sb.WriteString("## Strings\n\n")
+	sb.WriteString("The copy of the design's text layers, in document order, as a copy deck for writers and translators.\n\n")
+	placeholders := 0
+	for _, t := range texts {
+		if t.Placeholder {
+			placeholders++
+		}
+	}
+	if placeholders > 0 {
+		sb.WriteString(fmt.Sprintf("> ⚠️ %d strings look like placeholder copy (marked ⚠️). Ask for the final copy before implementing them.\n\n", placeholders))
+	}
+	sb.WriteString("| Path | Layer | Text |\n")
+	sb.WriteString("|------|-------|------|\n")
+	for _, t := range texts {
+		text := markdownCell(t.Text)
+		if t.Placeholder {
+			text = "⚠️ " + text
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s |\n", markdownCell(t.Path), markdownCell(t.Name), text))
+	}
+	sb.WriteString("\n")
+}
+
+// writeComments writes the "Open Comments" section: the unresolved comment threads of the
+// design grouped by the node they are anchored to, with their replies.
+func writeComments(sb *strings.Builder, comments []extractor.Comment) {
+	sb.WriteString("## Open Comments\n\n")
+	sb.WriteString("Unresolved comments left by the design team. Check them before implementing the nodes they refer to:\n\n")
+
+	date := func(timestamp string) string {
+		if len(timestamp) >= 10 {
+			return timestamp[:10]
+		}
+		return timestamp
+	}
+	message := func(s string) string {
+		return strings.Join(strings.Fields(s), " ")
+	}
+
+	nodeID := "\x00"
+	for _, c := range comments {
+		if c.NodeID != nodeID {
+			if nodeID != "\x00" {
+				sb.WriteString("\n")
+			}
+			nodeID = c.NodeID
+			if c.NodeID == "" {
+				sb.WriteString("**Canvas**\n\n")
+			} else {
+				sb.WriteString(fmt.Sprintf("**%s** (`%s`)\n\n", c.NodeName, c.NodeID))
+			}
+		}
+
+		number := ""
+		if c.Number != "" {
+			number = "#" + c.Number + " "
+		}
+		sb.WriteString(fmt.Sprintf("- %s**@%s** (%s): %s\n", number, c.Author, date(c.CreatedAt), message(c.Message)))
+		for _, r := range c.Replies {
+			sb.WriteString(fmt.Sprintf("  - **@%s** (%s): %s\n", r.Author, date(r.CreatedAt), message(r.Message)))
+		}
+	}
+	sb.WriteString("\n")
+}
+
+// writeFrames writes the "Frames" section: for every extracted frame its screenshot, the
+// tokens it uses and its node tree.
+func writeFrames(sb *strings.Builder, frames []extractor.Frame, assets []extractor.ExportedAssetInfo, assetDir string) {
+	sb.WriteString("## Frames\n\n")
+	for _, frame := range frames {
+		sb.WriteString(fmt.Sprintf("### %s\n\n", frame.Name))
+		for _, asset := range assets {
+			if asset.IsScreenshot && asset.NodeID == frame.NodeID {
+				sb.WriteString(fmt.Sprintf("![%s](%s%s)\n\n", frame.Name, assetDir, asset.FileName))
+				break
+			}
+		}
+
+		specs := frame.Specs
+		var tokens strings.Builder
+		for _, group := range colorGroups(specs.Colors) {
+			for _, name := range sortedKeys(group.Colors) {
+				tokens.WriteString(fmt.Sprintf("--color-%s%s: %s;\n", group.Prefix, toKebabCase(name), group.Colors[name]))
+			}
+		}
+		if specs.Typography.FontFamily != "" {
+			tokens.WriteString(fmt.Sprintf("--font-primary: '%s', system-ui, -apple-system, sans-serif;\n", specs.Typography.FontFamily))
+		}
+		for _, name := range sortedKeys(specs.Typography.FontSizes) {
+			tokens.WriteString(fmt.Sprintf("--text-%s: %s;\n", name, px(specs.Typography.FontSizes[name])))
+		}
+		for _, name := range sortedKeys(specs.Typography.FontWeights) {
+			tokens.WriteString(fmt.Sprintf("--font-%s: %g;\n", toKebabCase(name), specs.Typography.FontWeights[name]))
+		}
+		for _, name := range sortedKeys(specs.Spacing.Values) {
+			tokens.WriteString(fmt.Sprintf("--space-%s: %s;\n", name, px(specs.Spacing.Values[name])))
+		}
+		for _, name := range sortedKeys(specs.Radii.Values) {
+			tokens.WriteString(fmt.Sprintf("--radius-%s: %s;\n", name, px(specs.Radii.Values[name])))
+		}
+		names, shadows := shadowTokens(specs.Shadows)
+		for _, name := range names {
+			tokens.WriteString(fmt.Sprintf("--shadow-%s: %s;\n", name, shadows[name]))
+		}
+		if tokens.Len() > 0 {
+			sb.WriteString("Tokens used in this frame:\n\n```css\n")
+			sb.WriteString(tokens.String())
+			sb.WriteString("```\n\n")
+		}
+
+		if len(specs.NodeTree) > 0 {
+			sb.WriteString("```\n")
+			for _, root := range specs.NodeTree {
+				renderNodeDescription(sb, root, 0, assetDir)
+			}
+			sb.WriteString("```\n\n")
+		}
+	}
+}
+
+// writeDuplicates writes the "Duplicates" section: every group of identical frames, groups or
+// components with its original and copies, so designers can clean up the file.
+func writeDuplicates(sb *strings.Builder, groups []extractor.DuplicateGroup) {
+	sb.WriteString("## Duplicates\n\n")
+	sb.WriteString("These layers look identical to another one. Only the original contributes tokens and the copies are collapsed in the component tree; consider turning them into instances of a component or removing them:\n\n")
+	sb.WriteString("| Original | Copies | Type | Size |\n")
+	sb.WriteString("|----------|--------|------|------|\n")
+
+	member := func(n extractor.DuplicateNode) string {
+		s := "`" + n.Name + "`"
+		if n.Page != "" {
+			s += " (" + n.Page + ")"
+		}
+		return s
+	}
+	for _, g := range groups {
+		var copies []string
+		for _, c := range g.Copies {
+			copies = append(copies, member(c))
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s | %.0f×%.0f |\n",
+			markdownCell(member(g.Original)), markdownCell(strings.Join(copies, ", ")), g.Type, g.Width, g.Height))
+	}
+	sb.WriteString("\n")
+}
+
+// writeInteractions writes the "Flows & Interactions" section: the prototype flows followed by
+// a table of every interaction with its trigger, action, destination and animation.
+func writeInteractions(sb *strings.Builder, flows []extractor.Flow, interactions []extractor.Interaction) {
+	sb.WriteString("## Flows & Interactions\n\n")
+
+	if len(flows) > 0 {
+		sb.WriteString("### Flows\n\n")
+		for _, f := range flows {
+			start := f.StartNodeName
+			if start == "" {
+				start = f.StartNodeID
+			}
+			sb.WriteString(fmt.Sprintf("- **%s** starts at `%s`", f.Name, start))
+			if f.Page != "" {
+				sb.WriteString(fmt.Sprintf(" (page %s)", f.Page))
+			}
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(interactions) > 0 {
+		sb.WriteString("### Interactions\n\n")
+		sb.WriteString("| Layer | Trigger | Action | Destination | Animation |\n")
+		sb.WriteString("|-------|---------|--------|-------------|-----------|\n")
+		for _, in := range interactions {
+			trigger := humanize(in.Trigger)
+			if in.Delay > 0 {
+				trigger += fmt.Sprintf(" (%gms)", in.Delay)
+			}
+
+			destination := "-"
+			switch {
+			case in.URL != "":
+				destination = in.URL
+			case in.DestinationName != "":
+				destination = in.DestinationName
+			case in.DestinationID != "":
+				destination = in.DestinationID
+			}
+
+			animation := "Instant"
+			if in.Transition != "" || in.Duration > 0 {
+				var parts []string
+				if in.Transition != "" {
+					parts = append(parts, humanize(in.Transition))
+				}
+				if in.Direction != "" {
+					parts = append(parts, strings.ToLower(in.Direction))
+				}
+				if in.Easing != "" {
+					parts = append(parts, strings.ReplaceAll(strings.ToLower(in.Easing), "_", "-"))
+				}
+				if in.Duration > 0 {
+					parts = append(parts, fmt.Sprintf("%gms", in.Duration))
+				}
+				animation = strings.Join(parts, ", ")
+			}
+
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n",
+				markdownCell(in.NodeName), trigger, interactionAction(in.Action), markdownCell(destination), animation))
+		}
+		sb.WriteString("\n")
+	}
+}
+
+// interactionAction describes a prototype action the way Figma's prototype panel does.
+func interactionAction(action string) string {
+	switch action {
+	case "NAVIGATE":
+		return "Navigate to"
+	case "OVERLAY":
+		return "Open overlay"
+	case "SWAP":
+		return "Swap overlay"
+	case "SCROLL_TO":
+		return "Scroll to"
+	case "CHANGE_TO":
+		return "Change to"
+	case "CLOSE":
+		return "Close overlay"
+	case "URL":
+		return "Open link"
+	}
+	return humanize(action)
+}
+
+// humanize turns a Figma enum such as "ON_CLICK" into "On click".
+func humanize(s string) string {
+	if s == "" {
+		return "-"
+	}
+	s = strings.ReplaceAll(strings.ToLower(s), "_", " ")
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// writeBlendModes writes the blend mode warning: one row per blend mode and target, with
+// the layers using it and the CSS property reproducing it.
+func writeBlendModes(sb *strings.Builder, usages []extractor.BlendModeUsage) {
+	type group struct {
+		mode, target string
+		layers       []string
+	}
+	var groups []*group
+	index := make(map[string]*group)
+	for _, u := range usages {
+		key := u.Mode + "/" + u.Target
+		g, ok := index[key]
+		if !ok {
+			g = &group{mode: u.Mode, target: u.Target}
+			index[key] = g
+			groups = append(groups, g)
+		}
+		g.layers = append(g.layers, u.NodeName)
+	}
+	sort.SliceStable(groups, func(i, j int) bool { return groups[i].mode < groups[j].mode })
+
+	sb.WriteString("### Blend Modes\n\n")
+	sb.WriteString(fmt.Sprintf("> ⚠️ %d layers or paints use a blend mode other than NORMAL. Their colors are mixed with the content below them, so reproduce the blend mode instead of copying the color values as-is.\n\n", len(usages)))
+	sb.WriteString("| Blend Mode | Applies to | Layers | CSS |\n")
+	sb.WriteString("|------------|------------|--------|-----|\n")
+	for _, g := range groups {
+		layers := g.layers
+		more := ""
+		if len(layers) > 3 {
+			more = fmt.Sprintf(", +%d more", len(layers)-3)
+			layers = layers[:3]
+		}
+		css := "no CSS equivalent"
+		if value := cssBlendMode(g.mode); value != "" {
+			property := "mix-blend-mode"
+			if g.target == "fill" {
+				property = "background-blend-mode"
+			}
+			css = fmt.Sprintf("`%s: %s`", property, value)
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n",
+			g.mode, g.target, markdownCell(strings.Join(layers, ", ")+more), css))
+	}
+	sb.WriteString("\n")
+}
+
+// orDefault returns s, or def when s is empty.
+func orDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
+
 // toKebabCase converts a string to kebab-case format (lowercase with hyphens).
-// This is used for generating CSS variable names from Figma node names.
-// Special characters are removed, and spaces/underscores are replaced with hyphens.
+// This is used for generating CSS variable names from Figma node and style names.
+// Special characters are removed, spaces/underscores/slashes are replaced with hyphens,
+// and runs of hyphens are collapsed so that "Brand / Primary" becomes "brand-primary".
 func toKebabCase(s string) string {
-	// Remove special characters and replace spaces with hyphens
+	// Remove special characters and replace separators with hyphens
 	s = strings.ToLower(s)
 	s = strings.ReplaceAll(s, " ", "-")
 	s = strings.ReplaceAll(s, "_", "-")
+	s = strings.ReplaceAll(s, "/", "-")
 
 	// Remove any non-alphanumeric characters except hyphens
 	var result strings.Builder
 	for _, r := range s {
-		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			result.WriteRune(r)
+		} else if r == '-' && result.Len() > 0 && !strings.HasSuffix(result.String(), "-") {
 			result.WriteRune(r)
 		}
 	}
 
-	return result.String()
+	return strings.TrimSuffix(result.String(), "-")
+}
+
+// markdownCell escapes text for use inside a markdown table cell.
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	s = strings.ReplaceAll(s, "\r\n", " ")
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+// writeIcons writes the icons drawn from vector paths, each as a collapsible SVG snippet.
+func writeIcons(sb *strings.Builder, icons []extractor.Icon) {
+	sb.WriteString("## Icons\n\n")
+	sb.WriteString(fmt.Sprintf("%d icon(s) drawn from the vector paths of the file, ready to inline.\n\n", len(icons)))
+	for _, icon := range icons {
+		sb.WriteString(fmt.Sprintf("<details>\n<summary>%s (%.0f×%.0f)</summary>\n\n", html.EscapeString(icon.Name), icon.Width, icon.Height))
+		sb.WriteString("```svg\n")
+		sb.WriteString(icon.SVG)
+		sb.WriteString("\n```\n\n</details>\n\n")
+	}
+}
+
+// writeComponentCatalog writes the component table. Usage and link columns are only added when
+// library analytics or dev resources were fetched.
+func writeComponentCatalog(sb *strings.Builder, components []extractor.Component) {
+	withUsage, withLinks := false, false
+	for _, c := range components {
+		withUsage = withUsage || c.Usage != nil
+		withLinks = withLinks || len(c.Links) > 0
+	}
+
+	header := []string{"Component", "Type", "Variants", "Size", "Page"}
+	if withUsage {
+		header = append(header, "Instances", "Teams", "Files")
+	}
+	if withLinks {
+		header = append(header, "Links")
+	}
+	header = append(header, "Description")
+
+	sb.WriteString("## Components\n\n")
+	sb.WriteString("| " + strings.Join(header, " | ") + " |\n")
+	for _, h := range header {
+		sb.WriteString("|" + strings.Repeat("-", len(h)+2))
+	}
+	sb.WriteString("|\n")
+
+	for _, c := range components {
+		kind := "Component"
+		variants := "-"
+		if c.Type == "COMPONENT_SET" {
+			kind = "Component Set"
+			variants = fmt.Sprintf("%d", c.VariantCount)
+		}
+		size := "-"
+		if c.Width > 0 || c.Height > 0 {
+			size = fmt.Sprintf("%.0f×%.0f", c.Width, c.Height)
+		}
+		row := []string{markdownCell(c.Name), kind, variants, size, markdownCell(c.Page)}
+		if withUsage {
+			instances, teams, files := "-", "-", "-"
+			if c.Usage != nil {
+				instances, teams, files = fmt.Sprint(c.Usage.Instances), fmt.Sprint(c.Usage.Teams), fmt.Sprint(c.Usage.Files)
+			}
+			row = append(row, instances, teams, files)
+		}
+		if withLinks {
+			links := "-"
+			if len(c.Links) > 0 {
+				list := make([]string, len(c.Links))
+				for i, link := range c.Links {
+					list[i] = fmt.Sprintf("[%s](%s)", markdownCell(link.Name), link.URL)
+				}
+				links = strings.Join(list, ", ")
+			}
+			row = append(row, links)
+		}
+		row = append(row, markdownCell(c.Description))
+		sb.WriteString("| " + strings.Join(row, " | ") + " |\n")
+	}
+	sb.WriteString("\n")
 }
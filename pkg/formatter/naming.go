@@ -0,0 +1,181 @@
+package formatter
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// Token identifies a design token to be named by a NamingStrategy.
+type Token struct {
+	Category string  // token category, e.g. "color", "text", "font", "leading", "space", "radius", "shadow"
+	Name     string  // name within the category as the default naming writes it, e.g. "primary-500"
+	Value    float64 // pixel value of font size, spacing and radius tokens; 0 for the rest
+}
+
+// NamingStrategy names the tokens of the css and scss formats, so generated variables follow
+// the conventions of the codebase consuming them. Names are written without the leading "--"
+// or "$". Figma variables keep the names their designers gave them.
+type NamingStrategy interface {
+	TokenName(t Token) string
+}
+
+// NamingFunc adapts a function to the NamingStrategy interface.
+type NamingFunc func(t Token) string
+
+// TokenName calls f(t).
+func (f NamingFunc) TokenName(t Token) string {
+	return f(t)
+}
+
+// Built-in naming strategies.
+var (
+	// KebabNaming joins category and name with hyphens: "color-primary-500", "space-md".
+	// It is the default.
+	KebabNaming NamingStrategy = NamingFunc(kebabTokenName)
+	// CamelNaming writes lowerCamelCase names: "colorPrimary500", "spaceMd".
+	CamelNaming NamingStrategy = NamingFunc(camelTokenName)
+	// BEMNaming writes block__element--modifier names, the category being the block and the
+	// last word of a multi-word name the modifier: "color__primary--500", "space__md".
+	BEMNaming NamingStrategy = NamingFunc(bemTokenName)
+	// TailwindNaming names font sizes, spacing and radii after the Tailwind CSS scale step of
+	// their value ("text-base" for 16px, "space-4" for 16px, "radius-lg" for 8px), and the
+	// rest like KebabNaming. Values off the scale keep their names.
+	TailwindNaming NamingStrategy = NamingFunc(tailwindTokenName)
+)
+
+// namingStrategies maps the names of the built-in strategies to them.
+var namingStrategies = map[string]NamingStrategy{
+	"kebab":    KebabNaming,
+	"camel":    CamelNaming,
+	"bem":      BEMNaming,
+	"tailwind": TailwindNaming,
+}
+
+// NamingStrategies returns the names of the built-in naming strategies in alphabetical order.
+func NamingStrategies() []string {
+	return sortedKeys(namingStrategies)
+}
+
+// Naming returns the built-in naming strategy called name ("kebab", "camel", "bem" or
+// "tailwind").
+func Naming(name string) (NamingStrategy, error) {
+	naming, ok := namingStrategies[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown naming strategy %q (must be one of %s)", name, strings.Join(NamingStrategies(), ", "))
+	}
+	return naming, nil
+}
+
+// tokenName names t with naming, or KebabNaming when naming is nil.
+func tokenName(naming NamingStrategy, t Token) string {
+	if naming == nil {
+		naming = KebabNaming
+	}
+	return naming.TokenName(t)
+}
+
+func kebabTokenName(t Token) string {
+	if t.Name == "" {
+		return t.Category
+	}
+	return t.Category + "-" + t.Name
+}
+
+func camelTokenName(t Token) string {
+	words := append(nameWords(t.Category), nameWords(t.Name)...)
+	for i := 1; i < len(words); i++ {
+		words[i] = strings.ToUpper(words[i][:1]) + words[i][1:]
+	}
+	return strings.Join(words, "")
+}
+
+func bemTokenName(t Token) string {
+	block := strings.Join(nameWords(t.Category), "-")
+	words := nameWords(t.Name)
+	switch len(words) {
+	case 0:
+		return block
+	case 1:
+		return block + "__" + words[0]
+	}
+	return block + "__" + strings.Join(words[:len(words)-1], "-") + "--" + words[len(words)-1]
+}
+
+// tailwindFontSizes and tailwindRadii are the default Tailwind CSS scales by pixel value.
+var (
+	tailwindFontSizes = map[float64]string{
+		12: "xs", 14: "sm", 16: "base", 18: "lg", 20: "xl", 24: "2xl", 30: "3xl",
+		36: "4xl", 48: "5xl", 60: "6xl", 72: "7xl", 96: "8xl", 128: "9xl",
+	}
+	tailwindRadii = map[float64]string{
+		0: "none", 2: "sm", 4: "DEFAULT", 6: "md", 8: "lg", 12: "xl", 16: "2xl", 24: "3xl", 9999: "full",
+	}
+)
+
+func tailwindTokenName(t Token) string {
+	switch t.Category {
+	case "text":
+		if step, ok := tailwindFontSizes[t.Value]; ok {
+			return "text-" + step
+		}
+	case "radius":
+		if step, ok := tailwindRadii[t.Value]; ok {
+			if step == "DEFAULT" {
+				return "radius"
+			}
+			return "radius-" + step
+		}
+	case "space":
+		// One spacing step is 4px; half steps are written with an underscore (0_5 for 2px).
+		switch {
+		case t.Value == 1:
+			return "space-px"
+		case t.Value >= 0 && math.Mod(t.Value, 2) == 0:
+			return "space-" + strings.ReplaceAll(fmt.Sprintf("%g", t.Value/4), ".", "_")
+		}
+	}
+	return kebabTokenName(t)
+}
+
+// nameWords splits a name into lowercase words at separators and lower-to-upper case
+// changes: "Card-paddingLeft" becomes ["card", "padding", "left"].
+func nameWords(s string) []string {
+	var words []string
+	var word []rune
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, strings.ToLower(string(word)))
+			word = word[:0]
+		}
+	}
+	var prev rune
+	for _, r := range s {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && (unicode.IsLower(prev) || unicode.IsDigit(prev)):
+			flush()
+			word = append(word, r)
+		default:
+			word = append(word, r)
+		}
+		prev = r
+	}
+	flush()
+	return words
+}
+
+// uniqueNames drops names already returned before, for strategies that give several tokens
+// the same name (such as TailwindNaming for equal values).
+type uniqueNames map[string]bool
+
+// add reports whether name is new and records it.
+func (u uniqueNames) add(name string) bool {
+	if u[name] {
+		return false
+	}
+	u[name] = true
+	return true
+}
@@ -0,0 +1,236 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hellenic-development/figma-extractor/pkg/extractor"
+)
+
+// ToSCSS renders design specifications as a Sass partial. Every token becomes a $variable
+// named like its CSS counterpart in the markdown report ($color-primary-main, $text-base,
+// $space-4, ...), and each category is additionally collected into a Sass map ($colors,
+// $font-sizes, $spacing, ...) for iteration with @each or lookup with map.get.
+// Composite text styles become @mixin text-<style> declarations.
+// Figma variables produce one map per mode plus a $themes map keyed by mode name.
+func ToSCSS(specs *extractor.DesignSpecs, fileName string) string {
+	return ToSCSSWithNaming(specs, fileName, nil)
+}
+
+// ToSCSSWithNaming is like ToSCSS but names the $variables of the tokens with naming
+// (nil = KebabNaming). Map keys keep the names of the tokens within their category.
+func ToSCSSWithNaming(specs *extractor.DesignSpecs, fileName string, naming NamingStrategy) string {
+	return toSCSS(specs, fileName, naming, Units{})
+}
+
+// toSCSS is like ToSCSSWithNaming but emits the typography, spacing and radius tokens in units.
+func toSCSS(specs *extractor.DesignSpecs, fileName string, naming NamingStrategy, units Units) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("// Design tokens extracted from Figma: %s\n", fileName))
+	sb.WriteString("// Generated by figma-extractor. Do not edit by hand.\n\n")
+
+	// variable declares the $variable of a token and returns a reference to it. Tokens the
+	// strategy gives an already declared name refer to the earlier declaration.
+	declared := make(uniqueNames)
+	variable := func(t Token, value string) string {
+		name := tokenName(naming, t)
+		if declared.add(name) {
+			sb.WriteString(fmt.Sprintf("$%s: %s;\n", name, value))
+		}
+		return "$" + name
+	}
+
+	// Colors
+	var colorEntries []scssEntry
+	for _, group := range colorGroups(specs.Colors) {
+		if len(group.Colors) == 0 {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("// %s\n", group.Label))
+		for _, name := range sortedKeys(group.Colors) {
+			key := group.Prefix + toKebabCase(name)
+			colorEntries = append(colorEntries, scssEntry{key, variable(Token{Category: "color", Name: key}, group.Colors[name])})
+		}
+		sb.WriteString("\n")
+	}
+	writeSCSSMap(&sb, "colors", colorEntries)
+
+	// Typography
+	if specs.Typography.FontFamily != "" {
+		sb.WriteString("// Font Family\n")
+		variable(Token{Category: "font", Name: "primary"}, fmt.Sprintf("'%s', system-ui, -apple-system, sans-serif", specs.Typography.FontFamily))
+		sb.WriteString("\n")
+	}
+
+	var sizeEntries []scssEntry
+	if len(specs.Typography.FontSizes) > 0 {
+		sb.WriteString("// Font Sizes\n")
+		for _, name := range sortedKeys(specs.Typography.FontSizes) {
+			size := specs.Typography.FontSizes[name]
+			sizeEntries = append(sizeEntries, scssEntry{name, variable(Token{Category: "text", Name: name, Value: size}, units.size(size))})
+		}
+		sb.WriteString("\n")
+	}
+	writeSCSSMap(&sb, "font-sizes", sizeEntries)
+
+	var weightEntries []scssEntry
+	if len(specs.Typography.FontWeights) > 0 {
+		sb.WriteString("// Font Weights\n")
+		for _, name := range sortedKeys(specs.Typography.FontWeights) {
+			key := toKebabCase(name)
+			weightEntries = append(weightEntries, scssEntry{key, variable(Token{Category: "font", Name: key}, fmt.Sprintf("%g", specs.Typography.FontWeights[name]))})
+		}
+		sb.WriteString("\n")
+	}
+	writeSCSSMap(&sb, "font-weights", weightEntries)
+
+	var leadingEntries []scssEntry
+	if len(specs.Typography.LineHeights) > 0 {
+		sb.WriteString("// Line Heights\n")
+		for _, name := range sortedKeys(specs.Typography.LineHeights) {
+			key := toKebabCase(name)
+			leadingEntries = append(leadingEntries, scssEntry{key, variable(Token{Category: "leading", Name: key}, units.size(specs.Typography.LineHeights[name]))})
+		}
+		sb.WriteString("\n")
+	}
+	writeSCSSMap(&sb, "line-heights", leadingEntries)
+
+	for _, detail := range textDetails(specs.Typography, naming, units) {
+		sb.WriteString(fmt.Sprintf("// %s\n", detail.Label))
+		for _, d := range detail.Decls {
+			if declared.add(d[0]) {
+				sb.WriteString(fmt.Sprintf("$%s: %s;\n", d[0], d[1]))
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	// Composite text styles as mixins.
+	if len(specs.TextStyles) > 0 {
+		sb.WriteString("// Text Styles\n")
+		writeTextStyleRules(&sb, specs.TextStyles, "@mixin %s", units)
+		sb.WriteString("\n")
+	}
+
+	// Spacing
+	var spaceEntries []scssEntry
+	if len(specs.Spacing.Values) > 0 {
+		sb.WriteString("// Spacing Scale\n")
+		for _, name := range sortedKeys(specs.Spacing.Values) {
+			space := specs.Spacing.Values[name]
+			spaceEntries = append(spaceEntries, scssEntry{name, variable(Token{Category: "space", Name: name, Value: space}, units.size(space))})
+		}
+		sb.WriteString("\n")
+	}
+	writeSCSSMap(&sb, "spacing", spaceEntries)
+
+	// Border radii
+	var radiusEntries []scssEntry
+	if len(specs.Radii.Values) > 0 {
+		sb.WriteString("// Border Radius\n")
+		for _, name := range sortedKeys(specs.Radii.Values) {
+			radius := specs.Radii.Values[name]
+			radiusEntries = append(radiusEntries, scssEntry{name, variable(Token{Category: "radius", Name: name, Value: radius}, units.size(radius))})
+		}
+		radiusEntries = append(radiusEntries, scssEntry{"full", variable(Token{Category: "radius", Name: "full", Value: 9999}, "9999px")})
+		sb.WriteString("\n")
+	}
+	writeSCSSMap(&sb, "radii", radiusEntries)
+
+	// Borders
+	var borderEntries []scssEntry
+	if len(specs.Borders) > 0 {
+		sb.WriteString("// Borders\n")
+		for _, name := range sortedKeys(specs.Borders) {
+			b := specs.Borders[name]
+			key := toKebabCase(name)
+			ref := variable(Token{Category: "border", Name: key}, cssBorder(b))
+			if len(b.Sides) == 4 {
+				variable(Token{Category: "border-width", Name: key}, cssBorderWidths(b.Sides))
+			}
+			borderEntries = append(borderEntries, scssEntry{key, ref})
+		}
+		sb.WriteString("\n")
+	}
+	writeSCSSMap(&sb, "borders", borderEntries)
+
+	// Shadows
+	var shadowEntries []scssEntry
+	if len(specs.Shadows) > 0 {
+		sb.WriteString("// Shadows\n")
+		names, values := shadowTokens(specs.Shadows)
+		for _, name := range names {
+			shadowEntries = append(shadowEntries, scssEntry{name, variable(Token{Category: "shadow", Name: name}, values[name])})
+		}
+		sb.WriteString("\n")
+	}
+	writeSCSSMap(&sb, "shadows", shadowEntries)
+
+	// Blurs
+	var blurEntries []scssEntry
+	if len(specs.Blurs) > 0 {
+		sb.WriteString("// Blurs (filter / backdrop-filter)\n")
+		names, blurs := blurTokens(specs.Blurs)
+		for _, name := range names {
+			blurEntries = append(blurEntries, scssEntry{name, variable(Token{Category: "blur", Name: name}, cssBlur(blurs[name]))})
+		}
+		sb.WriteString("\n")
+	}
+	writeSCSSMap(&sb, "blurs", blurEntries)
+
+	// Breakpoints, for use in media queries
+	var breakpointEntries []scssEntry
+	if len(specs.Layout.Breakpoints) > 0 {
+		sb.WriteString("// Breakpoints\n")
+		for _, bp := range specs.Layout.Breakpoints {
+			breakpointEntries = append(breakpointEntries, scssEntry{bp.Name, variable(Token{Category: "breakpoint", Name: bp.Name}, px(bp.Width))})
+		}
+		sb.WriteString("\n")
+	}
+	writeSCSSMap(&sb, "breakpoints", breakpointEntries)
+
+	// Variables: one map per mode, plus a $themes map per collection.
+	for _, coll := range specs.Variables {
+		collName := toKebabCase(coll.Name)
+		sb.WriteString(fmt.Sprintf("// Variables: %s\n", coll.Name))
+
+		var themes []scssEntry
+		for _, mode := range coll.Modes {
+			mapName := collName + "-" + toKebabCase(mode.Name)
+			var entries []scssEntry
+			for _, v := range mode.Variables {
+				entries = append(entries, scssEntry{variableCSSName(v.Name), variableCSSValue(v)})
+			}
+			writeSCSSMap(&sb, mapName, entries)
+			themes = append(themes, scssEntry{toKebabCase(mode.Name), "$" + mapName})
+		}
+		writeSCSSMap(&sb, collName+"-themes", themes)
+	}
+
+	return strings.TrimRight(sb.String(), "\n") + "\n"
+}
+
+// renderSCSS adapts ToSCSS to the renderFunc signature.
+func renderSCSS(in Input) ([]File, error) {
+	return []File{{Name: "_tokens.scss", Content: []byte(toSCSS(in.Specs, in.FileName, in.Naming, in.Units))}}, nil
+}
+
+// scssEntry is a single key/value pair of a Sass map.
+type scssEntry struct {
+	key   string
+	value string
+}
+
+// writeSCSSMap writes a Sass map declaration. Nothing is written for an empty map.
+func writeSCSSMap(sb *strings.Builder, name string, entries []scssEntry) {
+	if len(entries) == 0 {
+		return
+	}
+
+	sb.WriteString(fmt.Sprintf("$%s: (\n", name))
+	for _, e := range entries {
+		sb.WriteString(fmt.Sprintf("  \"%s\": %s,\n", e.key, e.value))
+	}
+	sb.WriteString(");\n\n")
+}
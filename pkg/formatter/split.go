@@ -0,0 +1,94 @@
+package formatter
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hellenic-development/figma-extractor/pkg/extractor"
+)
+
+// CanSplit reports whether RenderSplit can write a file per frame in format.
+func CanSplit(format string) bool {
+	return format == "markdown" || format == "json"
+}
+
+// frameIndex is the "index.json" of RenderSplit.
+type frameIndex struct {
+	FileName string            `json:"fileName"`
+	Version  string            `json:"version,omitempty"`
+	Frames   []frameIndexEntry `json:"frames"`
+}
+
+type frameIndexEntry struct {
+	NodeID string `json:"nodeId"`
+	Name   string `json:"name"`
+	File   string `json:"file"`
+}
+
+// RenderSplit renders every frame of in.Specs.Frames on its own, in the "markdown" or "json"
+// format, into a file named after the frame, e.g. "login-screen.md", and adds an index of the
+// frames: "index.md" linking their reports, or "index.json" listing their node IDs, names and
+// files. Frames render their own Specs, whose ExportedAssets should hold the assets, and
+// screenshot, of the frame alone.
+func RenderSplit(format string, in Input) ([]File, error) {
+	if !CanSplit(format) {
+		return nil, fmt.Errorf("format %q cannot be split per frame (must be markdown or json)", format)
+	}
+	ext := ".md"
+	if format == "json" {
+		ext = ".json"
+	}
+
+	index := frameIndex{FileName: in.FileName, Version: in.Version}
+	used := map[string]bool{"index": true}
+	files := []File{{}} // the index, filled in last
+	for _, frame := range in.Specs.Frames {
+		name := frameFileName(frame, used) + ext
+		frameIn := in
+		frameIn.Specs = frame.Specs
+		frameIn.FileName = in.FileName + " / " + frame.Name
+		rendered, err := Render(format, frameIn)
+		if err != nil {
+			return nil, fmt.Errorf("frame %s: %w", frame.Name, err)
+		}
+		files = append(files, File{Name: name, Content: rendered[0].Content})
+		index.Frames = append(index.Frames, frameIndexEntry{NodeID: frame.NodeID, Name: frame.Name, File: name})
+	}
+
+	if format == "json" {
+		data, err := json.MarshalIndent(index, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("render index: %w", err)
+		}
+		files[0] = File{Name: "index.json", Content: append(data, '\n')}
+		return files, nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# Figma Design Specifications - %s\n\n", in.FileName))
+	sb.WriteString("One report per extracted frame.\n\n")
+	sb.WriteString("| Frame | Node ID | Report |\n")
+	sb.WriteString("|-------|---------|--------|\n")
+	for _, f := range index.Frames {
+		sb.WriteString(fmt.Sprintf("| %s | `%s` | [%s](%s) |\n", f.Name, f.NodeID, f.File, f.File))
+	}
+	files[0] = File{Name: "index.md", Content: []byte(sb.String())}
+	return files, nil
+}
+
+// frameFileName returns the base name of the file of frame, the kebab-case frame name, or its
+// node ID when the name has no usable characters, numbered when already used.
+func frameFileName(frame extractor.Frame, used map[string]bool) string {
+	base := toKebabCase(frame.Name)
+	if base == "" {
+		base = "node-" + toKebabCase(strings.ReplaceAll(frame.NodeID, ":", "-"))
+	}
+	name := base
+	for i := 2; used[name]; i++ {
+		name = base + "-" + strconv.Itoa(i)
+	}
+	used[name] = true
+	return name
+}
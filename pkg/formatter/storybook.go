@@ -0,0 +1,250 @@
+package formatter
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hellenic-development/figma-extractor/pkg/extractor"
+)
+
+// storybookDir is the directory the Storybook docs pages are written into.
+const storybookDir = "storybook/"
+
+// storybookSampleText is the sample text of the typography pages.
+const storybookSampleText = "The quick brown fox jumps over the lazy dog"
+
+// ToStorybook renders design specifications as Storybook Docs pages (MDX) using the doc blocks
+// of @storybook/blocks, so the design system shows up inside an existing Storybook under
+// "Design System": Colors.mdx (a ColorPalette per color category), Typography.mdx (Typeset
+// blocks for the font family and every text style), Tokens.mdx (tables of spacing, radii,
+// borders, shadows and breakpoints with previews), Icons.mdx (an IconGallery) and
+// Components.mdx (the component inventory). Pages without content are left out.
+func ToStorybook(specs *extractor.DesignSpecs, fileName string) []File {
+	var files []File
+	page := func(name, title, body string) {
+		if body == "" {
+			return
+		}
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "{/* Design tokens extracted from the Figma file %s. Generated, do not edit. */}\n\n", mdxComment(fileName))
+		sb.WriteString(body)
+		files = append(files, File{Name: storybookDir + name, Content: []byte(mdxPage(title, sb.String()))})
+	}
+
+	page("Colors.mdx", "Colors", storybookColors(specs))
+	page("Typography.mdx", "Typography", storybookTypography(specs))
+	page("Tokens.mdx", "Tokens", storybookTokens(specs))
+	page("Icons.mdx", "Icons", storybookIcons(specs))
+	page("Components.mdx", "Components", storybookComponents(specs))
+	return files
+}
+
+// renderStorybook adapts ToStorybook to the renderFunc signature.
+func renderStorybook(in Input) ([]File, error) {
+	return ToStorybook(in.Specs, in.FileName), nil
+}
+
+// mdxPage returns a docs page: its imports, Meta title and heading followed by body. The
+// imports are collected from the doc blocks the body uses.
+func mdxPage(title, body string) string {
+	blocks := []string{"Meta"}
+	for _, block := range []string{"ColorPalette", "ColorItem", "Typeset", "IconGallery", "IconItem"} {
+		if strings.Contains(body, "<"+block) {
+			blocks = append(blocks, block)
+		}
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "import { %s } from '@storybook/blocks';\n\n", strings.Join(blocks, ", "))
+	fmt.Fprintf(&sb, "<Meta title=%s />\n\n", jsxString("Design System/"+title))
+	fmt.Fprintf(&sb, "# %s\n\n", title)
+	sb.WriteString(body)
+	return sb.String()
+}
+
+func storybookColors(specs *extractor.DesignSpecs) string {
+	var sb strings.Builder
+	for _, group := range colorGroups(specs.Colors) {
+		if len(group.Colors) == 0 {
+			continue
+		}
+		if sb.Len() == 0 {
+			sb.WriteString("<ColorPalette>\n")
+		}
+		colors := make([]string, 0, len(group.Colors))
+		for _, name := range sortedKeys(group.Colors) {
+			colors = append(colors, fmt.Sprintf("%s: %s", jsString(name), jsString(group.Colors[name])))
+		}
+		fmt.Fprintf(&sb, "  <ColorItem\n    title=%s\n    subtitle=%s\n    colors={{ %s }}\n  />\n",
+			jsxString(group.Label), jsxString("--color-"+group.Prefix+"*"), strings.Join(colors, ", "))
+	}
+	if sb.Len() == 0 {
+		return ""
+	}
+	sb.WriteString("</ColorPalette>\n")
+	return sb.String()
+}
+
+func storybookTypography(specs *extractor.DesignSpecs) string {
+	var sb strings.Builder
+	typo := specs.Typography
+	if typo.FontFamily != "" && len(typo.FontSizes) > 0 {
+		fmt.Fprintf(&sb, "## %s\n\n", mdxText(typo.FontFamily))
+		sizes := make([]string, 0, len(typo.FontSizes))
+		for _, size := range sortedFloats(typo.FontSizes) {
+			sizes = append(sizes, jsString(px(size)))
+		}
+		fmt.Fprintf(&sb, "<Typeset\n  fontFamily=%s\n  fontSizes={[%s]}\n  fontWeight={400}\n  sampleText=%s\n/>\n\n",
+			jsxString(typo.FontFamily), strings.Join(sizes, ", "), jsxString(storybookSampleText))
+	}
+
+	if len(specs.TextStyles) > 0 {
+		sb.WriteString("## Text Styles\n\n")
+		for _, name := range sortedKeys(specs.TextStyles) {
+			ts := specs.TextStyles[name]
+			fmt.Fprintf(&sb, "### %s\n\n", mdxText(name))
+			fmt.Fprintf(&sb, "Class `.%s` · %gpx · weight %g", textStyleClass(name), ts.FontSize, ts.FontWeight)
+			if ts.LineHeight > 0 {
+				fmt.Fprintf(&sb, " · line height %gpx", ts.LineHeight)
+			}
+			sb.WriteString("\n\n")
+			family := ts.FontFamily
+			if family == "" {
+				family = typo.FontFamily
+			}
+			fmt.Fprintf(&sb, "<Typeset\n  fontFamily=%s\n  fontSizes={[%s]}\n  fontWeight={%g}\n  sampleText=%s\n/>\n\n",
+				jsxString(family), jsString(px(ts.FontSize)), ts.FontWeight, jsxString(storybookSampleText))
+		}
+	}
+	return sb.String()
+}
+
+func storybookTokens(specs *extractor.DesignSpecs) string {
+	var sb strings.Builder
+	table := func(title string, rows [][3]string, preview func(value string) string) {
+		if len(rows) == 0 {
+			return
+		}
+		fmt.Fprintf(&sb, "## %s\n\n| Token | Value | Preview |\n|-------|-------|---------|\n", title)
+		for _, row := range rows {
+			fmt.Fprintf(&sb, "| `%s` | `%s` | %s |\n", row[0], mdxTableCell(row[1]), preview(row[2]))
+		}
+		sb.WriteString("\n")
+	}
+	box := func(style string) func(string) string {
+		return func(value string) string {
+			return fmt.Sprintf(`<div style={{ %s: %s, width: 48, height: 32, background: "#f5f5f5", border: "1px solid #ddd" }} />`, style, jsString(value))
+		}
+	}
+	values := func(prefix string, m map[string]float64) [][3]string {
+		var rows [][3]string
+		for _, name := range sortedKeys(m) {
+			v := px(m[name])
+			rows = append(rows, [3]string{"--" + prefix + toKebabCase(name), v, v})
+		}
+		return rows
+	}
+
+	table("Spacing", values("space-", specs.Spacing.Values), func(value string) string {
+		return fmt.Sprintf(`<div style={{ width: %s, height: 12, background: "#4c8bf5" }} />`, jsString(value))
+	})
+	table("Border Radius", values("radius-", specs.Radii.Values), box("borderRadius"))
+
+	var borders [][3]string
+	for _, name := range sortedKeys(specs.Borders) {
+		v := cssBorder(specs.Borders[name])
+		borders = append(borders, [3]string{"--border-" + toKebabCase(name), v, v})
+	}
+	table("Borders", borders, func(value string) string {
+		return fmt.Sprintf(`<div style={{ border: %s, width: 48, height: 32 }} />`, jsString(value))
+	})
+
+	var shadows [][3]string
+	names, shadowValues := shadowTokens(specs.Shadows)
+	for _, name := range names {
+		shadows = append(shadows, [3]string{"--shadow-" + name, shadowValues[name], shadowValues[name]})
+	}
+	table("Shadows", shadows, box("boxShadow"))
+
+	var breakpoints [][3]string
+	for _, bp := range specs.Layout.Breakpoints {
+		breakpoints = append(breakpoints, [3]string{"--breakpoint-" + bp.Name, px(bp.Width), ""})
+	}
+	table("Breakpoints", breakpoints, func(string) string { return "" })
+	return sb.String()
+}
+
+func storybookIcons(specs *extractor.DesignSpecs) string {
+	if len(specs.Icons) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("<IconGallery>\n")
+	for _, icon := range specs.Icons {
+		// Icons are embedded as images: their SVG attributes are not valid JSX.
+		src := "data:image/svg+xml;base64," + base64.StdEncoding.EncodeToString([]byte(icon.SVG))
+		fmt.Fprintf(&sb, "  <IconItem name=%s>\n    <img src=%s alt=\"\" />\n  </IconItem>\n", jsxString(icon.Name), jsxString(src))
+	}
+	sb.WriteString("</IconGallery>\n")
+	return sb.String()
+}
+
+func storybookComponents(specs *extractor.DesignSpecs) string {
+	if len(specs.Components) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("| Component | Page | Size | Variants | Description |\n|-----------|------|------|----------|-------------|\n")
+	for _, c := range specs.Components {
+		variants := ""
+		if c.VariantCount > 0 {
+			variants = fmt.Sprint(c.VariantCount)
+		}
+		fmt.Fprintf(&sb, "| %s | %s | %g × %g | %s | %s |\n",
+			mdxTableCell(mdxText(c.Name)), mdxTableCell(mdxText(c.Page)), c.Width, c.Height, variants,
+			mdxTableCell(mdxText(strings.Join(strings.Fields(c.Description), " "))))
+	}
+	return sb.String()
+}
+
+// sortedFloats returns the distinct values of m in ascending order.
+func sortedFloats(m map[string]float64) []float64 {
+	seen := make(map[float64]bool, len(m))
+	var values []float64
+	for _, name := range sortedKeys(m) {
+		if v := m[name]; !seen[v] {
+			seen[v] = true
+			values = append(values, v)
+		}
+	}
+	sort.Float64s(values)
+	return values
+}
+
+// jsString returns s as a JavaScript string literal.
+func jsString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+// jsxString returns s as a JSX attribute value: a JavaScript string literal in braces.
+func jsxString(s string) string {
+	return "{" + jsString(s) + "}"
+}
+
+// mdxText escapes the characters MDX would read as JSX or expressions in text.
+func mdxText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "{", `\{`, "}", `\}`, "<", `\<`, ">", `\>`).Replace(s)
+}
+
+// mdxTableCell escapes the pipes that would end a table cell.
+func mdxTableCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+// mdxComment makes s safe to place inside an MDX comment.
+func mdxComment(s string) string {
+	return strings.ReplaceAll(s, "*/", "* /")
+}
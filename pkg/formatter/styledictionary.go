@@ -0,0 +1,156 @@
+package formatter
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hellenic-development/figma-extractor/pkg/extractor"
+)
+
+// ToStyleDictionary renders design specifications as an Amazon Style Dictionary source tree.
+// Tokens follow the Category/Type/Item convention and are split into one file per category
+// (properties/color.json, properties/size.json, properties/font.json, properties/shadow.json).
+// Figma variables are written to properties/<collection>.json using the default mode; every
+// other mode is written to themes/<mode>/<collection>.json so it can be layered on top with a
+// separate Style Dictionary source without colliding with the defaults. Variables aliasing
+// other variables reference their properties.
+//
+// Dimension values are emitted as pixel strings ("16px"); use transforms that leave them
+// untouched (e.g. disable size/rem) when building.
+func ToStyleDictionary(specs *extractor.DesignSpecs) ([]File, error) {
+	trees := make(map[string]map[string]any) // file name -> token tree
+
+	tree := func(name string) map[string]any {
+		if _, ok := trees[name]; !ok {
+			trees[name] = make(map[string]any)
+		}
+		return trees[name]
+	}
+
+	// Colors
+	colors := map[string]map[string]string{
+		"primary":    specs.Colors.Primary,
+		"secondary":  specs.Colors.Secondary,
+		"background": specs.Colors.Background,
+		"text":       specs.Colors.Text,
+		"status":     specs.Colors.Status,
+		"border":     specs.Colors.Border,
+		"brand":      specs.Colors.Brand,
+		"accent":     specs.Colors.Accent,
+		"neutral":    specs.Colors.Neutral,
+	}
+	for _, category := range sortedKeys(colors) {
+		for _, name := range sortedKeys(colors[category]) {
+			setToken(tree("properties/color.json"), append([]string{"color", category}, tokenPath(name)...), sdToken(colors[category][name]))
+		}
+	}
+
+	// Sizes
+	for _, name := range sortedKeys(specs.Typography.FontSizes) {
+		setToken(tree("properties/size.json"), []string{"size", "font", name}, sdToken(px(specs.Typography.FontSizes[name])))
+	}
+	for _, name := range sortedKeys(specs.Typography.LineHeights) {
+		setToken(tree("properties/size.json"), append([]string{"size", "line-height"}, tokenPath(name)...), sdToken(px(specs.Typography.LineHeights[name])))
+	}
+	for _, name := range sortedKeys(specs.Typography.LetterSpacings) {
+		setToken(tree("properties/size.json"), append([]string{"size", "letter-spacing"}, tokenPath(name)...), sdToken(px(specs.Typography.LetterSpacings[name])))
+	}
+	for _, name := range sortedKeys(specs.Spacing.Values) {
+		setToken(tree("properties/size.json"), []string{"size", "spacing", name}, sdToken(px(specs.Spacing.Values[name])))
+	}
+	for _, name := range sortedKeys(specs.Radii.Values) {
+		setToken(tree("properties/size.json"), []string{"size", "radius", name}, sdToken(px(specs.Radii.Values[name])))
+	}
+
+	// Fonts
+	if specs.Typography.FontFamily != "" {
+		setToken(tree("properties/font.json"), []string{"font", "family", "primary"},
+			sdToken(fmt.Sprintf("'%s', system-ui, -apple-system, sans-serif", specs.Typography.FontFamily)))
+	}
+	for _, name := range sortedKeys(specs.Typography.FontWeights) {
+		setToken(tree("properties/font.json"), append([]string{"font", "weight"}, tokenPath(name)...), sdToken(specs.Typography.FontWeights[name]))
+	}
+
+	// Shadows: layered shadows sharing a name are combined into one comma-separated value.
+	shadows := make(map[string][]string)
+	var shadowOrder []string
+	for i, shadow := range specs.Shadows {
+		name := strings.Join(tokenPath(shadow.Name), "/")
+		if name == "" {
+			name = fmt.Sprintf("shadow-%d", i+1)
+		}
+		if _, ok := shadows[name]; !ok {
+			shadowOrder = append(shadowOrder, name)
+		}
+		shadows[name] = append(shadows[name], cssShadow(shadow))
+	}
+	for _, name := range shadowOrder {
+		setToken(tree("properties/shadow.json"), append([]string{"shadow"}, strings.Split(name, "/")...), sdToken(strings.Join(shadows[name], ", ")))
+	}
+
+	// Variables
+	for _, coll := range specs.Variables {
+		collName := sdCollectionName(coll.Name)
+		for _, mode := range coll.Modes {
+			file := fmt.Sprintf("properties/%s.json", collName)
+			if !mode.Default {
+				file = fmt.Sprintf("themes/%s/%s.json", toKebabCase(mode.Name), collName)
+			}
+			for _, v := range mode.Variables {
+				setToken(tree(file), append([]string{collName}, tokenPath(v.Name)...), sdToken(sdVariableValue(v)))
+			}
+		}
+	}
+
+	files := make([]File, 0, len(trees))
+	for _, name := range sortedKeys(trees) {
+		data, err := json.MarshalIndent(trees[name], "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("marshal %s: %w", name, err)
+		}
+		files = append(files, File{Name: name, Content: append(data, '\n')})
+	}
+
+	return files, nil
+}
+
+// renderStyleDictionary adapts ToStyleDictionary to the renderFunc signature.
+func renderStyleDictionary(in Input) ([]File, error) {
+	return ToStyleDictionary(in.Specs)
+}
+
+// sdToken builds a single Style Dictionary token object.
+func sdToken(value any) map[string]any {
+	return map[string]any{"value": value}
+}
+
+// sdCollectionName returns the top-level group of the variables of a collection.
+func sdCollectionName(name string) string {
+	if name := strings.Join(tokenPath(name), "-"); name != "" {
+		return name
+	}
+	return "variables"
+}
+
+// sdVariableValue maps a resolved variable onto a Style Dictionary value. Aliases become
+// references to the property of the variable they alias ("{primitives.primary.500.value}").
+func sdVariableValue(v extractor.Variable) any {
+	if len(v.Aliases) > 0 {
+		ref := v.Aliases[0]
+		return "{" + strings.Join(append([]string{sdCollectionName(ref.Collection)}, tokenPath(ref.Name)...), ".") + ".value}"
+	}
+	switch v.Type {
+	case "COLOR":
+		return v.Color
+	case "FLOAT":
+		if v.Dimension {
+			return px(v.Number)
+		}
+		return v.Number
+	case "BOOLEAN":
+		return v.Bool
+	default:
+		return v.String
+	}
+}
@@ -0,0 +1,238 @@
+package formatter
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/hellenic-development/figma-extractor/pkg/extractor"
+)
+
+// ToSwift renders design specifications as SwiftUI source for iOS projects: a Color extension
+// with one static color per palette entry, a Font extension built from the font family and
+// size scale, Font.Weight constants, CGFloat constants in points for spacing, radii, line
+// heights and letter spacings (for tracking(_:)), and shadow tokens. Figma variables become
+// one namespace per collection and mode.
+//
+// Shadow radii are half the Figma blur, which approximates SwiftUI's shadow(radius:) rendering.
+func ToSwift(specs *extractor.DesignSpecs, fileName string) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("// Design tokens extracted from Figma: %s\n", fileName))
+	sb.WriteString("// Generated by figma-extractor. Do not edit by hand.\n\n")
+	sb.WriteString("import SwiftUI\n\n")
+
+	// Colors
+	var colorLines []string
+	for _, group := range colorGroups(specs.Colors) {
+		for _, name := range sortedKeys(group.Colors) {
+			ident := swiftIdent(toCamelCase(group.Key+" "+name), "color")
+			colorLines = append(colorLines, fmt.Sprintf("static let %s = %s", ident, swiftColor(group.Colors[name])))
+		}
+	}
+	writeSwiftBlock(&sb, "public extension Color", colorLines)
+
+	// Fonts
+	var fontLines []string
+	for _, name := range sortedKeys(specs.Typography.FontSizes) {
+		ident := swiftIdent(toCamelCase("text "+name), "text")
+		size := specs.Typography.FontSizes[name]
+		if specs.Typography.FontFamily != "" {
+			fontLines = append(fontLines, fmt.Sprintf("static let %s = Font.custom(%q, size: %g)", ident, specs.Typography.FontFamily, size))
+		} else {
+			fontLines = append(fontLines, fmt.Sprintf("static let %s = Font.system(size: %g)", ident, size))
+		}
+	}
+	writeSwiftBlock(&sb, "public extension Font", fontLines)
+
+	var weightLines []string
+	for _, name := range sortedKeys(specs.Typography.FontWeights) {
+		ident := swiftIdent(toCamelCase(name), "weight")
+		weightLines = append(weightLines, fmt.Sprintf("static let %s: Font.Weight = .%s", ident, swiftFontWeight(specs.Typography.FontWeights[name])))
+	}
+	writeSwiftBlock(&sb, "public enum FontWeights", weightLines)
+
+	var leadingLines []string
+	for _, name := range sortedKeys(specs.Typography.LineHeights) {
+		ident := swiftIdent(toCamelCase(name), "leading")
+		leadingLines = append(leadingLines, fmt.Sprintf("static let %s: CGFloat = %g", ident, specs.Typography.LineHeights[name]))
+	}
+	writeSwiftBlock(&sb, "public enum LineHeights", leadingLines)
+
+	var trackingLines []string
+	for _, name := range sortedKeys(specs.Typography.LetterSpacings) {
+		ident := swiftIdent(toCamelCase(name), "tracking")
+		trackingLines = append(trackingLines, fmt.Sprintf("static let %s: CGFloat = %g", ident, specs.Typography.LetterSpacings[name]))
+	}
+	writeSwiftBlock(&sb, "public enum Tracking", trackingLines)
+
+	// Spacing and radii
+	var spaceLines []string
+	for _, name := range sortedKeys(specs.Spacing.Values) {
+		ident := swiftIdent(toCamelCase("space "+name), "space")
+		spaceLines = append(spaceLines, fmt.Sprintf("static let %s: CGFloat = %g", ident, specs.Spacing.Values[name]))
+	}
+	writeSwiftBlock(&sb, "public enum Spacing", spaceLines)
+
+	var radiusLines []string
+	for _, name := range sortedKeys(specs.Radii.Values) {
+		ident := swiftIdent(toCamelCase(name), "radius")
+		radiusLines = append(radiusLines, fmt.Sprintf("static let %s: CGFloat = %g", ident, specs.Radii.Values[name]))
+	}
+	if len(radiusLines) > 0 {
+		radiusLines = append(radiusLines, "static let full: CGFloat = 9999")
+	}
+	writeSwiftBlock(&sb, "public enum Radius", radiusLines)
+
+	// Shadows: SwiftUI applies one shadow per modifier, so only the first layer of each name is kept.
+	var shadowLines []string
+	seenShadows := make(map[string]bool)
+	for i, shadow := range specs.Shadows {
+		if shadow.Type == "INNER_SHADOW" {
+			continue
+		}
+		ident := swiftIdent(toCamelCase(shadow.Name), "shadow")
+		if ident == "" {
+			ident = fmt.Sprintf("shadow%d", i+1)
+		}
+		if seenShadows[ident] {
+			continue
+		}
+		seenShadows[ident] = true
+		shadowLines = append(shadowLines, fmt.Sprintf("static let %s = ShadowToken(color: %s, radius: %g, x: %g, y: %g)",
+			ident, swiftColor(shadow.Color), shadow.Blur/2, shadow.X, shadow.Y))
+	}
+	if len(shadowLines) > 0 {
+		sb.WriteString("public struct ShadowToken {\n")
+		sb.WriteString("    public let color: Color\n")
+		sb.WriteString("    public let radius: CGFloat\n")
+		sb.WriteString("    public let x: CGFloat\n")
+		sb.WriteString("    public let y: CGFloat\n")
+		sb.WriteString("}\n\n")
+		sb.WriteString("public extension View {\n")
+		sb.WriteString("    func shadow(_ token: ShadowToken) -> some View {\n")
+		sb.WriteString("        shadow(color: token.color, radius: token.radius, x: token.x, y: token.y)\n")
+		sb.WriteString("    }\n")
+		sb.WriteString("}\n\n")
+	}
+	writeSwiftBlock(&sb, "public enum Shadows", shadowLines)
+
+	// Variables: enum <Collection> { enum <Mode> { ... } }
+	for _, coll := range specs.Variables {
+		collIdent := swiftTypeName(coll.Name, "Variables")
+		sb.WriteString(fmt.Sprintf("public enum %s {\n", collIdent))
+		for _, mode := range coll.Modes {
+			sb.WriteString(fmt.Sprintf("    public enum %s {\n", swiftTypeName(mode.Name, "Mode")))
+			for _, v := range mode.Variables {
+				sb.WriteString(fmt.Sprintf("        public static let %s = %s\n", swiftIdent(toCamelCase(v.Name), "value"), swiftVariableValue(v)))
+			}
+			sb.WriteString("    }\n")
+		}
+		sb.WriteString("}\n\n")
+	}
+
+	// Hex color helper used by the declarations above.
+	sb.WriteString("private extension Color {\n")
+	sb.WriteString("    init(hex: UInt32) {\n")
+	sb.WriteString("        self.init(\n")
+	sb.WriteString("            red: Double((hex >> 16) & 0xFF) / 255,\n")
+	sb.WriteString("            green: Double((hex >> 8) & 0xFF) / 255,\n")
+	sb.WriteString("            blue: Double(hex & 0xFF) / 255\n")
+	sb.WriteString("        )\n")
+	sb.WriteString("    }\n")
+	sb.WriteString("}\n")
+
+	return sb.String()
+}
+
+// renderSwift adapts ToSwift to the renderFunc signature.
+func renderSwift(in Input) ([]File, error) {
+	return []File{{Name: "DesignTokens.swift", Content: []byte(ToSwift(in.Specs, in.FileName))}}, nil
+}
+
+// writeSwiftBlock writes a type or extension declaration whose members are public static lets.
+// Nothing is written when there are no members.
+func writeSwiftBlock(sb *strings.Builder, decl string, lines []string) {
+	if len(lines) == 0 {
+		return
+	}
+
+	// Members of a "public extension" inherit its access level; others need it spelled out.
+	access := "public "
+	if strings.HasPrefix(decl, "public extension") {
+		access = ""
+	}
+
+	sb.WriteString(decl + " {\n")
+	for _, line := range lines {
+		sb.WriteString("    " + access + line + "\n")
+	}
+	sb.WriteString("}\n\n")
+}
+
+// swiftColor formats a "#RRGGBB" hex color using the generated Color(hex:) initializer.
+func swiftColor(hex string) string {
+	return fmt.Sprintf("Color(hex: 0x%s)", strings.TrimPrefix(hex, "#"))
+}
+
+// swiftFontWeight maps a numeric CSS font weight onto the closest SwiftUI Font.Weight.
+func swiftFontWeight(weight float64) string {
+	names := []string{"ultraLight", "thin", "light", "regular", "medium", "semibold", "bold", "heavy", "black"}
+	i := int(math.Round(weight/100)) - 1
+	if i < 0 {
+		i = 0
+	}
+	if i >= len(names) {
+		i = len(names) - 1
+	}
+	return names[i]
+}
+
+// swiftVariableValue formats a resolved variable value as a Swift expression.
+func swiftVariableValue(v extractor.Variable) string {
+	switch v.Type {
+	case "COLOR":
+		return swiftColor(v.Color)
+	case "FLOAT":
+		return fmt.Sprintf("CGFloat(%g)", v.Number)
+	case "BOOLEAN":
+		return fmt.Sprintf("%t", v.Bool)
+	default:
+		return fmt.Sprintf("%q", v.String)
+	}
+}
+
+// swiftKeywords lists reserved words that must be escaped with backticks when used as identifiers.
+var swiftKeywords = map[string]bool{
+	"default": true, "case": true, "class": true, "enum": true, "extension": true, "func": true,
+	"import": true, "init": true, "let": true, "protocol": true, "static": true, "struct": true,
+	"var": true, "if": true, "else": true, "for": true, "in": true, "return": true, "self": true,
+	"switch": true, "where": true, "while": true, "public": true, "private": true, "internal": true,
+}
+
+// swiftIdent makes a camelCase name usable as a Swift identifier: names starting with a digit
+// are prefixed and reserved words are escaped.
+func swiftIdent(name, prefix string) string {
+	if name == "" {
+		return ""
+	}
+	if name[0] >= '0' && name[0] <= '9' {
+		name = prefix + name
+	}
+	if swiftKeywords[name] {
+		return "`" + name + "`"
+	}
+	return name
+}
+
+// swiftTypeName converts a Figma name into an UpperCamelCase Swift type name.
+func swiftTypeName(name, fallback string) string {
+	ident := toPascalCase(name)
+	if ident == "" {
+		return fallback
+	}
+	if ident[0] >= '0' && ident[0] <= '9' {
+		return fallback + ident
+	}
+	return ident
+}
@@ -0,0 +1,123 @@
+package formatter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hellenic-development/figma-extractor/pkg/extractor"
+)
+
+// ToTailwind renders design specifications as a Tailwind CSS config whose theme.extend holds
+// the extracted colors, font families, sizes, weights and line heights, spacing, border radii,
+// shadows, blurs and breakpoints. Theme keys are named like the tokens of the css format
+// (theme.extend.colors.primary.500 for --color-primary-500), so the config can be checked with
+// the lint command, and it can be used as is or as a preset of an existing config.
+func ToTailwind(specs *extractor.DesignSpecs, fileName string) string {
+	return toTailwind(specs, fileName, Units{})
+}
+
+// toTailwind is like ToTailwind but emits the typography, spacing and radius tokens in units.
+func toTailwind(specs *extractor.DesignSpecs, fileName string, units Units) string {
+	extend := &tsObject{}
+
+	extend.setObject("colors", tailwindColors(specs))
+
+	fontFamily := &tsObject{}
+	if specs.Typography.FontFamily != "" {
+		fontFamily.set("primary", fmt.Sprintf("[%s, \"system-ui\", \"-apple-system\", \"sans-serif\"]", strconv.Quote(specs.Typography.FontFamily)))
+	}
+	extend.setObject("fontFamily", fontFamily)
+
+	extend.setObject("fontSize", tailwindValues(specs.Typography.FontSizes, units.size))
+	extend.setObject("fontWeight", tailwindValues(specs.Typography.FontWeights, func(v float64) string { return fmt.Sprintf("%g", v) }))
+	extend.setObject("lineHeight", tailwindValues(specs.Typography.LineHeights, units.size))
+	extend.setObject("spacing", tailwindValues(specs.Spacing.Values, units.size))
+
+	radii := tailwindValues(specs.Radii.Values, units.size)
+	if !radii.empty() {
+		radii.set("full", strconv.Quote("9999px"))
+	}
+	extend.setObject("borderRadius", radii)
+
+	shadows := &tsObject{}
+	if len(specs.Shadows) > 0 {
+		names, values := shadowTokens(specs.Shadows)
+		for _, name := range names {
+			shadows.set(name, strconv.Quote(values[name]))
+		}
+	}
+	extend.setObject("boxShadow", shadows)
+
+	blurs := &tsObject{}
+	if len(specs.Blurs) > 0 {
+		names, values := blurTokens(specs.Blurs)
+		for _, name := range names {
+			blurs.set(name, strconv.Quote(px(values[name].Radius/2)))
+		}
+	}
+	extend.setObject("blur", blurs)
+
+	screens := &tsObject{}
+	for _, bp := range specs.Layout.Breakpoints {
+		screens.set(toKebabCase(bp.Name), strconv.Quote(px(bp.Width)))
+	}
+	extend.setObject("screens", screens)
+
+	theme := &tsObject{}
+	if extend.empty() {
+		theme.set("extend", "{}")
+	} else {
+		theme.setObject("extend", extend)
+	}
+	config := &tsObject{}
+	config.setObject("theme", theme)
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("/* Design tokens extracted from Figma: %s */\n", fileName))
+	sb.WriteString("/* Generated by figma-extractor. Do not edit by hand. */\n\n")
+	sb.WriteString("/** @type {import('tailwindcss').Config} */\n")
+	sb.WriteString("module.exports = ")
+	config.write(&sb, "")
+	sb.WriteString(";\n")
+	return sb.String()
+}
+
+// renderTailwind adapts ToTailwind to the renderFunc signature.
+func renderTailwind(in Input) ([]File, error) {
+	return []File{{Name: "tailwind.config.js", Content: []byte(toTailwind(in.Specs, in.FileName, in.Units))}}, nil
+}
+
+// tailwindColors returns the colors of the palette as a Tailwind theme object, a nested object
+// per color category named after the prefix of its css tokens.
+func tailwindColors(specs *extractor.DesignSpecs) *tsObject {
+	colors := &tsObject{}
+	for _, group := range colorGroups(specs.Colors) {
+		if len(group.Colors) == 0 {
+			continue
+		}
+		// Status colors have no prefix and sit at the top of the palette.
+		category := colors
+		prefix := strings.TrimSuffix(group.Prefix, "-")
+		if prefix != "" {
+			category = &tsObject{}
+		}
+		for _, name := range sortedKeys(group.Colors) {
+			category.set(toKebabCase(name), strconv.Quote(group.Colors[name]))
+		}
+		if prefix != "" {
+			colors.setObject(prefix, category)
+		}
+	}
+	return colors
+}
+
+// tailwindValues returns the values of a token scale as a Tailwind theme object, formatted by
+// format.
+func tailwindValues(values map[string]float64, format func(float64) string) *tsObject {
+	obj := &tsObject{}
+	for _, name := range sortedKeys(values) {
+		obj.set(toKebabCase(name), strconv.Quote(format(values[name])))
+	}
+	return obj
+}
@@ -0,0 +1,75 @@
+package formatter
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/hellenic-development/figma-extractor/pkg/extractor"
+)
+
+// TemplateData is the value templates are executed with. The embedded DesignSpecs fields are
+// available directly, e.g. {{.Colors.Primary}} or {{.Typography.FontFamily}}.
+type TemplateData struct {
+	*extractor.DesignSpecs
+	FileName string // Figma file name
+	ImageDir string // directory exported assets were written to
+}
+
+// TemplateFuncs returns the functions available to user-defined templates in addition to
+// the text/template builtins. Ranging over a map in a template visits keys in sorted order,
+// so output is deterministic without extra helpers.
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"kebab":  toKebabCase,
+		"camel":  toCamelCase,
+		"pascal": toPascalCase,
+		"snake":  func(s string) string { return strings.ReplaceAll(toKebabCase(s), "-", "_") },
+		"path":   tokenPath,
+		"px":     px,
+		"shadow": cssShadow,
+		"upper":  strings.ToUpper,
+		"lower":  strings.ToLower,
+		"trim":   strings.TrimSpace,
+		"join":   strings.Join,
+		"replace": func(old, new, s string) string {
+			return strings.ReplaceAll(s, old, new)
+		},
+	}
+}
+
+// ParseTemplate parses text as a user-defined output template with TemplateFuncs available.
+func ParseTemplate(text string) (*template.Template, error) {
+	return template.New("output").Funcs(TemplateFuncs()).Option("missingkey=error").Parse(text)
+}
+
+// ToTemplate renders design specifications with a user-defined text/template, so bespoke
+// output formats can be produced without changing this package. See TemplateData for the
+// value the template receives and TemplateFuncs for the available helper functions.
+func ToTemplate(specs *extractor.DesignSpecs, fileName, imageDir, text string) (string, error) {
+	tmpl, err := ParseTemplate(text)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	data := TemplateData{DesignSpecs: specs, FileName: fileName, ImageDir: imageDir}
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// renderTemplate adapts ToTemplate to the renderFunc signature.
+func renderTemplate(in Input) ([]File, error) {
+	if in.Template == "" {
+		return nil, errors.New("no output template given")
+	}
+	out, err := ToTemplate(in.Specs, in.FileName, in.ImageDir, in.Template)
+	if err != nil {
+		return nil, fmt.Errorf("execute template: %w", err)
+	}
+	return []File{{Name: "output.txt", Content: []byte(out)}}, nil
+}
@@ -0,0 +1,192 @@
+package formatter
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hellenic-development/figma-extractor/pkg/extractor"
+)
+
+// tokensStudioGlobal is the token set holding the extracted styles.
+const tokensStudioGlobal = "global"
+
+// ToTokensStudio renders design specifications in the single-file JSON format of the Tokens
+// Studio for Figma plugin (formerly Figma Tokens), so extracted values can be loaded back into
+// it. Styles go into the "global" token set; every mode of a Figma variable collection becomes
+// a token set of its own ("<collection>/<mode>") and a theme enabling it on top of "global".
+//
+// Dimensions are unitless pixel values as the plugin writes them ("16"), and the font family of
+// typography tokens references the fontFamilies token.
+func ToTokensStudio(specs *extractor.DesignSpecs) ([]byte, error) {
+	global := make(map[string]any)
+
+	// Colors
+	for _, group := range colorGroups(specs.Colors) {
+		for _, name := range sortedKeys(group.Colors) {
+			setToken(global, append([]string{"color", group.Key}, tokenPath(name)...), tsToken("color", group.Colors[name]))
+		}
+	}
+
+	// Typography
+	if specs.Typography.FontFamily != "" {
+		setToken(global, []string{"fontFamilies", "primary"}, tsToken("fontFamilies", specs.Typography.FontFamily))
+	}
+	for _, name := range sortedKeys(specs.Typography.FontSizes) {
+		setToken(global, []string{"fontSizes", name}, tsToken("fontSizes", tsNumber(specs.Typography.FontSizes[name])))
+	}
+	for _, name := range sortedKeys(specs.Typography.FontWeights) {
+		setToken(global, append([]string{"fontWeights"}, tokenPath(name)...), tsToken("fontWeights", tsNumber(specs.Typography.FontWeights[name])))
+	}
+	for _, name := range sortedKeys(specs.Typography.LineHeights) {
+		setToken(global, append([]string{"lineHeights"}, tokenPath(name)...), tsToken("lineHeights", tsNumber(specs.Typography.LineHeights[name])))
+	}
+	for _, name := range sortedKeys(specs.Typography.LetterSpacings) {
+		setToken(global, append([]string{"letterSpacing"}, tokenPath(name)...), tsToken("letterSpacing", tsNumber(specs.Typography.LetterSpacings[name])))
+	}
+	for _, name := range sortedKeys(specs.TextStyles) {
+		setToken(global, append([]string{"typography"}, tokenPath(name)...), tsToken("typography", tsTypography(specs.TextStyles[name], specs.Typography.FontFamily)))
+	}
+
+	// Spacing, radii and borders
+	for _, name := range sortedKeys(specs.Spacing.Values) {
+		setToken(global, []string{"spacing", name}, tsToken("spacing", tsNumber(specs.Spacing.Values[name])))
+	}
+	for _, name := range sortedKeys(specs.Radii.Values) {
+		setToken(global, []string{"borderRadius", name}, tsToken("borderRadius", tsNumber(specs.Radii.Values[name])))
+	}
+	for _, name := range sortedKeys(specs.Borders) {
+		b := specs.Borders[name]
+		setToken(global, append([]string{"border"}, tokenPath(name)...), tsToken("border", map[string]any{
+			"color": b.Color,
+			"width": tsNumber(b.Width),
+			"style": b.Style,
+		}))
+	}
+
+	// Shadows: layered shadows sharing a name become a single token with an array value.
+	shadows := make(map[string][]any)
+	var shadowOrder []string
+	for i, shadow := range specs.Shadows {
+		name := strings.Join(tokenPath(shadow.Name), "/")
+		if name == "" {
+			name = fmt.Sprintf("shadow-%d", i+1)
+		}
+		if _, ok := shadows[name]; !ok {
+			shadowOrder = append(shadowOrder, name)
+		}
+		shadowType := "dropShadow"
+		if shadow.Type == "INNER_SHADOW" {
+			shadowType = "innerShadow"
+		}
+		shadows[name] = append(shadows[name], map[string]any{
+			"x":      tsNumber(shadow.X),
+			"y":      tsNumber(shadow.Y),
+			"blur":   tsNumber(shadow.Blur),
+			"spread": tsNumber(shadow.Spread),
+			"color":  shadow.Color,
+			"type":   shadowType,
+		})
+	}
+	for _, name := range shadowOrder {
+		var value any = shadows[name]
+		if len(shadows[name]) == 1 {
+			value = shadows[name][0]
+		}
+		setToken(global, append([]string{"boxShadow"}, strings.Split(name, "/")...), tsToken("boxShadow", value))
+	}
+
+	// Breakpoints
+	for _, bp := range specs.Layout.Breakpoints {
+		setToken(global, []string{"sizing", "breakpoint", bp.Name}, tsToken("sizing", tsNumber(bp.Width)))
+	}
+
+	root := map[string]any{tokensStudioGlobal: global}
+	order := []string{tokensStudioGlobal}
+	themes := []map[string]any{}
+
+	// Variables: a token set and a theme per mode.
+	for _, coll := range specs.Variables {
+		for _, mode := range coll.Modes {
+			set := coll.Name + "/" + mode.Name
+			tokens := make(map[string]any)
+			for _, v := range mode.Variables {
+				setToken(tokens, tokenPath(v.Name), tsVariableToken(v))
+			}
+			root[set] = tokens
+			order = append(order, set)
+			themes = append(themes, map[string]any{
+				"id":    toKebabCase(set),
+				"name":  mode.Name,
+				"group": coll.Name,
+				"selectedTokenSets": map[string]string{
+					tokensStudioGlobal: "source",
+					set:                "enabled",
+				},
+			})
+		}
+	}
+
+	root["$themes"] = themes
+	root["$metadata"] = map[string]any{"tokenSetOrder": order}
+	return json.MarshalIndent(root, "", "  ")
+}
+
+// renderTokensStudio adapts ToTokensStudio to the renderFunc signature.
+func renderTokensStudio(in Input) ([]File, error) {
+	data, err := ToTokensStudio(in.Specs)
+	if err != nil {
+		return nil, fmt.Errorf("render tokensstudio: %w", err)
+	}
+	return []File{{Name: "tokens-studio.json", Content: append(data, '\n')}}, nil
+}
+
+// tsToken builds a single Tokens Studio token object.
+func tsToken(tokenType string, value any) map[string]any {
+	return map[string]any{
+		"value": value,
+		"type":  tokenType,
+	}
+}
+
+// tsNumber formats a pixel value the way Tokens Studio stores it, without unit.
+func tsNumber(v float64) string {
+	return fmt.Sprintf("%g", v)
+}
+
+// tsTypography builds the value of a Tokens Studio typography token. The font family is a
+// reference to the fontFamilies token when it is the primary one.
+func tsTypography(ts extractor.TextStyle, primaryFamily string) map[string]any {
+	family := ts.FontFamily
+	if family != "" && family == primaryFamily {
+		family = "{fontFamilies.primary}"
+	}
+	lineHeight := "AUTO"
+	if ts.LineHeight > 0 {
+		lineHeight = tsNumber(ts.LineHeight)
+	}
+	return map[string]any{
+		"fontFamily":    family,
+		"fontWeight":    tsNumber(ts.FontWeight),
+		"fontSize":      tsNumber(ts.FontSize),
+		"lineHeight":    lineHeight,
+		"letterSpacing": tsNumber(ts.LetterSpacing),
+	}
+}
+
+// tsVariableToken maps a resolved variable onto a Tokens Studio token.
+func tsVariableToken(v extractor.Variable) map[string]any {
+	switch v.Type {
+	case "COLOR":
+		return tsToken("color", v.Color)
+	case "FLOAT":
+		if v.Dimension {
+			return tsToken("dimension", tsNumber(v.Number))
+		}
+		return tsToken("number", tsNumber(v.Number))
+	case "BOOLEAN":
+		return tsToken("boolean", fmt.Sprint(v.Bool))
+	default:
+		return tsToken("text", v.String)
+	}
+}
@@ -0,0 +1,267 @@
+package formatter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hellenic-development/figma-extractor/pkg/extractor"
+)
+
+// ToTypeScript renders design specifications as a typed TypeScript theme module. Every token
+// category is exported as a const object declared "as const", so values keep their literal
+// types (e.g. "#3B82F6" rather than string) and typos in token names are compile errors.
+// A combined theme object, its Theme type and key union types are exported as well.
+// Figma variables are exported as a themes object keyed by collection and mode.
+func ToTypeScript(specs *extractor.DesignSpecs, fileName string) string {
+	return toTypeScript(specs, fileName, Units{})
+}
+
+// toTypeScript is like ToTypeScript but emits the typography, spacing and radius tokens in units.
+func toTypeScript(specs *extractor.DesignSpecs, fileName string, units Units) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("// Design tokens extracted from Figma: %s\n", fileName))
+	sb.WriteString("// Generated by figma-extractor. Do not edit by hand.\n\n")
+
+	var exported []string
+	export := func(name string, obj *tsObject) {
+		if obj.empty() {
+			return
+		}
+		sb.WriteString(fmt.Sprintf("export const %s = ", name))
+		obj.write(&sb, "")
+		sb.WriteString(" as const;\n\n")
+		exported = append(exported, name)
+	}
+
+	// Colors
+	colors := &tsObject{}
+	for _, group := range colorGroups(specs.Colors) {
+		if len(group.Colors) == 0 {
+			continue
+		}
+		category := &tsObject{}
+		for _, name := range sortedKeys(group.Colors) {
+			category.set(toCamelCase(name), strconv.Quote(group.Colors[name]))
+		}
+		colors.setObject(group.Key, category)
+	}
+	export("colors", colors)
+
+	// Typography
+	fontFamily := &tsObject{}
+	if specs.Typography.FontFamily != "" {
+		fontFamily.set("primary", strconv.Quote(fmt.Sprintf("'%s', system-ui, -apple-system, sans-serif", specs.Typography.FontFamily)))
+	}
+	export("fontFamily", fontFamily)
+
+	fontSizes := &tsObject{}
+	for _, name := range sortedKeys(specs.Typography.FontSizes) {
+		fontSizes.set(name, strconv.Quote(units.size(specs.Typography.FontSizes[name])))
+	}
+	export("fontSizes", fontSizes)
+
+	fontWeights := &tsObject{}
+	for _, name := range sortedKeys(specs.Typography.FontWeights) {
+		fontWeights.set(toCamelCase(name), fmt.Sprintf("%g", specs.Typography.FontWeights[name]))
+	}
+	export("fontWeights", fontWeights)
+
+	lineHeights := &tsObject{}
+	for _, name := range sortedKeys(specs.Typography.LineHeights) {
+		lineHeights.set(toCamelCase(name), strconv.Quote(units.size(specs.Typography.LineHeights[name])))
+	}
+	export("lineHeights", lineHeights)
+
+	letterSpacings := &tsObject{}
+	for _, name := range sortedKeys(specs.Typography.LetterSpacings) {
+		letterSpacings.set(toCamelCase(name), strconv.Quote(units.size(specs.Typography.LetterSpacings[name])))
+	}
+	export("letterSpacings", letterSpacings)
+
+	textStyles := &tsObject{}
+	for _, name := range sortedKeys(specs.TextStyles) {
+		style := &tsObject{}
+		for _, d := range textStyleCSS(specs.TextStyles[name], units) {
+			style.set(toCamelCase(d[0]), strconv.Quote(d[1]))
+		}
+		textStyles.setObject(toCamelCase(name), style)
+	}
+	export("textStyles", textStyles)
+
+	// Spacing, radii, borders, shadows and blurs
+	spacing := &tsObject{}
+	for _, name := range sortedKeys(specs.Spacing.Values) {
+		spacing.set(name, strconv.Quote(units.size(specs.Spacing.Values[name])))
+	}
+	export("spacing", spacing)
+
+	radii := &tsObject{}
+	for _, name := range sortedKeys(specs.Radii.Values) {
+		radii.set(name, strconv.Quote(units.size(specs.Radii.Values[name])))
+	}
+	if !radii.empty() {
+		radii.set("full", strconv.Quote("9999px"))
+	}
+	export("radii", radii)
+
+	borders := &tsObject{}
+	for _, name := range sortedKeys(specs.Borders) {
+		borders.set(toCamelCase(name), strconv.Quote(cssBorder(specs.Borders[name])))
+	}
+	export("borders", borders)
+
+	shadows := &tsObject{}
+	names, values := shadowTokens(specs.Shadows)
+	for _, name := range names {
+		shadows.set(toCamelCase(name), strconv.Quote(values[name]))
+	}
+	export("shadows", shadows)
+
+	blurs := &tsObject{}
+	blurNames, blurValues := blurTokens(specs.Blurs)
+	for _, name := range blurNames {
+		blurs.set(toCamelCase(name), strconv.Quote(cssBlur(blurValues[name])))
+	}
+	export("blurs", blurs)
+
+	// Breakpoints
+	breakpoints := &tsObject{}
+	for _, bp := range specs.Layout.Breakpoints {
+		breakpoints.set(bp.Name, strconv.Quote(px(bp.Width)))
+	}
+	export("breakpoints", breakpoints)
+
+	// Variables
+	themes := &tsObject{}
+	for _, coll := range specs.Variables {
+		modes := &tsObject{}
+		for _, mode := range coll.Modes {
+			vars := &tsObject{}
+			for _, v := range mode.Variables {
+				vars.set(toCamelCase(v.Name), tsVariableValue(v))
+			}
+			modes.setObject(toCamelCase(mode.Name), vars)
+		}
+		themes.setObject(toCamelCase(coll.Name), modes)
+	}
+	export("themes", themes)
+
+	if len(exported) == 0 {
+		sb.WriteString("export const theme = {} as const;\n\n")
+	} else {
+		sb.WriteString("export const theme = {\n")
+		for _, name := range exported {
+			sb.WriteString(fmt.Sprintf("  %s,\n", name))
+		}
+		sb.WriteString("} as const;\n\n")
+	}
+	sb.WriteString("export type Theme = typeof theme;\n")
+
+	// Key unions for the most commonly referenced scales.
+	unions := []struct{ typeName, obj string }{
+		{"FontSize", "fontSizes"},
+		{"FontWeight", "fontWeights"},
+		{"Spacing", "spacing"},
+		{"Radius", "radii"},
+		{"Shadow", "shadows"},
+		{"Border", "borders"},
+		{"Blur", "blurs"},
+		{"Breakpoint", "breakpoints"},
+		{"TextStyle", "textStyles"},
+	}
+	for _, u := range unions {
+		for _, name := range exported {
+			if name == u.obj {
+				sb.WriteString(fmt.Sprintf("export type %s = keyof typeof %s;\n", u.typeName, u.obj))
+				break
+			}
+		}
+	}
+
+	return sb.String()
+}
+
+// renderTypeScript adapts ToTypeScript to the renderFunc signature.
+func renderTypeScript(in Input) ([]File, error) {
+	return []File{{Name: "theme.ts", Content: []byte(toTypeScript(in.Specs, in.FileName, in.Units))}}, nil
+}
+
+// tsVariableValue formats a resolved variable value as a TypeScript literal.
+func tsVariableValue(v extractor.Variable) string {
+	switch v.Type {
+	case "COLOR":
+		return strconv.Quote(v.Color)
+	case "FLOAT":
+		if v.Dimension {
+			return strconv.Quote(px(v.Number))
+		}
+		return fmt.Sprintf("%g", v.Number)
+	case "BOOLEAN":
+		return strconv.FormatBool(v.Bool)
+	default:
+		return strconv.Quote(v.String)
+	}
+}
+
+// tsObject is an ordered TypeScript object literal whose values are either literals or nested objects.
+type tsObject struct {
+	keys   []string
+	values map[string]any // string literal or *tsObject
+}
+
+func (o *tsObject) set(key, literal string) {
+	o.put(key, literal)
+}
+
+func (o *tsObject) setObject(key string, obj *tsObject) {
+	if !obj.empty() {
+		o.put(key, obj)
+	}
+}
+
+func (o *tsObject) put(key string, value any) {
+	if key == "" {
+		return
+	}
+	if o.values == nil {
+		o.values = make(map[string]any)
+	}
+	if _, exists := o.values[key]; !exists {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = value
+}
+
+func (o *tsObject) empty() bool {
+	return len(o.keys) == 0
+}
+
+// write renders the object literal; indent is the indentation of the line it starts on.
+func (o *tsObject) write(sb *strings.Builder, indent string) {
+	sb.WriteString("{\n")
+	for _, key := range o.keys {
+		sb.WriteString(fmt.Sprintf("%s  %s: ", indent, tsKey(key)))
+		switch v := o.values[key].(type) {
+		case *tsObject:
+			v.write(sb, indent+"  ")
+		case string:
+			sb.WriteString(v)
+		}
+		sb.WriteString(",\n")
+	}
+	sb.WriteString(indent + "}")
+}
+
+// tsKey returns key as a bare identifier when possible and as a quoted string otherwise.
+func tsKey(key string) string {
+	for i, r := range key {
+		isLetter := (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || r == '_' || r == '$'
+		isDigit := r >= '0' && r <= '9'
+		if !isLetter && (!isDigit || i == 0) {
+			return strconv.Quote(key)
+		}
+	}
+	return key
+}
@@ -0,0 +1,507 @@
+package imager
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"math"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// AndroidResDir is the resource directory written by WriteAndroidResources.
+const AndroidResDir = "res"
+
+// androidDensities maps export scales to the density qualifiers of drawable directories.
+var androidDensities = map[float64]string{
+	0.75: "ldpi",
+	1:    "mdpi",
+	1.5:  "hdpi",
+	2:    "xhdpi",
+	3:    "xxhdpi",
+	4:    "xxxhdpi",
+}
+
+// WriteAndroidResources lays exported images out as Android drawable resources: PNGs and JPEGs
+// move to res/drawable-<density>/, by scale (1x mdpi, 1.5x hdpi, 2x xhdpi, 3x xxhdpi, 4x
+// xxxhdpi), and SVGs are converted to vector drawables in res/drawable/, the SVGs themselves
+// staying where they are. Resource names are the node names in snake_case. Images of other
+// scales or formats, and SVGs using features vector drawables lack (gradients, masks, filters),
+// are left out and reported as errors. It returns the assets with their new file names and,
+// when manifest is not nil, records the new names in it.
+func WriteAndroidResources(ctx context.Context, store AssetStore, assets []ExportedAsset, manifest *Manifest) ([]ExportedAsset, []error) {
+	var errs []error
+	names := androidResourceNames(assets)
+	for i, a := range assets {
+		name, ok := names[a.NodeID]
+		if !ok {
+			continue
+		}
+		format := strings.ToLower(a.Format)
+		switch {
+		case format == "svg":
+			var data bytes.Buffer
+			if err := copyAsset(ctx, store, a.FileName, &data); err != nil {
+				errs = append(errs, fmt.Errorf("read %q: %w", a.FileName, err))
+				continue
+			}
+			drawable, err := vectorDrawable(data.Bytes())
+			if err != nil {
+				errs = append(errs, fmt.Errorf("convert %q to a vector drawable: %w", a.FileName, err))
+				continue
+			}
+			if err := store.Put(ctx, path.Join(AndroidResDir, "drawable", name+".xml"), bytes.NewReader(drawable)); err != nil {
+				errs = append(errs, err)
+			}
+		case format == "png" || format == "jpg" || format == "jpeg":
+			density, ok := androidDensities[a.Scale]
+			if !ok {
+				errs = append(errs, fmt.Errorf("%q: no Android density for scale %g", a.FileName, a.Scale))
+				continue
+			}
+			newName := path.Join(AndroidResDir, "drawable-"+density, name+"."+format)
+			if newName == a.FileName {
+				continue
+			}
+			if err := MoveAsset(ctx, store, a.FileName, newName); err != nil {
+				errs = append(errs, fmt.Errorf("move %q into %s: %w", a.FileName, AndroidResDir, err))
+				continue
+			}
+			if manifest != nil {
+				manifest.Rename(a.FileName, newName)
+			}
+			assets[i].FileName = newName
+		}
+	}
+	return assets, errs
+}
+
+// androidResourceNames returns the resource name of the images of every node: its name in
+// snake_case, starting with a letter and unique, in a stable order.
+func androidResourceNames(assets []ExportedAsset) map[string]string {
+	nodeNames := make(map[string]string)
+	for _, a := range assets {
+		nodeNames[a.NodeID] = a.NodeName
+	}
+	ids := make([]string, 0, len(nodeNames))
+	for id := range nodeNames {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	names := make(map[string]string, len(ids))
+	used := make(map[string]int)
+	for _, id := range ids {
+		name := strings.ReplaceAll(toKebabCase(nodeNames[id]), "-", "_")
+		if name == "" {
+			name = strings.ReplaceAll(toKebabCase(id), "-", "_")
+		}
+		if name == "" || name[0] < 'a' || name[0] > 'z' {
+			name = "img_" + name
+		}
+		if used[name]++; used[name] > 1 {
+			name = fmt.Sprintf("%s_%d", name, used[name])
+		}
+		names[id] = name
+	}
+	return names
+}
+
+// vdStyle is the paint of SVG elements, inherited by their children.
+type vdStyle struct {
+	fill, stroke                     string
+	fillOpacity, strokeOpacity, opac float64
+	strokeWidth                      string
+	fillRule, lineCap, lineJoin      string
+}
+
+// vectorDrawable converts an SVG document to an Android vector drawable. It supports paths,
+// basic shapes, groups with transforms, clip paths and solid colors, which covers the icons
+// Figma exports.
+func vectorDrawable(data []byte) ([]byte, error) {
+	root, err := parseSVG(data)
+	if err != nil {
+		return nil, err
+	}
+
+	width := svgLength(attrValue(root.attrs, "width"))
+	height := svgLength(attrValue(root.attrs, "height"))
+	viewport := strings.Fields(strings.ReplaceAll(attrValue(root.attrs, "viewBox"), ",", " "))
+	var minX, minY, vw, vh float64
+	if len(viewport) == 4 {
+		minX, _ = strconv.ParseFloat(viewport[0], 64)
+		minY, _ = strconv.ParseFloat(viewport[1], 64)
+		vw, _ = strconv.ParseFloat(viewport[2], 64)
+		vh, _ = strconv.ParseFloat(viewport[3], 64)
+	}
+	if vw == 0 || vh == 0 {
+		vw, vh = width, height
+	}
+	if width == 0 || height == 0 {
+		width, height = vw, vh
+	}
+	if width == 0 || height == 0 {
+		return nil, errors.New("SVG without a size")
+	}
+
+	c := &vdConverter{defs: make(map[string]*svgNode)}
+	c.collectDefs(root)
+
+	fmt.Fprintf(&c.out, `<vector xmlns:android="http://schemas.android.com/apk/res/android"
+    android:width="%sdp"
+    android:height="%sdp"
+    android:viewportWidth="%s"
+    android:viewportHeight="%s">
+`, formatSVGNumber(width), formatSVGNumber(height), formatSVGNumber(vw), formatSVGNumber(vh))
+
+	style, err := inheritStyle(vdStyle{fill: "#000000", fillOpacity: 1, strokeOpacity: 1, opac: 1}, root.attrs)
+	if err != nil {
+		return nil, err
+	}
+	depth := 1
+	if minX != 0 || minY != 0 {
+		c.line(depth, fmt.Sprintf(`<group android:translateX="%s" android:translateY="%s">`, formatSVGNumber(-minX), formatSVGNumber(-minY)))
+		depth++
+	}
+	if err := c.children(root.children, style, depth); err != nil {
+		return nil, err
+	}
+	if depth > 1 {
+		c.line(1, "</group>")
+	}
+	c.out.WriteString("</vector>\n")
+	return c.out.Bytes(), nil
+}
+
+// vdConverter writes the vector drawable of an SVG document.
+type vdConverter struct {
+	out  bytes.Buffer
+	defs map[string]*svgNode // elements by ID, e.g. clip paths
+}
+
+func (c *vdConverter) collectDefs(n *svgNode) {
+	if id := attrValue(n.attrs, "id"); id != "" {
+		c.defs[id] = n
+	}
+	for _, child := range n.children {
+		c.collectDefs(child)
+	}
+}
+
+func (c *vdConverter) line(depth int, s string) {
+	c.out.WriteString(strings.Repeat("    ", depth) + s + "\n")
+}
+
+func (c *vdConverter) children(nodes []*svgNode, style vdStyle, depth int) error {
+	for _, n := range nodes {
+		if n.name == "" {
+			continue
+		}
+		if err := c.element(n, style, depth); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// element writes an element and its children.
+func (c *vdConverter) element(n *svgNode, style vdStyle, depth int) error {
+	switch n.name {
+	case "defs", "title", "desc", "metadata", "clipPath":
+		return nil // referenced or not drawn
+	case "linearGradient", "radialGradient", "mask", "filter", "pattern", "image", "text", "use":
+		return fmt.Errorf("unsupported element <%s>", n.name)
+	}
+	if attrValue(n.attrs, "mask") != "" || attrValue(n.attrs, "filter") != "" {
+		return fmt.Errorf("unsupported mask or filter on <%s>", n.name)
+	}
+
+	style, err := inheritStyle(style, n.attrs)
+	if err != nil {
+		return err
+	}
+
+	// Transforms and clip paths need a group of their own.
+	group, err := vdTransform(attrValue(n.attrs, "transform"))
+	if err != nil {
+		return err
+	}
+	clip := ""
+	if ref := attrValue(n.attrs, "clip-path"); ref != "" {
+		id := strings.TrimSuffix(strings.TrimPrefix(ref, "url(#"), ")")
+		def, ok := c.defs[id]
+		if !ok {
+			return fmt.Errorf("unknown clip path %q", ref)
+		}
+		var paths []string
+		for _, shape := range def.children {
+			if d := shapePath(shape); d != "" {
+				paths = append(paths, d)
+			}
+		}
+		clip = strings.Join(paths, " ")
+	}
+	if n.name == "g" || group != "" || clip != "" {
+		c.line(depth, "<group"+group+">")
+		if clip != "" {
+			c.line(depth+1, fmt.Sprintf(`<clip-path android:pathData="%s"/>`, clip))
+		}
+		defer c.line(depth, "</group>")
+		depth++
+	}
+
+	if n.name == "g" || n.name == "svg" {
+		return c.children(n.children, style, depth)
+	}
+	d := shapePath(n)
+	if d == "" {
+		return nil
+	}
+
+	attrs := []string{fmt.Sprintf(`android:pathData="%s"`, d)}
+	if style.fill != "none" {
+		color, err := androidColor(style.fill)
+		if err != nil {
+			return err
+		}
+		attrs = append(attrs, fmt.Sprintf(`android:fillColor="%s"`, color))
+		if a := style.fillOpacity * style.opac; a < 1 {
+			attrs = append(attrs, fmt.Sprintf(`android:fillAlpha="%s"`, vdAlpha(a)))
+		}
+		if style.fillRule == "evenodd" {
+			attrs = append(attrs, `android:fillType="evenOdd"`)
+		}
+	}
+	if style.stroke != "" && style.stroke != "none" {
+		color, err := androidColor(style.stroke)
+		if err != nil {
+			return err
+		}
+		attrs = append(attrs, fmt.Sprintf(`android:strokeColor="%s"`, color))
+		width := style.strokeWidth
+		if width == "" {
+			width = "1"
+		}
+		attrs = append(attrs, fmt.Sprintf(`android:strokeWidth="%s"`, width))
+		if a := style.strokeOpacity * style.opac; a < 1 {
+			attrs = append(attrs, fmt.Sprintf(`android:strokeAlpha="%s"`, vdAlpha(a)))
+		}
+		if style.lineCap != "" {
+			attrs = append(attrs, fmt.Sprintf(`android:strokeLineCap="%s"`, style.lineCap))
+		}
+		if style.lineJoin != "" {
+			attrs = append(attrs, fmt.Sprintf(`android:strokeLineJoin="%s"`, style.lineJoin))
+		}
+	}
+	c.line(depth, "<path")
+	for i, a := range attrs {
+		end := ""
+		if i == len(attrs)-1 {
+			end = "/>"
+		}
+		c.line(depth+1, a+end)
+	}
+	return nil
+}
+
+// inheritStyle applies the presentation attributes and style declarations of an element.
+func inheritStyle(style vdStyle, attrs []xml.Attr) (vdStyle, error) {
+	props := make(map[string]string)
+	for _, a := range attrs {
+		if a.Name.Space == "" {
+			props[a.Name.Local] = a.Value
+		}
+	}
+	for _, decl := range strings.Split(props["style"], ";") {
+		if k, v, ok := strings.Cut(decl, ":"); ok {
+			props[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		}
+	}
+	for k, v := range props {
+		switch k {
+		case "fill":
+			style.fill = v
+		case "stroke":
+			style.stroke = v
+		case "stroke-width":
+			style.strokeWidth = formatSVGNumber(svgLength(v))
+		case "fill-rule":
+			style.fillRule = v
+		case "stroke-linecap":
+			style.lineCap = v
+		case "stroke-linejoin":
+			style.lineJoin = v
+		case "fill-opacity", "stroke-opacity", "opacity":
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return style, fmt.Errorf("invalid %s %q", k, v)
+			}
+			switch k {
+			case "fill-opacity":
+				style.fillOpacity = f
+			case "stroke-opacity":
+				style.strokeOpacity = f
+			default:
+				style.opac *= f // the opacity of groups applies to everything in them
+			}
+		}
+	}
+	if strings.HasPrefix(style.fill, "url(") || strings.HasPrefix(style.stroke, "url(") {
+		return style, errors.New("unsupported gradient or pattern paint")
+	}
+	return style, nil
+}
+
+// vdTransform returns the group attributes of an SVG transform: translations, scales and
+// rotations, and matrices without skew.
+func vdTransform(transform string) (string, error) {
+	transform = strings.TrimSpace(transform)
+	if transform == "" {
+		return "", nil
+	}
+	fn, rest, ok := strings.Cut(transform, "(")
+	args, tail, ok2 := strings.Cut(rest, ")")
+	if !ok || !ok2 || strings.TrimSpace(tail) != "" {
+		return "", fmt.Errorf("unsupported transform %q", transform)
+	}
+	var v []float64
+	for _, f := range strings.FieldsFunc(args, func(r rune) bool { return r == ',' || r == ' ' }) {
+		n, err := strconv.ParseFloat(f, 64)
+		if err != nil {
+			return "", fmt.Errorf("invalid transform %q", transform)
+		}
+		v = append(v, n)
+	}
+	attr := func(name string, n float64) string {
+		return fmt.Sprintf(` android:%s="%s"`, name, formatSVGNumber(n))
+	}
+
+	switch {
+	case strings.TrimSpace(fn) == "translate" && len(v) >= 1:
+		ty := 0.0
+		if len(v) > 1 {
+			ty = v[1]
+		}
+		return attr("translateX", v[0]) + attr("translateY", ty), nil
+	case strings.TrimSpace(fn) == "scale" && len(v) >= 1:
+		sy := v[0]
+		if len(v) > 1 {
+			sy = v[1]
+		}
+		return attr("scaleX", v[0]) + attr("scaleY", sy), nil
+	case strings.TrimSpace(fn) == "rotate" && (len(v) == 1 || len(v) == 3):
+		out := attr("rotation", v[0])
+		if len(v) == 3 {
+			out += attr("pivotX", v[1]) + attr("pivotY", v[2])
+		}
+		return out, nil
+	case strings.TrimSpace(fn) == "matrix" && len(v) == 6:
+		// matrix(a b c d e f) = translate(e f) rotate(θ) scale(sx sy) when it has no skew.
+		a, b, c, d, e, f := v[0], v[1], v[2], v[3], v[4], v[5]
+		sx := math.Hypot(a, b)
+		sy := (a*d - b*c) / sx
+		if sx == 0 || math.Abs(a*c+b*d) > 1e-6*sx*sx {
+			return "", fmt.Errorf("unsupported skewed transform %q", transform)
+		}
+		out := attr("translateX", e) + attr("translateY", f)
+		if rotation := math.Atan2(b, a) * 180 / math.Pi; math.Abs(rotation) > 1e-6 {
+			out += attr("rotation", rotation)
+		}
+		return out + attr("scaleX", sx) + attr("scaleY", sy), nil
+	}
+	return "", fmt.Errorf("unsupported transform %q", transform)
+}
+
+// shapePath returns the path data of a path or basic shape, empty for other elements.
+func shapePath(n *svgNode) string {
+	num := func(name string) float64 { return svgLength(attrValue(n.attrs, name)) }
+	f := formatSVGNumber
+	switch n.name {
+	case "path":
+		return attrValue(n.attrs, "d")
+	case "rect":
+		x, y, w, h := num("x"), num("y"), num("width"), num("height")
+		if w <= 0 || h <= 0 {
+			return ""
+		}
+		rx, ry := num("rx"), num("ry")
+		if rx == 0 {
+			rx = ry
+		}
+		if ry == 0 {
+			ry = rx
+		}
+		rx, ry = math.Min(rx, w/2), math.Min(ry, h/2)
+		if rx == 0 {
+			return fmt.Sprintf("M%s,%sh%sv%sh%sz", f(x), f(y), f(w), f(h), f(-w))
+		}
+		arc := func(dx, dy float64) string {
+			return fmt.Sprintf("a%s,%s 0 0 1 %s,%s", f(rx), f(ry), f(dx), f(dy))
+		}
+		return fmt.Sprintf("M%s,%sh%s%sv%s%sh%s%sv%s%sz",
+			f(x+rx), f(y), f(w-2*rx), arc(rx, ry), f(h-2*ry), arc(-rx, ry), f(-(w - 2*rx)), arc(-rx, -ry), f(-(h - 2*ry)), arc(rx, -ry))
+	case "circle", "ellipse":
+		cx, cy := num("cx"), num("cy")
+		rx, ry := num("r"), num("r")
+		if n.name == "ellipse" {
+			rx, ry = num("rx"), num("ry")
+		}
+		if rx <= 0 || ry <= 0 {
+			return ""
+		}
+		return fmt.Sprintf("M%s,%sa%s,%s 0 1 0 %s,0a%s,%s 0 1 0 %s,0z",
+			f(cx-rx), f(cy), f(rx), f(ry), f(2*rx), f(rx), f(ry), f(-2*rx))
+	case "line":
+		return fmt.Sprintf("M%s,%sL%s,%s", f(num("x1")), f(num("y1")), f(num("x2")), f(num("y2")))
+	case "polyline", "polygon":
+		points := strings.Fields(strings.ReplaceAll(attrValue(n.attrs, "points"), ",", " "))
+		if len(points) < 4 {
+			return ""
+		}
+		d := "M" + points[0] + "," + points[1]
+		for i := 2; i+1 < len(points); i += 2 {
+			d += "L" + points[i] + "," + points[i+1]
+		}
+		if n.name == "polygon" {
+			d += "z"
+		}
+		return d
+	}
+	return ""
+}
+
+// vdAlpha formats an opacity, e.g. "0.5".
+func vdAlpha(a float64) string {
+	return strconv.FormatFloat(math.Round(a*1000)/1000, 'f', -1, 64)
+}
+
+// androidColor converts an SVG color to an Android one, "#RRGGBB".
+func androidColor(color string) (string, error) {
+	color = strings.ToLower(strings.TrimSpace(color))
+	switch color {
+	case "black", "currentcolor":
+		return "#000000", nil
+	case "white":
+		return "#FFFFFF", nil
+	}
+	if strings.HasPrefix(color, "#") {
+		hex := color[1:]
+		if len(hex) == 3 {
+			hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+		}
+		if _, err := strconv.ParseUint(hex, 16, 32); err == nil && len(hex) == 6 {
+			return "#" + strings.ToUpper(hex), nil
+		}
+	}
+	return "", fmt.Errorf("unsupported color %q", color)
+}
+
+// svgLength parses an SVG length in user units or pixels; other values are 0.
+func svgLength(s string) float64 {
+	n, _ := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(s), "px"), 64)
+	return n
+}
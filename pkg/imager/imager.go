@@ -1,14 +1,22 @@
 package imager
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"os"
+	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/hellenic-development/figma-extractor/pkg/figma"
 )
@@ -17,23 +25,163 @@ import (
 type ExportConfig struct {
 	Format    string    // "png", "svg", "jpg", "pdf"
 	Scales    []float64 // e.g., [1, 2] for raster; ignored for svg/pdf
-	OutputDir string    // local directory, default "figma-assets"
+	OutputDir string    // local directory, default "figma-assets"; unused with a Store
+
+	// Store, when set, stores the images instead of OutputDir, e.g. an S3Store to publish them
+	// straight to a bucket. Images are downloaded and optimized in temporary files first.
+	Store AssetStore
+
+	// Dirs places the images of nodes in subdirectories of the output, by node ID, e.g. the
+	// directories of AssetDirs; nodes without one are written to OutputDir itself.
+	Dirs map[string]string
+
+	// Manifest, when set, skips the images recorded in it whose nodes did not change since they
+	// were exported, and records the new ones. Save it once all images are exported.
+	Manifest *Manifest
+
+	// RenderOptions are passed to the Images API, e.g. to keep layer IDs in SVGs or to render
+	// the full bounds of cropped nodes; the zero value renders like Figma's default export.
+	figma.RenderOptions
+
+	// Optimize recompresses downloaded PNGs and strips the metadata of PNGs and JPEGs without
+	// changing their pixels, to keep committed assets small.
+	Optimize bool
+	// OptimizeSVG minifies downloaded SVGs: it strips comments and editor metadata, collapses
+	// groups without attributes and rounds coordinates.
+	OptimizeSVG bool
+
+	// Concurrency is the number of images downloaded at the same time; 0 = 5.
+	Concurrency int
+	// MaxBytesPerSecond caps the combined download speed of an export; 0 = unlimited.
+	MaxBytesPerSecond int64
+
+	// Retries is how often a download that failed with a transient error is retried, with
+	// exponential backoff, resuming partial downloads; 0 = 3, negative = never.
+	Retries int
+
+	// Progress, when set, is called as images are downloaded, e.g. to draw a progress bar.
+	Progress ProgressFunc
+
+	// HTTPClient downloads the rendered images; nil = http.DefaultClient.
+	HTTPClient *http.Client
+	// CacheDir keeps a copy of every download, keyed by its URL, and serves later downloads of
+	// the same URL from it; empty = no cache. The Figma client returns the same URLs again when
+	// it caches the image responses of a pinned version.
+	CacheDir string
+
+	limiter  *bandwidthLimiter // shared by the downloads of an export, see MaxBytesPerSecond
+	progress *progressTracker  // shared by the downloads of an export, see Progress
+}
+
+// forExport returns the config used by the downloads of one export.
+func (c ExportConfig) forExport() ExportConfig {
+	if c.Concurrency <= 0 {
+		c.Concurrency = defaultParallelDownloads
+	}
+	if c.Retries == 0 {
+		c.Retries = defaultDownloadRetries
+	}
+	if c.MaxBytesPerSecond > 0 {
+		c.limiter = &bandwidthLimiter{bytesPerSecond: c.MaxBytesPerSecond}
+	}
+	if c.Progress != nil {
+		c.progress = &progressTracker{fn: c.Progress}
+	}
+	return c
+}
+
+// wrapBody wraps the body of a download to throttle it and to report its progress.
+func (c ExportConfig) wrapBody(ctx context.Context, r io.Reader) io.Reader {
+	if c.limiter != nil {
+		r = &throttledReader{ctx: ctx, r: r, limiter: c.limiter}
+	}
+	if c.progress != nil {
+		r = &countingReader{r: r, progress: c.progress}
+	}
+	return r
+}
+
+// settingsHash returns a hash of the settings that change the exported images, recorded in the
+// manifest so that changing them exports the images again. Image fills are downloaded as they
+// are, so only rendered images depend on RenderOptions.
+func (c ExportConfig) settingsHash(render bool) string {
+	settings := struct {
+		Render      *figma.RenderOptions `json:"render,omitempty"`
+		Optimize    bool                 `json:"optimize"`
+		OptimizeSVG bool                 `json:"optimizeSvg"`
+	}{Optimize: c.Optimize, OptimizeSVG: c.OptimizeSVG}
+	if render {
+		settings.Render = &c.RenderOptions
+	}
+	data, _ := json.Marshal(settings)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// store returns the store to write images to.
+func (c ExportConfig) store() AssetStore {
+	if c.Store != nil {
+		return c.Store
+	}
+	return LocalStore{Dir: c.OutputDir}
+}
+
+// createOutputDir creates the output directory of a local store.
+func (c ExportConfig) createOutputDir() error {
+	local, ok := c.store().(LocalStore)
+	if !ok {
+		return nil
+	}
+	if err := os.MkdirAll(local.Dir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory %q: %w", local.Dir, err)
+	}
+	return nil
+}
+
+// httpClient returns the client to download images with.
+func (c ExportConfig) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return http.DefaultClient
 }
 
 // ExportedAsset represents a single exported image asset.
 type ExportedAsset struct {
 	NodeID   string
 	NodeName string
-	FileName string
+	FileName string // path relative to the output directory or store, slash-separated
 	Format   string
 	Scale    float64
 }
 
 // ExportResult holds the results of an image export operation.
 type ExportResult struct {
-	Assets          []ExportedAsset
-	Errors          []error          // non-fatal per-image download failures
-	UnresolvedNodes []ImageFillNode  // IMAGE fill nodes with no download URL (need render fallback)
+	Assets          []ExportedAsset // sorted by file name, whatever order the downloads finished in
+	Errors          []error         // non-fatal per-image download failures, *NodeError values
+	UnresolvedNodes []ImageFillNode // IMAGE fill nodes with no download URL (need render fallback)
+	BytesSaved      int64           // bytes saved by optimizing the images, see ExportConfig.Optimize and OptimizeSVG
+	Skipped         int             // unchanged images kept from a previous export, see ExportConfig.Manifest
+}
+
+// NodeError is a failure to export the image of a node.
+type NodeError struct {
+	NodeID   string
+	NodeName string // empty when unknown
+	Err      error
+}
+
+func (e *NodeError) Error() string { return e.Err.Error() }
+func (e *NodeError) Unwrap() error { return e.Err }
+
+// sortAssets sorts the assets by file name, then node ID.
+func (r *ExportResult) sortAssets() {
+	sort.SliceStable(r.Assets, func(i, j int) bool {
+		if r.Assets[i].FileName != r.Assets[j].FileName {
+			return r.Assets[i].FileName < r.Assets[j].FileName
+		}
+		return r.Assets[i].NodeID < r.Assets[j].NodeID
+	})
 }
 
 // ImageFillNode represents a node that contains an embedded IMAGE fill.
@@ -44,7 +192,47 @@ type ImageFillNode struct {
 }
 
 const maxNodesPerRequest = 100
-const maxParallelDownloads = 5
+const defaultParallelDownloads = 5
+const defaultDownloadRetries = 3
+
+// downloadBackoff is the wait before the first retry of a download, doubled for each next one.
+var downloadBackoff = time.Second
+
+// AssetDirs returns the directory of every node under a document, mirroring its page and
+// top-level frame: "page" for the top-level frames of a page and "page/frame" for the nodes
+// inside them, with names in kebab-case. Pages and the document itself have no directory.
+func AssetDirs(document *figma.Node) map[string]string {
+	dirs := make(map[string]string)
+	var walk func(node *figma.Node, dir string)
+	walk = func(node *figma.Node, dir string) {
+		for i := range node.Children {
+			child := &node.Children[i]
+			dirs[child.ID] = dir
+			walk(child, dir)
+		}
+	}
+	for i := range document.Children {
+		page := &document.Children[i]
+		pageDir := dirName(page.Name, page.ID)
+		for j := range page.Children {
+			frame := &page.Children[j]
+			dirs[frame.ID] = pageDir
+			walk(frame, path.Join(pageDir, dirName(frame.Name, frame.ID)))
+		}
+	}
+	return dirs
+}
+
+// dirName returns the directory name of a page or frame.
+func dirName(name, id string) string {
+	if dir := toKebabCase(name); dir != "" {
+		return dir
+	}
+	if dir := toKebabCase(id); dir != "" {
+		return dir
+	}
+	return "untitled"
+}
 
 // CollectExportableNodes walks the Figma node tree and returns a map of nodeID -> nodeName
 // for nodes that have ExportSettings defined by the designer.
@@ -65,19 +253,21 @@ func collectExportable(node *figma.Node, nodes map[string]string) {
 
 // ExportImages orchestrates the full image export pipeline:
 // creates output directory, batches API requests, downloads images concurrently.
-func ExportImages(client *figma.Client, fileKey string, nodes map[string]string, config ExportConfig) (*ExportResult, error) {
-	if err := os.MkdirAll(config.OutputDir, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create output directory %q: %w", config.OutputDir, err)
+// ctx bounds the render API requests and the downloads: once it is done, no further batches
+// are requested, in-flight downloads are aborted and the context's error is returned.
+func ExportImages(ctx context.Context, client *figma.Client, fileKey string, nodes map[string]string, config ExportConfig) (*ExportResult, error) {
+	if err := config.createOutputDir(); err != nil {
+		return nil, err
 	}
 
-	result := &ExportResult{}
-	usedNames := make(map[string]int) // track filename collisions
+	exp := newExporter(client, fileKey, config)
 
 	// Collect node IDs into a slice for batching.
 	nodeIDs := make([]string, 0, len(nodes))
 	for id := range nodes {
 		nodeIDs = append(nodeIDs, id)
 	}
+	sort.Strings(nodeIDs)
 
 	// Determine effective scales: for SVG/PDF, always use scale 1.
 	scales := config.Scales
@@ -86,103 +276,373 @@ func ExportImages(client *figma.Client, fileKey string, nodes map[string]string,
 	}
 
 	for _, scale := range scales {
-		// Batch node IDs (max 100 per API request).
-		for i := 0; i < len(nodeIDs); i += maxNodesPerRequest {
-			end := i + maxNodesPerRequest
-			if end > len(nodeIDs) {
-				end = len(nodeIDs)
+		err := exp.render(ctx, nodeIDs, config.Format, scale, func(nodeID string) (string, string) {
+			return nodes[nodeID], buildFileName(nodes[nodeID], nodeID, config.Format, scale)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	exp.result.sortAssets()
+	return exp.result, nil
+}
+
+// newExporter returns an exporter collecting the images rendered with config into one result.
+func newExporter(client *figma.Client, fileKey string, config ExportConfig) *exporter {
+	return &exporter{client: client, fileKey: fileKey, config: config.forExport(), result: &ExportResult{}, usedNames: make(map[string]int)}
+}
+
+// exporter renders nodes through the Images API and downloads them into the output directory,
+// collecting the assets of several renders into one result with unique file names.
+type exporter struct {
+	client  *figma.Client
+	fileKey string
+	config  ExportConfig
+
+	mu        sync.Mutex
+	result    *ExportResult
+	usedNames map[string]int // track filename collisions
+}
+
+// render renders nodeIDs in the given format and scale, in batches of at most 100 nodes, and
+// downloads the images concurrently. name returns the node name and file name of a node.
+func (e *exporter) render(ctx context.Context, nodeIDs []string, format string, scale float64, name func(nodeID string) (nodeName, fileName string)) error {
+	e.config.progress.add(len(nodeIDs))
+	nodeIDs = e.skipUnchanged(ctx, nodeIDs, format, scale, name)
+
+	// Batch node IDs (max 100 per API request).
+	for i := 0; i < len(nodeIDs); i += maxNodesPerRequest {
+		end := min(i+maxNodesPerRequest, len(nodeIDs))
+		batch := nodeIDs[i:end]
+
+		imgResp, err := e.client.GetImagesWithOptions(ctx, e.fileKey, batch, format, scale, e.config.RenderOptions)
+		if err != nil {
+			return fmt.Errorf("failed to get images from Figma API: %w", err)
+		}
+
+		// Download images concurrently with a semaphore.
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, e.config.Concurrency)
+
+		// File names are assigned in batch order, so colliding names get the same suffixes
+		// whatever order the downloads finish in.
+		for _, nodeID := range batch {
+			imageURL, ok := imgResp.Images[nodeID]
+			if !ok {
+				continue
+			}
+			if imageURL == "" {
+				e.mu.Lock()
+				e.result.Errors = append(e.result.Errors, &NodeError{NodeID: nodeID, Err: fmt.Errorf("no image URL returned for node %s", nodeID)})
+				e.mu.Unlock()
+				e.config.progress.done()
+				continue
 			}
-			batch := nodeIDs[i:end]
 
-			imgResp, err := client.GetImages(fileKey, batch, config.Format, scale)
-			if err != nil {
-				return nil, fmt.Errorf("failed to get images from Figma API: %w", err)
+			nodeName, fileName := name(nodeID)
+			fileName = e.config.assetPath(nodeID, fileName)
+
+			// Deduplicate filenames.
+			e.mu.Lock()
+			if count, exists := e.usedNames[fileName]; exists {
+				ext := filepath.Ext(fileName)
+				base := strings.TrimSuffix(fileName, ext)
+				fileName = fmt.Sprintf("%s-%d%s", base, count+1, ext)
+				e.usedNames[fileName] = count + 1
+			} else {
+				e.usedNames[fileName] = 1
 			}
+			e.mu.Unlock()
+
+			wg.Add(1)
+			go func(nID, url, nodeName, fileName string) {
+				defer wg.Done()
+				var downloaded *ExportedAsset
+				defer func() { e.config.progress.complete(downloaded) }()
+				select {
+				case sem <- struct{}{}:
+					defer func() { <-sem }()
+				case <-ctx.Done():
+					return
+				}
 
-			// Download images concurrently with a semaphore.
-			var wg sync.WaitGroup
-			sem := make(chan struct{}, maxParallelDownloads)
-			var mu sync.Mutex
-
-			for nodeID, imageURL := range imgResp.Images {
-				if imageURL == "" {
-					mu.Lock()
-					result.Errors = append(result.Errors, fmt.Errorf("no image URL returned for node %s", nodeID))
-					mu.Unlock()
-					continue
+				saved, hash, err := e.config.fetch(ctx, url, fileName)
+				if err != nil {
+					e.mu.Lock()
+					e.result.Errors = append(e.result.Errors, &NodeError{NodeID: nID, NodeName: nodeName, Err: fmt.Errorf("failed to download %s: %w", nodeName, err)})
+					e.mu.Unlock()
+					return
 				}
 
-				wg.Add(1)
-				go func(nID, url string) {
-					defer wg.Done()
-					sem <- struct{}{}
-					defer func() { <-sem }()
+				asset := ExportedAsset{
+					NodeID:   nID,
+					NodeName: nodeName,
+					FileName: fileName,
+					Format:   format,
+					Scale:    scale,
+				}
+				if m := e.config.Manifest; m != nil {
+					m.record(manifestKey(nID, format, scale), m.nodeHash(nID), e.config.settingsHash(true), hash, asset)
+				}
+
+				e.mu.Lock()
+				e.result.BytesSaved += saved
+				e.result.Assets = append(e.result.Assets, asset)
+				e.mu.Unlock()
+				downloaded = &asset
+			}(nodeID, imageURL, nodeName, fileName)
+		}
+
+		wg.Wait()
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 
-					nodeName := nodes[nID]
-					fileName := buildFileName(nodeName, nID, config.Format, scale)
-
-					// Deduplicate filenames.
-					mu.Lock()
-					if count, exists := usedNames[fileName]; exists {
-						ext := filepath.Ext(fileName)
-						base := strings.TrimSuffix(fileName, ext)
-						fileName = fmt.Sprintf("%s-%d%s", base, count+1, ext)
-						usedNames[fileName] = count + 1
-					} else {
-						usedNames[fileName] = 1
-					}
-					mu.Unlock()
-
-					destPath := filepath.Join(config.OutputDir, fileName)
-					if err := downloadFile(url, destPath); err != nil {
-						mu.Lock()
-						result.Errors = append(result.Errors, fmt.Errorf("failed to download %s: %w", nodeName, err))
-						mu.Unlock()
-						return
-					}
-
-					mu.Lock()
-					result.Assets = append(result.Assets, ExportedAsset{
-						NodeID:   nID,
-						NodeName: nodeName,
-						FileName: fileName,
-						Format:   config.Format,
-						Scale:    scale,
-					})
-					mu.Unlock()
-				}(nodeID, imageURL)
+		// Nodes the API returned nothing for are done too.
+		for _, id := range batch {
+			if _, ok := imgResp.Images[id]; !ok {
+				e.config.progress.done()
 			}
+		}
+	}
+	return nil
+}
 
-			wg.Wait()
+// skipUnchanged returns the nodes to render, adding the images of the manifest that are still
+// current to the result instead.
+func (e *exporter) skipUnchanged(ctx context.Context, nodeIDs []string, format string, scale float64, name func(nodeID string) (string, string)) []string {
+	m := e.config.Manifest
+	if m == nil {
+		return nodeIDs
+	}
+
+	var changed []string
+	settings := e.config.settingsHash(true)
+	for _, id := range nodeIDs {
+		fileName, ok := m.current(ctx, manifestKey(id, format, scale), m.nodeHash(id), settings)
+		if !ok {
+			changed = append(changed, id)
+			continue
 		}
+		nodeName, _ := name(id)
+		e.mu.Lock()
+		e.usedNames[fileName]++
+		e.result.Skipped++
+		e.config.progress.done()
+		e.result.Assets = append(e.result.Assets, ExportedAsset{
+			NodeID:   id,
+			NodeName: nodeName,
+			FileName: fileName,
+			Format:   format,
+			Scale:    scale,
+		})
+		e.mu.Unlock()
 	}
+	return changed
+}
 
-	return result, nil
+// assetPath returns the slash-separated path of an asset of the node, relative to the output.
+func (c ExportConfig) assetPath(nodeID, fileName string) string {
+	return path.Join(c.Dirs[nodeID], fileName)
 }
 
-// downloadFile performs an HTTP GET and saves the response body to destPath.
-func downloadFile(url, destPath string) error {
-	resp, err := http.Get(url)
+// fetch downloads the file at url into a temporary file, optimizes it when enabled and stores
+// it under name, returning the bytes saved and the SHA-256 of the stored file. A failed
+// optimization keeps the downloaded file as is.
+func (c ExportConfig) fetch(ctx context.Context, url, name string) (saved int64, hash string, err error) {
+	tmp, err := os.CreateTemp("", "figma-asset-*"+path.Ext(name))
 	if err != nil {
-		return fmt.Errorf("HTTP GET failed: %w", err)
+		return 0, "", fmt.Errorf("failed to create temporary file: %w", err)
 	}
-	defer resp.Body.Close()
+	tmp.Close()
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected status %d downloading image", resp.StatusCode)
+	if err := c.download(ctx, url, tmpPath); err != nil {
+		return 0, "", err
+	}
+
+	var optimize func([]byte) ([]byte, error)
+	switch ext := strings.ToLower(path.Ext(name)); {
+	case c.Optimize && ext == ".png":
+		optimize = optimizePNG
+	case c.Optimize && (ext == ".jpg" || ext == ".jpeg"):
+		optimize = stripJPEG
+	case c.OptimizeSVG && ext == ".svg":
+		optimize = optimizeSVG
+	}
+	if optimize != nil {
+		saved, _ = optimizeFile(tmpPath, optimize)
 	}
 
+	if hash, err = fileHash(tmpPath); err != nil {
+		return 0, "", err
+	}
+	f, err := os.Open(tmpPath)
+	if err != nil {
+		return 0, "", err
+	}
+	defer f.Close()
+	if err := c.store().Put(ctx, name, f); err != nil {
+		return 0, "", err
+	}
+	return saved, hash, nil
+}
+
+// download saves the file at url to destPath, going through the cache directory when set.
+func (c ExportConfig) download(ctx context.Context, url, destPath string) error {
+	if c.CacheDir == "" {
+		return downloadFile(ctx, c.httpClient(), url, destPath, c.Retries, c.wrapBody)
+	}
+
+	sum := sha256.Sum256([]byte(url))
+	cachePath := filepath.Join(c.CacheDir, hex.EncodeToString(sum[:16]))
+	if err := copyFile(cachePath, destPath); err == nil {
+		return nil
+	}
+
+	if err := os.MkdirAll(c.CacheDir, 0755); err != nil {
+		return downloadFile(ctx, c.httpClient(), url, destPath, c.Retries, c.wrapBody)
+	}
+	// Download into a temporary file first so that an interrupted download is never cached.
+	tmp, err := os.CreateTemp(c.CacheDir, "*.tmp")
+	if err != nil {
+		return downloadFile(ctx, c.httpClient(), url, destPath, c.Retries, c.wrapBody)
+	}
+	tmp.Close()
+	tmpPath := tmp.Name()
+	if err := downloadFile(ctx, c.httpClient(), url, tmpPath, c.Retries, c.wrapBody); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, cachePath); err != nil {
+		os.Remove(tmpPath)
+		return downloadFile(ctx, c.httpClient(), url, destPath, c.Retries, c.wrapBody)
+	}
+	return copyFile(cachePath, destPath)
+}
+
+// copyFile copies the file at src to dst.
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return fmt.Errorf("failed to create file %q: %w", dst, err)
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to write file %q: %w", dst, err)
+	}
+	return out.Close()
+}
+
+// downloadFile performs an HTTP GET with client and saves the response body to destPath.
+// Transient failures (network errors, 408, 429 and 5xx responses) are retried up to retries
+// times with exponential backoff, resuming the partial file with a Range request when the
+// server supports it. wrap, when not nil, wraps the response body, e.g. to throttle the download.
+func downloadFile(ctx context.Context, client *http.Client, url, destPath string, retries int, wrap func(context.Context, io.Reader) io.Reader) error {
 	f, err := os.Create(destPath)
 	if err != nil {
 		return fmt.Errorf("failed to create file %q: %w", destPath, err)
 	}
-	defer f.Close()
 
-	if _, err := io.Copy(f, resp.Body); err != nil {
-		return fmt.Errorf("failed to write file %q: %w", destPath, err)
+	var size int64 // bytes of the file downloaded so far
+	backoff := downloadBackoff
+	for attempt := 0; ; attempt++ {
+		size, err = downloadAttempt(ctx, client, url, f, size, wrap)
+		var dlErr *downloadError
+		if err == nil || !errors.As(err, &dlErr) || !dlErr.retry || attempt >= retries || ctx.Err() != nil {
+			break
+		}
+		if sleepErr := sleep(ctx, backoff); sleepErr != nil {
+			err = sleepErr
+			break
+		}
+		backoff *= 2
 	}
 
-	return nil
+	if err != nil {
+		// Never leave a partial image behind, e.g. after a cancellation.
+		f.Close()
+		os.Remove(destPath)
+		return err
+	}
+	return f.Close()
+}
+
+// downloadError is a failed download attempt; retry tells whether another attempt may succeed.
+type downloadError struct {
+	err   error
+	retry bool
+}
+
+func (e *downloadError) Error() string { return e.err.Error() }
+func (e *downloadError) Unwrap() error { return e.err }
+
+// downloadAttempt downloads url into f, resuming after the first size bytes when the server
+// honors the range, and returns the size of the file afterwards.
+func downloadAttempt(ctx context.Context, client *http.Client, url string, f *os.File, size int64, wrap func(context.Context, io.Reader) io.Reader) (int64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return size, fmt.Errorf("HTTP GET failed: %w", err)
+	}
+	if size > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", size))
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return size, &downloadError{err: fmt.Errorf("HTTP GET failed: %w", err), retry: true}
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && size > 0 && strings.HasPrefix(resp.Header.Get("Content-Range"), fmt.Sprintf("bytes %d-", size)):
+		// Resume where the previous attempt stopped.
+	case resp.StatusCode == http.StatusOK:
+		// Start over: the server ignored the range, or there was none.
+		size = 0
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable:
+		return 0, &downloadError{err: fmt.Errorf("unexpected status %d downloading image", resp.StatusCode), retry: true}
+	default:
+		retry := resp.StatusCode == http.StatusRequestTimeout || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return size, &downloadError{err: fmt.Errorf("unexpected status %d downloading image", resp.StatusCode), retry: retry}
+	}
+
+	if err := f.Truncate(size); err != nil {
+		return size, fmt.Errorf("failed to write file %q: %w", f.Name(), err)
+	}
+	if _, err := f.Seek(size, io.SeekStart); err != nil {
+		return size, fmt.Errorf("failed to write file %q: %w", f.Name(), err)
+	}
+
+	var body io.Reader = resp.Body
+	if wrap != nil {
+		body = wrap(ctx, body)
+	}
+	n, err := io.Copy(f, body)
+	size += n
+	if err != nil {
+		return size, &downloadError{err: fmt.Errorf("failed to write file %q: %w", f.Name(), err), retry: true}
+	}
+	return size, nil
+}
+
+// sleep waits for d, or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 // buildFileName creates a sanitized filename from a node name.
@@ -252,27 +712,51 @@ func collectImageFills(node *figma.Node, nodes *[]ImageFillNode) {
 // It matches each ImageFillNode's ImageRef to a download URL from the FileImagesResponse.
 // Nodes whose ImageRef is not found in the response are returned in UnresolvedNodes
 // so callers can fall back to the render API.
-func ExportImageFills(fileImagesResp *figma.FileImagesResponse, imageFillNodes []ImageFillNode, config ExportConfig) (*ExportResult, error) {
-	if err := os.MkdirAll(config.OutputDir, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create output directory %q: %w", config.OutputDir, err)
+// ctx bounds the downloads: once it is done, in-flight downloads are aborted and the context's
+// error is returned.
+func ExportImageFills(ctx context.Context, fileImagesResp *figma.FileImagesResponse, imageFillNodes []ImageFillNode, config ExportConfig) (*ExportResult, error) {
+	if err := config.createOutputDir(); err != nil {
+		return nil, err
 	}
 
+	config = config.forExport()
 	result := &ExportResult{}
 	usedNames := make(map[string]int)
 
 	var wg sync.WaitGroup
-	sem := make(chan struct{}, maxParallelDownloads)
+	sem := make(chan struct{}, config.Concurrency)
 	var mu sync.Mutex
 
+	config.progress.add(len(imageFillNodes))
+	settings := config.settingsHash(false)
 	for _, node := range imageFillNodes {
+		// Image references are hashes of the images: an unchanged reference is an unchanged image.
+		key := manifestKey(node.NodeID, "fill", 1)
+		if config.Manifest != nil {
+			if fileName, ok := config.Manifest.current(ctx, key, node.ImageRef, settings); ok {
+				usedNames[fileName]++
+				result.Skipped++
+				config.progress.done()
+				result.Assets = append(result.Assets, ExportedAsset{
+					NodeID:   node.NodeID,
+					NodeName: node.NodeName,
+					FileName: fileName,
+					Format:   strings.TrimPrefix(path.Ext(fileName), "."),
+					Scale:    1,
+				})
+				continue
+			}
+		}
+
 		downloadURL, ok := fileImagesResp.Images[node.ImageRef]
 		if !ok || downloadURL == "" {
 			result.UnresolvedNodes = append(result.UnresolvedNodes, node)
+			config.progress.done()
 			continue
 		}
 
 		ext := detectExtensionFromURL(downloadURL)
-		fileName := buildFileName(node.NodeName, node.NodeID, ext, 1)
+		fileName := config.assetPath(node.NodeID, buildFileName(node.NodeName, node.NodeID, ext, 1))
 
 		// Deduplicate filenames.
 		if count, exists := usedNames[fileName]; exists {
@@ -284,34 +768,50 @@ func ExportImageFills(fileImagesResp *figma.FileImagesResponse, imageFillNodes [
 			usedNames[fileName] = 1
 		}
 
-		destPath := filepath.Join(config.OutputDir, fileName)
-
 		wg.Add(1)
-		go func(n ImageFillNode, dlURL, dest, fName string) {
+		go func(n ImageFillNode, dlURL, fName string) {
 			defer wg.Done()
-			sem <- struct{}{}
-			defer func() { <-sem }()
+			var downloaded *ExportedAsset
+			defer func() { config.progress.complete(downloaded) }()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				return
+			}
 
-			if err := downloadFile(dlURL, dest); err != nil {
+			saved, hash, err := config.fetch(ctx, dlURL, fName)
+			if err != nil {
 				mu.Lock()
-				result.Errors = append(result.Errors, fmt.Errorf("failed to download image fill %s: %w", n.NodeName, err))
+				result.Errors = append(result.Errors, &NodeError{NodeID: n.NodeID, NodeName: n.NodeName, Err: fmt.Errorf("failed to download image fill %s: %w", n.NodeName, err)})
 				mu.Unlock()
 				return
 			}
 
-			mu.Lock()
-			result.Assets = append(result.Assets, ExportedAsset{
+			asset := ExportedAsset{
 				NodeID:   n.NodeID,
 				NodeName: n.NodeName,
 				FileName: fName,
 				Format:   filepath.Ext(fName)[1:], // strip leading dot
 				Scale:    1,
-			})
+			}
+			if config.Manifest != nil {
+				config.Manifest.record(key, n.ImageRef, settings, hash, asset)
+			}
+
+			mu.Lock()
+			result.BytesSaved += saved
+			result.Assets = append(result.Assets, asset)
 			mu.Unlock()
-		}(node, downloadURL, destPath, fileName)
+			downloaded = &asset
+		}(node, downloadURL, fileName)
 	}
 
 	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	result.sortAssets()
 	return result, nil
 }
 
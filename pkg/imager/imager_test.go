@@ -1,7 +1,26 @@
 package imager
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/hellenic-development/figma-extractor/pkg/figma"
 )
@@ -245,3 +264,770 @@ func TestCollectExportableNodes_WalksChildren(t *testing.T) {
 		t.Errorf("expected node 2:1 (Logo), got %v", got)
 	}
 }
+
+func TestCollectNamedNodes(t *testing.T) {
+	root := figma.Node{
+		ID:   "0:1",
+		Name: "Page",
+		Children: []figma.Node{
+			{
+				ID:   "1:1",
+				Name: "Icons/Arrow",
+				ExportSettings: []figma.ExportSetting{
+					{Format: "SVG"},
+				},
+				Children: []figma.Node{
+					{ID: "2:1", Name: "Icons/Arrow/Head"},
+				},
+			},
+			{ID: "1:2", Name: "Icons/Arrow/Left"},
+			{ID: "1:3", Name: "Hero"},
+			{
+				ID:   "1:4",
+				Name: "Group",
+				Children: []figma.Node{
+					{ID: "2:2", Name: "Icons/Close"},
+				},
+			},
+		},
+	}
+
+	got := CollectNamedNodes(&root, []string{"Icons/*", "["})
+	if len(got) != 2 {
+		t.Fatalf("CollectNamedNodes() returned %d nodes, want 2: %+v", len(got), got)
+	}
+	if got[0].NodeID != "1:1" || len(got[0].Settings) != 1 {
+		t.Errorf("first node = %+v, want 1:1 with its export setting", got[0])
+	}
+	if got[1].NodeID != "2:2" {
+		t.Errorf("second node = %+v, want 2:2", got[1])
+	}
+}
+
+func TestSettingScale(t *testing.T) {
+	setting := func(format, constraint string, value float64) figma.ExportSetting {
+		s := figma.ExportSetting{Format: format}
+		s.Constraint.Type = constraint
+		s.Constraint.Value = value
+		return s
+	}
+	tests := []struct {
+		name    string
+		setting figma.ExportSetting
+		want    float64
+	}{
+		{name: "scale", setting: setting("PNG", "SCALE", 2), want: 2},
+		{name: "no constraint", setting: setting("PNG", "", 0), want: 1},
+		{name: "width", setting: setting("JPG", "WIDTH", 200), want: 4},
+		{name: "height", setting: setting("PNG", "HEIGHT", 100), want: 4},
+		{name: "width above limit", setting: setting("PNG", "WIDTH", 1000), want: 4},
+		{name: "svg ignores scale", setting: setting("SVG", "SCALE", 3), want: 1},
+		{name: "pdf ignores scale", setting: setting("PDF", "SCALE", 2), want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := settingScale(tt.setting, 50, 25); got != tt.want {
+				t.Errorf("settingScale() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSettingFileName(t *testing.T) {
+	tests := []struct {
+		name     string
+		nodeName string
+		suffix   string
+		format   string
+		scale    float64
+		want     string
+	}{
+		{name: "suffix", nodeName: "Icon Button", suffix: "@2x", format: "png", scale: 2, want: "icon-button@2x.png"},
+		{name: "no suffix", nodeName: "Icon Button", format: "png", scale: 3, want: "icon-button@3x.png"},
+		{name: "no suffix vector", nodeName: "Logo", format: "svg", scale: 1, want: "logo.svg"},
+		{name: "suffix with spaces", nodeName: "Logo", suffix: " dark/mode", format: "svg", scale: 1, want: "logo-darkmode.svg"},
+		{name: "unnamed", nodeName: "", suffix: "-small", format: "jpg", scale: 0.5, want: "12-small.jpg"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := settingFileName(tt.nodeName, "1:2", tt.suffix, tt.format, tt.scale); got != tt.want {
+				t.Errorf("settingFileName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// testChunk builds a raw PNG chunk.
+func testChunk(typ string, data []byte) []byte {
+	chunk := binary.BigEndian.AppendUint32(nil, uint32(len(data)))
+	chunk = append(chunk, typ...)
+	chunk = append(chunk, data...)
+	return binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(chunk[4:]))
+}
+
+func TestOptimizePNG(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 64, 64))
+	for y := range 64 {
+		for x := range 64 {
+			img.Set(x, y, color.NRGBA{R: uint8(x * 4), G: uint8(y * 4), B: 128, A: 255})
+		}
+	}
+	var encoded bytes.Buffer
+	if err := (&png.Encoder{CompressionLevel: png.NoCompression}).Encode(&encoded, img); err != nil {
+		t.Fatal(err)
+	}
+
+	// Insert a gamma chunk and a text chunk after the header (8-byte signature + 25-byte IHDR).
+	data := encoded.Bytes()
+	gamma := testChunk("gAMA", binary.BigEndian.AppendUint32(nil, 45455))
+	text := testChunk("tEXt", []byte("Software\x00Figma"))
+	original := append(append(append(append([]byte{}, data[:33]...), gamma...), text...), data[33:]...)
+
+	optimized, err := optimizePNG(original)
+	if err != nil {
+		t.Fatalf("optimizePNG() error = %v", err)
+	}
+	if len(optimized) >= len(original) {
+		t.Errorf("optimizePNG() = %d bytes, want less than %d", len(optimized), len(original))
+	}
+	if bytes.Contains(optimized, []byte("tEXt")) {
+		t.Error("optimizePNG() kept the text chunk")
+	}
+	if !bytes.Contains(optimized, gamma) {
+		t.Error("optimizePNG() dropped the gamma chunk")
+	}
+
+	got, err := png.Decode(bytes.NewReader(optimized))
+	if err != nil {
+		t.Fatalf("decode optimized PNG: %v", err)
+	}
+	for y := range 64 {
+		for x := range 64 {
+			if want := img.At(x, y); color.NRGBAModel.Convert(got.At(x, y)) != want {
+				t.Fatalf("pixel (%d,%d) = %v, want %v", x, y, got.At(x, y), want)
+			}
+		}
+	}
+}
+
+func TestStripJPEG(t *testing.T) {
+	segment := func(marker byte, data string) []byte {
+		return append([]byte{0xFF, marker, 0, byte(len(data) + 2)}, data...)
+	}
+	scan := []byte{0xFF, 0xDA, 0, 2, 1, 2, 3, 0xFF, 0xD9}
+	build := func(segments ...[]byte) []byte {
+		data := []byte{0xFF, 0xD8}
+		for _, s := range segments {
+			data = append(data, s...)
+		}
+		return append(data, scan...)
+	}
+
+	jfif := segment(0xE0, "JFIF\x00")
+	icc := segment(0xE2, "ICC_PROFILE\x00")
+	exif := segment(0xE1, "Exif\x00\x00")
+	comment := segment(0xFE, "made in Figma")
+	table := segment(0xDB, "table")
+
+	got, err := stripJPEG(build(jfif, exif, icc, comment, table))
+	if err != nil {
+		t.Fatalf("stripJPEG() error = %v", err)
+	}
+	if want := build(jfif, icc, table); !bytes.Equal(got, want) {
+		t.Errorf("stripJPEG() = %x, want %x", got, want)
+	}
+
+	if _, err := stripJPEG([]byte("not a jpeg")); err == nil {
+		t.Error("stripJPEG() on invalid data: want error")
+	}
+}
+
+func TestOptimizeSVG(t *testing.T) {
+	input := `<?xml version="1.0" encoding="UTF-8"?>
+<!-- Generator: Figma -->
+<svg width="24" height="24" viewBox="0 0 24 24" fill="none" xmlns="http://www.w3.org/2000/svg" xmlns:sketch="http://www.bohemiancoding.com/sketch/ns">
+<metadata>editor data</metadata>
+<g>
+<g clip-path="url(#clip0_1_2)">
+<path d="M12.000001 2.5C6.47715 2.5 2 6.97715 2 12.5.5.5" fill="#0D99FF" sketch:type="MSShapeGroup"/>
+</g>
+</g>
+<g></g>
+<defs>
+<clipPath id="clip0_1_2">
+<rect width="24" height="24" fill="white"/>
+</clipPath>
+</defs>
+<text x="1.23456"> A &amp; B </text>
+</svg>
+`
+	want := `<svg width="24" height="24" viewBox="0 0 24 24" fill="none" xmlns="http://www.w3.org/2000/svg">` +
+		`<g clip-path="url(#clip0_1_2)"><path d="M12 2.5C6.477 2.5 2 6.977 2 12.5.5.5" fill="#0D99FF"/></g>` +
+		`<defs><clipPath id="clip0_1_2"><rect width="24" height="24" fill="white"/></clipPath></defs>` +
+		`<text x="1.235"> A &amp; B </text></svg>`
+
+	got, err := optimizeSVG([]byte(input))
+	if err != nil {
+		t.Fatalf("optimizeSVG() error = %v", err)
+	}
+	if string(got) != want {
+		t.Errorf("optimizeSVG() =\n%s\nwant\n%s", got, want)
+	}
+
+	if _, err := optimizeSVG([]byte("<html></html>")); err == nil {
+		t.Error("optimizeSVG() on a non-SVG document: want error")
+	}
+}
+
+func TestRoundSVGNumbers(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "M0.123456 10.0000001", want: "M.123 10"},
+		{in: "M1.5.5", want: "M1.5.5"},
+		{in: "M1.0004.5", want: "M1 .5"},
+		{in: "M1-0.00001", want: "M1 0"},
+		{in: "a1 1 0 011 1", want: "a1 1 0 011 1"},
+		{in: "matrix(0.7071068 -0.7071068 0.7071068 0.7071068 0 0)", want: "matrix(.707 -.707 .707 .707 0 0)"},
+		{in: "100%", want: "100%"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := roundSVGNumbers(tt.in); got != tt.want {
+				t.Errorf("roundSVGNumbers(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAssetDirs(t *testing.T) {
+	document := figma.Node{
+		ID:   "0:0",
+		Type: "DOCUMENT",
+		Children: []figma.Node{
+			{
+				ID:   "0:1",
+				Name: "Home Page",
+				Type: "CANVAS",
+				Children: []figma.Node{
+					{
+						ID:   "1:1",
+						Name: "Hero",
+						Type: "FRAME",
+						Children: []figma.Node{
+							{ID: "1:2", Name: "Logo", Children: []figma.Node{{ID: "1:3", Name: "Mark"}}},
+						},
+					},
+					{ID: "1:4", Name: "", Type: "FRAME", Children: []figma.Node{{ID: "1:5", Name: "Badge"}}},
+				},
+			},
+		},
+	}
+
+	want := map[string]string{
+		"1:1": "home-page",
+		"1:2": "home-page/hero",
+		"1:3": "home-page/hero",
+		"1:4": "home-page",
+		"1:5": "home-page/14",
+	}
+	got := AssetDirs(&document)
+	if len(got) != len(want) {
+		t.Errorf("AssetDirs() = %v, want %v", got, want)
+	}
+	for id, dir := range want {
+		if got[id] != dir {
+			t.Errorf("AssetDirs()[%q] = %q, want %q", id, got[id], dir)
+		}
+	}
+}
+
+func TestNodeHashes(t *testing.T) {
+	doc := figma.Node{ID: "0:0", Children: []figma.Node{
+		{ID: "1:1", Name: "Card", Children: []figma.Node{{ID: "2:1", Name: "Title"}}},
+		{ID: "1:2", Name: "Other"},
+	}}
+	before := NodeHashes(&doc)
+	doc.Children[0].Children[0].Name = "Heading"
+	after := NodeHashes(&doc)
+
+	for _, id := range []string{"0:0", "1:1", "2:1"} {
+		if before[id] == after[id] {
+			t.Errorf("hash of %s did not change with a node under it", id)
+		}
+	}
+	if before["1:2"] != after["1:2"] {
+		t.Error("hash of an unrelated node changed")
+	}
+}
+
+func TestManifestSkipsUnchanged(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store := LocalStore{Dir: dir}
+	doc := figma.Node{ID: "0:0", Children: []figma.Node{{ID: "1:1", Name: "Logo"}}}
+
+	m, err := LoadManifest(ctx, store, &doc, "v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "logo.png"), []byte("png"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	hash, err := fileHash(filepath.Join(dir, "logo.png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := manifestKey("1:1", "png", 1)
+	settings := ExportConfig{}.settingsHash(true)
+	m.record(key, m.nodeHash("1:1"), settings, hash, ExportedAsset{NodeID: "1:1", FileName: "logo.png", Format: "png", Scale: 1})
+	if err := m.Save(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err = LoadManifest(ctx, store, &doc, "v2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name, ok := m.current(ctx, key, m.nodeHash("1:1"), settings); !ok || name != "logo.png" {
+		t.Errorf("current() = %q, %v, want logo.png, true", name, ok)
+	}
+	for _, config := range []ExportConfig{
+		{RenderOptions: figma.RenderOptions{SVGIncludeID: true}},
+		{RenderOptions: figma.RenderOptions{UseAbsoluteBounds: true}},
+		{Optimize: true},
+		{OptimizeSVG: true},
+	} {
+		if _, ok := m.current(ctx, key, m.nodeHash("1:1"), config.settingsHash(true)); ok {
+			t.Errorf("current() reported an image exported with other settings than %+v as current", config)
+		}
+	}
+	if (ExportConfig{RenderOptions: figma.RenderOptions{SVGIncludeID: true}}).settingsHash(false) != (ExportConfig{}).settingsHash(false) {
+		t.Error("settingsHash(false) depends on the render options of image fills")
+	}
+
+	doc.Children[0].Name = "Logo Dark"
+	changed, err := LoadManifest(ctx, store, &doc, "v3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := changed.current(ctx, key, changed.nodeHash("1:1"), settings); ok {
+		t.Error("current() reported a changed node as current")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "logo.png"), []byte("edited"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := m.current(ctx, key, m.nodeHash("1:1"), settings); ok {
+		t.Error("current() reported a modified file as current")
+	}
+}
+
+func TestThrottledReader(t *testing.T) {
+	limiter := &bandwidthLimiter{bytesPerSecond: 100 * 1024}
+	data := make([]byte, 20*1024)
+
+	start := time.Now()
+	n, err := io.Copy(io.Discard, &throttledReader{ctx: context.Background(), r: bytes.NewReader(data), limiter: limiter})
+	if err != nil || n != int64(len(data)) {
+		t.Fatalf("io.Copy() = %d, %v, want %d, nil", n, err, len(data))
+	}
+	// 20 KB at 100 KB/s take 200ms.
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Errorf("throttled read took %v, want at least 150ms", elapsed)
+	}
+}
+
+func TestProgressTracker(t *testing.T) {
+	var reports []Progress
+	tracker := &progressTracker{fn: func(p Progress) { reports = append(reports, p) }}
+
+	tracker.add(2)
+	if _, err := io.Copy(io.Discard, &countingReader{r: bytes.NewReader(make([]byte, 100)), progress: tracker}); err != nil {
+		t.Fatal(err)
+	}
+	tracker.done()
+	tracker.done()
+
+	want := Progress{Completed: 2, Total: 2, Bytes: 100}
+	if got := reports[len(reports)-1]; got != want {
+		t.Errorf("last progress = %+v, want %+v", got, want)
+	}
+
+	// A nil tracker, when no ProgressFunc is set, ignores updates.
+	var none *progressTracker
+	none.add(1)
+	none.done()
+}
+
+func TestExportImageFillsCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	dir := t.TempDir()
+	resp := &figma.FileImagesResponse{Images: map[string]string{"ref": "http://127.0.0.1:0/image.png"}}
+	nodes := []ImageFillNode{{NodeID: "1:1", NodeName: "Photo", ImageRef: "ref"}}
+
+	if _, err := ExportImageFills(ctx, resp, nodes, ExportConfig{OutputDir: dir}); err != context.Canceled {
+		t.Errorf("ExportImageFills() error = %v, want %v", err, context.Canceled)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("cancelled export left %d file(s) behind", len(entries))
+	}
+}
+
+func TestExportImageFillsSortsAssets(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow.png" {
+			time.Sleep(50 * time.Millisecond)
+		}
+		w.Write([]byte("png"))
+	}))
+	defer srv.Close()
+
+	resp := &figma.FileImagesResponse{Images: map[string]string{
+		"slow": srv.URL + "/slow.png",
+		"fast": srv.URL + "/fast.png",
+	}}
+	nodes := []ImageFillNode{
+		{NodeID: "1:1", NodeName: "Photo", ImageRef: "slow"},
+		{NodeID: "1:2", NodeName: "Photo", ImageRef: "fast"},
+		{NodeID: "1:3", NodeName: "Avatar", ImageRef: "fast"},
+	}
+
+	result, err := ExportImageFills(context.Background(), resp, nodes, ExportConfig{OutputDir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, a := range result.Assets {
+		got = append(got, a.NodeID+"="+a.FileName)
+	}
+	want := []string{"1:3=avatar.png", "1:2=photo-2.png", "1:1=photo.png"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Assets = %v, want %v", got, want)
+	}
+}
+
+func TestExportImageFillsReportsAssets(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing.png" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("png"))
+	}))
+	defer srv.Close()
+
+	resp := &figma.FileImagesResponse{Images: map[string]string{
+		"ok":      srv.URL + "/ok.png",
+		"missing": srv.URL + "/missing.png",
+	}}
+	nodes := []ImageFillNode{
+		{NodeID: "1:1", NodeName: "Photo", ImageRef: "ok"},
+		{NodeID: "1:2", NodeName: "Broken", ImageRef: "missing"},
+	}
+
+	var downloaded []string
+	var last Progress
+	config := ExportConfig{OutputDir: t.TempDir(), Retries: -1, Progress: func(p Progress) {
+		if p.Asset != nil {
+			downloaded = append(downloaded, p.Asset.NodeID+"="+p.Asset.FileName)
+		}
+		last = p
+	}}
+	if _, err := ExportImageFills(context.Background(), resp, nodes, config); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"1:1=photo.png"}; fmt.Sprint(downloaded) != fmt.Sprint(want) {
+		t.Errorf("downloaded assets = %v, want %v", downloaded, want)
+	}
+	if last.Completed != 2 || last.Total != 2 {
+		t.Errorf("last progress = %d/%d, want 2/2", last.Completed, last.Total)
+	}
+}
+
+func TestDownloadFileResumes(t *testing.T) {
+	defer func(backoff time.Duration) { downloadBackoff = backoff }(downloadBackoff)
+	downloadBackoff = time.Millisecond
+
+	data := bytes.Repeat([]byte("0123456789"), 1000)
+	var ranges []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		switch len(ranges) {
+		case 1: // drop the connection halfway
+			w.Header().Set("Content-Length", fmt.Sprint(len(data)))
+			w.Write(data[:4000])
+			w.(http.Flusher).Flush()
+			panic(http.ErrAbortHandler)
+		case 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			var start int
+			fmt.Sscanf(r.Header.Get("Range"), "bytes=%d-", &start)
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, len(data)-1, len(data)))
+			w.WriteHeader(http.StatusPartialContent)
+			w.Write(data[start:])
+		}
+	}))
+	defer srv.Close()
+
+	dest := filepath.Join(t.TempDir(), "image.png")
+	if err := downloadFile(context.Background(), srv.Client(), srv.URL, dest, 3, nil); err != nil {
+		t.Fatalf("downloadFile() error = %v", err)
+	}
+	if got, _ := os.ReadFile(dest); !bytes.Equal(got, data) {
+		t.Errorf("downloaded %d bytes, want %d", len(got), len(data))
+	}
+	if want := []string{"", "bytes=4000-", "bytes=4000-"}; fmt.Sprint(ranges) != fmt.Sprint(want) {
+		t.Errorf("Range headers = %q, want %q", ranges, want)
+	}
+}
+
+func TestDownloadFileGivesUp(t *testing.T) {
+	defer func(backoff time.Duration) { downloadBackoff = backoff }(downloadBackoff)
+	downloadBackoff = time.Millisecond
+
+	tests := []struct {
+		status   int
+		retries  int
+		requests int
+	}{
+		{http.StatusInternalServerError, 2, 3},
+		{http.StatusTooManyRequests, 1, 2},
+		{http.StatusNotFound, 3, 1},
+		{http.StatusInternalServerError, -1, 1},
+	}
+	for _, tt := range tests {
+		requests := 0
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.WriteHeader(tt.status)
+		}))
+
+		dest := filepath.Join(t.TempDir(), "image.png")
+		if err := downloadFile(context.Background(), srv.Client(), srv.URL, dest, tt.retries, nil); err == nil {
+			t.Errorf("status %d: downloadFile() succeeded", tt.status)
+		}
+		if requests != tt.requests {
+			t.Errorf("status %d, %d retries: %d requests, want %d", tt.status, tt.retries, requests, tt.requests)
+		}
+		if _, err := os.Stat(dest); !os.IsNotExist(err) {
+			t.Errorf("status %d: failed download left a file behind", tt.status)
+		}
+		srv.Close()
+	}
+}
+
+func TestLocalStore(t *testing.T) {
+	ctx := context.Background()
+	store := LocalStore{Dir: t.TempDir()}
+
+	if err := store.Put(ctx, "home/logo.png", bytes.NewReader([]byte("png"))); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	var got bytes.Buffer
+	if err := copyAsset(ctx, store, "home/logo.png", &got); err != nil || got.String() != "png" {
+		t.Errorf("Open() = %q, %v, want png", got.String(), err)
+	}
+	if err := store.Delete(ctx, "home/logo.png"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := store.Open(ctx, "home/logo.png"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Open() of a deleted asset error = %v, want fs.ErrNotExist", err)
+	}
+	if err := store.Delete(ctx, "home/logo.png"); err != nil {
+		t.Errorf("Delete() of a missing asset error = %v", err)
+	}
+}
+
+func TestS3Store(t *testing.T) {
+	objects := make(map[string][]byte)
+	var auth []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = append(auth, r.Header.Get("Authorization"))
+		key := r.URL.EscapedPath()
+		switch r.Method {
+		case http.MethodPut:
+			data, _ := io.ReadAll(r.Body)
+			sum := sha256.Sum256(data)
+			if r.Header.Get("X-Amz-Content-Sha256") != hex.EncodeToString(sum[:]) || r.Header.Get("Content-Type") != "image/png" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			objects[key] = data
+		case http.MethodGet:
+			data, ok := objects[key]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write(data)
+		case http.MethodDelete:
+			delete(objects, key)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer srv.Close()
+
+	store := &S3Store{
+		Bucket:          "assets",
+		Prefix:          "figma",
+		Region:          "eu-west-1",
+		Endpoint:        srv.URL,
+		AccessKeyID:     "AKID",
+		SecretAccessKey: "secret",
+		HTTPClient:      srv.Client(),
+		now:             func() time.Time { return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC) },
+	}
+	ctx := context.Background()
+	if err := store.Put(ctx, "home/logo@2x.png", strings.NewReader("png")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if _, ok := objects["/assets/figma/home/logo%402x.png"]; !ok {
+		t.Fatalf("Put() stored %v, want /assets/figma/home/logo%%402x.png", objects)
+	}
+	if want := "AWS4-HMAC-SHA256 Credential=AKID/20260102/eu-west-1/s3/aws4_request, SignedHeaders=content-type;host;x-amz-content-sha256;x-amz-date, Signature="; !strings.HasPrefix(auth[0], want) {
+		t.Errorf("Authorization = %q, want prefix %q", auth[0], want)
+	}
+
+	var got bytes.Buffer
+	if err := copyAsset(ctx, store, "home/logo@2x.png", &got); err != nil || got.String() != "png" {
+		t.Errorf("Open() = %q, %v, want png", got.String(), err)
+	}
+	if err := store.Delete(ctx, "home/logo@2x.png"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := store.Open(ctx, "home/logo@2x.png"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Open() of a deleted object error = %v, want fs.ErrNotExist", err)
+	}
+}
+
+func TestBuildSprite(t *testing.T) {
+	icons := map[string][]byte{
+		"home/arrow.svg":  []byte(`<svg width="24" height="24" viewBox="0 0 24 24" fill="none" xmlns="http://www.w3.org/2000/svg"><g clip-path="url(#clip0)"><path d="M0 0h24"/></g><defs><clipPath id="clip0"><rect width="24" height="24"/></clipPath></defs></svg>`),
+		"about/arrow.svg": []byte(`<svg width="16" height="16" xmlns="http://www.w3.org/2000/svg"><use href="#p"/><path id="p" d="M0 0h16"/></svg>`),
+		"broken.svg":      []byte(`not svg`),
+	}
+	sprite, symbols := buildSprite(icons)
+
+	want := []SpriteSymbol{
+		{ID: "arrow", FileName: "about/arrow.svg", ViewBox: "0 0 16 16"},
+		{ID: "arrow-2", FileName: "home/arrow.svg", ViewBox: "0 0 24 24"},
+	}
+	if fmt.Sprint(symbols) != fmt.Sprint(want) {
+		t.Errorf("symbols = %v, want %v", symbols, want)
+	}
+	for _, s := range []string{
+		`<symbol id="arrow" viewBox="0 0 16 16"><use href="#arrow-p"/><path id="arrow-p" d="M0 0h16"/></symbol>`,
+		`<symbol id="arrow-2" viewBox="0 0 24 24" fill="none"><g clip-path="url(#arrow-2-clip0)">`,
+		`<clipPath id="arrow-2-clip0">`,
+	} {
+		if !bytes.Contains(sprite, []byte(s)) {
+			t.Errorf("sprite misses %s:\n%s", s, sprite)
+		}
+	}
+	if usage := spriteUsage(symbols); !strings.Contains(usage, `<svg width="24" height="24" aria-hidden="true"><use href="sprite.svg#arrow-2"></use></svg>`) {
+		t.Errorf("spriteUsage() = %s", usage)
+	}
+}
+
+func TestWriteAssetCatalog(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store := LocalStore{Dir: dir}
+	assets := []ExportedAsset{
+		{NodeID: "1:1", NodeName: "Logo", FileName: "logo@2x.png", Format: "png", Scale: 2},
+		{NodeID: "1:1", NodeName: "Logo", FileName: "logo.png", Format: "png", Scale: 1},
+		{NodeID: "1:2", NodeName: "Arrow", FileName: "arrow.svg", Format: "svg", Scale: 1},
+		{NodeID: "1:3", NodeName: "Hero", FileName: "hero@4x.png", Format: "png", Scale: 4},
+	}
+	for _, a := range assets {
+		if err := store.Put(ctx, a.FileName, strings.NewReader(a.FileName)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := WriteAssetCatalog(ctx, store, assets, nil)
+	if err != nil {
+		t.Fatalf("WriteAssetCatalog() error = %v", err)
+	}
+	want := []string{
+		"Images.xcassets/logo.imageset/logo@2x.png",
+		"Images.xcassets/logo.imageset/logo.png",
+		"Images.xcassets/arrow.imageset/arrow.svg",
+		"hero@4x.png",
+	}
+	for i, a := range got {
+		if a.FileName != want[i] {
+			t.Errorf("asset %d moved to %q, want %q", i, a.FileName, want[i])
+		}
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(a.FileName))); err != nil {
+			t.Errorf("asset %d: %v", i, err)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "Images.xcassets", "logo.imageset", "Contents.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{`"filename": "logo.png"`, `"filename": "logo@2x.png"`, `"scale": "3x"`} {
+		if !strings.Contains(string(data), s) {
+			t.Errorf("Contents.json misses %s:\n%s", s, data)
+		}
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "Images.xcassets", "arrow.imageset", "Contents.json")); !strings.Contains(string(data), `"preserves-vector-representation": true`) {
+		t.Errorf("vector Contents.json = %s", data)
+	}
+}
+
+func TestWriteAndroidResources(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store := LocalStore{Dir: dir}
+	assets := []ExportedAsset{
+		{NodeID: "1:1", NodeName: "Logo Mark", FileName: "logo-mark@2x.png", Format: "png", Scale: 2},
+		{NodeID: "1:2", NodeName: "1 Arrow", FileName: "1-arrow.svg", Format: "svg", Scale: 1},
+	}
+	files := map[string]string{
+		"logo-mark@2x.png": "png",
+		"1-arrow.svg":      `<svg width="24" height="24" viewBox="0 0 24 24" fill="none" xmlns="http://www.w3.org/2000/svg"><path d="M4 12h16" stroke="#333" stroke-width="2"/></svg>`,
+	}
+	for name, data := range files {
+		if err := store.Put(ctx, name, strings.NewReader(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, errs := WriteAndroidResources(ctx, store, assets, nil)
+	if len(errs) > 0 {
+		t.Fatalf("WriteAndroidResources() errors = %v", errs)
+	}
+	if got[0].FileName != "res/drawable-xhdpi/logo_mark.png" || got[1].FileName != "1-arrow.svg" {
+		t.Errorf("file names = %q, %q", got[0].FileName, got[1].FileName)
+	}
+	drawable, err := os.ReadFile(filepath.Join(dir, "res", "drawable", "img_1_arrow.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{`android:viewportWidth="24"`, `android:pathData="M4 12h16"`, `android:strokeColor="#333333"`, `android:strokeWidth="2"`} {
+		if !strings.Contains(string(drawable), s) {
+			t.Errorf("vector drawable misses %s:\n%s", s, drawable)
+		}
+	}
+	if strings.Contains(string(drawable), "fillColor") {
+		t.Errorf("vector drawable fills an unfilled path:\n%s", drawable)
+	}
+}
+
+func TestVectorDrawableUnsupported(t *testing.T) {
+	svg := `<svg width="24" height="24" xmlns="http://www.w3.org/2000/svg"><rect width="24" height="24" fill="url(#g)"/><defs><linearGradient id="g"/></defs></svg>`
+	if _, err := vectorDrawable([]byte(svg)); err == nil {
+		t.Error("vectorDrawable() converted a gradient")
+	}
+}
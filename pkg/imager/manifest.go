@@ -0,0 +1,177 @@
+package imager
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"sync"
+
+	"github.com/hellenic-development/figma-extractor/pkg/figma"
+)
+
+// ManifestFile is the name of the manifest written to the image directory or store.
+const ManifestFile = ".figma-manifest.json"
+
+// Manifest records where every exported image came from, so that later exports skip the
+// images whose nodes did not change. It is safe for concurrent use.
+type Manifest struct {
+	mu      sync.Mutex
+	store   AssetStore
+	version string
+	hashes  map[string]string // node ID -> hash of the node and its subtree in the current file
+
+	Assets map[string]ManifestEntry `json:"assets"` // by node ID, format and scale
+}
+
+// ManifestEntry describes an exported image.
+type ManifestEntry struct {
+	NodeID   string  `json:"nodeId"`
+	FileName string  `json:"fileName"` // relative to the image directory, slash-separated
+	Format   string  `json:"format"`
+	Scale    float64 `json:"scale"`
+	Version  string  `json:"version,omitempty"` // file version the image was exported from
+	NodeHash string  `json:"nodeHash"`          // hash of the node when exported, or the image reference of an image fill
+	Settings string  `json:"settings"`          // hash of the render and optimization settings, see ExportConfig.settingsHash
+	Hash     string  `json:"hash"`              // SHA-256 of the image file
+}
+
+// LoadManifest reads the manifest of the images in store, or starts an empty one, to export
+// the images of document, the current state of the file at the given version.
+func LoadManifest(ctx context.Context, store AssetStore, document *figma.Node, version string) (*Manifest, error) {
+	m := &Manifest{
+		store:   store,
+		version: version,
+		hashes:  NodeHashes(document),
+		Assets:  make(map[string]ManifestEntry),
+	}
+	var data bytes.Buffer
+	err := copyAsset(ctx, store, ManifestFile, &data)
+	if errors.Is(err, fs.ErrNotExist) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read manifest: %w", err)
+	}
+	if err := json.Unmarshal(data.Bytes(), m); err != nil {
+		return nil, fmt.Errorf("parse manifest: %w", err)
+	}
+	if m.Assets == nil {
+		m.Assets = make(map[string]ManifestEntry)
+	}
+	return m, nil
+}
+
+// Save writes the manifest back to the store.
+func (m *Manifest) Save(ctx context.Context) error {
+	m.mu.Lock()
+	data, err := json.MarshalIndent(m, "", "  ")
+	m.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return m.store.Put(ctx, ManifestFile, bytes.NewReader(append(data, '\n')))
+}
+
+// manifestKey identifies an image of a node in the manifest.
+func manifestKey(nodeID, format string, scale float64) string {
+	return fmt.Sprintf("%s@%gx.%s", nodeID, scale, format)
+}
+
+// current returns the file name of the image of a node when it was already exported from the
+// same node with the same settings and the file is still in the store, unmodified. source is
+// the hash of the node, or the image reference of an image fill.
+func (m *Manifest) current(ctx context.Context, key, source, settings string) (string, bool) {
+	m.mu.Lock()
+	entry, ok := m.Assets[key]
+	m.mu.Unlock()
+	if !ok || source == "" || entry.NodeHash != source || entry.Settings != settings {
+		return "", false
+	}
+	h := sha256.New()
+	if err := copyAsset(ctx, m.store, entry.FileName, h); err != nil || hex.EncodeToString(h.Sum(nil)) != entry.Hash {
+		return "", false
+	}
+	return entry.FileName, true
+}
+
+// record stores the image of a node, exported with settings and stored with the given SHA-256.
+func (m *Manifest) record(key, source, settings, hash string, asset ExportedAsset) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Assets[key] = ManifestEntry{
+		NodeID:   asset.NodeID,
+		FileName: asset.FileName,
+		Format:   asset.Format,
+		Scale:    asset.Scale,
+		Version:  m.version,
+		NodeHash: source,
+		Settings: settings,
+		Hash:     hash,
+	}
+}
+
+// Rename records that an image moved from oldName to newName, e.g. into an asset catalog.
+func (m *Manifest) Rename(oldName, newName string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for key, entry := range m.Assets {
+		if entry.FileName == oldName {
+			entry.FileName = newName
+			m.Assets[key] = entry
+		}
+	}
+}
+
+// nodeHash returns the hash of a node in the current file, empty when it is unknown.
+func (m *Manifest) nodeHash(nodeID string) string {
+	return m.hashes[nodeID]
+}
+
+// NodeHashes returns a hash of every node under root, covering its properties and its whole
+// subtree: a node's hash changes whenever anything drawn inside it does.
+func NodeHashes(root *figma.Node) map[string]string {
+	hashes := make(map[string]string)
+	var walk func(node *figma.Node) string
+	walk = func(node *figma.Node) string {
+		h := sha256.New()
+		props := *node
+		props.Children = nil
+		if data, err := json.Marshal(props); err == nil {
+			h.Write(data)
+		}
+		for i := range node.Children {
+			io.WriteString(h, walk(&node.Children[i]))
+		}
+		sum := hex.EncodeToString(h.Sum(nil))
+		hashes[node.ID] = sum
+		return sum
+	}
+	if root != nil {
+		walk(root)
+	}
+	return hashes
+}
+
+// fileHash returns the SHA-256 of the file at path.
+func fileHash(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
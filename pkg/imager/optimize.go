@@ -0,0 +1,174 @@
+package imager
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image/png"
+	"os"
+	"path/filepath"
+)
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// pngColorChunks are the ancillary PNG chunks that affect how pixels are displayed. They are
+// kept by the optimizer; every other ancillary chunk (text, time, EXIF, physical size) is
+// metadata and dropped.
+var pngColorChunks = map[string]bool{
+	"cHRM": true,
+	"gAMA": true,
+	"iCCP": true,
+	"sRGB": true,
+	"sBIT": true,
+}
+
+// optimizeFile rewrites the file at path in place with optimize, returning how many bytes were
+// saved. Files that cannot be made smaller are left untouched.
+func optimizeFile(path string, optimize func([]byte) ([]byte, error)) (int64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	optimized, err := optimize(data)
+	if err != nil {
+		return 0, err
+	}
+	saved := int64(len(data) - len(optimized))
+	if saved <= 0 {
+		return 0, nil
+	}
+
+	// Replace through a temporary file so that a failed write never leaves a broken image.
+	tmp, err := os.CreateTemp(filepath.Dir(path), "*.tmp")
+	if err != nil {
+		return 0, err
+	}
+	_, err = tmp.Write(optimized)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return 0, err
+	}
+	return saved, nil
+}
+
+// pngChunk is a raw PNG chunk: length, type, data and CRC.
+type pngChunk struct {
+	typ string
+	raw []byte
+}
+
+// pngChunks splits a PNG file into its chunks.
+func pngChunks(data []byte) ([]pngChunk, error) {
+	if !bytes.HasPrefix(data, pngSignature) {
+		return nil, errors.New("not a PNG image")
+	}
+	var chunks []pngChunk
+	for rest := data[len(pngSignature):]; len(rest) > 0; {
+		if len(rest) < 12 {
+			return nil, errors.New("truncated PNG chunk")
+		}
+		size := int(binary.BigEndian.Uint32(rest[:4]))
+		if size > len(rest)-12 {
+			return nil, errors.New("truncated PNG chunk")
+		}
+		chunks = append(chunks, pngChunk{typ: string(rest[4:8]), raw: rest[:12+size]})
+		rest = rest[12+size:]
+	}
+	if len(chunks) == 0 || chunks[0].typ != "IHDR" {
+		return nil, errors.New("PNG image without header")
+	}
+	return chunks, nil
+}
+
+// optimizePNG re-encodes a PNG image with the best compression and keeps only the chunks that
+// affect its display. The pixels are unchanged: PNG is lossless.
+func optimizePNG(data []byte) ([]byte, error) {
+	chunks, err := pngChunks(data)
+	if err != nil {
+		return nil, err
+	}
+	var colorChunks []pngChunk
+	for _, c := range chunks {
+		if pngColorChunks[c.typ] {
+			colorChunks = append(colorChunks, c)
+		}
+	}
+
+	// stripped drops the metadata chunks, which is always possible, even without re-encoding.
+	stripped := func(chunks []pngChunk) []byte {
+		var out bytes.Buffer
+		out.Write(pngSignature)
+		for _, c := range chunks {
+			critical := c.typ[0] >= 'A' && c.typ[0] <= 'Z'
+			if critical || c.typ == "tRNS" || pngColorChunks[c.typ] {
+				out.Write(c.raw)
+			}
+		}
+		return out.Bytes()
+	}
+	best := stripped(chunks)
+
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return best, nil
+	}
+	var encoded bytes.Buffer
+	if err := (&png.Encoder{CompressionLevel: png.BestCompression}).Encode(&encoded, img); err != nil {
+		return best, nil
+	}
+	reencoded, err := pngChunks(encoded.Bytes())
+	if err != nil {
+		return best, nil
+	}
+	// The color chunks of the original image must precede PLTE and IDAT: put them right after
+	// the header of the re-encoded one.
+	merged := append([]pngChunk{reencoded[0]}, colorChunks...)
+	merged = append(merged, reencoded[1:]...)
+	if candidate := stripped(merged); len(candidate) < len(best) {
+		best = candidate
+	}
+	return best, nil
+}
+
+// stripJPEG removes the metadata segments of a JPEG image: EXIF and XMP (APP1), other
+// application data and comments. The JFIF header (APP0), the ICC profile (APP2) and the Adobe
+// color transform (APP14) are kept, and the compressed image data is copied unchanged, so the
+// image stays exactly the same.
+func stripJPEG(data []byte) ([]byte, error) {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return nil, errors.New("not a JPEG image")
+	}
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	out.Write(data[:2])
+
+	for rest := data[2:]; ; {
+		// Skip fill bytes before the marker.
+		for len(rest) > 1 && rest[0] == 0xFF && rest[1] == 0xFF {
+			rest = rest[1:]
+		}
+		if len(rest) < 4 || rest[0] != 0xFF {
+			return nil, errors.New("malformed JPEG segment")
+		}
+		marker := rest[1]
+		size := int(binary.BigEndian.Uint16(rest[2:4]))
+		if size < 2 || size+2 > len(rest) {
+			return nil, errors.New("truncated JPEG segment")
+		}
+		if marker == 0xDA { // start of scan: the rest is image data
+			out.Write(rest)
+			return out.Bytes(), nil
+		}
+
+		metadata := marker == 0xFE || (marker >= 0xE1 && marker <= 0xEF && marker != 0xE2 && marker != 0xEE)
+		if !metadata {
+			out.Write(rest[:size+2])
+		}
+		rest = rest[size+2:]
+	}
+}
@@ -0,0 +1,74 @@
+package imager
+
+import (
+	"io"
+	"sync"
+)
+
+// Progress is the state of an image export.
+type Progress struct {
+	Completed int   // images done: downloaded, kept from a previous export or failed
+	Total     int   // images of the export known so far; grows as batches are rendered
+	Bytes     int64 // bytes downloaded so far
+
+	// Asset is the image whose download completed the report; nil for other reports.
+	Asset *ExportedAsset
+}
+
+// ProgressFunc receives the progress of an image export. Calls are serialized, but come from
+// the download goroutines: the function should return quickly.
+type ProgressFunc func(Progress)
+
+// progressTracker counts the progress of an export and reports every change.
+type progressTracker struct {
+	mu    sync.Mutex
+	state Progress
+	fn    ProgressFunc
+}
+
+// update applies change to the progress and reports it.
+func (p *progressTracker) update(change func(*Progress)) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	change(&p.state)
+	p.fn(p.state)
+	p.state.Asset = nil
+}
+
+// add announces n more images.
+func (p *progressTracker) add(n int) {
+	if n > 0 {
+		p.update(func(s *Progress) { s.Total += n })
+	}
+}
+
+// done reports an image as completed.
+func (p *progressTracker) done() {
+	p.complete(nil)
+}
+
+// complete reports an image as completed, with the asset downloaded for it, if any.
+func (p *progressTracker) complete(asset *ExportedAsset) {
+	p.update(func(s *Progress) {
+		s.Completed++
+		s.Asset = asset
+	})
+}
+
+// countingReader reports the bytes read to a progress tracker.
+type countingReader struct {
+	r        io.Reader
+	progress *progressTracker
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	if n > 0 {
+		c.progress.update(func(s *Progress) { s.Bytes += int64(n) })
+	}
+	return n, err
+}
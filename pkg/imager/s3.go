@@ -0,0 +1,310 @@
+package imager
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// S3Store stores assets in a bucket of Amazon S3 or of an S3-compatible service, such as
+// Cloudflare R2, MinIO or Google Cloud Storage with HMAC keys, e.g. to publish them straight
+// to a CDN. Requests are signed with AWS Signature Version 4.
+type S3Store struct {
+	Bucket string
+	Prefix string // prepended to every name, e.g. "design/assets"
+	Region string // default "us-east-1"
+
+	// Endpoint is the URL of an S3-compatible service, e.g. "https://<account>.r2.cloudflarestorage.com";
+	// empty = Amazon S3. Buckets of other services are addressed by path.
+	Endpoint string
+
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string // of temporary credentials, optional
+
+	// CacheControl, when set, is stored with every object, e.g. "public, max-age=31536000".
+	CacheControl string
+
+	// HTTPClient sends the requests; nil = http.DefaultClient.
+	HTTPClient *http.Client
+
+	now func() time.Time // for tests
+}
+
+// S3StoreFromEnv returns the store of a bucket configured by the standard AWS environment
+// variables: AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, AWS_SESSION_TOKEN, AWS_REGION (or
+// AWS_DEFAULT_REGION) and AWS_ENDPOINT_URL_S3 (or AWS_ENDPOINT_URL) for S3-compatible services.
+func S3StoreFromEnv(bucket, prefix string) (*S3Store, error) {
+	s := &S3Store{
+		Bucket:          bucket,
+		Prefix:          prefix,
+		Region:          firstEnv("AWS_REGION", "AWS_DEFAULT_REGION"),
+		Endpoint:        firstEnv("AWS_ENDPOINT_URL_S3", "AWS_ENDPOINT_URL"),
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+	if s.AccessKeyID == "" || s.SecretAccessKey == "" {
+		return nil, errors.New("S3 store requires AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY")
+	}
+	return s, nil
+}
+
+// firstEnv returns the first non-empty environment variable of keys.
+func firstEnv(keys ...string) string {
+	for _, key := range keys {
+		if v := os.Getenv(key); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// Put uploads the object of name. The content is read into memory when r is not seekable, to
+// sign its hash.
+func (s *S3Store) Put(ctx context.Context, name string, r io.Reader) error {
+	body, ok := r.(io.ReadSeeker)
+	if !ok {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	h := sha256.New()
+	size, err := io.Copy(h, body)
+	if err != nil {
+		return err
+	}
+	if _, err := body.Seek(-size, io.SeekCurrent); err != nil {
+		return err
+	}
+
+	header := http.Header{"Content-Type": {contentType(name)}}
+	if s.CacheControl != "" {
+		header.Set("Cache-Control", s.CacheControl)
+	}
+	var content io.ReadCloser = http.NoBody // a zero length with a body would be sent chunked
+	if size > 0 {
+		content = io.NopCloser(body)
+	}
+	resp, err := s.do(ctx, http.MethodPut, name, header, content, size, hex.EncodeToString(h.Sum(nil)))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Open downloads the object of name.
+func (s *S3Store) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	resp, err := s.do(ctx, http.MethodGet, name, nil, nil, 0, emptySHA256)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Delete removes the object of name.
+func (s *S3Store) Delete(ctx context.Context, name string) error {
+	resp, err := s.do(ctx, http.MethodDelete, name, nil, nil, 0, emptySHA256)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (s *S3Store) String() string {
+	return "s3://" + path.Join(s.Bucket, s.Prefix)
+}
+
+// emptySHA256 is the hash of an empty request body.
+const emptySHA256 = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// s3Error is a failed S3 request.
+type s3Error struct {
+	Method string
+	Key    string
+	Status int
+	Body   string
+}
+
+func (e *s3Error) Error() string {
+	return fmt.Sprintf("S3 %s %q: status %d: %s", e.Method, e.Key, e.Status, e.Body)
+}
+
+// Is makes a missing object match fs.ErrNotExist.
+func (e *s3Error) Is(target error) bool {
+	return target == fs.ErrNotExist && e.Status == http.StatusNotFound
+}
+
+// do sends a signed request for the object of name, failing for non-2xx responses.
+func (s *S3Store) do(ctx context.Context, method, name string, header http.Header, body io.ReadCloser, size int64, payloadHash string) (*http.Response, error) {
+	key := path.Join(s.Prefix, path.Clean("/" + name)[1:])
+	u, err := s.objectURL(key)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = size
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	s.sign(req, payloadHash)
+
+	client := s.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		defer resp.Body.Close()
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, &s3Error{Method: method, Key: key, Status: resp.StatusCode, Body: strings.TrimSpace(string(msg))}
+	}
+	return resp, nil
+}
+
+// objectURL returns the URL of an object: virtual-hosted on Amazon S3, by path otherwise.
+func (s *S3Store) objectURL(key string) (*url.URL, error) {
+	u := &url.URL{Scheme: "https", Host: s.Bucket + ".s3." + s.region() + ".amazonaws.com", Path: "/" + key}
+	if s.Endpoint != "" {
+		var err error
+		if u, err = url.Parse(s.Endpoint); err != nil {
+			return nil, fmt.Errorf("invalid S3 endpoint %q: %w", s.Endpoint, err)
+		}
+		u.Path = path.Join("/", u.Path, s.Bucket, key)
+	}
+	// Signatures cover the path escaped the way S3 does.
+	u.RawPath = s3Escape(u.Path)
+	return u, nil
+}
+
+// s3Escape escapes every byte of a path but the unreserved characters and slashes.
+func s3Escape(p string) string {
+	var sb strings.Builder
+	for i := 0; i < len(p); i++ {
+		c := p[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || strings.IndexByte("-_.~/", c) >= 0 {
+			sb.WriteByte(c)
+		} else {
+			fmt.Fprintf(&sb, "%%%02X", c)
+		}
+	}
+	return sb.String()
+}
+
+func (s *S3Store) region() string {
+	if s.Region == "" {
+		return "us-east-1"
+	}
+	return s.Region
+}
+
+// sign adds the AWS Signature Version 4 authorization of req.
+func (s *S3Store) sign(req *http.Request, payloadHash string) {
+	now := time.Now
+	if s.now != nil {
+		now = s.now
+	}
+	t := now().UTC()
+	amzDate := t.Format("20060102T150405Z")
+	date := amzDate[:8]
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if s.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.SessionToken)
+	}
+
+	// Sign the host and every x-amz-* and content header.
+	headers := map[string]string{"host": req.URL.Host}
+	for k, v := range req.Header {
+		lk := strings.ToLower(k)
+		if strings.HasPrefix(lk, "x-amz-") || strings.HasPrefix(lk, "content-") || lk == "cache-control" {
+			headers[lk] = strings.TrimSpace(strings.Join(v, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, k := range names {
+		canonicalHeaders.WriteString(k + ":" + headers[k] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + s.region() + "/s3/aws4_request"
+	canonicalHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(canonicalHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.SecretAccessKey), date)
+	key = hmacSHA256(key, s.region())
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// contentType returns the media type of an asset by its extension.
+func contentType(name string) string {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".png":
+		return "image/png"
+	case ".jpg", ".jpeg":
+		return "image/jpeg"
+	case ".svg":
+		return "image/svg+xml"
+	case ".pdf":
+		return "application/pdf"
+	case ".gif":
+		return "image/gif"
+	case ".webp":
+		return "image/webp"
+	case ".json":
+		return "application/json"
+	default:
+		return "application/octet-stream"
+	}
+}
@@ -0,0 +1,198 @@
+package imager
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/hellenic-development/figma-extractor/pkg/figma"
+)
+
+// Scale limits of the Images API.
+const (
+	minScale = 0.01
+	maxScale = 4
+)
+
+// ExportableNode is a node with the export settings its designer configured in Figma.
+type ExportableNode struct {
+	NodeID   string
+	NodeName string
+	Width    float64 // size of the node, used to resolve WIDTH and HEIGHT constraints
+	Height   float64
+	Settings []figma.ExportSetting
+}
+
+// CollectExportSettings walks the Figma node tree and returns the nodes that have ExportSettings
+// defined by the designer, together with those settings, in document order.
+func CollectExportSettings(root *figma.Node) []ExportableNode {
+	var nodes []ExportableNode
+	var walk func(node *figma.Node)
+	walk = func(node *figma.Node) {
+		if len(node.ExportSettings) > 0 {
+			n := ExportableNode{NodeID: node.ID, NodeName: node.Name, Settings: node.ExportSettings}
+			if box := node.AbsoluteBoundingBox; box != nil {
+				n.Width, n.Height = box.Width, box.Height
+			}
+			nodes = append(nodes, n)
+		}
+		for i := range node.Children {
+			walk(&node.Children[i])
+		}
+	}
+	walk(root)
+	return nodes
+}
+
+// CollectNamedNodes walks the Figma node tree and returns the nodes whose names match one of the
+// patterns, with their export settings if any, in document order. Patterns use the syntax of
+// path.Match, so "Icons/*" matches "Icons/Arrow" but not "Icons/Arrow/Left". The children of a
+// matching node are not visited.
+func CollectNamedNodes(root *figma.Node, patterns []string) []ExportableNode {
+	var nodes []ExportableNode
+	var walk func(node *figma.Node)
+	walk = func(node *figma.Node) {
+		if matchName(node.Name, patterns) {
+			n := ExportableNode{NodeID: node.ID, NodeName: node.Name, Settings: node.ExportSettings}
+			if box := node.AbsoluteBoundingBox; box != nil {
+				n.Width, n.Height = box.Width, box.Height
+			}
+			nodes = append(nodes, n)
+			return
+		}
+		for i := range node.Children {
+			walk(&node.Children[i])
+		}
+	}
+	walk(root)
+	return nodes
+}
+
+// matchName reports whether name matches one of the path.Match patterns. Malformed patterns
+// match nothing.
+func matchName(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// renderKey groups the nodes rendered by the same Images API request.
+type renderKey struct {
+	format string
+	scale  float64
+}
+
+// ExportWithSettings exports every node in the formats, scales and file name suffixes its
+// designer configured, instead of the global Format and Scales of config. Nodes sharing a
+// format and scale are rendered together.
+func ExportWithSettings(ctx context.Context, client *figma.Client, fileKey string, nodes []ExportableNode, config ExportConfig) (*ExportResult, error) {
+	if err := config.createOutputDir(); err != nil {
+		return nil, err
+	}
+
+	exp := newExporter(client, fileKey, config)
+
+	groups := make(map[renderKey][]string)             // render -> node IDs
+	fileNames := make(map[renderKey]map[string]string) // render -> node ID -> file name
+	names := make(map[string]string)                   // node ID -> node name
+	for _, n := range nodes {
+		names[n.NodeID] = n.NodeName
+		for _, setting := range n.Settings {
+			key := renderKey{format: strings.ToLower(setting.Format), scale: settingScale(setting, n.Width, n.Height)}
+			if key.format == "" {
+				key.format = "png"
+			}
+			if fileNames[key] == nil {
+				fileNames[key] = make(map[string]string)
+			}
+			if _, dup := fileNames[key][n.NodeID]; dup {
+				continue
+			}
+			groups[key] = append(groups[key], n.NodeID)
+			fileNames[key][n.NodeID] = settingFileName(n.NodeName, n.NodeID, setting.Suffix, key.format, key.scale)
+		}
+	}
+
+	keys := make([]renderKey, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].format != keys[j].format {
+			return keys[i].format < keys[j].format
+		}
+		return keys[i].scale < keys[j].scale
+	})
+
+	for _, key := range keys {
+		err := exp.render(ctx, groups[key], key.format, key.scale, func(nodeID string) (string, string) {
+			return names[nodeID], fileNames[key][nodeID]
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	exp.result.sortAssets()
+	return exp.result, nil
+}
+
+// settingScale returns the render scale of an export setting: its SCALE constraint, or the
+// scale that makes a node of the given size as wide (WIDTH) or as high (HEIGHT) as its
+// constraint asks. Vector formats are always rendered at scale 1.
+func settingScale(setting figma.ExportSetting, width, height float64) float64 {
+	format := strings.ToLower(setting.Format)
+	if format == "svg" || format == "pdf" {
+		return 1
+	}
+
+	scale := 1.0
+	value := setting.Constraint.Value
+	switch setting.Constraint.Type {
+	case "SCALE":
+		if value > 0 {
+			scale = value
+		}
+	case "WIDTH":
+		if value > 0 && width > 0 {
+			scale = value / width
+		}
+	case "HEIGHT":
+		if value > 0 && height > 0 {
+			scale = value / height
+		}
+	}
+	return min(max(scale, minScale), maxScale)
+}
+
+// settingFileName builds the file name of a node exported with a designer setting: the node
+// name followed by the suffix of the setting, like Figma names its exports. Without a suffix,
+// raster scales above 1 get the usual @2x-style suffix.
+func settingFileName(nodeName, nodeID, suffix, format string, scale float64) string {
+	suffix = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '@', r == '-', r == '_', r == '.':
+			return r
+		case r == ' ':
+			return '-'
+		}
+		return -1
+	}, suffix)
+	if suffix == "" {
+		return buildFileName(nodeName, nodeID, format, scale)
+	}
+
+	name := toKebabCase(nodeName)
+	if name == "" {
+		name = toKebabCase(nodeID)
+	}
+	if name == "" {
+		name = "asset"
+	}
+	return fmt.Sprintf("%s%s.%s", name, suffix, format)
+}
@@ -0,0 +1,192 @@
+package imager
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// SpriteFile is the name of the SVG sprite written by WriteSprite, and SpriteUsageFile the name
+// of the HTML snippet showing how to use its symbols.
+const (
+	SpriteFile      = "sprite.svg"
+	SpriteUsageFile = "sprite.html"
+)
+
+// spriteRootAttrs are the attributes of an icon's root element that do not carry over to its
+// symbol: the size is set where the symbol is used.
+var spriteRootAttrs = map[string]bool{"width": true, "height": true, "x": true, "y": true, "version": true, "id": true}
+
+// svgURLRef matches a reference to an element by ID in an attribute value, e.g. "url(#clip0)".
+var svgURLRef = regexp.MustCompile(`url\(#[^)]+\)`)
+
+// SpriteSymbol is an icon of a sprite.
+type SpriteSymbol struct {
+	ID       string // symbol ID, referenced as sprite.svg#ID
+	FileName string // the SVG the symbol was made of
+	ViewBox  string
+}
+
+// WriteSprite combines the SVG icons of store named by fileNames into one sprite of symbols,
+// written to the store as sprite.svg, along with a usage snippet, sprite.html. Icons that are
+// not valid SVG documents are skipped. It returns the symbols of the sprite.
+func WriteSprite(ctx context.Context, store AssetStore, fileNames []string) ([]SpriteSymbol, error) {
+	icons := make(map[string][]byte, len(fileNames))
+	for _, name := range fileNames {
+		var data bytes.Buffer
+		if err := copyAsset(ctx, store, name, &data); err != nil {
+			return nil, fmt.Errorf("read icon %q: %w", name, err)
+		}
+		icons[name] = data.Bytes()
+	}
+
+	sprite, symbols := buildSprite(icons)
+	if len(symbols) == 0 {
+		return nil, nil
+	}
+	if err := store.Put(ctx, SpriteFile, bytes.NewReader(sprite)); err != nil {
+		return nil, err
+	}
+	if err := store.Put(ctx, SpriteUsageFile, strings.NewReader(spriteUsage(symbols))); err != nil {
+		return nil, err
+	}
+	return symbols, nil
+}
+
+// buildSprite returns an SVG sprite with a symbol per icon, by file name, and its symbols
+// sorted by ID. IDs inside the icons are prefixed with the symbol ID so that gradients and
+// clip paths of different icons never collide.
+func buildSprite(icons map[string][]byte) ([]byte, []SpriteSymbol) {
+	fileNames := make([]string, 0, len(icons))
+	for name := range icons {
+		fileNames = append(fileNames, name)
+	}
+	sort.Strings(fileNames)
+
+	var symbols []SpriteSymbol
+	var nodes []*svgNode
+	used := make(map[string]int)
+	for _, name := range fileNames {
+		root, err := parseSVG(icons[name])
+		if err != nil {
+			continue
+		}
+
+		id := toKebabCase(strings.TrimSuffix(path.Base(name), path.Ext(name)))
+		if id == "" {
+			id = "icon"
+		}
+		if used[id]++; used[id] > 1 {
+			id = fmt.Sprintf("%s-%d", id, used[id])
+		}
+
+		symbol := &svgNode{name: "symbol", attrs: []xml.Attr{{Name: xml.Name{Local: "id"}, Value: id}}}
+		var width, height string
+		for _, a := range root.attrs {
+			switch {
+			case a.Name.Space == "" && a.Name.Local == "width":
+				width = strings.TrimSuffix(a.Value, "px")
+			case a.Name.Space == "" && a.Name.Local == "height":
+				height = strings.TrimSuffix(a.Value, "px")
+			case a.Name.Space == "xmlns" || (a.Name.Space == "" && a.Name.Local == "xmlns"):
+			case a.Name.Space == "" && spriteRootAttrs[a.Name.Local]:
+			default:
+				symbol.attrs = append(symbol.attrs, a)
+			}
+		}
+		viewBox := attrValue(symbol.attrs, "viewBox")
+		if viewBox == "" && width != "" && height != "" {
+			viewBox = "0 0 " + width + " " + height
+			symbol.attrs = append(symbol.attrs, xml.Attr{Name: xml.Name{Local: "viewBox"}, Value: viewBox})
+		}
+		symbol.children = root.children
+		prefixSVGIDs(symbol.children, id+"-")
+
+		nodes = append(nodes, symbol)
+		symbols = append(symbols, SpriteSymbol{ID: id, FileName: name, ViewBox: viewBox})
+	}
+	if len(symbols) == 0 {
+		return nil, nil
+	}
+
+	var out bytes.Buffer
+	out.WriteString(`<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" style="display:none">` + "\n")
+	for _, symbol := range nodes {
+		writeSVG(&out, symbol)
+		out.WriteString("\n")
+	}
+	out.WriteString("</svg>\n")
+	sort.Slice(symbols, func(i, j int) bool { return symbols[i].ID < symbols[j].ID })
+	return out.Bytes(), symbols
+}
+
+// attrValue returns the value of an attribute without namespace, empty when missing.
+func attrValue(attrs []xml.Attr, name string) string {
+	for _, a := range attrs {
+		if a.Name.Space == "" && a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// prefixSVGIDs prefixes the IDs of nodes and the references to them: url(#id) values and
+// href="#id" links.
+func prefixSVGIDs(nodes []*svgNode, prefix string) {
+	ids := make(map[string]bool)
+	var collect func(nodes []*svgNode)
+	collect = func(nodes []*svgNode) {
+		for _, n := range nodes {
+			if id := attrValue(n.attrs, "id"); id != "" {
+				ids[id] = true
+			}
+			collect(n.children)
+		}
+	}
+	collect(nodes)
+	if len(ids) == 0 {
+		return
+	}
+
+	var rewrite func(nodes []*svgNode)
+	rewrite = func(nodes []*svgNode) {
+		for _, n := range nodes {
+			for i, a := range n.attrs {
+				switch {
+				case a.Name.Space == "" && a.Name.Local == "id":
+					n.attrs[i].Value = prefix + a.Value
+				case a.Name.Local == "href" && strings.HasPrefix(a.Value, "#") && ids[a.Value[1:]]:
+					n.attrs[i].Value = "#" + prefix + a.Value[1:]
+				default:
+					n.attrs[i].Value = svgURLRef.ReplaceAllStringFunc(a.Value, func(ref string) string {
+						if id := ref[len("url(#") : len(ref)-1]; ids[id] {
+							return "url(#" + prefix + id + ")"
+						}
+						return ref
+					})
+				}
+			}
+			rewrite(n.children)
+		}
+	}
+	rewrite(nodes)
+}
+
+// spriteUsage returns an HTML snippet using every symbol of a sprite.
+func spriteUsage(symbols []SpriteSymbol) string {
+	var sb strings.Builder
+	sb.WriteString("<!-- Reference the symbols of " + SpriteFile + " by URL, or inline the sprite once in the page and use \"#id\". -->\n")
+	for _, s := range symbols {
+		width, height := "24", "24"
+		if f := strings.Fields(strings.ReplaceAll(s.ViewBox, ",", " ")); len(f) == 4 {
+			width, height = f[2], f[3]
+		}
+		fmt.Fprintf(&sb, "<svg width=\"%s\" height=\"%s\" aria-hidden=\"true\"><use href=\"%s#%s\"></use></svg>\n", width, height, SpriteFile, s.ID)
+	}
+	return sb.String()
+}
@@ -0,0 +1,124 @@
+package imager
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// AssetStore stores exported images and the files describing them. Names are slash-separated
+// paths relative to the root of the store, e.g. "home/hero@2x.png".
+type AssetStore interface {
+	// Put stores the content of r under name, replacing any previous content.
+	Put(ctx context.Context, name string, r io.Reader) error
+	// Open returns the content stored under name; the error wraps fs.ErrNotExist when there is
+	// none.
+	Open(ctx context.Context, name string) (io.ReadCloser, error)
+	// Delete removes the content stored under name. Deleting a missing name is not an error.
+	Delete(ctx context.Context, name string) error
+}
+
+// LocalStore stores assets in a directory of the local disk.
+type LocalStore struct {
+	Dir string
+}
+
+// path returns the file path of name.
+func (s LocalStore) path(name string) string {
+	return filepath.Join(s.Dir, filepath.FromSlash(path.Clean("/"+name)))
+}
+
+// Put writes the file through a temporary one, so that a failed write never leaves a broken
+// asset behind.
+func (s LocalStore) Put(ctx context.Context, name string, r io.Reader) error {
+	dest := s.path(name)
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return fmt.Errorf("failed to create directory %q: %w", filepath.Dir(dest), err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(dest), "*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create file %q: %w", dest, err)
+	}
+	_, err = io.Copy(tmp, r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		// CreateTemp creates files readable only by their owner.
+		err = os.Chmod(tmp.Name(), 0644)
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), dest)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write file %q: %w", dest, err)
+	}
+	return nil
+}
+
+// Open opens the file of name.
+func (s LocalStore) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	return os.Open(s.path(name))
+}
+
+// Delete removes the file of name.
+func (s LocalStore) Delete(ctx context.Context, name string) error {
+	if err := os.Remove(s.path(name)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (s LocalStore) String() string {
+	return s.Dir
+}
+
+// OpenStore returns the store of a location: "s3://bucket/prefix" for an S3-compatible bucket,
+// configured by the standard AWS environment variables (see S3StoreFromEnv), or a local
+// directory otherwise.
+func OpenStore(location string) (AssetStore, error) {
+	if !strings.HasPrefix(location, "s3://") {
+		return LocalStore{Dir: location}, nil
+	}
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, fmt.Errorf("invalid store location %q: %w", location, err)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid store location %q: missing bucket", location)
+	}
+	return S3StoreFromEnv(u.Host, strings.Trim(u.Path, "/"))
+}
+
+// copyAsset copies the content of a store to w.
+func copyAsset(ctx context.Context, store AssetStore, name string, w io.Writer) error {
+	r, err := store.Open(ctx, name)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	_, err = io.Copy(w, r)
+	return err
+}
+
+// MoveAsset renames an asset of store.
+func MoveAsset(ctx context.Context, store AssetStore, from, to string) error {
+	if from == to {
+		return nil
+	}
+	var data bytes.Buffer
+	if err := copyAsset(ctx, store, from, &data); err != nil {
+		return err
+	}
+	if err := store.Put(ctx, to, &data); err != nil {
+		return err
+	}
+	return store.Delete(ctx, from)
+}
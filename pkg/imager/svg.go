@@ -0,0 +1,232 @@
+package imager
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// svgPrecision is the number of decimals coordinates are rounded to by the SVG optimizer.
+const svgPrecision = 3
+
+// svgEditorNamespaces are the prefixes of elements and attributes only design tools read.
+var svgEditorNamespaces = map[string]bool{
+	"sodipodi": true,
+	"inkscape": true,
+	"sketch":   true,
+	"figma":    true,
+}
+
+// svgNumericAttrs are the attributes holding coordinates and lengths, rounded by the optimizer.
+var svgNumericAttrs = map[string]bool{
+	"d": true, "points": true, "transform": true, "gradientTransform": true, "patternTransform": true,
+	"x": true, "y": true, "width": true, "height": true, "viewBox": true,
+	"x1": true, "y1": true, "x2": true, "y2": true, "cx": true, "cy": true, "r": true, "rx": true, "ry": true,
+	"fx": true, "fy": true, "dx": true, "dy": true, "stroke-width": true, "stdDeviation": true,
+}
+
+// svgTextElements keep their whitespace.
+var svgTextElements = map[string]bool{"text": true, "tspan": true, "style": true, "textPath": true}
+
+// svgNumber matches a number in SVG attribute values, e.g. "-1.5", ".5" or "1e-3".
+var svgNumber = regexp.MustCompile(`-?(?:\d+\.?\d*|\.\d+)(?:[eE][-+]?\d+)?`)
+
+// svgFlags matches digits that start with a zero, which in path data are packed arc flags.
+var svgFlags = regexp.MustCompile(`^-?0\d`)
+
+// svgNode is an element or, when name is empty, character data of an SVG document.
+type svgNode struct {
+	name     string
+	attrs    []xml.Attr
+	children []*svgNode
+	text     string
+}
+
+// optimizeSVG minifies an SVG document: it drops comments, processing instructions, metadata
+// and editor-specific markup, unwraps groups without attributes, removes empty groups and
+// definitions, and rounds coordinates to three decimals.
+func optimizeSVG(data []byte) ([]byte, error) {
+	root, err := parseSVG(data)
+	if err != nil {
+		return nil, err
+	}
+	root.attrs = cleanSVGAttrs(root.attrs)
+	root.children = cleanSVG(root.children, false)
+
+	var out bytes.Buffer
+	writeSVG(&out, root)
+	return out.Bytes(), nil
+}
+
+// parseSVG reads the root element of an SVG document. Prefixes are kept as written.
+func parseSVG(data []byte) (*svgNode, error) {
+	d := xml.NewDecoder(bytes.NewReader(data))
+
+	var root *svgNode
+	var stack []*svgNode
+	for {
+		tok, err := d.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			node := &svgNode{name: qualifiedName(t.Name), attrs: t.Attr}
+			if len(stack) == 0 {
+				if root != nil {
+					return nil, errors.New("SVG document with several root elements")
+				}
+				root = node
+			} else {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, node)
+			}
+			stack = append(stack, node)
+		case xml.EndElement:
+			if len(stack) == 0 {
+				return nil, errors.New("unbalanced SVG element")
+			}
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, &svgNode{text: string(t)})
+			}
+		}
+	}
+	if root == nil || root.name != "svg" || len(stack) > 0 {
+		return nil, errors.New("not an SVG document")
+	}
+	return root, nil
+}
+
+// qualifiedName returns a name with its prefix, e.g. "xlink:href".
+func qualifiedName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+	return name.Space + ":" + name.Local
+}
+
+// cleanSVG returns the optimized children of an element; inText keeps whitespace.
+func cleanSVG(children []*svgNode, inText bool) []*svgNode {
+	var cleaned []*svgNode
+	for _, child := range children {
+		if child.name == "" {
+			if inText || strings.TrimSpace(child.text) != "" {
+				cleaned = append(cleaned, child)
+			}
+			continue
+		}
+		prefix, local, found := strings.Cut(child.name, ":")
+		if !found {
+			prefix, local = "", child.name
+		}
+		if local == "metadata" || svgEditorNamespaces[prefix] {
+			continue
+		}
+
+		child.attrs = cleanSVGAttrs(child.attrs)
+		child.children = cleanSVG(child.children, inText || svgTextElements[local])
+
+		switch {
+		case (local == "g" || local == "defs") && len(child.children) == 0:
+			continue
+		case local == "g" && len(child.attrs) == 0:
+			cleaned = append(cleaned, child.children...)
+		default:
+			cleaned = append(cleaned, child)
+		}
+	}
+	return cleaned
+}
+
+// cleanSVGAttrs drops the attributes of editors and rounds coordinates.
+func cleanSVGAttrs(attrs []xml.Attr) []xml.Attr {
+	cleaned := attrs[:0]
+	for _, a := range attrs {
+		if svgEditorNamespaces[a.Name.Space] || (a.Name.Space == "xmlns" && svgEditorNamespaces[a.Name.Local]) {
+			continue
+		}
+		if a.Name.Space == "" && svgNumericAttrs[a.Name.Local] {
+			a.Value = roundSVGNumbers(a.Value)
+		}
+		cleaned = append(cleaned, a)
+	}
+	return cleaned
+}
+
+// roundSVGNumbers rounds the numbers in an attribute value, keeping the value parsable where
+// numbers were written without separators, such as "1.5.5" in path data.
+func roundSVGNumbers(value string) string {
+	var out strings.Builder
+	last, prev := 0, ""
+	for _, m := range svgNumber.FindAllStringIndex(value, -1) {
+		number := value[m[0]:m[1]]
+		if svgFlags.MatchString(number) {
+			continue // packed arc flags such as "011", kept as written
+		}
+		n, err := strconv.ParseFloat(number, 64)
+		if err != nil {
+			continue
+		}
+		rounded := formatSVGNumber(n)
+		out.WriteString(value[last:m[0]])
+		// A number directly following another must still start a new one.
+		if m[0] == last && last > 0 && rounded[0] != '-' && !(rounded[0] == '.' && strings.Contains(prev, ".")) {
+			out.WriteByte(' ')
+		}
+		out.WriteString(rounded)
+		last, prev = m[1], rounded
+	}
+	out.WriteString(value[last:])
+	return out.String()
+}
+
+// formatSVGNumber formats n with at most svgPrecision decimals and no leading zero.
+func formatSVGNumber(n float64) string {
+	scale := math.Pow(10, svgPrecision)
+	n = math.Round(n*scale) / scale
+	if n == 0 {
+		return "0"
+	}
+	s := strconv.FormatFloat(n, 'f', -1, 64)
+	if strings.HasPrefix(s, "0.") {
+		return s[1:]
+	}
+	if strings.HasPrefix(s, "-0.") {
+		return "-" + s[2:]
+	}
+	return s
+}
+
+// writeSVG writes node and its children without indentation.
+func writeSVG(out *bytes.Buffer, node *svgNode) {
+	if node.name == "" {
+		xml.EscapeText(out, []byte(node.text))
+		return
+	}
+	out.WriteString("<" + node.name)
+	for _, a := range node.attrs {
+		out.WriteString(" " + qualifiedName(a.Name) + `="`)
+		xml.EscapeText(out, []byte(a.Value))
+		out.WriteString(`"`)
+	}
+	if len(node.children) == 0 {
+		out.WriteString("/>")
+		return
+	}
+	out.WriteString(">")
+	for _, child := range node.children {
+		writeSVG(out, child)
+	}
+	out.WriteString("</" + node.name + ">")
+}
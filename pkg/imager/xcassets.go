@@ -0,0 +1,168 @@
+package imager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// AssetCatalogDir is the Xcode asset catalog written by WriteAssetCatalog.
+const AssetCatalogDir = "Images.xcassets"
+
+// xcassetsInfo is the info of the Contents.json files of an asset catalog.
+var xcassetsInfo = map[string]any{"author": "xcode", "version": 1}
+
+// xcassetsImage is an image slot of an image set.
+type xcassetsImage struct {
+	FileName string `json:"filename,omitempty"`
+	Idiom    string `json:"idiom"`
+	Scale    string `json:"scale,omitempty"`
+}
+
+// xcassetsContents is the Contents.json of an image set.
+type xcassetsContents struct {
+	Images     []xcassetsImage `json:"images"`
+	Info       map[string]any  `json:"info"`
+	Properties map[string]any  `json:"properties,omitempty"`
+}
+
+// WriteAssetCatalog moves exported images into an Xcode asset catalog, Images.xcassets, with an
+// image set per node holding its 1x, 2x and 3x images, and writes the Contents.json files that
+// describe them. SVG and PDF images become single images that preserve their vector data.
+// Images of other scales or formats are left where they are. It returns the assets with their
+// new file names and, when manifest is not nil, records the new names in it.
+func WriteAssetCatalog(ctx context.Context, store AssetStore, assets []ExportedAsset, manifest *Manifest) ([]ExportedAsset, error) {
+	sets, moved := assetCatalogLayout(assets)
+	if len(sets) == 0 {
+		return assets, nil
+	}
+
+	for i, a := range assets {
+		newName, ok := moved[i]
+		if !ok || newName == a.FileName {
+			continue
+		}
+		if err := MoveAsset(ctx, store, a.FileName, newName); err != nil {
+			return nil, fmt.Errorf("move %q into the asset catalog: %w", a.FileName, err)
+		}
+		if manifest != nil {
+			manifest.Rename(a.FileName, newName)
+		}
+		assets[i].FileName = newName
+	}
+
+	if err := putJSON(ctx, store, path.Join(AssetCatalogDir, "Contents.json"), map[string]any{"info": xcassetsInfo}); err != nil {
+		return nil, err
+	}
+	for dir, contents := range sets {
+		if err := putJSON(ctx, store, path.Join(dir, "Contents.json"), contents); err != nil {
+			return nil, err
+		}
+	}
+	return assets, nil
+}
+
+// assetCatalogLayout returns the Contents.json of every image set by directory, and the new
+// file names of the assets that go into the catalog, by index.
+func assetCatalogLayout(assets []ExportedAsset) (map[string]*xcassetsContents, map[int]string) {
+	// Group the images of a node, in a stable order so that image set names do not change
+	// between exports.
+	byNode := make(map[string][]int)
+	var nodeIDs []string
+	for i, a := range assets {
+		if !catalogImage(a) {
+			continue
+		}
+		if _, ok := byNode[a.NodeID]; !ok {
+			nodeIDs = append(nodeIDs, a.NodeID)
+		}
+		byNode[a.NodeID] = append(byNode[a.NodeID], i)
+	}
+	sort.Strings(nodeIDs)
+
+	sets := make(map[string]*xcassetsContents)
+	moved := make(map[int]string)
+	used := make(map[string]int)
+	for _, id := range nodeIDs {
+		indexes := byNode[id]
+		first := assets[indexes[0]]
+		vector := -1 // a vector image of the node, which covers every scale
+		for _, i := range indexes {
+			if vectorFormat(assets[i].Format) {
+				vector = i
+				break
+			}
+		}
+		name := toKebabCase(first.NodeName)
+		if name == "" {
+			name = toKebabCase(id)
+		}
+		if used[name]++; used[name] > 1 {
+			name = fmt.Sprintf("%s-%d", name, used[name])
+		}
+		dir := path.Join(AssetCatalogDir, name+".imageset")
+
+		contents := &xcassetsContents{Info: xcassetsInfo}
+		if vector >= 0 {
+			fileName := name + "." + assets[vector].Format
+			moved[vector] = path.Join(dir, fileName)
+			contents.Images = []xcassetsImage{{FileName: fileName, Idiom: "universal"}}
+			contents.Properties = map[string]any{"preserves-vector-representation": true}
+			sets[dir] = contents
+			continue
+		}
+
+		slots := make(map[string]string) // scale -> file name
+		for _, i := range indexes {
+			a := assets[i]
+			if vectorFormat(a.Format) {
+				continue
+			}
+			scale := fmt.Sprintf("%gx", a.Scale)
+			if _, taken := slots[scale]; taken {
+				continue
+			}
+			fileName := name + "." + a.Format
+			if a.Scale != 1 {
+				fileName = fmt.Sprintf("%s@%s.%s", name, scale, a.Format)
+			}
+			slots[scale] = fileName
+			moved[i] = path.Join(dir, fileName)
+		}
+		for _, scale := range []string{"1x", "2x", "3x"} {
+			contents.Images = append(contents.Images, xcassetsImage{FileName: slots[scale], Idiom: "universal", Scale: scale})
+		}
+		sets[dir] = contents
+	}
+	return sets, moved
+}
+
+// catalogImage reports whether an asset fits an image set: a 1x, 2x or 3x PNG or JPEG, or an
+// SVG or PDF.
+func catalogImage(a ExportedAsset) bool {
+	switch strings.ToLower(a.Format) {
+	case "svg", "pdf":
+		return true
+	case "png", "jpg", "jpeg":
+		return a.Scale == 1 || a.Scale == 2 || a.Scale == 3
+	}
+	return false
+}
+
+func vectorFormat(format string) bool {
+	format = strings.ToLower(format)
+	return format == "svg" || format == "pdf"
+}
+
+// putJSON stores v as indented JSON under name.
+func putJSON(ctx context.Context, store AssetStore, name string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return store.Put(ctx, name, bytes.NewReader(append(data, '\n')))
+}
@@ -0,0 +1,142 @@
+package figmaextractor
+
+import (
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+)
+
+// maxExtractBody bounds the size of an extract request body.
+const maxExtractBody = 1 << 16
+
+// ExtractRequest is the JSON body of a request to an ExtractHandler.
+type ExtractRequest struct {
+	URL     string   `json:"url"`               // Figma file URL, may select nodes with its node-id parameter
+	NodeIDs []string `json:"nodeIds,omitempty"` // nodes to extract; empty = those of the URL, or the entire file
+	Format  string   `json:"format,omitempty"`  // output format, see formatter.Formats(); empty = "markdown"
+	Version string   `json:"version,omitempty"` // file version to extract; empty = current
+}
+
+// ExtractResponse is the JSON response of an ExtractHandler for formats rendering several files.
+type ExtractResponse struct {
+	FileName string          `json:"fileName"`
+	Version  string          `json:"version"`
+	Files    []ExtractedFile `json:"files"`
+	Warnings []string        `json:"warnings,omitempty"`
+}
+
+// ExtractedFile is a rendered file of an ExtractResponse.
+type ExtractedFile struct {
+	Name    string `json:"name"`
+	Content string `json:"content"`
+}
+
+// ExtractHandler is an http.Handler running the extraction for every POST request carrying an
+// ExtractRequest, so services can use the extractor over HTTP:
+//
+//	http.Handle("/extract", figmaextractor.NewExtractHandler(opts))
+//
+// Formats rendering a single file, such as "markdown", "json" or "css", respond with the file
+// itself, typed by its extension; the others respond with an ExtractResponse. The number of
+// warnings of the run is sent in the X-Extractor-Warnings header. Requests are extracted
+// concurrently, each until its client goes away.
+type ExtractHandler struct {
+	opts Options
+
+	// AllowServerToken extracts the requests without an X-Figma-Token header with the
+	// AccessToken of the options, instead of rejecting them with 401 Unauthorized. Every client
+	// reaching the handler can then read the files that token can.
+	AllowServerToken bool
+}
+
+// NewExtractHandler returns a handler extracting with opts, whose FileURL, NodeIDs, Format and
+// Version are taken from each request. Requests send their own Figma access token in the
+// X-Figma-Token header; see AllowServerToken to fall back to the AccessToken of opts.
+func NewExtractHandler(opts Options) *ExtractHandler {
+	return &ExtractHandler{opts: opts}
+}
+
+// requestToken returns the Figma access token to extract a request with: the token the request
+// sends, or the server's own when allowed. It reports false when there is neither.
+func requestToken(token string, opts Options, allowServerToken bool) (string, bool) {
+	switch {
+	case token != "":
+		return token, true
+	case allowServerToken && opts.AccessToken != "":
+		return opts.AccessToken, true
+	}
+	return "", false
+}
+
+// ServeHTTP handles an extract request. Requests without a usable token are answered with 401
+// Unauthorized, invalid requests and options with 400 Bad Request, failed extractions with 502
+// Bad Gateway.
+func (h *ExtractHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	token, ok := requestToken(r.Header.Get("X-Figma-Token"), h.opts, h.AllowServerToken)
+	if !ok {
+		http.Error(w, "missing X-Figma-Token header", http.StatusUnauthorized)
+		return
+	}
+
+	var req ExtractRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxExtractBody)).Decode(&req); err != nil {
+		http.Error(w, "invalid extract request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	opts := h.opts
+	opts.FileURL = req.URL
+	opts.NodeIDs = req.NodeIDs
+	opts.Format = req.Format
+	opts.Formats = nil
+	opts.Version = req.Version
+	opts.AccessToken = token
+
+	result, err := Run(r.Context(), opts)
+	if err != nil {
+		if r.Context().Err() != nil {
+			return // the client went away
+		}
+		status := http.StatusBadGateway
+		if configErr := (*ConfigError)(nil); errors.As(err, &configErr) {
+			status = http.StatusBadRequest
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	w.Header().Set("X-Extractor-Warnings", strconv.Itoa(len(result.Warnings)))
+	if len(result.Files) == 1 {
+		file := result.Files[0]
+		contentType := mime.TypeByExtension(path.Ext(file.Name))
+		switch {
+		case path.Ext(file.Name) == ".md":
+			contentType = "text/markdown; charset=utf-8"
+		case contentType == "":
+			contentType = "text/plain; charset=utf-8"
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": path.Base(file.Name)}))
+		w.Write(file.Content)
+		return
+	}
+
+	resp := ExtractResponse{FileName: result.FileName, Version: result.Version, Files: []ExtractedFile{}}
+	for _, f := range result.Files {
+		resp.Files = append(resp.Files, ExtractedFile{Name: f.Name, Content: string(f.Content)})
+	}
+	for _, warning := range result.Warnings {
+		resp.Warnings = append(resp.Warnings, warning.String())
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
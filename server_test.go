@@ -0,0 +1,197 @@
+package figmaextractor
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// testFileURL is the Figma file served by stubFigmaAPI.
+const testFileURL = "https://www.figma.com/design/ABC123/Foundations"
+
+// stubFigmaAPI returns a client sending the Figma API requests and image downloads to a test
+// server, which serves the file of testFileURL, and the renders of its rectangle, to the token
+// "good" and rejects other tokens.
+func stubFigmaAPI(t *testing.T) *http.Client {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/renders/1-1.png" {
+			w.Write([]byte("rendered image"))
+			return
+		}
+		if r.Header.Get("X-Figma-Token") != "good" {
+			http.Error(w, `{"status":403,"err":"Invalid token"}`, http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/files/ABC123":
+		case "/v1/images/ABC123":
+			w.Write([]byte(`{"err": null, "images": {"1:1": "https://renders.example.com/renders/1-1.png"}}`))
+			return
+		case "/v1/files/ABC123/images":
+			w.Write([]byte(`{"err": null, "images": {}}`))
+			return
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{
+			"name": "Foundations",
+			"version": "42",
+			"document": {"id": "0:0", "name": "Document", "type": "DOCUMENT", "children": [
+				{"id": "0:1", "name": "Page", "type": "CANVAS", "children": [
+					{"id": "1:1", "name": "Primary", "type": "RECTANGLE", "cornerRadius": 8, "styles": {"fill": "S:1"},
+					 "fills": [{"type": "SOLID", "visible": true, "color": {"r": 0.2, "g": 0.4, "b": 0.8, "a": 1}}]}
+				]}
+			]},
+			"styles": {"S:1": {"key": "s1", "name": "Primary/500", "styleType": "FILL"}}
+		}`))
+	}))
+	t.Cleanup(server.Close)
+
+	target, _ := url.Parse(server.URL)
+	return &http.Client{Transport: rewriteTransport{target: target}}
+}
+
+// rewriteTransport sends every request to target instead of its own host.
+type rewriteTransport struct {
+	target *url.URL
+}
+
+func (t rewriteTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.URL.Scheme, r.URL.Host, r.Host = t.target.Scheme, t.target.Host, ""
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestExtractHandler(t *testing.T) {
+	opts := Options{AccessToken: "good", HTTPClient: stubFigmaAPI(t), NoScreenshot: true}
+
+	tests := []struct {
+		name             string
+		method           string
+		token            string
+		allowServerToken bool
+		body             string
+		wantStatus       int
+		wantType         string // Content-Type prefix of the response
+		wantBody         string // substring of the response body
+	}{
+		{
+			name:       "get",
+			method:     http.MethodGet,
+			token:      "good",
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:       "no token",
+			body:       `{"url": "` + testFileURL + `"}`,
+			wantStatus: http.StatusUnauthorized,
+			wantBody:   "missing X-Figma-Token header",
+		},
+		{
+			name:             "server token",
+			allowServerToken: true,
+			body:             `{"url": "` + testFileURL + `", "format": "css"}`,
+			wantStatus:       http.StatusOK,
+			wantType:         "text/css",
+			wantBody:         "--color-",
+		},
+		{
+			name:       "invalid json",
+			token:      "good",
+			body:       `{"url": `,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "invalid extract request",
+		},
+		{
+			name:       "body too large",
+			token:      "good",
+			body:       `{"url": "` + strings.Repeat("a", maxExtractBody) + `"}`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "request body too large",
+		},
+		{
+			name:       "invalid url",
+			token:      "good",
+			body:       `{"url": "https://example.com/not-figma"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "unknown format",
+			token:      "good",
+			body:       `{"url": "` + testFileURL + `", "format": "docx"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "rejected token",
+			token:      "bad",
+			body:       `{"url": "` + testFileURL + `"}`,
+			wantStatus: http.StatusBadGateway,
+		},
+		{
+			name:       "single file",
+			token:      "good",
+			body:       `{"url": "` + testFileURL + `"}`,
+			wantStatus: http.StatusOK,
+			wantType:   "text/markdown",
+			wantBody:   "Foundations",
+		},
+		{
+			name:       "several files",
+			token:      "good",
+			body:       `{"url": "` + testFileURL + `", "format": "android"}`,
+			wantStatus: http.StatusOK,
+			wantType:   "application/json",
+			wantBody:   `"version":"42"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewExtractHandler(opts)
+			handler.AllowServerToken = tt.allowServerToken
+
+			method := tt.method
+			if method == "" {
+				method = http.MethodPost
+			}
+			req := httptest.NewRequest(method, "/extract", strings.NewReader(tt.body))
+			if tt.token != "" {
+				req.Header.Set("X-Figma-Token", tt.token)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d; body %q", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, tt.wantType) {
+				t.Errorf("Content-Type = %q, want %q", got, tt.wantType)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("body = %q, want it to contain %q", rec.Body.String(), tt.wantBody)
+			}
+			if tt.wantStatus == http.StatusMethodNotAllowed && rec.Header().Get("Allow") != http.MethodPost {
+				t.Errorf("Allow = %q, want POST", rec.Header().Get("Allow"))
+			}
+			if tt.wantStatus == http.StatusOK && rec.Header().Get("X-Extractor-Warnings") == "" {
+				t.Error("X-Extractor-Warnings header not set")
+			}
+			if tt.wantType == "application/json" {
+				var resp ExtractResponse
+				if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+					t.Fatalf("response is not an ExtractResponse: %v", err)
+				}
+				if resp.FileName != "Foundations" || len(resp.Files) < 2 {
+					t.Errorf("response = %s %s with %d files, want Foundations with several files", resp.FileName, resp.Version, len(resp.Files))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package figmaextractor
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// Phases of a run, logged as the "phase" field by SlogLogger.
+const (
+	phaseAuth      = "auth"
+	phaseFetch     = "fetch"
+	phaseLibrary   = "library-styles"
+	phaseExtract   = "extract"
+	phaseVariables = "variables"
+	phaseUsage     = "component-usage"
+	phaseDevRes    = "dev-resources"
+	phaseComments  = "comments"
+	phaseImages    = "images"
+	phaseRender    = "render"
+	phaseWatch     = "watch"
+)
+
+// SlogLogger adapts l to a Logger, so runs can log to a *slog.Logger:
+//
+//	opts.Logger = figmaextractor.SlogLogger(slog.Default())
+//
+// Messages are logged at the Debug, Info, Warn and Error levels with the fields of the run
+// instead of inside the message: "file_key", "phase" (auth, fetch, library-styles, extract,
+// variables, component-usage, dev-resources, comments, images, render or watch), for warnings,
+// "category" and the "node_id" and "node_name" of the node they concern and, for the requests
+// traced with Options.TraceHTTP, "method", "url", "status" and "duration".
+func SlogLogger(l *slog.Logger) Logger {
+	return &slogLogger{l: l}
+}
+
+type slogLogger struct {
+	l *slog.Logger
+}
+
+func (s *slogLogger) Debugf(format string, args ...any) {
+	s.l.Debug(fmt.Sprintf(format, args...))
+}
+
+func (s *slogLogger) Infof(format string, args ...any) {
+	s.l.Info(fmt.Sprintf(format, args...))
+}
+
+func (s *slogLogger) Warnf(format string, args ...any) {
+	s.l.Warn(fmt.Sprintf(format, args...))
+}
+
+func (s *slogLogger) Errorf(format string, args ...any) {
+	s.l.Error(fmt.Sprintf(format, args...))
+}
+
+func (s *slogLogger) logAttrs(level slog.Level, msg string, attrs []slog.Attr) {
+	s.l.LogAttrs(context.Background(), level, msg, attrs...)
+}
+
+// attrLogger is a Logger taking the fields of a message apart from it, see SlogLogger.
+type attrLogger interface {
+	logAttrs(level slog.Level, msg string, attrs []slog.Attr)
+}
+
+// log sends a message to the Logger: with the fields of the run and attrs to an attrLogger,
+// formatted by the method of its level to others.
+func (o *Options) log(level slog.Level, attrs []slog.Attr, f string, a ...any) {
+	switch l := o.Logger.(type) {
+	case nil:
+	case attrLogger:
+		var fields []slog.Attr
+		if o.fileKey != "" {
+			fields = append(fields, slog.String("file_key", o.fileKey))
+		}
+		if o.phase != "" {
+			fields = append(fields, slog.String("phase", o.phase))
+		}
+		l.logAttrs(level, fmt.Sprintf(f, a...), append(fields, attrs...))
+	default:
+		switch {
+		case level >= slog.LevelError:
+			l.Errorf(f, a...)
+		case level >= slog.LevelWarn:
+			l.Warnf(f, a...)
+		case level >= slog.LevelInfo:
+			l.Infof(f, a...)
+		default:
+			if d, ok := l.(DebugLogger); ok {
+				d.Debugf(f, a...)
+			}
+		}
+	}
+}
+
+// tracingTransport logs the requests it sends at the debug level, for Options.TraceHTTP.
+type tracingTransport struct {
+	opts *Options
+	next http.RoundTripper // nil = http.DefaultTransport
+}
+
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	start := time.Now()
+	resp, err := next.RoundTrip(req)
+	elapsed := time.Since(start).Round(time.Millisecond)
+
+	// Only the URL is logged: the access token travels in the headers.
+	url := req.URL.Redacted()
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("url", url),
+		slog.Duration("duration", elapsed),
+	}
+	if err != nil {
+		t.opts.log(slog.LevelDebug, append(attrs, slog.String("error", err.Error())), "%s %s: %v (%s)", req.Method, url, err, elapsed)
+		return nil, err
+	}
+	t.opts.log(slog.LevelDebug, append(attrs, slog.Int("status", resp.StatusCode)), "%s %s: %s (%s)", req.Method, url, resp.Status, elapsed)
+	return resp, nil
+}
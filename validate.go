@@ -0,0 +1,215 @@
+package figmaextractor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/hellenic-development/figma-extractor/pkg/extractor"
+	"github.com/hellenic-development/figma-extractor/pkg/figma"
+	"github.com/hellenic-development/figma-extractor/pkg/formatter"
+)
+
+// Errors of invalid options, wrapped by ConfigError.
+var (
+	ErrMissingToken  = errors.New("no access token")
+	ErrInvalidURL    = errors.New("not a Figma file URL")
+	ErrInvalidFormat = errors.New("unknown format")
+	ErrInvalidScale  = errors.New("invalid scale")
+	ErrInvalidValue  = errors.New("invalid value")
+	ErrNotWritable   = errors.New("directory not writable")
+)
+
+// ConfigError reports an invalid option. Its Err wraps one of the Err* values, so callers can
+// tell the problems apart with errors.Is:
+//
+//	if errors.Is(err, figmaextractor.ErrMissingToken) { ... }
+type ConfigError struct {
+	Option string // name of the Options field, e.g. "AccessToken" or "ImageScales"
+	Err    error
+}
+
+func (e *ConfigError) Error() string { return e.Option + ": " + e.Err.Error() }
+func (e *ConfigError) Unwrap() error { return e.Err }
+
+// Validate checks the options of Run up front, without any API request: the presence of the
+// access token, the shape of FileURL and FileURLs, the output formats, token unit, report
+// sections and template, the image format, scales and node name patterns, the proxy and rules
+// file, and that the image, cache and capture directories are writable. It returns every
+// problem found, joined, as *ConfigError values; nil when the options are valid. Run validates
+// its options the same way.
+func (o Options) Validate() error {
+	o.applyDefaults()
+	return o.validate(true)
+}
+
+// applyDefaults sets the defaults of unset options.
+func (o *Options) applyDefaults() {
+	if o.ImageFormat == "" {
+		o.ImageFormat = "png"
+	}
+	if o.ImageDir == "" {
+		o.ImageDir = "figma-assets"
+	}
+	if len(o.ImageScales) == 0 {
+		o.ImageScales = []float64{1}
+	}
+	if o.Format == "" {
+		o.Format = "markdown"
+		if o.OutputTemplate != "" {
+			o.Format = "template"
+		}
+	}
+}
+
+// formats returns the output formats to render.
+func (o *Options) formats() []string {
+	if formats := uniqueFormats(o.Formats); len(formats) > 0 {
+		return formats
+	}
+	return []string{o.Format}
+}
+
+// validate checks the options with their defaults applied. Options that only matter to API
+// requests and image export are checked when online is true.
+func (o *Options) validate(online bool) error {
+	var errs []error
+	invalid := func(option string, err error) {
+		errs = append(errs, &ConfigError{Option: option, Err: err})
+	}
+
+	if online {
+		if o.AccessToken == "" {
+			invalid("AccessToken", ErrMissingToken)
+		}
+		if o.FileURL != "" || len(o.FileURLs) == 0 {
+			if _, err := figma.ExtractFileKey(o.FileURL); err != nil {
+				invalid("FileURL", fmt.Errorf("%w: %q must be a figma.com URL with a /file/ or /design/ path", ErrInvalidURL, o.FileURL))
+			}
+		}
+		for _, fileURL := range o.FileURLs {
+			if _, err := figma.ExtractFileKey(fileURL); err != nil {
+				invalid("FileURLs", fmt.Errorf("%w: %q must be a figma.com URL with a /file/ or /design/ path", ErrInvalidURL, fileURL))
+			}
+		}
+		if o.Proxy != "" {
+			if _, err := figma.ParseProxy(o.Proxy); err != nil {
+				invalid("Proxy", fmt.Errorf("%w: %v", ErrInvalidValue, err))
+			}
+		}
+		for _, dir := range []struct{ option, path string }{
+			{"CacheDir", o.CacheDir},
+			{"CaptureDir", o.CaptureDir},
+		} {
+			if dir.path != "" {
+				if err := checkWritable(dir.path); err != nil {
+					invalid(dir.option, err)
+				}
+			}
+		}
+
+		if o.ExportImages {
+			switch o.ImageFormat {
+			case "png", "svg", "jpg", "pdf":
+			default:
+				invalid("ImageFormat", fmt.Errorf("%w %q (must be png, svg, jpg, or pdf)", ErrInvalidFormat, o.ImageFormat))
+			}
+			for _, s := range o.ImageScales {
+				if s < 0.01 || s > 4 {
+					invalid("ImageScales", fmt.Errorf("%w %g (must be between 0.01 and 4)", ErrInvalidScale, s))
+				}
+			}
+			for _, pattern := range o.ImageNodes {
+				if _, err := path.Match(pattern, ""); err != nil {
+					invalid("ImageNodes", fmt.Errorf("%w: pattern %q: %v", ErrInvalidValue, pattern, err))
+				}
+			}
+			if o.ImageStore == nil {
+				if err := checkWritable(o.ImageDir); err != nil {
+					invalid("ImageDir", err)
+				}
+			}
+		}
+	}
+
+	formatsOption := "Format"
+	if len(o.Formats) > 0 {
+		formatsOption = "Formats"
+	}
+	for _, format := range o.formats() {
+		if !formatter.IsFormat(format) {
+			invalid(formatsOption, fmt.Errorf("%w %q (must be one of %s)", ErrInvalidFormat, format, strings.Join(formatter.Formats(), ", ")))
+			continue
+		}
+		if format == "template" {
+			if o.OutputTemplate == "" {
+				invalid("OutputTemplate", fmt.Errorf("%w: output format %q requires an output template", ErrInvalidValue, format))
+			} else if _, err := formatter.ParseTemplate(o.OutputTemplate); err != nil {
+				invalid("OutputTemplate", fmt.Errorf("%w: parse output template: %v", ErrInvalidValue, err))
+			}
+		}
+	}
+	if err := formatter.ValidateSections(o.Sections); err != nil {
+		invalid("Sections", fmt.Errorf("%w: %v", ErrInvalidValue, err))
+	}
+	if o.Unit != "" && o.Unit != "px" && o.Unit != "rem" {
+		invalid("Unit", fmt.Errorf("%w: %q, want px or rem", ErrInvalidValue, o.Unit))
+	}
+	if o.BaseFontSize < 0 {
+		invalid("BaseFontSize", fmt.Errorf("%w: %g, want a size in px", ErrInvalidValue, o.BaseFontSize))
+	}
+	for _, filter := range []struct {
+		option   string
+		patterns []string
+	}{
+		{"IncludeNodes", o.IncludeNodes},
+		{"ExcludeNodes", o.ExcludeNodes},
+	} {
+		for _, pattern := range filter.patterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				invalid(filter.option, fmt.Errorf("%w: pattern %q: %v", ErrInvalidValue, pattern, err))
+			}
+		}
+	}
+	if o.RulesFile != "" {
+		data, err := os.ReadFile(o.RulesFile)
+		if err == nil {
+			_, err = extractor.ParseColorRules(data)
+		}
+		if err != nil {
+			invalid("RulesFile", fmt.Errorf("%w: %v", ErrInvalidValue, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// checkWritable reports whether files can be created in dir, or in its nearest existing parent
+// when dir does not exist yet. Nothing is left behind.
+func checkWritable(dir string) error {
+	path := dir
+	for {
+		info, err := os.Stat(path)
+		if err == nil {
+			if !info.IsDir() {
+				return fmt.Errorf("%w: %s is not a directory", ErrNotWritable, path)
+			}
+			break
+		}
+		parent := filepath.Dir(path)
+		if !os.IsNotExist(err) || parent == path {
+			return fmt.Errorf("%w: %v", ErrNotWritable, err)
+		}
+		path = parent
+	}
+
+	f, err := os.CreateTemp(path, ".figma-extractor-*")
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrNotWritable, dir, err)
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
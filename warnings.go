@@ -0,0 +1,64 @@
+package figmaextractor
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/hellenic-development/figma-extractor/pkg/imager"
+)
+
+// WarningCategory classifies a Warning.
+type WarningCategory string
+
+// Warning categories.
+const (
+	WarningAPI    WarningCategory = "api"    // an optional Figma API request failed, e.g. variables or comments
+	WarningImage  WarningCategory = "image"  // an image, screenshot or embedded image could not be exported
+	WarningOutput WarningCategory = "output" // a side file, such as assets.json or the image manifest, could not be written
+	WarningOption WarningCategory = "option" // an option was ignored
+	WarningNode   WarningCategory = "node"   // a requested node was not found
+)
+
+// Warning is a non-fatal issue of a run: the extraction went on without the data or file
+// concerned. Warnings are logged as they occur and collected in Result.Warnings.
+type Warning struct {
+	Category WarningCategory
+	Message  string // the logged message
+	NodeID   string // node the warning is about; empty when it is not about a node
+	NodeName string // empty when unknown
+	Err      error  // underlying error; nil for warnings without one
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("%s: %s", w.Category, w.Message)
+}
+
+// warn logs w and records it for the Result of the run.
+func (o *Options) warn(w Warning) {
+	attrs := []slog.Attr{slog.String("category", string(w.Category))}
+	if w.NodeID != "" {
+		attrs = append(attrs, slog.String("node_id", w.NodeID))
+	}
+	if w.NodeName != "" {
+		attrs = append(attrs, slog.String("node_name", w.NodeName))
+	}
+	o.log(slog.LevelWarn, attrs, "%s", w.Message)
+	o.warnings = append(o.warnings, w)
+}
+
+// warnf logs a warning of the given category and records it for the Result of the run. The
+// last error among a is kept as its Err, and an *imager.NodeError names its node.
+func (o *Options) warnf(category WarningCategory, f string, a ...any) {
+	w := Warning{Category: category, Message: fmt.Sprintf(f, a...)}
+	for _, arg := range a {
+		if err, ok := arg.(error); ok {
+			w.Err = err
+		}
+	}
+	var nodeErr *imager.NodeError
+	if errors.As(w.Err, &nodeErr) {
+		w.NodeID, w.NodeName = nodeErr.NodeID, nodeErr.NodeName
+	}
+	o.warn(w)
+}
@@ -0,0 +1,90 @@
+package figmaextractor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hellenic-development/figma-extractor/pkg/figma"
+)
+
+// defaultPollInterval is how often Watch checks the file for changes when
+// Options.PollInterval is not set. Every check is one lightweight API request.
+const defaultPollInterval = 30 * time.Second
+
+// Watch runs the extraction like Run, then keeps polling the file's version every
+// opts.PollInterval and runs the extraction again whenever the design changes.
+//
+// onResult is called with the result of every run. A failed first run is returned as is,
+// since it usually means the options are wrong; later failures are passed to onResult with a
+// nil result so that a temporary API outage does not end the watch. Watch returns when ctx is
+// done, with ctx.Err(), or when onResult returns a non-nil error, with that error.
+func Watch(ctx context.Context, opts Options, onResult func(*Result, error) error) error {
+	interval := opts.PollInterval
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+
+	fileKey, err := figma.ExtractFileKey(opts.FileURL)
+	if err != nil {
+		return fmt.Errorf("extract file key: %w", err)
+	}
+
+	result, err := Run(ctx, opts)
+	if err != nil {
+		return err
+	}
+	if err := onResult(result, nil); err != nil {
+		return err
+	}
+	version, lastModified := result.Version, result.LastModified
+
+	client, err := opts.newClient()
+	if err != nil {
+		return err
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	// The checks are logged with the fields of the watch; every run sets its own.
+	watch := opts
+	watch.fileKey, watch.phase = fileKey, phaseWatch
+
+	for {
+		watch.logInfo("Watching for changes (checking every %s)...", interval)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		meta, err := client.GetFileMetadata(ctx, fileKey)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			watch.logWarn("Checking for changes failed: %v", err)
+			continue
+		}
+		if meta.Version == version && meta.LastModified == lastModified {
+			continue
+		}
+
+		watch.logInfo("File changed (version %s, modified %s), extracting again...", meta.Version, meta.LastModified)
+		result, err := Run(ctx, opts)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if err := onResult(nil, err); err != nil {
+				return err
+			}
+			continue
+		}
+		// Remember the version that was actually extracted, which may be newer than meta's.
+		version, lastModified = result.Version, result.LastModified
+		if err := onResult(result, nil); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package figmaextractor
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/hellenic-development/figma-extractor/pkg/figma"
+)
+
+// maxWebhookBody bounds the size of a webhook event body.
+const maxWebhookBody = 1 << 20
+
+// WebhookHandler is an http.Handler receiving Figma webhook events. Every FILE_UPDATE event for
+// the file of its options runs the extraction in the background and passes the outcome to
+// OnResult, so token pipelines are updated as soon as the design changes instead of polling.
+//
+// Figma expects a quick response, so events are acknowledged before extracting. Updates
+// arriving while an extraction runs are coalesced into a single follow-up run.
+type WebhookHandler struct {
+	ctx      context.Context
+	opts     Options
+	fileKey  string
+	passcode string
+	onResult func(*Result, error)
+
+	mu      sync.Mutex
+	running bool
+	pending bool
+}
+
+// NewWebhookHandler returns a handler extracting opts.FileURL on every FILE_UPDATE event of that
+// file carrying passcode. Extractions run with ctx, which should live as long as the server.
+// onResult is called from a background goroutine after every extraction.
+func NewWebhookHandler(ctx context.Context, opts Options, passcode string, onResult func(*Result, error)) (*WebhookHandler, error) {
+	fileKey, err := figma.ExtractFileKey(opts.FileURL)
+	if err != nil {
+		return nil, fmt.Errorf("extract file key: %w", err)
+	}
+	if passcode == "" {
+		return nil, fmt.Errorf("webhook passcode is required")
+	}
+
+	return &WebhookHandler{
+		ctx:      ctx,
+		opts:     opts,
+		fileKey:  fileKey,
+		passcode: passcode,
+		onResult: onResult,
+	}, nil
+}
+
+// ServeHTTP handles a webhook event. Requests that are not POSTs carrying the passcode are
+// rejected; PING events and events of other types or files are acknowledged and ignored.
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var event figma.WebhookEvent
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxWebhookBody)).Decode(&event); err != nil {
+		http.Error(w, "invalid webhook event", http.StatusBadRequest)
+		return
+	}
+	if subtle.ConstantTimeCompare([]byte(event.Passcode), []byte(h.passcode)) != 1 {
+		http.Error(w, "invalid passcode", http.StatusForbidden)
+		return
+	}
+
+	if event.EventType == "FILE_UPDATE" && event.FileKey == h.fileKey {
+		h.opts.logInfo("Received FILE_UPDATE for %s, extracting...", event.FileName)
+		h.trigger()
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// trigger starts an extraction, or schedules another one when an extraction is running.
+func (h *WebhookHandler) trigger() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.running {
+		h.pending = true
+		return
+	}
+	h.running = true
+	go h.extract()
+}
+
+// extract runs extractions until no update arrived during the last one.
+func (h *WebhookHandler) extract() {
+	for {
+		result, err := Run(h.ctx, h.opts)
+		if h.onResult != nil {
+			h.onResult(result, err)
+		}
+
+		h.mu.Lock()
+		if !h.pending || h.ctx.Err() != nil {
+			h.running = false
+			h.mu.Unlock()
+			return
+		}
+		h.pending = false
+		h.mu.Unlock()
+	}
+}
+
+// RegisterWebhook registers a FILE_UPDATE webhook posting the updates of every file of a team to
+// endpoint, where a WebhookHandler created with the same passcode should be listening. The
+// token of opts needs the webhooks:write scope. Delete the webhook with
+// figma.Client.DeleteWebhook when it is no longer needed.
+func RegisterWebhook(ctx context.Context, opts Options, teamID, endpoint, passcode string) (*figma.Webhook, error) {
+	client, err := opts.newClient()
+	if err != nil {
+		return nil, err
+	}
+	webhook, err := client.CreateWebhook(ctx, figma.WebhookRequest{
+		EventType:   "FILE_UPDATE",
+		TeamID:      teamID,
+		Endpoint:    endpoint,
+		Passcode:    passcode,
+		Description: "figma-extractor",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("register webhook: %w", err)
+	}
+	return webhook, nil
+}